@@ -27,11 +27,18 @@ type DatabaseConfig struct {
 	DSN string `yaml:"dsn"`
 }
 
+// DownloadConfig 下载配置
+type DownloadConfig struct {
+	// Mode 下载方式: redirect 重定向到 MinIO 预签名URL（默认），proxy 由网关代理流式传输
+	Mode string `yaml:"mode"`
+}
+
 // GlobalConfig 全局配置结构
 type GlobalConfig struct {
 	Etcd     EtcdConfig     `yaml:"etcd"`
 	Minio    MinioConfig    `yaml:"minio"`
 	Database DatabaseConfig `yaml:"database"`
+	Download DownloadConfig `yaml:"download"`
 }
 
 const configPath = "/home/haobin/桌面/test/go_test/micro-cloud-storage/gateway/global/global.yaml"
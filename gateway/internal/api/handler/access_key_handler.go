@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	userpb "github.com/waitform/micro-cloud-storage/protos/user/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleCreateAccessKey 为登录用户创建 S3 访问密钥，secret_access_key 只在这里返回一次
+func (h *UserHandler) HandleCreateAccessKey(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	resp, err := h.userClient.CreateAccessKey(context.Background(), &userpb.CreateAccessKeyRequest{UserId: userID})
+	if err != nil {
+		utils.Error("Failed to create access key: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to create access key")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Access key created successfully", resp.GetAccessKey())
+}
+
+// HandleListAccessKeys 列出登录用户的 S3 访问密钥，不包含密钥本身
+func (h *UserHandler) HandleListAccessKeys(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	resp, err := h.userClient.ListAccessKeys(context.Background(), &userpb.ListAccessKeysRequest{UserId: userID})
+	if err != nil {
+		utils.Error("Failed to list access keys: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to list access keys")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Access keys retrieved successfully", resp.GetAccessKeys())
+}
+
+// HandleDeleteAccessKey 删除登录用户的 S3 访问密钥
+// 请求体: {"access_key_id": "AK..."}
+func (h *UserHandler) HandleDeleteAccessKey(c *gin.Context) {
+	var req userpb.DeleteAccessKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.AccessKeyId == "" {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	resp, err := h.userClient.DeleteAccessKey(context.Background(), &req)
+	if err != nil {
+		utils.Error("Failed to delete access key: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to delete access key")
+		return
+	}
+	if !resp.GetSuccess() {
+		pack.WriteError(c, http.StatusNotFound, resp.GetMessage())
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Access key deleted successfully", nil)
+}
@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 打包下载的格式
+const (
+	ArchiveFormatZip   = "zip"
+	ArchiveFormatTarGz = "tar.gz"
+)
+
+const (
+	maxArchiveEntries  = 1000 // 一次最多打包的文件数
+	maxArchiveDepth    = 32   // 打包文件夹时最多展开的层数
+	archiveListPage    = 200
+	defaultArchiveName = "archive"
+)
+
+var (
+	errArchiveTooMany   = errors.New("too many files")
+	errArchiveTooDeep   = errors.New("folder nesting too deep")
+	errArchiveForbidden = errors.New("permission denied")
+	errArchiveNotFound  = errors.New("file not found")
+)
+
+// DownloadArchiveRequest 打包下载请求
+type DownloadArchiveRequest struct {
+	FileIDs  []int64 `json:"file_ids"`
+	FolderID int64   `json:"folder_id"` // 不为0时打包该文件夹下的全部文件，保留子文件夹结构
+	Format   string  `json:"format"`    // zip（默认）或 tar.gz
+	Name     string  `json:"name"`      // 下载的文件名，不含扩展名
+}
+
+// archiveEntry 压缩包中的一个文件
+type archiveEntry struct {
+	path string
+	file *filepb.FileInfo
+}
+
+// HandleDownloadArchive 处理打包下载请求，在网关边读取边生成 ZIP 或 tar.gz，不缓存整个文件
+// 每个文件都需要读权限，输出经公平传输管理器限速
+// 请求体: {"file_ids": [1, 2], "folder_id": 0, "format": "zip", "name": "photos"}
+func (h *FileHandler) HandleDownloadArchive(c *gin.Context) {
+	var req DownloadArchiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	switch req.Format {
+	case "":
+		req.Format = ArchiveFormatZip
+	case ArchiveFormatZip, ArchiveFormatTarGz:
+	case "tgz":
+		req.Format = ArchiveFormatTarGz
+	default:
+		pack.WriteError(c, http.StatusBadRequest, "Unsupported archive format")
+		return
+	}
+	if len(req.FileIDs) == 0 && req.FolderID == 0 {
+		pack.WriteError(c, http.StatusBadRequest, "Missing file_ids or folder_id")
+		return
+	}
+
+	// 先完成全部校验，开始输出后就无法再返回错误状态码
+	entries, err := h.collectArchiveEntries(c.Request.Context(), userID, &req)
+	switch {
+	case errors.Is(err, errArchiveTooMany):
+		pack.WriteError(c, http.StatusBadRequest, fmt.Sprintf("An archive can contain at most %d files", maxArchiveEntries))
+		return
+	case errors.Is(err, errArchiveTooDeep):
+		pack.WriteError(c, http.StatusBadRequest, fmt.Sprintf("Folders can be nested at most %d levels deep", maxArchiveDepth))
+		return
+	case errors.Is(err, errArchiveForbidden):
+		pack.WriteError(c, http.StatusForbidden, "You don't have permission to access this resource")
+		return
+	case errors.Is(err, errArchiveNotFound):
+		pack.WriteError(c, http.StatusNotFound, "File not found")
+		return
+	case err != nil:
+		utils.Error("Failed to prepare archive: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to prepare archive")
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		name = defaultArchiveName
+	}
+	contentType := "application/zip"
+	if req.Format == ArchiveFormatTarGz {
+		contentType = "application/gzip"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + "." + req.Format}))
+	c.Status(http.StatusOK)
+
+	// 下载时间与文件大小有关，取消 HTTP 服务器的写超时
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	w := utils.NewFairRateLimitedWriter(c.Writer, transferLimiters(c)...)
+	read := func(entry archiveEntry, dst io.Writer) error {
+		_, err := h.fileClient.ReadFile(c.Request.Context(), &filepb.ReadFileRequest{
+			FileId:    entry.file.GetId(),
+			VersionId: entry.file.GetVersionId(),
+		}, dst)
+		return err
+	}
+	if req.Format == ArchiveFormatTarGz {
+		err = writeTarGz(w, entries, read)
+	} else {
+		err = writeZip(w, entries, read)
+	}
+	if err != nil {
+		// 响应头已发出，只能中断连接
+		utils.Error("Failed to stream archive for user %d: %v", userID, err)
+		c.Abort()
+	}
+}
+
+// collectArchiveEntries 查询要打包的文件并检查读权限，重名的文件追加序号
+func (h *FileHandler) collectArchiveEntries(ctx context.Context, userID int64, req *DownloadArchiveRequest) ([]archiveEntry, error) {
+	var entries []archiveEntry
+	seen := make(map[string]bool)
+	add := func(p string, file *filepb.FileInfo) error {
+		if len(entries) >= maxArchiveEntries {
+			return errArchiveTooMany
+		}
+		allowed, err := hasFilePermission(userID, file.GetId(), "read")
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errArchiveForbidden
+		}
+		entries = append(entries, archiveEntry{path: uniqueArchivePath(seen, p), file: file})
+		return nil
+	}
+
+	for _, fileID := range req.FileIDs {
+		resp, err := h.fileClient.GetFileInfo(ctx, &filepb.GetFileInfoRequest{FileId: fileID})
+		if err != nil {
+			return nil, errArchiveNotFound
+		}
+		file := resp.GetFile()
+		if file.GetStatus() != 1 || file.GetTrashedAt() != 0 {
+			return nil, errArchiveNotFound
+		}
+		if err := add(file.GetName(), file); err != nil {
+			return nil, err
+		}
+	}
+	if req.FolderID != 0 {
+		if err := h.walkArchiveFolder(ctx, userID, req.FolderID, "", 0, add); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// walkArchiveFolder 递归列出文件夹下已完成上传的文件，prefix 为文件夹在压缩包中的路径
+func (h *FileHandler) walkArchiveFolder(ctx context.Context, userID, folderID int64, prefix string, depth int, add func(string, *filepb.FileInfo) error) error {
+	if depth >= maxArchiveDepth {
+		return errArchiveTooDeep
+	}
+	var folders []*filepb.FolderInfo
+	for page, listed := int32(1), int64(0); ; page++ {
+		resp, err := h.fileClient.ListDirectory(ctx, &filepb.ListDirectoryRequest{
+			UserId:   userID,
+			FolderId: folderID,
+			Page:     page,
+			PageSize: archiveListPage,
+		})
+		if err != nil {
+			return fmt.Errorf("list folder %d: %w", folderID, err)
+		}
+		folders = append(folders, resp.GetFolders()...)
+		for _, file := range resp.GetFiles() {
+			if file.GetStatus() != 1 {
+				continue
+			}
+			if err := add(path.Join(prefix, file.GetName()), file); err != nil {
+				return err
+			}
+		}
+		listed += int64(len(resp.GetFolders()) + len(resp.GetFiles()))
+		if len(resp.GetFolders())+len(resp.GetFiles()) == 0 || listed >= resp.GetTotal() {
+			break
+		}
+	}
+	for _, folder := range folders {
+		if err := h.walkArchiveFolder(ctx, userID, folder.GetId(), path.Join(prefix, folder.GetName()), depth+1, add); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uniqueArchivePath 压缩包中已有同名文件时追加 " (n)" 后缀
+func uniqueArchivePath(seen map[string]bool, p string) string {
+	ext := path.Ext(p)
+	base := strings.TrimSuffix(p, ext)
+	candidate := p
+	for i := 1; seen[candidate]; i++ {
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+	seen[candidate] = true
+	return candidate
+}
+
+// archiveModTime 压缩包中文件的修改时间，优先使用客户端提供的原始修改时间
+func archiveModTime(file *filepb.FileInfo) time.Time {
+	for _, ts := range []int64{file.GetMtime(), file.GetUpdatedAt(), file.GetCreatedAt()} {
+		if ts > 0 {
+			return time.Unix(ts, 0)
+		}
+	}
+	return time.Now()
+}
+
+// writeZip 按顺序把文件写入 ZIP，内容使用 Deflate 压缩
+func writeZip(w io.Writer, entries []archiveEntry, read func(archiveEntry, io.Writer) error) error {
+	zw := zip.NewWriter(w)
+	for _, entry := range entries {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     entry.path,
+			Method:   zip.Deflate,
+			Modified: archiveModTime(entry.file),
+		})
+		if err != nil {
+			return err
+		}
+		if err := read(entry, fw); err != nil {
+			return fmt.Errorf("read file %d: %w", entry.file.GetId(), err)
+		}
+	}
+	return zw.Close()
+}
+
+// writeTarGz 按顺序把文件写入 tar 并用 gzip 压缩，文件大小必须与记录一致
+func writeTarGz(w io.Writer, entries []archiveEntry, read func(archiveEntry, io.Writer) error) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, entry := range entries {
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     entry.path,
+			Size:     entry.file.GetSize(),
+			Mode:     0o644,
+			ModTime:  archiveModTime(entry.file),
+			Format:   tar.FormatPAX,
+		})
+		if err != nil {
+			return err
+		}
+		if err := read(entry, tw); err != nil {
+			return fmt.Errorf("read file %d: %w", entry.file.GetId(), err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/waitform/micro-cloud-storage/internal/casbin"
+)
+
+// newArchiveTest 创建打包下载的路由，readable 中的文件授予 testUserID 读权限
+func newArchiveTest(t *testing.T, fake *fakeFileService, readable ...int64) http.Handler {
+	t.Helper()
+	h := newTestHandler(t, fake)
+	for _, id := range readable {
+		setFileOwner(testUserID, id)
+	}
+	r := newTestRouter()
+	r.POST("/archive", h.HandleDownloadArchive)
+	return r
+}
+
+func archiveRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/archive", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// readArchive 解出压缩包中的文件
+func readArchive(t *testing.T, format string, data []byte) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	if format == ArchiveFormatZip {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("zip.NewReader() error = %v", err)
+		}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatalf("open %s error = %v", f.Name, err)
+			}
+			content, _ := io.ReadAll(rc)
+			rc.Close()
+			files[f.Name] = string(content)
+		}
+		return files
+	}
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatalf("tar Next() error = %v", err)
+		}
+		content, _ := io.ReadAll(tr)
+		files[hdr.Name] = string(content)
+	}
+}
+
+func TestArchive_StreamsSelectionAndFolder(t *testing.T) {
+	for _, format := range []string{ArchiveFormatZip, ArchiveFormatTarGz} {
+		t.Run(format, func(t *testing.T) {
+			fake := newFakeFileService(4)
+			fake.addFile(1, 0, "a.txt", "alpha")
+			fake.addFolder(10, 0, "docs")
+			fake.addFile(2, 10, "b.txt", "beta")
+			fake.addFolder(11, 10, "old")
+			fake.addFile(3, 11, "b.txt", "gamma")
+			r := newArchiveTest(t, fake, 1, 2, 3)
+
+			w := serve(r, archiveRequest(fmt.Sprintf(`{"folder_id": 10, "file_ids": [1], "format": %q}`, format)))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+			}
+			got := readArchive(t, format, w.Body.Bytes())
+			want := map[string]string{"a.txt": "alpha", "b.txt": "beta", "old/b.txt": "gamma"}
+			if len(got) != len(want) {
+				t.Fatalf("archive = %v, want %v", got, want)
+			}
+			for name, content := range want {
+				if got[name] != content {
+					t.Errorf("%s = %q, want %q", name, got[name], content)
+				}
+			}
+		})
+	}
+}
+
+func TestArchive_RejectsUnreadableFile(t *testing.T) {
+	fake := newFakeFileService(4)
+	fake.addFile(1, 0, "mine.txt", "mine")
+	fake.addFile(2, 0, "other.txt", "other")
+	fake.addFolder(10, 0, "shared")
+	fake.addFile(3, 10, "secret.txt", "secret")
+	r := newArchiveTest(t, fake, 1)
+
+	for _, body := range []string{`{"file_ids": [1, 2]}`, `{"folder_id": 10}`} {
+		if w := serve(r, archiveRequest(body)); w.Code != http.StatusForbidden {
+			t.Errorf("%s status = %d, want 403", body, w.Code)
+		}
+	}
+	if w := serve(r, archiveRequest(`{"file_ids": [99]}`)); w.Code != http.StatusNotFound {
+		t.Errorf("missing file status = %d, want 404", w.Code)
+	}
+}
+
+func TestArchive_Limits(t *testing.T) {
+	t.Run("entries", func(t *testing.T) {
+		fake := newFakeFileService(4)
+		fake.addFolder(10, 0, "many")
+		for i := int64(1); i <= maxArchiveEntries+1; i++ {
+			fake.addFile(i, 10, "f"+strconv.FormatInt(i, 10), "x")
+		}
+		r := newArchiveTest(t, fake)
+		casbin.AddPolicy(strconv.FormatInt(testUserID, 10), "file:*", "read")
+
+		w := serve(r, archiveRequest(`{"folder_id": 10}`))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), strconv.Itoa(maxArchiveEntries)) {
+			t.Errorf("status = %d, body = %s, want 400 about the entry limit", w.Code, w.Body)
+		}
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		fake := newFakeFileService(4)
+		parent := int64(0)
+		for depth := int64(1); depth <= maxArchiveDepth+1; depth++ {
+			fake.addFolder(depth, parent, "d")
+			parent = depth
+		}
+		fake.addFile(1, parent, "deep.txt", "x")
+		r := newArchiveTest(t, fake, 1)
+
+		w := serve(r, archiveRequest(`{"folder_id": 1}`))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), strconv.Itoa(maxArchiveDepth)) {
+			t.Errorf("status = %d, body = %s, want 400 about the depth limit", w.Code, w.Body)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxChunkSize 单个分块的上限，与文件服务的 FastCDC 最大分块一致
+const maxChunkSize = 4 * 1024 * 1024
+
+// HandleInitChunkedUpload 处理分块上传初始化请求
+// 客户端按 FastCDC 切分文件后提交分块清单，响应中的 missing_chunks 为需要上传的分块
+func (h *FileHandler) HandleInitChunkedUpload(c *gin.Context) {
+	var req filepb.InitChunkedUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if userID, ok := getUserID(c); ok {
+		req.UserID = userID
+	}
+
+	resp, err := h.fileClient.InitChunkedUpload(context.Background(), &req)
+	if err != nil {
+		utils.Error("Failed to init chunked upload: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to init chunked upload")
+		return
+	}
+	if resp.File.GetStatus() == 1 {
+		grantFileOwner(c, resp.File.Id)
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Chunked upload initialized successfully", resp)
+}
+
+// HandleUploadChunk 处理上传分块请求，请求体为分块内容
+func (h *FileHandler) HandleUploadChunk(c *gin.Context) {
+	fileIDStr := c.GetHeader("X-File-Id")
+	chunkSha256 := c.GetHeader("X-Chunk-Sha256")
+	if fileIDStr == "" || chunkSha256 == "" {
+		pack.WriteError(c, http.StatusBadRequest, "Missing required headers: X-File-Id, X-Chunk-Sha256")
+		return
+	}
+	fileID, err := strconv.ParseInt(fileIDStr, 10, 64)
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid file ID")
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxChunkSize))
+	if err != nil {
+		pack.WriteError(c, http.StatusRequestEntityTooLarge, "Chunk too large")
+		return
+	}
+	if len(data) == 0 {
+		pack.WriteError(c, http.StatusBadRequest, "Empty chunk")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, err = h.fileClient.UploadChunk(ctx, &filepb.UploadChunkRequest{
+		FileId: fileID,
+		Sha256: chunkSha256,
+		Data:   data,
+	})
+	if err != nil {
+		utils.Error("Failed to upload chunk: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to upload chunk")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Chunk uploaded successfully", nil)
+}
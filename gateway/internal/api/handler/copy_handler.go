@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleCopyFile 处理复制文件请求，需要源文件的读权限，新文件归当前用户所有
+// 请求体: {"file_id": 1, "folder_id": 0, "name": "副本.txt"}
+func (h *FileHandler) HandleCopyFile(c *gin.Context) {
+	var req filepb.CopyFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	// 文件服务不校验源文件归属，由网关检查读权限
+	allowed, err := hasFilePermission(userID, req.FileId, "read")
+	if err != nil {
+		utils.Error("Failed to authorize file copy: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Error occurred when authorizing user")
+		return
+	}
+	if !allowed {
+		pack.WriteError(c, http.StatusForbidden, "You don't have permission to access this resource")
+		return
+	}
+
+	ctx := context.Background()
+	resp, err := h.fileClient.CopyFile(ctx, &req)
+	if err != nil {
+		utils.Error("Failed to copy file: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to copy file")
+		return
+	}
+	setFileOwner(userID, resp.GetFile().GetId())
+
+	pack.WriteJSON(c, http.StatusOK, "File copied successfully", resp.GetFile())
+}
+
+// HandleMoveFile 处理移动文件请求，只能在自己的目录间移动
+// 请求体: {"file_id": 1, "folder_id": 2, "name": ""}
+func (h *FileHandler) HandleMoveFile(c *gin.Context) {
+	var req filepb.MoveFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	// 文件服务会校验文件归属
+	req.UserId = userID
+
+	ctx := context.Background()
+	resp, err := h.fileClient.MoveFile(ctx, &req)
+	if err != nil {
+		utils.Error("Failed to move file: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to move file")
+		return
+	}
+	pack.WriteJSON(c, http.StatusOK, "File moved successfully", resp.GetFile())
+}
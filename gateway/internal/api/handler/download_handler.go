@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 下载方式，由 global.yaml 中的 download.mode 配置
+const (
+	DownloadModeRedirect = "redirect" // 重定向到 MinIO 预签名URL
+	DownloadModeProxy    = "proxy"    // 网关代理流式传输，支持 Range 断点续传
+
+	defaultDownloadContent = "application/octet-stream"
+)
+
+var (
+	errRangeInvalid       = errors.New("invalid range")
+	errRangeUnsatisfiable = errors.New("range not satisfiable")
+)
+
+// HandleDownloadFile 处理文件下载请求
+// 通过分享链接访问时只能下载被分享文件的当前版本；
+// 登录用户可通过 file_id 和可选的 version_id 下载自己的文件
+func (h *FileHandler) HandleDownloadFile(c *gin.Context) {
+	var (
+		fileID    int64
+		versionID int64
+		err       error
+	)
+	userID, isOwner := getUserID(c)
+
+	if fileIDVal, exists := c.Get("file_id"); exists {
+		// 来自分享鉴权中间件，忽略查询参数，防止借分享链接访问其他文件
+		fileID = fileIDVal.(int64)
+		isOwner = false
+	} else {
+		if !isOwner {
+			pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+			return
+		}
+		fileIDStr := c.Query("file_id")
+		if fileIDStr == "" {
+			pack.WriteError(c, http.StatusBadRequest, "Missing file_id parameter")
+			return
+		}
+		fileID, err = strconv.ParseInt(fileIDStr, 10, 64)
+		if err != nil {
+			pack.WriteError(c, http.StatusBadRequest, "Invalid file_id parameter")
+			return
+		}
+		if versionIDStr := c.Query("version_id"); versionIDStr != "" {
+			versionID, err = strconv.ParseInt(versionIDStr, 10, 64)
+			if err != nil {
+				pack.WriteError(c, http.StatusBadRequest, "Invalid version_id parameter")
+				return
+			}
+		}
+	}
+
+	ctx := context.Background()
+	infoResp, err := h.fileClient.GetFileInfo(ctx, &filepb.GetFileInfoRequest{FileId: fileID})
+	if err != nil {
+		utils.Error("Failed to get file info: %v", err)
+		pack.WriteError(c, http.StatusNotFound, "File not found")
+		return
+	}
+	file := infoResp.GetFile()
+	if isOwner && file.GetUserID() != userID {
+		pack.WriteError(c, http.StatusForbidden, "You don't have permission to access this file")
+		return
+	}
+
+	if h.downloadMode == DownloadModeProxy {
+		h.proxyDownload(c, file, userID, versionID)
+		return
+	}
+
+	// 生成预签名URL用于下载
+	req := &filepb.GeneratePresignedURLRequest{
+		FileId:        fileID,
+		ExpireSeconds: 3600, // 1小时过期时间
+		VersionId:     versionID,
+	}
+	resp, err := h.fileClient.GeneratePresignedURL(ctx, req)
+	if status.Code(err) == codes.FailedPrecondition {
+		// 加密、压缩、分块存储的文件或不支持预签名的存储后端无法重定向，改由网关代理
+		utils.Info("Presigned URL unsupported, falling back to proxy download: %v", err)
+		h.proxyDownload(c, file, userID, versionID)
+		return
+	}
+	if err != nil {
+		utils.Error("Failed to generate presigned URL: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to generate download URL")
+		return
+	}
+
+	// 重定向到预签名URL
+	c.Redirect(http.StatusFound, resp.GetUrl())
+}
+
+// proxyDownload 通过网关流式返回文件内容，支持 Range、If-Range、If-None-Match
+func (h *FileHandler) proxyDownload(c *gin.Context, file *filepb.FileInfo, userID, versionID int64) {
+	size := file.GetSize()
+	contentType := file.GetMimeType()
+	tag := file.GetSha256()
+	if tag == "" {
+		tag = file.GetMd5()
+	}
+	if versionID != 0 {
+		resp, err := h.fileClient.GetVersion(context.Background(), &filepb.GetVersionRequest{
+			UserId:    userID,
+			FileId:    file.GetId(),
+			VersionId: versionID,
+		})
+		if err != nil || resp.GetVersion().GetStatus() != 1 {
+			pack.WriteError(c, http.StatusNotFound, "Version not found")
+			return
+		}
+		size = resp.GetVersion().GetSize()
+		tag = resp.GetVersion().GetSha256()
+		if tag == "" {
+			tag = resp.GetVersion().GetMd5()
+		}
+		if mimeType := resp.GetVersion().GetMimeType(); mimeType != "" {
+			contentType = mimeType
+		}
+	}
+	if tag == "" {
+		tag = fmt.Sprintf("%d-%d", file.GetId(), file.GetVersionId())
+	}
+	etag := `"` + tag + `"`
+
+	if contentType == "" {
+		contentType = defaultDownloadContent
+	}
+	c.Header("ETag", etag)
+	c.Header("Accept-Ranges", "bytes")
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.GetName()}))
+
+	if match := c.GetHeader("If-None-Match"); match != "" && (match == "*" || strings.Contains(match, etag)) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	start, length, status := int64(0), size, http.StatusOK
+	if rangeHeader := c.GetHeader("Range"); rangeHeader != "" && ifRangeMatches(c.GetHeader("If-Range"), etag) {
+		s, l, err := parseRange(rangeHeader, size)
+		switch err {
+		case nil:
+			start, length, status = s, l, http.StatusPartialContent
+			c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, size))
+		case errRangeUnsatisfiable:
+			c.Header("Content-Range", fmt.Sprintf("bytes */%d", size))
+			c.Status(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		// 语法不合法或多段范围时忽略 Range，返回完整内容
+	}
+	c.Header("Content-Length", strconv.FormatInt(length, 10))
+	c.Status(status)
+	if length == 0 || c.Request.Method == http.MethodHead {
+		return
+	}
+
+	// 下载时间与文件大小有关，取消 HTTP 服务器的写超时
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	w := utils.NewFairRateLimitedWriter(c.Writer, transferLimiters(c)...)
+
+	req := &filepb.ReadFileRequest{
+		FileId:    file.GetId(),
+		VersionId: versionID,
+		Offset:    start,
+		Length:    length,
+	}
+	written, err := h.fileClient.ReadFile(c.Request.Context(), req, w)
+	if err != nil {
+		// 响应头已发出，只能中断连接，客户端可凭 ETag 续传
+		utils.Error("Failed to stream file %d after %d bytes: %v", file.GetId(), written, err)
+		c.Abort()
+	}
+}
+
+// transferLimiters 返回下载限流中间件写入上下文的全局和用户限速器
+func transferLimiters(c *gin.Context) []*rate.Limiter {
+	var limiters []*rate.Limiter
+	for _, key := range []string{"global_limiter", "user_limiter"} {
+		if v, exists := c.Get(key); exists {
+			if limiter, ok := v.(*rate.Limiter); ok {
+				limiters = append(limiters, limiter)
+			}
+		}
+	}
+	return limiters
+}
+
+// ifRangeMatches 没有 If-Range 或其值与当前 ETag 一致时才处理 Range
+// 只支持强 ETag 比较，日期形式视为不匹配
+func ifRangeMatches(ifRange, etag string) bool {
+	return ifRange == "" || ifRange == etag
+}
+
+// parseRange 解析单段的 Range 请求头，返回起始位置和长度
+func parseRange(header string, size int64) (int64, int64, error) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(header, prefix) {
+		return 0, 0, errRangeInvalid
+	}
+	spec := strings.TrimSpace(header[len(prefix):])
+	if strings.Contains(spec, ",") {
+		return 0, 0, errRangeInvalid
+	}
+	dash := strings.IndexByte(spec, '-')
+	if dash < 0 {
+		return 0, 0, errRangeInvalid
+	}
+	startStr := strings.TrimSpace(spec[:dash])
+	endStr := strings.TrimSpace(spec[dash+1:])
+
+	// 后缀范围 bytes=-n 表示最后 n 个字节
+	if startStr == "" {
+		n, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, errRangeInvalid
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, errRangeUnsatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, nil
+	}
+
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, errRangeInvalid
+	}
+	if start >= size {
+		return 0, 0, errRangeUnsatisfiable
+	}
+	end := size - 1
+	if endStr != "" {
+		e, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || e < start {
+			return 0, 0, errRangeInvalid
+		}
+		if e < end {
+			end = e
+		}
+	}
+	return start, end - start + 1, nil
+}
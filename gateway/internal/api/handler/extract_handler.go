@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleExtractArchive 处理解压请求，把自己的 zip、tar 或 tar.gz 文件解压到 folder_id 下，当前用户获得新文件的全部权限
+// 请求体: {"file_id": 1, "folder_id": 0}
+func (h *FileHandler) HandleExtractArchive(c *gin.Context) {
+	var req filepb.ExtractArchiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	// 文件服务会校验文件归属
+	req.UserId = userID
+
+	resp, err := h.fileClient.ExtractArchive(context.Background(), &req)
+	if err != nil {
+		utils.Error("Failed to extract archive: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to extract archive")
+		return
+	}
+	for _, file := range resp.GetFiles() {
+		setFileOwner(userID, file.GetId())
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Archive extracted successfully", resp)
+}
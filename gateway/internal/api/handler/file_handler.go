@@ -7,7 +7,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/waitform/micro-cloud-storage/internal/casbin"
 	pack "github.com/waitform/micro-cloud-storage/internal/pack"
 	"github.com/waitform/micro-cloud-storage/internal/rpc"
 	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
@@ -17,12 +16,17 @@ import (
 )
 
 type FileHandler struct {
-	fileClient *rpc.FileServiceClient
+	fileClient   *rpc.FileServiceClient
+	downloadMode string // DownloadModeRedirect 或 DownloadModeProxy
 }
 
-func NewFileHandler(fileClient *rpc.FileServiceClient) *FileHandler {
+func NewFileHandler(fileClient *rpc.FileServiceClient, downloadMode string) *FileHandler {
+	if downloadMode != DownloadModeProxy {
+		downloadMode = DownloadModeRedirect
+	}
 	return &FileHandler{
-		fileClient: fileClient,
+		fileClient:   fileClient,
+		downloadMode: downloadMode,
 	}
 }
 
@@ -33,6 +37,10 @@ func (h *FileHandler) HandleInitUpload(c *gin.Context) {
 		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	// 以登录用户为准，避免写入他人的目录
+	if userID, ok := getUserID(c); ok {
+		req.UserID = userID
+	}
 
 	ctx := context.Background()
 	resp, err := h.fileClient.InitUpload(ctx, &req)
@@ -41,6 +49,10 @@ func (h *FileHandler) HandleInitUpload(c *gin.Context) {
 		pack.WriteError(c, http.StatusInternalServerError, "Failed to init upload")
 		return
 	}
+	// 秒传时上传已经完成，不会再调用完成上传接口
+	if resp.File.GetStatus() == 1 {
+		grantFileOwner(c, resp.File.Id)
+	}
 
 	pack.WriteJSON(c, http.StatusOK, "Upload initialized successfully", resp)
 }
@@ -118,21 +130,7 @@ func (h *FileHandler) HandleCompleteUpload(c *gin.Context) {
 		return
 	}
 	//添加文件权限
-	userIDValue, exists := c.Get("user_id")
-	if exists {
-		if userID, ok := userIDValue.(string); ok {
-			// 为用户添加文件的所有权限
-			fileID := strconv.FormatInt(resp.File.Id, 10)
-			obj := "file:" + fileID
-
-			// 添加读权限
-			casbin.AddPolicy(userID, obj, "read")
-			// 添加写权限
-			casbin.AddPolicy(userID, obj, "write")
-			// 添加删除权限
-			casbin.AddPolicy(userID, obj, "delete")
-		}
-	}
+	grantFileOwner(c, resp.File.Id)
 
 	pack.WriteJSON(c, http.StatusOK, "Upload completed successfully", resp)
 }
@@ -363,56 +361,20 @@ func (h *FileHandler) HandleCancelUpload(c *gin.Context) {
 	pack.WriteJSON(c, http.StatusOK, "Upload cancelled successfully", nil)
 }
 
-// HandleDownloadFile 处理文件下载请求
-func (h *FileHandler) HandleDownloadFile(c *gin.Context) {
-	// 首先尝试从查询参数获取文件ID
-	fileIDStr := c.Query("file_id")
-
-	// 如果查询参数中没有file_id，则尝试从上下文中获取（来自分享鉴权中间件）
-	if fileIDStr == "" {
-		if fileIDVal, exists := c.Get("file_id"); exists {
-			// 从分享鉴权中间件中获取的file_id
-			fileIDStr = strconv.FormatInt(fileIDVal.(int64), 10)
-		}
-	}
-
-	// 如果仍然没有file_id，则返回错误
-	if fileIDStr == "" {
-		pack.WriteError(c, http.StatusBadRequest, "Missing file_id parameter")
-		return
-	}
-
-	fileID, err := strconv.ParseInt(fileIDStr, 10, 64)
-	if err != nil {
-		pack.WriteError(c, http.StatusBadRequest, "Invalid file_id parameter")
-		return
-	}
-
-	// 生成预签名URL用于下载
-	req := &filepb.GeneratePresignedURLRequest{
-		FileId:        fileID,
-		ExpireSeconds: 3600, // 1小时过期时间
-	}
-
-	ctx := context.Background()
-	resp, err := h.fileClient.GeneratePresignedURL(ctx, req)
-	if err != nil {
-		utils.Error("Failed to generate presigned URL: %v", err)
-		pack.WriteError(c, http.StatusInternalServerError, "Failed to generate download link")
-		return
-	}
-
-	// 重定向到预签名URL
-	c.Redirect(http.StatusFound, resp.GetUrl())
-}
-
-// HandleDeleteFile 处理删除文件请求
+// HandleDeleteFile 处理删除文件请求，文件会被移入回收站
 func (h *FileHandler) HandleDeleteFile(c *gin.Context) {
 	var req filepb.DeleteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	// 文件服务会校验文件归属
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
 
 	ctx := context.Background()
 	_, err := h.fileClient.DeleteFile(ctx, &req)
@@ -422,5 +384,5 @@ func (h *FileHandler) HandleDeleteFile(c *gin.Context) {
 		return
 	}
 
-	pack.WriteJSON(c, http.StatusOK, "File deleted successfully", nil)
+	pack.WriteJSON(c, http.StatusOK, "File moved to trash successfully", nil)
 }
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleCreateFolder 处理创建文件夹请求
+func (h *FileHandler) HandleCreateFolder(c *gin.Context) {
+	var req filepb.CreateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	ctx := context.Background()
+	resp, err := h.fileClient.CreateFolder(ctx, &req)
+	if err != nil {
+		utils.Error("Failed to create folder: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to create folder")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Folder created successfully", resp.GetFolder())
+}
+
+// HandleRenameFolder 处理重命名文件夹请求
+func (h *FileHandler) HandleRenameFolder(c *gin.Context) {
+	var req filepb.RenameFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	ctx := context.Background()
+	resp, err := h.fileClient.RenameFolder(ctx, &req)
+	if err != nil {
+		utils.Error("Failed to rename folder: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to rename folder")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Folder renamed successfully", resp.GetFolder())
+}
+
+// HandleMoveFolder 处理移动文件夹请求
+func (h *FileHandler) HandleMoveFolder(c *gin.Context) {
+	var req filepb.MoveFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	ctx := context.Background()
+	resp, err := h.fileClient.MoveFolder(ctx, &req)
+	if err != nil {
+		utils.Error("Failed to move folder: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to move folder")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Folder moved successfully", resp.GetFolder())
+}
+
+// HandleDeleteFolder 处理删除文件夹请求
+func (h *FileHandler) HandleDeleteFolder(c *gin.Context) {
+	var req filepb.DeleteFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	ctx := context.Background()
+	_, err := h.fileClient.DeleteFolder(ctx, &req)
+	if err != nil {
+		utils.Error("Failed to delete folder: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to delete folder")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Folder deleted successfully", nil)
+}
+
+// HandleListDirectory 处理列出目录内容请求
+// 查询参数: folder_id, page, page_size, sort_by(name|size|created_at), order(asc|desc)
+func (h *FileHandler) HandleListDirectory(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	folderID, err := strconv.ParseInt(c.DefaultQuery("folder_id", "0"), 10, 64)
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid folder_id parameter")
+		return
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid page parameter")
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid page_size parameter")
+		return
+	}
+
+	req := &filepb.ListDirectoryRequest{
+		UserId:   userID,
+		FolderId: folderID,
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+		SortBy:   c.Query("sort_by"),
+		Order:    c.Query("order"),
+	}
+
+	ctx := context.Background()
+	resp, err := h.fileClient.ListDirectory(ctx, req)
+	if err != nil {
+		utils.Error("Failed to list directory: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to list directory")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Directory listed successfully", resp)
+}
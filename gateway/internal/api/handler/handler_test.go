@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	casbinlib "github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/gin-gonic/gin"
+	"github.com/waitform/micro-cloud-storage/internal/casbin"
+	"github.com/waitform/micro-cloud-storage/internal/rpc"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const testUserID int64 = 1
+
+// fakeFileService 内存中的文件服务，只实现网关测试用到的接口
+type fakeFileService struct {
+	filepb.UnimplementedFileServiceServer
+
+	mu       sync.Mutex
+	partSize int64
+	nextID   int64
+	uploads  map[int64]*fakeUpload
+
+	// 已完成的文件和目录树，folders 和 folderFiles 按父文件夹ID索引
+	files       map[int64]*filepb.FileInfo
+	contents    map[int64][]byte
+	folders     map[int64][]*filepb.FolderInfo
+	folderFiles map[int64][]*filepb.FileInfo
+
+	// uploadHook 不为 nil 时在保存每个分片前调用
+	uploadHook func(fileID, partNumber int64)
+}
+
+// fakeUpload 进行中或已完成的上传
+type fakeUpload struct {
+	userID    int64
+	name      string
+	size      int64
+	parts     map[int64][]byte
+	completed bool
+}
+
+func newFakeFileService(partSize int64) *fakeFileService {
+	return &fakeFileService{
+		partSize:    partSize,
+		uploads:     make(map[int64]*fakeUpload),
+		files:       make(map[int64]*filepb.FileInfo),
+		contents:    make(map[int64][]byte),
+		folders:     make(map[int64][]*filepb.FolderInfo),
+		folderFiles: make(map[int64][]*filepb.FileInfo),
+	}
+}
+
+// addFile 在 folderID 下添加一个已完成的文件
+func (f *fakeFileService) addFile(id, folderID int64, name, content string) *filepb.FileInfo {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file := &filepb.FileInfo{Id: id, Name: name, Size: int64(len(content)), UserID: testUserID, Status: 1, FolderId: folderID}
+	f.files[id] = file
+	f.contents[id] = []byte(content)
+	f.folderFiles[folderID] = append(f.folderFiles[folderID], file)
+	return file
+}
+
+// addFolder 在 parentID 下添加一个文件夹
+func (f *fakeFileService) addFolder(id, parentID int64, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.folders[parentID] = append(f.folders[parentID], &filepb.FolderInfo{Id: id, Name: name, ParentId: parentID, UserId: testUserID})
+}
+
+func (f *fakeFileService) GetFileInfo(ctx context.Context, req *filepb.GetFileInfoRequest) (*filepb.GetFileInfoResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, ok := f.files[req.FileId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "file not found")
+	}
+	return &filepb.GetFileInfoResponse{File: file}, nil
+}
+
+func (f *fakeFileService) ListDirectory(ctx context.Context, req *filepb.ListDirectoryRequest) (*filepb.ListDirectoryResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	folders, files := f.folders[req.FolderId], f.folderFiles[req.FolderId]
+	total := len(folders) + len(files)
+	start := min(int(req.Page-1)*int(req.PageSize), total)
+	end := min(start+int(req.PageSize), total)
+	resp := &filepb.ListDirectoryResponse{Total: int64(total)}
+	for i := start; i < end; i++ {
+		if i < len(folders) {
+			resp.Folders = append(resp.Folders, folders[i])
+		} else {
+			resp.Files = append(resp.Files, files[i-len(folders)])
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeFileService) ReadFile(req *filepb.ReadFileRequest, stream grpc.ServerStreamingServer[filepb.ReadFileResponse]) error {
+	f.mu.Lock()
+	content, ok := f.contents[req.FileId]
+	f.mu.Unlock()
+	if !ok {
+		return status.Error(codes.NotFound, "file not found")
+	}
+	return stream.Send(&filepb.ReadFileResponse{Data: content})
+}
+
+// offset 从第1个分片起连续保存的字节数
+func (u *fakeUpload) offset() int64 {
+	var offset int64
+	for n := int64(1); ; n++ {
+		part, ok := u.parts[n]
+		if !ok {
+			return offset
+		}
+		offset += int64(len(part))
+	}
+}
+
+func (f *fakeFileService) upload(fileID int64) *fakeUpload {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.uploads[fileID]
+}
+
+func (f *fakeFileService) InitUpload(ctx context.Context, req *filepb.InitUploadRequest) (*filepb.InitUploadResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	f.uploads[f.nextID] = &fakeUpload{userID: req.UserID, name: req.FileName, size: req.Size, parts: make(map[int64][]byte)}
+	return &filepb.InitUploadResponse{File: &filepb.FileInfo{Id: f.nextID, Name: req.FileName, Size: req.Size, UserID: req.UserID}}, nil
+}
+
+func (f *fakeFileService) GetUploadOffset(ctx context.Context, req *filepb.GetUploadOffsetRequest) (*filepb.GetUploadOffsetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.uploads[req.FileId]
+	if !ok || (req.UserId != 0 && req.UserId != u.userID) {
+		return nil, status.Error(codes.NotFound, "upload not found")
+	}
+	return &filepb.GetUploadOffsetResponse{
+		Offset:    u.offset(),
+		TotalSize: u.size,
+		PartSize:  f.partSize,
+		Completed: u.completed,
+	}, nil
+}
+
+func (f *fakeFileService) UploadPart(stream grpc.ClientStreamingServer[filepb.UploadPartRequest, emptypb.Empty]) error {
+	var meta *filepb.PartMetadata
+	var data []byte
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if m := req.GetPartMetadata(); m != nil {
+			meta = m
+		} else {
+			data = append(data, req.GetPartContent().GetData()...)
+		}
+	}
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "missing part metadata")
+	}
+	sum := md5.Sum(data)
+	if meta.Md5 != "" && meta.Md5 != hex.EncodeToString(sum[:]) {
+		return status.Error(codes.InvalidArgument, "md5 mismatch")
+	}
+	if f.uploadHook != nil {
+		f.uploadHook(meta.FileId, meta.PartNumber)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.uploads[meta.FileId]
+	if !ok || u.completed {
+		return status.Error(codes.NotFound, "upload not found")
+	}
+	u.parts[meta.PartNumber] = data
+	return stream.SendAndClose(&emptypb.Empty{})
+}
+
+func (f *fakeFileService) CompleteUpload(ctx context.Context, req *filepb.CompleteUploadRequest) (*filepb.CompleteUploadResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.uploads[req.FileId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "upload not found")
+	}
+	if u.offset() != u.size {
+		return nil, status.Error(codes.FailedPrecondition, "upload incomplete")
+	}
+	u.completed = true
+	return &filepb.CompleteUploadResponse{File: &filepb.FileInfo{Id: req.FileId, Name: u.name, Size: u.size, Status: 1}}, nil
+}
+
+func (f *fakeFileService) CancelUpload(ctx context.Context, req *filepb.CancelUploadRequest) (*emptypb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.uploads[req.FileId]; !ok {
+		return nil, status.Error(codes.NotFound, "upload not found")
+	}
+	delete(f.uploads, req.FileId)
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeFileService) ListUploadedParts(ctx context.Context, req *filepb.ListUploadedPartsRequest) (*filepb.ListUploadedPartsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.uploads[req.FileId]
+	if !ok || u.completed {
+		return nil, status.Error(codes.NotFound, "upload not found")
+	}
+	resp := &filepb.ListUploadedPartsResponse{}
+	for _, n := range u.partNumbers() {
+		sum := md5.Sum(u.parts[n])
+		resp.Parts = append(resp.Parts, &filepb.UploadedPart{PartNumber: int32(n), Size: int64(len(u.parts[n])), Md5: hex.EncodeToString(sum[:])})
+	}
+	return resp, nil
+}
+
+// partNumbers 已保存的分片编号，升序
+func (u *fakeUpload) partNumbers() []int64 {
+	numbers := make([]int64, 0, len(u.parts))
+	for n := range u.parts {
+		numbers = append(numbers, n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	return numbers
+}
+
+// content 按分片编号顺序拼接的上传内容
+func (u *fakeUpload) content() []byte {
+	var data []byte
+	for _, n := range u.partNumbers() {
+		data = append(data, u.parts[n]...)
+	}
+	return data
+}
+
+// newTestHandler 通过内存连接把 FileHandler 接到 fake 文件服务，并使用内存中的 Casbin 策略
+func newTestHandler(t *testing.T, fake filepb.FileServiceServer) *FileHandler {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	filepb.RegisterFileServiceServer(server, fake)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	m, err := model.NewModelFromFile("../../../config/rbac_model.conf")
+	if err != nil {
+		t.Fatalf("load casbin model error = %v", err)
+	}
+	enforcer, err := casbinlib.NewEnforcer(m)
+	if err != nil {
+		t.Fatalf("NewEnforcer() error = %v", err)
+	}
+	casbin.SetEnforcer(enforcer)
+
+	return NewFileHandler(rpc.NewFileServiceClientWithConn(conn), DownloadModeProxy)
+}
+
+// newTestRouter 创建以 testUserID 身份访问的路由
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("user_id", testUserID) })
+	return r
+}
+
+// serve 发送请求并返回响应
+func serve(r http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
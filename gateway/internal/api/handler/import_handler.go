@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleImportFromURL 处理从URL导入请求，返回上传中的文件
+// 进度通过 /api/file/upload/progress 查询，/api/file/upload/cancel 可中止导入
+// 请求体: {"url": "https://example.com/data.csv", "folder_id": 0, "name": "", "tags": {"source": "web"}}
+func (h *FileHandler) HandleImportFromURL(c *gin.Context) {
+	var req filepb.ImportFromURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Url == "" {
+		pack.WriteError(c, http.StatusBadRequest, "Missing url")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	resp, err := h.fileClient.ImportFromURL(context.Background(), &req)
+	if err != nil {
+		utils.Error("Failed to import from URL: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to import from URL")
+		return
+	}
+	grantFileOwner(c, resp.GetFile().GetId())
+
+	pack.WriteJSON(c, http.StatusOK, "Import started successfully", resp.GetFile())
+}
@@ -0,0 +1,478 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// S3 兼容接口，路径形式为 /s3/<bucket>/<key>，使用访问密钥的 SigV4 签名鉴权
+// 桶对应用户根目录下的文件夹，对象键按 / 拆分为桶内的子文件夹和文件名；子文件夹在写入时自动创建，
+// 列举时以 / 为分隔符的子文件夹作为 CommonPrefixes 返回。对象的 ETag 为内容的 MD5
+const (
+	s3Namespace       = "http://s3.amazonaws.com/doc/2006-03-01/"
+	s3TimeFormat      = "2006-01-02T15:04:05.000Z"
+	s3DefaultMaxKeys  = 1000
+	s3MaxListEntries  = 100000 // 单次列举最多遍历的对象数，超出部分不返回
+	s3ListPageSize    = 200
+	s3MaxXMLBodySize  = 1 << 20
+	s3MaxPartNumber   = 10000
+	s3PartTimeout     = 30 * time.Minute
+	s3EmptyObjectETag = `"d41d8cd98f00b204e9800998ecf8427e"`
+)
+
+type s3Owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+type s3Bucket struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+type listAllMyBucketsResult struct {
+	XMLName xml.Name   `xml:"ListAllMyBucketsResult"`
+	Xmlns   string     `xml:"xmlns,attr"`
+	Owner   s3Owner    `xml:"Owner"`
+	Buckets []s3Bucket `xml:"Buckets>Bucket"`
+}
+
+type s3Object struct {
+	Key          string   `xml:"Key"`
+	LastModified string   `xml:"LastModified"`
+	ETag         string   `xml:"ETag"`
+	Size         int64    `xml:"Size"`
+	StorageClass string   `xml:"StorageClass"`
+	Owner        *s3Owner `xml:"Owner,omitempty"`
+}
+
+type s3CommonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// listBucketResult ListObjects 和 ListObjectsV2 共用的响应，版本特有的字段为空时省略
+type listBucketResult struct {
+	XMLName               xml.Name         `xml:"ListBucketResult"`
+	Xmlns                 string           `xml:"xmlns,attr"`
+	Name                  string           `xml:"Name"`
+	Prefix                string           `xml:"Prefix"`
+	Marker                string           `xml:"Marker,omitempty"`
+	NextMarker            string           `xml:"NextMarker,omitempty"`
+	StartAfter            string           `xml:"StartAfter,omitempty"`
+	ContinuationToken     string           `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string           `xml:"NextContinuationToken,omitempty"`
+	KeyCount              *int             `xml:"KeyCount,omitempty"`
+	MaxKeys               int              `xml:"MaxKeys"`
+	Delimiter             string           `xml:"Delimiter,omitempty"`
+	EncodingType          string           `xml:"EncodingType,omitempty"`
+	IsTruncated           bool             `xml:"IsTruncated"`
+	Contents              []s3Object       `xml:"Contents"`
+	CommonPrefixes        []s3CommonPrefix `xml:"CommonPrefixes"`
+}
+
+type locationConstraint struct {
+	XMLName xml.Name `xml:"LocationConstraint"`
+	Xmlns   string   `xml:"xmlns,attr"`
+}
+
+// s3Entry 列举时的一项，file 为空表示公共前缀
+type s3Entry struct {
+	key  string
+	file *filepb.FileInfo
+}
+
+// HandleS3 S3 兼容接口入口，按请求方法和子资源分发到各个操作
+func (h *FileHandler) HandleS3(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteS3Error(c, http.StatusForbidden, "AccessDenied", "Access Denied")
+		return
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(c.Param("path"), "/"), "/")
+	query := c.Request.URL.Query()
+	method := c.Request.Method
+
+	switch {
+	case bucket == "" && method == http.MethodGet:
+		h.s3ListBuckets(c, userID)
+	case bucket == "":
+		pack.WriteS3Error(c, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource")
+	case key == "":
+		h.s3HandleBucket(c, userID, bucket, query)
+	default:
+		h.s3HandleObject(c, userID, bucket, key, query)
+	}
+}
+
+func (h *FileHandler) s3HandleBucket(c *gin.Context, userID int64, bucket string, query url.Values) {
+	switch c.Request.Method {
+	case http.MethodGet:
+		switch {
+		case query.Has("location"):
+			if _, ok := h.s3Bucket(c, userID, bucket); ok {
+				pack.WriteS3XML(c, http.StatusOK, locationConstraint{Xmlns: s3Namespace})
+			}
+		case query.Has("uploads"), query.Has("acl"), query.Has("policy"), query.Has("versioning"), query.Has("versions"), query.Has("tagging"):
+			s3NotImplemented(c)
+		default:
+			h.s3ListObjects(c, userID, bucket, query)
+		}
+	case http.MethodHead:
+		if _, ok := h.s3Bucket(c, userID, bucket); ok {
+			c.Status(http.StatusOK)
+		}
+	case http.MethodPut:
+		h.s3CreateBucket(c, userID, bucket)
+	default:
+		s3NotImplemented(c)
+	}
+}
+
+func (h *FileHandler) s3HandleObject(c *gin.Context, userID int64, bucket, key string, query url.Values) {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead:
+		h.s3GetObject(c, userID, bucket, key)
+	case http.MethodPut:
+		switch {
+		case query.Has("uploadId") && query.Has("partNumber"):
+			h.s3UploadPart(c, userID, query)
+		case c.GetHeader("X-Amz-Copy-Source") != "", len(query) > 0:
+			s3NotImplemented(c)
+		default:
+			h.s3PutObject(c, userID, bucket, key)
+		}
+	case http.MethodPost:
+		switch {
+		case query.Has("uploads"):
+			h.s3CreateMultipartUpload(c, userID, bucket, key)
+		case query.Has("uploadId"):
+			h.s3CompleteMultipartUpload(c, userID, bucket, key, query.Get("uploadId"))
+		default:
+			s3NotImplemented(c)
+		}
+	case http.MethodDelete:
+		if query.Has("uploadId") {
+			h.s3AbortMultipartUpload(c, userID, query.Get("uploadId"))
+		} else {
+			h.s3DeleteObject(c, userID, bucket, key)
+		}
+	default:
+		s3NotImplemented(c)
+	}
+}
+
+// s3ListBuckets 列出用户根目录下的文件夹
+func (h *FileHandler) s3ListBuckets(c *gin.Context, userID int64) {
+	result := listAllMyBucketsResult{
+		Xmlns:   s3Namespace,
+		Owner:   s3Owner{ID: strconv.FormatInt(userID, 10)},
+		Buckets: []s3Bucket{},
+	}
+	err := h.s3ListFolder(context.Background(), userID, 0, func(folders []*filepb.FolderInfo, _ []*filepb.FileInfo) bool {
+		for _, f := range folders {
+			result.Buckets = append(result.Buckets, s3Bucket{Name: f.GetName(), CreationDate: s3Time(f.GetCreatedAt())})
+		}
+		return true
+	})
+	if err != nil {
+		utils.Error("Failed to list buckets: %v", err)
+		s3InternalError(c)
+		return
+	}
+	pack.WriteS3XML(c, http.StatusOK, result)
+}
+
+// s3CreateBucket 在根目录下创建文件夹，已存在时直接返回成功
+func (h *FileHandler) s3CreateBucket(c *gin.Context, userID int64, bucket string) {
+	_, err := h.fileClient.LookupPath(context.Background(), &filepb.LookupPathRequest{
+		UserId:  userID,
+		Folders: []string{bucket},
+		Create:  true,
+	})
+	if err != nil {
+		pack.WriteS3Error(c, http.StatusBadRequest, "InvalidBucketName", "The specified bucket is not valid")
+		return
+	}
+	c.Header("Location", "/"+bucket)
+	c.Status(http.StatusOK)
+}
+
+// s3ListObjects 处理 ListObjects 和 ListObjectsV2（list-type=2）
+// 分隔符为 / 时只列出前缀所在的文件夹，其他分隔符或不指定时遍历前缀所在文件夹下的全部对象
+func (h *FileHandler) s3ListObjects(c *gin.Context, userID int64, bucket string, query url.Values) {
+	v2 := query.Get("list-type") == "2"
+	prefix := query.Get("prefix")
+	delimiter := query.Get("delimiter")
+	encodingType := query.Get("encoding-type")
+	if encodingType != "" && encodingType != "url" {
+		pack.WriteS3Error(c, http.StatusBadRequest, "InvalidArgument", "Invalid Encoding Method specified in Request")
+		return
+	}
+	maxKeys := s3DefaultMaxKeys
+	if v := query.Get("max-keys"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			pack.WriteS3Error(c, http.StatusBadRequest, "InvalidArgument", "Provided max-keys not an integer or within integer range")
+			return
+		}
+		maxKeys = min(n, s3DefaultMaxKeys)
+	}
+
+	result := listBucketResult{
+		Xmlns:        s3Namespace,
+		Name:         bucket,
+		Prefix:       prefix,
+		MaxKeys:      maxKeys,
+		Delimiter:    delimiter,
+		EncodingType: encodingType,
+	}
+	marker := query.Get("marker")
+	if v2 {
+		result.StartAfter = query.Get("start-after")
+		marker = result.StartAfter
+		if token := query.Get("continuation-token"); token != "" {
+			decoded, err := base64.RawURLEncoding.DecodeString(token)
+			if err != nil {
+				pack.WriteS3Error(c, http.StatusBadRequest, "InvalidArgument", "The continuation token provided is incorrect")
+				return
+			}
+			result.ContinuationToken = token
+			marker = string(decoded)
+		}
+	} else {
+		result.Marker = marker
+	}
+
+	bucketID, ok := h.s3Bucket(c, userID, bucket)
+	if !ok {
+		return
+	}
+	entries, err := h.s3CollectEntries(userID, bucketID, prefix, delimiter)
+	if err != nil {
+		utils.Error("Failed to list objects in bucket %s: %v", bucket, err)
+		s3InternalError(c)
+		return
+	}
+
+	var owner *s3Owner
+	if !v2 || query.Get("fetch-owner") == "true" {
+		owner = &s3Owner{ID: strconv.FormatInt(userID, 10)}
+	}
+	start := sort.Search(len(entries), func(i int) bool { return entries[i].key > marker })
+	entries = entries[start:]
+	if len(entries) > maxKeys {
+		result.IsTruncated = true
+		entries = entries[:maxKeys]
+	}
+	for _, e := range entries {
+		key := e.key
+		if encodingType == "url" {
+			key = url.QueryEscape(key)
+		}
+		if e.file == nil {
+			result.CommonPrefixes = append(result.CommonPrefixes, s3CommonPrefix{Prefix: key})
+			continue
+		}
+		result.Contents = append(result.Contents, s3Object{
+			Key:          key,
+			LastModified: s3Time(s3FileTime(e.file)),
+			ETag:         s3ETag(e.file),
+			Size:         e.file.GetSize(),
+			StorageClass: "STANDARD",
+			Owner:        owner,
+		})
+	}
+	if result.IsTruncated {
+		last := entries[len(entries)-1].key
+		if v2 {
+			result.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(last))
+		} else {
+			result.NextMarker = last
+		}
+	}
+	if v2 {
+		keyCount := len(entries)
+		result.KeyCount = &keyCount
+	}
+	if encodingType == "url" {
+		result.Prefix = url.QueryEscape(result.Prefix)
+		result.Delimiter = url.QueryEscape(result.Delimiter)
+		result.StartAfter = url.QueryEscape(result.StartAfter)
+		result.Marker = url.QueryEscape(result.Marker)
+		result.NextMarker = url.QueryEscape(result.NextMarker)
+	}
+	pack.WriteS3XML(c, http.StatusOK, result)
+}
+
+// s3CollectEntries 收集桶内以 prefix 开头的对象和公共前缀，按键排序
+func (h *FileHandler) s3CollectEntries(userID, bucketID int64, prefix, delimiter string) ([]s3Entry, error) {
+	// 从前缀中最后一个 / 之前的文件夹开始，不存在时结果为空
+	dir := prefix[:strings.LastIndex(prefix, "/")+1]
+	folderID := bucketID
+	if dir != "" {
+		folders := strings.Split(strings.TrimSuffix(dir, "/"), "/")
+		resp, err := h.fileClient.LookupPath(context.Background(), &filepb.LookupPathRequest{
+			UserId:   userID,
+			FolderId: bucketID,
+			Folders:  folders,
+		})
+		if err != nil || !resp.GetFound() {
+			return nil, nil
+		}
+		folderID = resp.GetFolderId()
+	}
+
+	var entries []s3Entry
+	if err := h.s3Walk(userID, folderID, dir, prefix, delimiter != "/", &entries); err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+	if delimiter == "" || delimiter == "/" {
+		return entries, nil
+	}
+
+	// 其他分隔符：前缀之后包含分隔符的键合并为公共前缀，排序后同一前缀的键相邻
+	grouped := entries[:0]
+	for _, e := range entries {
+		if i := strings.Index(e.key[len(prefix):], delimiter); i >= 0 {
+			common := e.key[:len(prefix)+i+len(delimiter)]
+			if n := len(grouped); n > 0 && grouped[n-1].file == nil && grouped[n-1].key == common {
+				continue
+			}
+			grouped = append(grouped, s3Entry{key: common})
+			continue
+		}
+		grouped = append(grouped, e)
+	}
+	return grouped, nil
+}
+
+// s3Walk 把文件夹下以 prefix 开头的文件加入 entries，recursive 为 false 时子文件夹作为公共前缀
+func (h *FileHandler) s3Walk(userID, folderID int64, base, prefix string, recursive bool, entries *[]s3Entry) error {
+	var subFolders []*filepb.FolderInfo
+	err := h.s3ListFolder(context.Background(), userID, folderID, func(folders []*filepb.FolderInfo, files []*filepb.FileInfo) bool {
+		for _, f := range files {
+			key := base + f.GetName()
+			if f.GetStatus() == 1 && strings.HasPrefix(key, prefix) {
+				*entries = append(*entries, s3Entry{key: key, file: f})
+			}
+		}
+		for _, f := range folders {
+			key := base + f.GetName() + "/"
+			switch {
+			case !recursive && strings.HasPrefix(key, prefix):
+				*entries = append(*entries, s3Entry{key: key})
+			case recursive && (strings.HasPrefix(key, prefix) || strings.HasPrefix(prefix, key)):
+				subFolders = append(subFolders, f)
+			}
+		}
+		return len(*entries) < s3MaxListEntries
+	})
+	if err != nil {
+		return err
+	}
+	for _, f := range subFolders {
+		if len(*entries) >= s3MaxListEntries {
+			utils.Warn("S3 listing of user %d stopped after %d entries", userID, s3MaxListEntries)
+			return nil
+		}
+		if err := h.s3Walk(userID, f.GetId(), base+f.GetName()+"/", prefix, true, entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// s3ListFolder 分页列出文件夹内容，fn 返回 false 时停止
+func (h *FileHandler) s3ListFolder(ctx context.Context, userID, folderID int64, fn func([]*filepb.FolderInfo, []*filepb.FileInfo) bool) error {
+	var seen int64
+	for page := int32(1); ; page++ {
+		resp, err := h.fileClient.ListDirectory(ctx, &filepb.ListDirectoryRequest{
+			UserId:   userID,
+			FolderId: folderID,
+			Page:     page,
+			PageSize: s3ListPageSize,
+		})
+		if err != nil {
+			return err
+		}
+		n := int64(len(resp.GetFolders()) + len(resp.GetFiles()))
+		seen += n
+		if !fn(resp.GetFolders(), resp.GetFiles()) || n == 0 || seen >= resp.GetTotal() {
+			return nil
+		}
+	}
+}
+
+// s3Bucket 返回桶对应的文件夹ID，不存在时写入 NoSuchBucket
+func (h *FileHandler) s3Bucket(c *gin.Context, userID int64, bucket string) (int64, bool) {
+	resp, err := h.fileClient.LookupPath(context.Background(), &filepb.LookupPathRequest{
+		UserId:  userID,
+		Folders: []string{bucket},
+	})
+	if err != nil || !resp.GetFound() {
+		pack.WriteS3Error(c, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return 0, false
+	}
+	return resp.GetFolderId(), true
+}
+
+// splitS3Key 把对象键拆分为文件夹路径和文件名，以 / 结尾的键文件名为空；空的路径段和 . 或 .. 不合法
+func splitS3Key(key string) ([]string, string, bool) {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		if (s == "" && i != len(segments)-1) || s == "." || s == ".." {
+			return nil, "", false
+		}
+	}
+	return segments[:len(segments)-1], segments[len(segments)-1], true
+}
+
+// s3ETag 对象的 ETag 为带引号的内容 MD5
+func s3ETag(file *filepb.FileInfo) string {
+	return `"` + file.GetMd5() + `"`
+}
+
+// s3FileTime 对象的修改时间，取文件更新时间
+func s3FileTime(file *filepb.FileInfo) int64 {
+	if file.GetUpdatedAt() != 0 {
+		return file.GetUpdatedAt()
+	}
+	return file.GetCreatedAt()
+}
+
+func s3Time(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(s3TimeFormat)
+}
+
+func s3NotImplemented(c *gin.Context) {
+	pack.WriteS3Error(c, http.StatusNotImplemented, "NotImplemented", "A header or query you provided implies functionality that is not implemented")
+}
+
+func s3InternalError(c *gin.Context) {
+	pack.WriteS3Error(c, http.StatusInternalServerError, "InternalError", "We encountered an internal error. Please try again.")
+}
+
+// s3BodyError 读取请求体失败时按签名校验错误或内容不完整响应
+func s3BodyError(c *gin.Context, err error) {
+	var sigErr *utils.SigV4Error
+	if errors.As(err, &sigErr) {
+		pack.WriteS3Error(c, sigErr.Status, sigErr.Code, sigErr.Message)
+		return
+	}
+	pack.WriteS3Error(c, http.StatusBadRequest, "IncompleteBody", "You did not provide the number of bytes specified by the Content-Length HTTP header")
+}
@@ -0,0 +1,483 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name `xml:"CompleteMultipartUpload"`
+	Parts   []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+}
+
+var (
+	errS3BadDigest      = errors.New("bad digest")
+	errS3IncompleteBody = errors.New("incomplete body")
+)
+
+// s3Body 记录读取请求体时的错误，用于区分签名校验失败和存储失败
+type s3Body struct {
+	r   io.Reader
+	err error
+}
+
+func (b *s3Body) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
+}
+
+// s3GetObject 处理 GetObject 和 HeadObject，支持单段 Range 和 If-Match、If-None-Match
+func (h *FileHandler) s3GetObject(c *gin.Context, userID int64, bucket, key string) {
+	file, ok := h.s3Object(c, userID, bucket, key)
+	if !ok {
+		return
+	}
+	etag := s3ETag(file)
+	contentType := file.GetMimeType()
+	if contentType == "" {
+		contentType = defaultDownloadContent
+	}
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", time.Unix(s3FileTime(file), 0).UTC().Format(http.TimeFormat))
+	c.Header("Content-Type", contentType)
+	c.Header("Accept-Ranges", "bytes")
+
+	if match := c.GetHeader("If-Match"); match != "" && match != "*" && !strings.Contains(match, etag) {
+		pack.WriteS3Error(c, http.StatusPreconditionFailed, "PreconditionFailed", "At least one of the pre-conditions you specified did not hold")
+		return
+	}
+	if match := c.GetHeader("If-None-Match"); match != "" && (match == "*" || strings.Contains(match, etag)) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	size := file.GetSize()
+	start, length, status := int64(0), size, http.StatusOK
+	if rangeHeader := c.GetHeader("Range"); rangeHeader != "" {
+		s, l, err := parseRange(rangeHeader, size)
+		switch err {
+		case nil:
+			start, length, status = s, l, http.StatusPartialContent
+			c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, size))
+		case errRangeUnsatisfiable:
+			c.Header("Content-Range", fmt.Sprintf("bytes */%d", size))
+			pack.WriteS3Error(c, http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "The requested range is not satisfiable")
+			return
+		}
+	}
+	c.Header("Content-Length", strconv.FormatInt(length, 10))
+	c.Status(status)
+	if length == 0 || c.Request.Method == http.MethodHead {
+		return
+	}
+
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	w := utils.NewFairRateLimitedWriter(c.Writer, transferLimiters(c)...)
+	req := &filepb.ReadFileRequest{
+		FileId:    file.GetId(),
+		VersionId: file.GetVersionId(),
+		Offset:    start,
+		Length:    length,
+	}
+	written, err := h.fileClient.ReadFile(c.Request.Context(), req, w)
+	if err != nil {
+		utils.Error("Failed to stream S3 object %d after %d bytes: %v", file.GetId(), written, err)
+		c.Abort()
+	}
+}
+
+// s3PutObject 上传对象，内容按分片大小写入文件服务；以 / 结尾的键只创建文件夹
+func (h *FileHandler) s3PutObject(c *gin.Context, userID int64, bucket, key string) {
+	folderID, name, ok := h.s3ObjectFolder(c, userID, bucket, key)
+	if !ok {
+		return
+	}
+	if name == "" {
+		c.Header("ETag", s3EmptyObjectETag)
+		c.Status(http.StatusOK)
+		return
+	}
+	size := c.Request.ContentLength
+	if size < 0 {
+		pack.WriteS3Error(c, http.StatusLengthRequired, "MissingContentLength", "You must provide the Content-Length HTTP header")
+		return
+	}
+	contentMD5, ok := s3ContentMD5(c)
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	initResp, err := h.fileClient.InitUpload(ctx, &filepb.InitUploadRequest{
+		FileName: name,
+		Size:     size,
+		UserID:   userID,
+		FolderId: folderID,
+	})
+	if err != nil {
+		utils.Error("Failed to init S3 upload: %v", err)
+		s3InternalError(c)
+		return
+	}
+	fileID := initResp.GetFile().GetId()
+
+	// 内容可能很大，不受服务器读超时限制
+	_ = http.NewResponseController(c.Writer).SetReadDeadline(time.Time{})
+	sum := md5.New()
+	err = h.s3WriteObject(ctx, fileID, userID, size, io.TeeReader(c.Request.Body, sum))
+	if err == nil && contentMD5 != nil && !bytes.Equal(sum.Sum(nil), contentMD5) {
+		err = errS3BadDigest
+	}
+	if err != nil {
+		if _, cerr := h.fileClient.CancelUpload(ctx, &filepb.CancelUploadRequest{FileId: fileID}); cerr != nil {
+			utils.Error("Failed to cancel S3 upload %d: %v", fileID, cerr)
+		}
+		s3WriteError(c, err)
+		return
+	}
+
+	resp, err := h.fileClient.CompleteUpload(ctx, &filepb.CompleteUploadRequest{FileId: fileID})
+	if err != nil {
+		utils.Error("Failed to complete S3 upload: %v", err)
+		s3InternalError(c)
+		return
+	}
+	grantFileOwner(c, resp.GetFile().GetId())
+	c.Header("ETag", `"`+hex.EncodeToString(sum.Sum(nil))+`"`)
+	c.Status(http.StatusOK)
+}
+
+// s3WriteObject 把 size 字节的内容写入上传中的文件，并读到请求体末尾以完成载荷校验
+func (h *FileHandler) s3WriteObject(ctx context.Context, fileID, userID, size int64, r io.Reader) error {
+	body := &s3Body{r: r}
+	if size == 0 {
+		// 空对象写入一个空分片，使上传可以完成
+		if err := h.uploadBufferedPart(ctx, fileID, 1, nil); err != nil {
+			return err
+		}
+	} else {
+		state, err := h.fileClient.GetUploadOffset(ctx, &filepb.GetUploadOffsetRequest{FileId: fileID, UserId: userID})
+		if err != nil {
+			return err
+		}
+		written, err := h.writeParts(ctx, fileID, state, body)
+		if body.err != nil {
+			return body.err
+		}
+		if err != nil {
+			return err
+		}
+		if written < size {
+			return errS3IncompleteBody
+		}
+	}
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// s3CreateMultipartUpload 创建大小未知的上传，UploadId 为文件ID
+func (h *FileHandler) s3CreateMultipartUpload(c *gin.Context, userID int64, bucket, key string) {
+	folderID, name, ok := h.s3ObjectFolder(c, userID, bucket, key)
+	if !ok {
+		return
+	}
+	if name == "" {
+		pack.WriteS3Error(c, http.StatusBadRequest, "InvalidArgument", "Object key must not end with /")
+		return
+	}
+	resp, err := h.fileClient.InitUpload(context.Background(), &filepb.InitUploadRequest{
+		FileName: name,
+		UserID:   userID,
+		FolderId: folderID,
+	})
+	if err != nil {
+		utils.Error("Failed to create S3 multipart upload: %v", err)
+		s3InternalError(c)
+		return
+	}
+	pack.WriteS3XML(c, http.StatusOK, initiateMultipartUploadResult{
+		Xmlns:    s3Namespace,
+		Bucket:   bucket,
+		Key:      key,
+		UploadID: strconv.FormatInt(resp.GetFile().GetId(), 10),
+	})
+}
+
+// s3UploadPart 把请求体作为一个分片流式写入，ETag 为分片内容的 MD5
+func (h *FileHandler) s3UploadPart(c *gin.Context, userID int64, query url.Values) {
+	partNumber, err := strconv.Atoi(query.Get("partNumber"))
+	if err != nil || partNumber < 1 || partNumber > s3MaxPartNumber {
+		pack.WriteS3Error(c, http.StatusBadRequest, "InvalidArgument", "Part number must be an integer between 1 and 10000, inclusive")
+		return
+	}
+	fileID, ok := h.s3Upload(c, userID, query.Get("uploadId"))
+	if !ok {
+		return
+	}
+	size := c.Request.ContentLength
+	if size < 0 {
+		pack.WriteS3Error(c, http.StatusLengthRequired, "MissingContentLength", "You must provide the Content-Length HTTP header")
+		return
+	}
+	contentMD5, ok := s3ContentMD5(c)
+	if !ok {
+		return
+	}
+
+	_ = http.NewResponseController(c.Writer).SetReadDeadline(time.Time{})
+	ctx, cancel := context.WithTimeout(context.Background(), s3PartTimeout)
+	defer cancel()
+	sum := md5.New()
+	body := &s3Body{r: io.TeeReader(c.Request.Body, sum)}
+	var partMD5 string
+	if contentMD5 != nil {
+		partMD5 = hex.EncodeToString(contentMD5)
+	}
+	if _, err := h.fileClient.UploadPartStream(ctx, fileID, int32(partNumber), size, body, partMD5); err != nil {
+		switch {
+		case body.err != nil:
+			err = body.err
+		case contentMD5 != nil && !bytes.Equal(sum.Sum(nil), contentMD5):
+			err = errS3BadDigest
+		default:
+			utils.Error("Failed to upload S3 part %d of %d: %v", partNumber, fileID, err)
+		}
+		s3WriteError(c, err)
+		return
+	}
+	c.Header("ETag", `"`+hex.EncodeToString(sum.Sum(nil))+`"`)
+	c.Status(http.StatusOK)
+}
+
+// s3CompleteMultipartUpload 完成上传，列出的分片编号必须从1开始连续，编号和 ETag 都必须与已上传的分片一致
+func (h *FileHandler) s3CompleteMultipartUpload(c *gin.Context, userID int64, bucket, key, uploadID string) {
+	fileID, ok := h.s3Upload(c, userID, uploadID)
+	if !ok {
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, s3MaxXMLBodySize))
+	if err != nil {
+		s3BodyError(c, err)
+		return
+	}
+	var req completeMultipartUpload
+	if err := xml.Unmarshal(data, &req); err != nil || len(req.Parts) == 0 {
+		pack.WriteS3Error(c, http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed or did not validate against our published schema")
+		return
+	}
+	for i, p := range req.Parts {
+		if i > 0 && p.PartNumber <= req.Parts[i-1].PartNumber {
+			pack.WriteS3Error(c, http.StatusBadRequest, "InvalidPartOrder", "The list of parts was not in ascending order")
+			return
+		}
+		if p.PartNumber != i+1 {
+			pack.WriteS3Error(c, http.StatusBadRequest, "InvalidPart", "Part numbers must be consecutive starting from 1")
+			return
+		}
+	}
+
+	// 已上传的分片必须正好是列出的这些，且内容与客户端确认的 ETag 一致，客户端重传过的分片以最后一次为准
+	ctx := context.Background()
+	uploaded, err := h.fileClient.ListUploadedParts(ctx, &filepb.ListUploadedPartsRequest{FileId: fileID})
+	if err != nil {
+		utils.Error("Failed to list uploaded parts of %d: %v", fileID, err)
+		s3InternalError(c)
+		return
+	}
+	parts := uploaded.GetParts()
+	matched := len(parts) == len(req.Parts)
+	for i := 0; matched && i < len(parts); i++ {
+		etag := strings.ToLower(strings.Trim(strings.TrimSpace(req.Parts[i].ETag), `"`))
+		matched = parts[i].GetPartNumber() == int32(req.Parts[i].PartNumber) && parts[i].GetMd5() != "" && parts[i].GetMd5() == etag
+	}
+	if !matched {
+		pack.WriteS3Error(c, http.StatusBadRequest, "InvalidPart", "One or more of the specified parts could not be found or the specified entity tag might not have matched the part's entity tag")
+		return
+	}
+
+	resp, err := h.fileClient.CompleteUpload(ctx, &filepb.CompleteUploadRequest{FileId: fileID})
+	if err != nil {
+		utils.Error("Failed to complete S3 multipart upload: %v", err)
+		s3InternalError(c)
+		return
+	}
+	grantFileOwner(c, resp.GetFile().GetId())
+	pack.WriteS3XML(c, http.StatusOK, completeMultipartUploadResult{
+		Xmlns:    s3Namespace,
+		Location: c.Request.URL.Path,
+		Bucket:   bucket,
+		Key:      key,
+		ETag:     s3ETag(resp.GetFile()),
+	})
+}
+
+// s3AbortMultipartUpload 取消上传，释放已上传的分片
+func (h *FileHandler) s3AbortMultipartUpload(c *gin.Context, userID int64, uploadID string) {
+	fileID, ok := h.s3Upload(c, userID, uploadID)
+	if !ok {
+		return
+	}
+	if _, err := h.fileClient.CancelUpload(context.Background(), &filepb.CancelUploadRequest{FileId: fileID}); err != nil {
+		utils.Error("Failed to abort S3 multipart upload %d: %v", fileID, err)
+		s3InternalError(c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// s3DeleteObject 把对象对应的文件移入回收站，对象不存在时同样返回成功
+func (h *FileHandler) s3DeleteObject(c *gin.Context, userID int64, bucket, key string) {
+	bucketID, ok := h.s3Bucket(c, userID, bucket)
+	if !ok {
+		return
+	}
+	folders, name, ok := splitS3Key(key)
+	if ok && name != "" {
+		resp, err := h.fileClient.LookupPath(context.Background(), &filepb.LookupPathRequest{
+			UserId:   userID,
+			FolderId: bucketID,
+			Folders:  folders,
+			Name:     name,
+		})
+		if err == nil && resp.GetFile() != nil && resp.GetFile().GetStatus() == 1 {
+			fileID := resp.GetFile().GetId()
+			if _, err := h.fileClient.DeleteFile(context.Background(), &filepb.DeleteRequest{FileId: fileID, UserId: userID}); err != nil {
+				utils.Error("Failed to delete S3 object %d: %v", fileID, err)
+				s3InternalError(c)
+				return
+			}
+		}
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// s3Object 查找已完成上传的对象，不存在时写入 NoSuchKey
+func (h *FileHandler) s3Object(c *gin.Context, userID int64, bucket, key string) (*filepb.FileInfo, bool) {
+	bucketID, ok := h.s3Bucket(c, userID, bucket)
+	if !ok {
+		return nil, false
+	}
+	folders, name, ok := splitS3Key(key)
+	if ok && name != "" {
+		resp, err := h.fileClient.LookupPath(context.Background(), &filepb.LookupPathRequest{
+			UserId:   userID,
+			FolderId: bucketID,
+			Folders:  folders,
+			Name:     name,
+		})
+		if err == nil && resp.GetFile() != nil && resp.GetFile().GetStatus() == 1 {
+			return resp.GetFile(), true
+		}
+	}
+	pack.WriteS3Error(c, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+	return nil, false
+}
+
+// s3ObjectFolder 返回对象所在的文件夹ID和文件名，不存在的文件夹会被创建
+func (h *FileHandler) s3ObjectFolder(c *gin.Context, userID int64, bucket, key string) (int64, string, bool) {
+	folders, name, ok := splitS3Key(key)
+	if !ok {
+		pack.WriteS3Error(c, http.StatusBadRequest, "InvalidArgument", "Object key contains empty, . or .. path segments")
+		return 0, "", false
+	}
+	bucketID, ok := h.s3Bucket(c, userID, bucket)
+	if !ok {
+		return 0, "", false
+	}
+	resp, err := h.fileClient.LookupPath(context.Background(), &filepb.LookupPathRequest{
+		UserId:   userID,
+		FolderId: bucketID,
+		Folders:  folders,
+		Create:   true,
+	})
+	if err != nil {
+		utils.Error("Failed to create folders for S3 key %q: %v", key, err)
+		pack.WriteS3Error(c, http.StatusBadRequest, "InvalidArgument", "Object key is not a valid path")
+		return 0, "", false
+	}
+	return resp.GetFolderId(), name, true
+}
+
+// s3Upload 解析 UploadId 并确认上传属于当前用户且仍在进行中，否则写入 NoSuchUpload
+func (h *FileHandler) s3Upload(c *gin.Context, userID int64, uploadID string) (int64, bool) {
+	fileID, err := strconv.ParseInt(uploadID, 10, 64)
+	if err == nil {
+		state, err := h.fileClient.GetUploadOffset(context.Background(), &filepb.GetUploadOffsetRequest{FileId: fileID, UserId: userID})
+		if err == nil && !state.GetCompleted() {
+			return fileID, true
+		}
+	}
+	pack.WriteS3Error(c, http.StatusNotFound, "NoSuchUpload", "The specified multipart upload does not exist")
+	return 0, false
+}
+
+// s3ContentMD5 解析可选的 Content-MD5 请求头，格式不正确时写入 InvalidDigest
+func s3ContentMD5(c *gin.Context) ([]byte, bool) {
+	header := c.GetHeader("Content-MD5")
+	if header == "" {
+		return nil, true
+	}
+	sum, err := base64.StdEncoding.DecodeString(header)
+	if err != nil || len(sum) != md5.Size {
+		pack.WriteS3Error(c, http.StatusBadRequest, "InvalidDigest", "The Content-MD5 you specified was invalid")
+		return nil, false
+	}
+	return sum, true
+}
+
+// s3WriteError 按写入对象时的错误类型响应
+func s3WriteError(c *gin.Context, err error) {
+	var sigErr *utils.SigV4Error
+	switch {
+	case errors.Is(err, errS3BadDigest):
+		pack.WriteS3Error(c, http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match what we received")
+	case errors.Is(err, errS3IncompleteBody), errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &sigErr):
+		s3BodyError(c, err)
+	default:
+		utils.Error("Failed to write S3 object: %v", err)
+		s3InternalError(c)
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+)
+
+func s3PartETag(data string) string {
+	sum := md5.Sum([]byte(data))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func completeBody(etags ...string) string {
+	var b strings.Builder
+	b.WriteString("<CompleteMultipartUpload>")
+	for i, etag := range etags {
+		fmt.Fprintf(&b, "<Part><PartNumber>%d</PartNumber><ETag>%s</ETag></Part>", i+1, etag)
+	}
+	b.WriteString("</CompleteMultipartUpload>")
+	return b.String()
+}
+
+func TestS3_CompleteMultipartUploadChecksETags(t *testing.T) {
+	tests := []struct {
+		name  string
+		etags []string
+		want  int
+	}{
+		{name: "matching etags", etags: []string{s3PartETag("hello "), s3PartETag("world")}, want: http.StatusOK},
+		{name: "unquoted upper-case etags", etags: []string{strings.ToUpper(strings.Trim(s3PartETag("hello "), `"`)), strings.Trim(s3PartETag("world"), `"`)}, want: http.StatusOK},
+		{name: "stale etag of a re-uploaded part", etags: []string{s3PartETag("hello "), s3PartETag("earth")}, want: http.StatusBadRequest},
+		{name: "missing etag", etags: []string{s3PartETag("hello "), ""}, want: http.StatusBadRequest},
+		{name: "fewer parts than uploaded", etags: []string{s3PartETag("hello ")}, want: http.StatusBadRequest},
+		{name: "more parts than uploaded", etags: []string{s3PartETag("hello "), s3PartETag("world"), s3PartETag("!")}, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeFileService(6)
+			h := newTestHandler(t, fake)
+			resp, _ := fake.InitUpload(context.Background(), &filepb.InitUploadRequest{FileName: "key", Size: 11, UserID: testUserID})
+			fileID := resp.GetFile().GetId()
+			fake.upload(fileID).parts[1] = []byte("hello ")
+			fake.upload(fileID).parts[2] = []byte("world")
+
+			r := newTestRouter()
+			r.POST("/bucket/key", func(c *gin.Context) {
+				h.s3CompleteMultipartUpload(c, testUserID, "bucket", "key", c.Query("uploadId"))
+			})
+			req := httptest.NewRequest(http.MethodPost, "/bucket/key?uploadId="+strconv.FormatInt(fileID, 10), strings.NewReader(completeBody(tt.etags...)))
+			w := serve(r, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			if completed := fake.upload(fileID).completed; completed != (tt.want == http.StatusOK) {
+				t.Errorf("completed = %v", completed)
+			}
+			if tt.want != http.StatusOK && !strings.Contains(w.Body.String(), "<Code>InvalidPart</Code>") {
+				t.Errorf("body = %s, want InvalidPart", w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleListFiles 处理列出当前用户所有文件请求
+// 查询参数: cursor, limit
+func (h *FileHandler) HandleListFiles(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	req := &filepb.ListFilesRequest{
+		UserId: userID,
+		Cursor: c.Query("cursor"),
+		Limit:  int32(limit),
+	}
+
+	ctx := context.Background()
+	resp, err := h.fileClient.ListFiles(ctx, req)
+	if err != nil {
+		utils.Error("Failed to list files: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to list files")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Files listed successfully", resp)
+}
+
+// HandleSearchFiles 处理搜索当前用户文件请求
+// 查询参数: name, min_size, max_size, status, created_after, created_before, updated_after, updated_before,
+// mtime_after, mtime_before, mime_type, tag, cursor, limit
+// tag 可重复，形如 key:value，只有 key 时匹配有该标签的文件
+func (h *FileHandler) HandleSearchFiles(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	req := &filepb.SearchFilesRequest{
+		UserId:   userID,
+		Name:     c.Query("name"),
+		MimeType: c.Query("mime_type"),
+		Cursor:   c.Query("cursor"),
+	}
+
+	int64Params := []struct {
+		name string
+		dst  *int64
+	}{
+		{"min_size", &req.MinSize},
+		{"max_size", &req.MaxSize},
+		{"created_after", &req.CreatedAfter},
+		{"created_before", &req.CreatedBefore},
+		{"updated_after", &req.UpdatedAfter},
+		{"updated_before", &req.UpdatedBefore},
+		{"mtime_after", &req.MtimeAfter},
+		{"mtime_before", &req.MtimeBefore},
+	}
+	for _, p := range int64Params {
+		value := c.Query(p.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			pack.WriteError(c, http.StatusBadRequest, "Invalid "+p.name+" parameter")
+			return
+		}
+		*p.dst = n
+	}
+
+	if tags := c.QueryArray("tag"); len(tags) > 0 {
+		req.Tags = make(map[string]string, len(tags))
+		for _, tag := range tags {
+			name, value, _ := strings.Cut(tag, ":")
+			if name == "" {
+				pack.WriteError(c, http.StatusBadRequest, "Invalid tag parameter")
+				return
+			}
+			req.Tags[name] = value
+		}
+	}
+
+	if value := c.Query("status"); value != "" {
+		status, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			pack.WriteError(c, http.StatusBadRequest, "Invalid status parameter")
+			return
+		}
+		s := int32(status)
+		req.Status = &s
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+	req.Limit = int32(limit)
+
+	ctx := context.Background()
+	resp, err := h.fileClient.SearchFiles(ctx, req)
+	if err != nil {
+		utils.Error("Failed to search files: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to search files")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Files searched successfully", resp)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleUpdateFileTags 处理设置或删除文件标签请求
+// 请求体: {"file_id": 1, "set": {"project": "alpha"}, "remove": ["draft"]}
+func (h *FileHandler) HandleUpdateFileTags(c *gin.Context) {
+	var req filepb.UpdateFileTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	ctx := context.Background()
+	resp, err := h.fileClient.UpdateFileTags(ctx, &req)
+	if err != nil {
+		utils.Error("Failed to update file tags: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to update file tags")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "File tags updated successfully", resp.GetFile())
+}
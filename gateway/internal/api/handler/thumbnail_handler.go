@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetThumbnail 处理获取缩略图请求，返回图片内容；文本文件返回开头部分的预览
+// size 为期望的最长边像素，可选
+func (h *FileHandler) HandleGetThumbnail(c *gin.Context) {
+	fileID, err := strconv.ParseInt(c.Query("file_id"), 10, 64)
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid file_id parameter")
+		return
+	}
+	var size int64
+	if sizeStr := c.Query("size"); sizeStr != "" {
+		size, err = strconv.ParseInt(sizeStr, 10, 32)
+		if err != nil || size < 0 {
+			pack.WriteError(c, http.StatusBadRequest, "Invalid size parameter")
+			return
+		}
+	}
+
+	resp, err := h.fileClient.GetThumbnail(context.Background(), &filepb.GetThumbnailRequest{
+		FileId: fileID,
+		Size:   int32(size),
+	})
+	if err != nil {
+		utils.Error("Failed to get thumbnail: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to get thumbnail")
+		return
+	}
+	if len(resp.GetData()) == 0 {
+		if resp.GetPending() {
+			pack.WriteError(c, http.StatusNotFound, "Thumbnail is not ready yet")
+			return
+		}
+		pack.WriteError(c, http.StatusNotFound, "Thumbnail not available")
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Data(http.StatusOK, resp.GetMimeType(), resp.GetData())
+}
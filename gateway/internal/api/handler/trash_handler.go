@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleListTrash 处理列出回收站请求
+// 查询参数: page, page_size
+func (h *FileHandler) HandleListTrash(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid page parameter")
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid page_size parameter")
+		return
+	}
+
+	req := &filepb.ListTrashRequest{
+		UserId:   userID,
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}
+
+	ctx := context.Background()
+	resp, err := h.fileClient.ListTrash(ctx, req)
+	if err != nil {
+		utils.Error("Failed to list trash: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to list trash")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Trash listed successfully", resp)
+}
+
+// HandleRestoreFile 处理从回收站恢复文件请求
+func (h *FileHandler) HandleRestoreFile(c *gin.Context) {
+	var req filepb.RestoreFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	ctx := context.Background()
+	resp, err := h.fileClient.RestoreFile(ctx, &req)
+	if err != nil {
+		utils.Error("Failed to restore file: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to restore file")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "File restored successfully", resp.GetFile())
+}
+
+// HandleEmptyTrash 处理清空回收站请求
+func (h *FileHandler) HandleEmptyTrash(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx := context.Background()
+	resp, err := h.fileClient.EmptyTrash(ctx, &filepb.EmptyTrashRequest{UserId: userID})
+	if err != nil {
+		utils.Error("Failed to empty trash: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to empty trash")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Trash emptied successfully", resp)
+}
@@ -0,0 +1,424 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tus 1.0 可续传上传协议（https://tus.io/protocols/resumable-upload），支持 creation、checksum、termination 和 expiration 扩展
+// 上传映射到 InitUpload、UploadPartStream、CompleteUpload 和 CancelUpload，偏移由文件服务根据已保存的分片计算。
+// PATCH 的内容按分片大小切分写入，不足一个分片的尾部（最后一个分片除外）不会保存，响应的 Upload-Offset 只包含已保存的部分，
+// 客户端从该偏移继续即可。分片大小通过 Tus-Min-Chunk-Size 响应头告知客户端，小于它（且不是最后一段）的 PATCH 无法推进偏移，直接拒绝。
+// 同一上传同时只允许一个 PATCH 写入，其余的返回 423。该锁只在当前进程内有效，文件服务不会拒绝同一编号的分片重复写入，
+// 多个网关实例同时处理同一上传时并发的 PATCH 会写入相同的分片，因此 tus 接口要求只部署一个网关实例（或按上传ID粘性路由）。
+const (
+	tusVersion             = "1.0.0"
+	tusExtensions          = "creation,checksum,termination,expiration"
+	tusChecksumAlgorithms  = "md5,sha1,sha256"
+	tusContentType         = "application/offset+octet-stream"
+	statusChecksumMismatch = 460 // tus checksum 扩展定义的状态码
+	tusMinChunkSizeHeader  = "Tus-Min-Chunk-Size"
+)
+
+var (
+	errTusBadChecksum      = errors.New("invalid Upload-Checksum")
+	errTusChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// tusLocks 正在被 PATCH 写入的上传，只在当前网关进程内有效
+var tusLocks sync.Map
+
+// lockTusUpload 获取上传的写入锁，已被其他请求持有时返回 false
+func lockTusUpload(fileID int64) (func(), bool) {
+	if _, busy := tusLocks.LoadOrStore(fileID, struct{}{}); busy {
+		return nil, false
+	}
+	return func() { tusLocks.Delete(fileID) }, true
+}
+
+// HandleTusOptions 返回服务端支持的 tus 版本和扩展，不需要认证
+func (h *FileHandler) HandleTusOptions(c *gin.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	c.Header("Tus-Checksum-Algorithm", tusChecksumAlgorithms)
+	c.Status(http.StatusNoContent)
+}
+
+// HandleTusCreate 创建上传，Location 为后续 HEAD、PATCH 和 DELETE 的地址
+// Upload-Metadata 中 filename（或 name）必填，可选 folder_id、mtime（秒）和 compression
+func (h *FileHandler) HandleTusCreate(c *gin.Context) {
+	if !tusResumable(c) {
+		return
+	}
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid or missing Upload-Length")
+		return
+	}
+	meta, err := parseTusMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, fmt.Sprintf("Invalid Upload-Metadata: %v", err))
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	req := &filepb.InitUploadRequest{
+		FileName:    meta["filename"],
+		Size:        length,
+		UserID:      userID,
+		Compression: meta["compression"],
+	}
+	if req.FileName == "" {
+		req.FileName = meta["name"]
+	}
+	if req.FileName == "" {
+		pack.WriteError(c, http.StatusBadRequest, "Missing filename in Upload-Metadata")
+		return
+	}
+	for key, dst := range map[string]*int64{"folder_id": &req.FolderId, "mtime": &req.Mtime} {
+		if v, ok := meta[key]; ok {
+			if *dst, err = strconv.ParseInt(v, 10, 64); err != nil {
+				pack.WriteError(c, http.StatusBadRequest, fmt.Sprintf("Invalid %s in Upload-Metadata", key))
+				return
+			}
+		}
+	}
+
+	ctx := context.Background()
+	resp, err := h.fileClient.InitUpload(ctx, req)
+	if err != nil {
+		utils.Error("Failed to init tus upload: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to init upload")
+		return
+	}
+	fileID := resp.GetFile().GetId()
+	if resp.GetFile().GetStatus() == 1 {
+		grantFileOwner(c, fileID)
+	} else if length == 0 {
+		// 空文件不会有 PATCH 请求，创建时直接写入一个空分片并完成上传
+		if err := h.uploadBufferedPart(ctx, fileID, 1, nil); err != nil {
+			utils.Error("Failed to upload empty tus part: %v", err)
+			pack.WriteError(c, http.StatusInternalServerError, "Failed to upload part")
+			return
+		}
+		if !h.completeTusUpload(c, fileID) {
+			return
+		}
+	} else {
+		state, err := h.fileClient.GetUploadOffset(ctx, &filepb.GetUploadOffsetRequest{FileId: fileID, UserId: userID})
+		if err == nil {
+			setTusExpires(c, state)
+			setTusMinChunkSize(c, state)
+		}
+	}
+
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+strconv.FormatInt(fileID, 10))
+	c.Status(http.StatusCreated)
+}
+
+// HandleTusHead 返回上传的当前偏移和总大小
+func (h *FileHandler) HandleTusHead(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	if !tusResumable(c) {
+		return
+	}
+	_, state, ok := h.tusUpload(c)
+	if !ok {
+		return
+	}
+	setTusMinChunkSize(c, state)
+	c.Header("Upload-Offset", strconv.FormatInt(state.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(state.TotalSize, 10))
+	c.Status(http.StatusOK)
+}
+
+// HandleTusPatch 从 Upload-Offset 起追加内容，写满全部内容后完成上传
+// 提供 Upload-Checksum 时先把内容暂存到临时文件校验，不一致时返回 460 且不保存任何内容
+// 内容小于一个分片且不是最后一段时返回 400，最小分块大小见 Tus-Min-Chunk-Size
+func (h *FileHandler) HandleTusPatch(c *gin.Context) {
+	if !tusResumable(c) {
+		return
+	}
+	if c.ContentType() != tusContentType {
+		pack.WriteError(c, http.StatusUnsupportedMediaType, "Content-Type must be "+tusContentType)
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid or missing Upload-Offset")
+		return
+	}
+	// 偏移的检查和写入必须在锁内，否则并发的 PATCH 会按同一偏移写入
+	if id, err := strconv.ParseInt(c.Param("file_id"), 10, 64); err == nil {
+		unlock, ok := lockTusUpload(id)
+		if !ok {
+			pack.WriteError(c, http.StatusLocked, "Upload is locked by another request")
+			return
+		}
+		defer unlock()
+	}
+	fileID, state, ok := h.tusUpload(c)
+	if !ok {
+		return
+	}
+	setTusMinChunkSize(c, state)
+	if offset != state.Offset {
+		pack.WriteError(c, http.StatusConflict, fmt.Sprintf("Upload-Offset %d does not match current offset %d", offset, state.Offset))
+		return
+	}
+	remaining := state.TotalSize - offset
+	if c.Request.ContentLength > remaining {
+		pack.WriteError(c, http.StatusRequestEntityTooLarge, "Request body exceeds Upload-Length")
+		return
+	}
+	minChunk := min(state.PartSize, remaining)
+	if c.Request.ContentLength > 0 && c.Request.ContentLength < minChunk {
+		pack.WriteError(c, http.StatusBadRequest, fmt.Sprintf("Request body must be at least %d bytes", minChunk))
+		return
+	}
+
+	// 内容可能很大，不受服务器读超时限制
+	_ = http.NewResponseController(c.Writer).SetReadDeadline(time.Time{})
+	received := &tusBody{r: io.LimitReader(c.Request.Body, remaining)}
+	var body io.Reader = received
+	if header := c.GetHeader("Upload-Checksum"); header != "" {
+		spooled, err := spoolTusChecksum(body, header)
+		switch {
+		case errors.Is(err, errTusBadChecksum):
+			pack.WriteError(c, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, errTusChecksumMismatch):
+			pack.WriteError(c, statusChecksumMismatch, "Checksum mismatch")
+			return
+		case err != nil:
+			utils.Error("Failed to spool tus upload: %v", err)
+			pack.WriteError(c, http.StatusInternalServerError, "Failed to read request body")
+			return
+		}
+		defer removeTemp(spooled)
+		body = spooled
+	}
+
+	ctx := context.Background()
+	written, err := h.writeParts(ctx, fileID, state, body)
+	offset += written
+	if err != nil {
+		// 已写入的分片保留，客户端通过 HEAD 获取偏移后继续
+		utils.Error("Failed to upload tus part: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to upload part")
+		return
+	}
+	if written == 0 && received.n > 0 {
+		// 没有 Content-Length 的请求只能在读完后发现内容不足一个分片
+		pack.WriteError(c, http.StatusBadRequest, fmt.Sprintf("Request body must be at least %d bytes", minChunk))
+		return
+	}
+	if !state.Completed && offset == state.TotalSize {
+		if !h.completeTusUpload(c, fileID) {
+			return
+		}
+		c.Writer.Header().Del("Upload-Expires")
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(offset, 10))
+	c.Status(http.StatusNoContent)
+}
+
+// HandleTusDelete 终止上传，释放已上传的分片
+func (h *FileHandler) HandleTusDelete(c *gin.Context) {
+	if !tusResumable(c) {
+		return
+	}
+	fileID, state, ok := h.tusUpload(c)
+	if !ok {
+		return
+	}
+	if state.Completed {
+		pack.WriteError(c, http.StatusConflict, "Upload already completed")
+		return
+	}
+
+	if _, err := h.fileClient.CancelUpload(context.Background(), &filepb.CancelUploadRequest{FileId: fileID}); err != nil {
+		utils.Error("Failed to cancel tus upload: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to cancel upload")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// HandleTusMethodOverride 按 X-HTTP-Method-Override 把 POST 当作 PATCH 或 DELETE 处理，供不能发送这两种请求的客户端使用
+func (h *FileHandler) HandleTusMethodOverride(c *gin.Context) {
+	switch c.GetHeader("X-HTTP-Method-Override") {
+	case http.MethodPatch:
+		h.HandleTusPatch(c)
+	case http.MethodDelete:
+		h.HandleTusDelete(c)
+	default:
+		pack.WriteError(c, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// tusResumable 设置 Tus-Resumable 响应头，请求的协议版本不受支持时返回 412
+func tusResumable(c *gin.Context) bool {
+	c.Header("Tus-Resumable", tusVersion)
+	if c.GetHeader("Tus-Resumable") != tusVersion {
+		c.Header("Tus-Version", tusVersion)
+		pack.WriteError(c, http.StatusPreconditionFailed, "Unsupported Tus-Resumable version")
+		return false
+	}
+	return true
+}
+
+// tusUpload 获取路径中上传的状态，只有文件所有者可以访问；过期的上传返回 410
+func (h *FileHandler) tusUpload(c *gin.Context) (int64, *filepb.GetUploadOffsetResponse, bool) {
+	fileID, err := strconv.ParseInt(c.Param("file_id"), 10, 64)
+	if err != nil {
+		pack.WriteError(c, http.StatusNotFound, "Upload not found")
+		return 0, nil, false
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return 0, nil, false
+	}
+
+	state, err := h.fileClient.GetUploadOffset(context.Background(), &filepb.GetUploadOffsetRequest{FileId: fileID, UserId: userID})
+	if err != nil {
+		utils.Error("Failed to get upload offset: %v", err)
+		pack.WriteError(c, http.StatusNotFound, "Upload not found")
+		return 0, nil, false
+	}
+	if !state.Completed && state.ExpiresAt != 0 && time.Now().Unix() >= state.ExpiresAt {
+		pack.WriteError(c, http.StatusGone, "Upload expired")
+		return 0, nil, false
+	}
+	setTusExpires(c, state)
+	return fileID, state, true
+}
+
+// setTusExpires 设置上传会话的过期时间，已完成或不过期的上传不设置
+func setTusExpires(c *gin.Context, state *filepb.GetUploadOffsetResponse) {
+	if state.Completed || state.ExpiresAt == 0 {
+		return
+	}
+	c.Header("Upload-Expires", time.Unix(state.ExpiresAt, 0).UTC().Format(http.TimeFormat))
+}
+
+// setTusMinChunkSize 告知客户端 PATCH 内容的最小大小，即文件服务的分片大小，已完成的上传不设置
+func setTusMinChunkSize(c *gin.Context, state *filepb.GetUploadOffsetResponse) {
+	if state.Completed || state.PartSize <= 0 {
+		return
+	}
+	c.Header(tusMinChunkSizeHeader, strconv.FormatInt(state.PartSize, 10))
+}
+
+// tusBody 记录从请求体读取的字节数
+type tusBody struct {
+	r io.Reader
+	n int64
+}
+
+func (b *tusBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// completeTusUpload 完成上传并授予所有者权限，失败时写入错误响应
+func (h *FileHandler) completeTusUpload(c *gin.Context, fileID int64) bool {
+	resp, err := h.fileClient.CompleteUpload(context.Background(), &filepb.CompleteUploadRequest{FileId: fileID})
+	if err != nil {
+		utils.Error("Failed to complete tus upload: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to complete upload")
+		return false
+	}
+	grantFileOwner(c, resp.GetFile().GetId())
+	return true
+}
+
+// parseTusMetadata 解析 Upload-Metadata：逗号分隔的 "键 base64值"，值可以省略
+func parseTusMetadata(header string) (map[string]string, error) {
+	meta := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q", key)
+		}
+		meta[key] = string(value)
+	}
+	return meta, nil
+}
+
+// spoolTusChecksum 把内容写入临时文件并按 Upload-Checksum（"算法 base64摘要"）校验，返回从头读取的临时文件
+func spoolTusChecksum(body io.Reader, header string) (*os.File, error) {
+	algorithm, encoded, _ := strings.Cut(header, " ")
+	var h hash.Hash
+	switch algorithm {
+	case "md5":
+		h = md5.New()
+	case "sha1":
+		h = sha1.New()
+	case "sha256":
+		h = sha256.New()
+	default:
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", errTusBadChecksum, algorithm)
+	}
+	want, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errTusBadChecksum, err)
+	}
+
+	f, err := os.CreateTemp("", "tus-patch-*")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(io.MultiWriter(f, h), body); err != nil {
+		removeTemp(f)
+		return nil, err
+	}
+	if !bytes.Equal(h.Sum(nil), want) {
+		removeTemp(f)
+		return nil, errTusChecksumMismatch
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		removeTemp(f)
+		return nil, err
+	}
+	return f, nil
+}
+
+// removeTemp 关闭并删除临时文件
+func removeTemp(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
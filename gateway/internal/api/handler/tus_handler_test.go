@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/waitform/micro-cloud-storage/internal/casbin"
+)
+
+const tusTestPartSize = 4
+
+var tusTestData = []byte("0123456789")
+
+// newTusTest 创建一个 10 字节、分片大小为 4 的上传，返回上传地址
+func newTusTest(t *testing.T) (*fakeFileService, http.Handler, string) {
+	t.Helper()
+	fake := newFakeFileService(tusTestPartSize)
+	h := newTestHandler(t, fake)
+	r := newTestRouter()
+	r.POST("/api/file/tus", h.HandleTusCreate)
+	r.HEAD("/api/file/tus/:file_id", h.HandleTusHead)
+	r.PATCH("/api/file/tus/:file_id", h.HandleTusPatch)
+	r.DELETE("/api/file/tus/:file_id", h.HandleTusDelete)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/file/tus", nil)
+	req.Header.Set("Tus-Resumable", tusVersion)
+	req.Header.Set("Upload-Length", strconv.Itoa(len(tusTestData)))
+	req.Header.Set("Upload-Metadata", "filename "+base64.StdEncoding.EncodeToString([]byte("a.txt")))
+	w := serve(r, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, body = %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get(tusMinChunkSizeHeader); got != strconv.Itoa(tusTestPartSize) {
+		t.Errorf("%s = %q, want %d", tusMinChunkSizeHeader, got, tusTestPartSize)
+	}
+	return fake, r, w.Header().Get("Location")
+}
+
+func tusPatchRequest(location string, offset int64, body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPatch, location, bytes.NewReader(body))
+	req.Header.Set("Tus-Resumable", tusVersion)
+	req.Header.Set("Content-Type", tusContentType)
+	req.Header.Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	return req
+}
+
+// tusOffset 通过 HEAD 查询上传的当前偏移
+func tusOffset(t *testing.T, r http.Handler, location string) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodHead, location, nil)
+	req.Header.Set("Tus-Resumable", tusVersion)
+	w := serve(r, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("HEAD status = %d", w.Code)
+	}
+	return w.Header().Get("Upload-Offset")
+}
+
+func TestTus_PatchRejections(t *testing.T) {
+	tests := []struct {
+		name     string
+		offset   int64
+		body     []byte
+		checksum string
+		want     int
+	}{
+		{name: "offset mismatch", offset: tusTestPartSize, body: tusTestData[tusTestPartSize:], want: http.StatusConflict},
+		{name: "chunk smaller than a part", body: tusTestData[:tusTestPartSize-1], want: http.StatusBadRequest},
+		{name: "checksum mismatch", body: tusTestData, checksum: "sha1 " + base64.StdEncoding.EncodeToString(sha1.New().Sum(nil)), want: statusChecksumMismatch},
+		{name: "bad checksum algorithm", body: tusTestData, checksum: "crc32 AAAA", want: http.StatusBadRequest},
+		{name: "exceeds Upload-Length", body: append(tusTestData, 'x'), want: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, r, location := newTusTest(t)
+			req := tusPatchRequest(location, tt.offset, tt.body)
+			if tt.checksum != "" {
+				req.Header.Set("Upload-Checksum", tt.checksum)
+			}
+			if w := serve(r, req); w.Code != tt.want {
+				t.Fatalf("PATCH status = %d, want %d, body = %s", w.Code, tt.want, w.Body)
+			}
+			if got := tusOffset(t, r, location); got != "0" {
+				t.Errorf("offset after rejected PATCH = %s, want 0", got)
+			}
+			if len(fake.upload(1).parts) != 0 {
+				t.Errorf("rejected PATCH wrote %d parts", len(fake.upload(1).parts))
+			}
+		})
+	}
+}
+
+func TestTus_PatchCompletesUpload(t *testing.T) {
+	fake, r, location := newTusTest(t)
+
+	// 不足一个分片的尾部不保存，偏移只推进到分片边界
+	w := serve(r, tusPatchRequest(location, 0, tusTestData[:6]))
+	if w.Code != http.StatusNoContent || w.Header().Get("Upload-Offset") != "4" {
+		t.Fatalf("first PATCH = %d, Upload-Offset %q, want 204 and 4", w.Code, w.Header().Get("Upload-Offset"))
+	}
+	if fake.upload(1).completed {
+		t.Fatal("upload completed before all content was written")
+	}
+
+	sum := sha1.Sum(tusTestData[4:])
+	req := tusPatchRequest(location, 4, tusTestData[4:])
+	req.Header.Set("Upload-Checksum", "sha1 "+base64.StdEncoding.EncodeToString(sum[:]))
+	w = serve(r, req)
+	if w.Code != http.StatusNoContent || w.Header().Get("Upload-Offset") != "10" {
+		t.Fatalf("last PATCH = %d, Upload-Offset %q, want 204 and 10", w.Code, w.Header().Get("Upload-Offset"))
+	}
+	if w.Header().Get("Upload-Expires") != "" {
+		t.Error("completed upload should not have Upload-Expires")
+	}
+	u := fake.upload(1)
+	if !u.completed || !bytes.Equal(u.content(), tusTestData) {
+		t.Fatalf("upload completed = %v, content = %q", u.completed, u.content())
+	}
+	if allowed, err := casbin.Enforce(strconv.FormatInt(testUserID, 10), fileObject(1), "read"); err != nil || !allowed {
+		t.Errorf("owner read permission = %v, %v", allowed, err)
+	}
+}
+
+func TestTus_Delete(t *testing.T) {
+	fake, r, location := newTusTest(t)
+	if w := serve(r, tusPatchRequest(location, 0, tusTestData[:4])); w.Code != http.StatusNoContent {
+		t.Fatalf("PATCH status = %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, location, nil)
+	req.Header.Set("Tus-Resumable", tusVersion)
+	if w := serve(r, req); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE status = %d, body = %s", w.Code, w.Body)
+	}
+	if fake.upload(1) != nil {
+		t.Error("upload still exists after DELETE")
+	}
+	req = httptest.NewRequest(http.MethodHead, location, nil)
+	req.Header.Set("Tus-Resumable", tusVersion)
+	if w := serve(r, req); w.Code != http.StatusNotFound {
+		t.Errorf("HEAD after DELETE status = %d, want 404", w.Code)
+	}
+}
+
+func TestTus_ConcurrentPatchLocked(t *testing.T) {
+	fake, r, location := newTusTest(t)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	fake.uploadHook = func(fileID, partNumber int64) {
+		once.Do(func() {
+			close(entered)
+			<-release
+		})
+	}
+
+	first := make(chan int)
+	go func() {
+		first <- serve(r, tusPatchRequest(location, 0, tusTestData[:4])).Code
+	}()
+	<-entered
+	if w := serve(r, tusPatchRequest(location, 0, tusTestData[:4])); w.Code != http.StatusLocked {
+		t.Errorf("concurrent PATCH status = %d, want 423", w.Code)
+	}
+	close(release)
+	if code := <-first; code != http.StatusNoContent {
+		t.Errorf("first PATCH status = %d, want 204", code)
+	}
+	if got := tusOffset(t, r, location); got != "4" {
+		t.Errorf("offset = %s, want 4", got)
+	}
+}
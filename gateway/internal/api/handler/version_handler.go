@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	filepb "github.com/waitform/micro-cloud-storage/protos/file/proto"
+	utils "github.com/waitform/micro-cloud-storage/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleListVersions 处理列出文件版本请求
+// 查询参数: file_id
+func (h *FileHandler) HandleListVersions(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	fileID, err := strconv.ParseInt(c.Query("file_id"), 10, 64)
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid file_id parameter")
+		return
+	}
+
+	req := &filepb.ListVersionsRequest{
+		UserId: userID,
+		FileId: fileID,
+	}
+
+	ctx := context.Background()
+	resp, err := h.fileClient.ListVersions(ctx, req)
+	if err != nil {
+		utils.Error("Failed to list versions: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to list versions")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Versions listed successfully", resp.GetVersions())
+}
+
+// HandleGetVersion 处理获取文件版本请求
+// 查询参数: file_id, version_id
+func (h *FileHandler) HandleGetVersion(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	fileID, err := strconv.ParseInt(c.Query("file_id"), 10, 64)
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid file_id parameter")
+		return
+	}
+	versionID, err := strconv.ParseInt(c.Query("version_id"), 10, 64)
+	if err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid version_id parameter")
+		return
+	}
+
+	req := &filepb.GetVersionRequest{
+		UserId:    userID,
+		FileId:    fileID,
+		VersionId: versionID,
+	}
+
+	ctx := context.Background()
+	resp, err := h.fileClient.GetVersion(ctx, req)
+	if err != nil {
+		utils.Error("Failed to get version: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to get version")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Version retrieved successfully", resp.GetVersion())
+}
+
+// HandleRestoreVersion 处理恢复历史版本请求
+func (h *FileHandler) HandleRestoreVersion(c *gin.Context) {
+	var req filepb.RestoreVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	ctx := context.Background()
+	resp, err := h.fileClient.RestoreVersion(ctx, &req)
+	if err != nil {
+		utils.Error("Failed to restore version: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to restore version")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Version restored successfully", resp.GetFile())
+}
+
+// HandleDeleteVersion 处理删除历史版本请求
+func (h *FileHandler) HandleDeleteVersion(c *gin.Context) {
+	var req filepb.DeleteVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		pack.WriteError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	userID, ok := getUserID(c)
+	if !ok {
+		pack.WriteError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.UserId = userID
+
+	ctx := context.Background()
+	if _, err := h.fileClient.DeleteVersion(ctx, &req); err != nil {
+		utils.Error("Failed to delete version: %v", err)
+		pack.WriteError(c, http.StatusInternalServerError, "Failed to delete version")
+		return
+	}
+
+	pack.WriteJSON(c, http.StatusOK, "Version deleted successfully", nil)
+}
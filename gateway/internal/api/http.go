@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/waitform/micro-cloud-storage/global"
 	"github.com/waitform/micro-cloud-storage/internal/api/handler"
 	"github.com/waitform/micro-cloud-storage/internal/router"
 	"github.com/waitform/micro-cloud-storage/internal/rpc"
@@ -18,6 +19,7 @@ type GatewayServer struct {
 	ShareHandler  *handler.ShareHandler
 	FileHandler   *handler.FileHandler
 	ShareClient   *rpc.ShareServiceClient
+	UserClient    *rpc.UserServiceClient
 	IPRateLimiter *utils.IPRateLimiter
 }
 
@@ -26,11 +28,12 @@ func NewGatewayServer(
 	userClient *rpc.UserServiceClient,
 	shareClient *rpc.ShareServiceClient,
 	fileClient *rpc.FileServiceClient,
+	downloadCfg global.DownloadConfig,
 ) *GatewayServer {
 	// 创建处理器实例
 	userHandler := handler.NewUserHandler(userClient)
 	shareHandler := handler.NewShareHandler(shareClient)
-	fileHandler := handler.NewFileHandler(fileClient)
+	fileHandler := handler.NewFileHandler(fileClient, downloadCfg.Mode)
 
 	// 创建IP限流器 (每秒10个请求，突发20个)
 	ipRateLimiter := utils.NewIPRateLimiter(rate.Limit(10), 20)
@@ -40,6 +43,7 @@ func NewGatewayServer(
 		ShareHandler:  shareHandler,
 		FileHandler:   fileHandler,
 		ShareClient:   shareClient,
+		UserClient:    userClient,
 		IPRateLimiter: ipRateLimiter,
 	}
 }
@@ -64,7 +68,7 @@ func (s *GatewayServer) StartHTTPServer(addr string) error {
 		MaxHeaderBytes: 1 << 20, // 1MB
 	}
 	// 注册路由，直接传递handler实例
-	router.RegisterRoutes(r, s.UserHandler, s.ShareHandler, s.FileHandler, s.ShareClient, s.IPRateLimiter)
+	router.RegisterRoutes(r, s.UserHandler, s.ShareHandler, s.FileHandler, s.ShareClient, s.UserClient, s.IPRateLimiter)
 
 	utils.Info("HTTP server starting on %s", addr)
 	return server.ListenAndServe()
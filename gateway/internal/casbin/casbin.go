@@ -68,6 +68,16 @@ func GetEnforcer() *casbin.Enforcer {
 	return enforcer
 }
 
+// SetEnforcer 替换全局执行器，供测试使用内存中的策略
+func SetEnforcer(e *casbin.Enforcer) {
+	enforcer = e
+}
+
+// Enforce 检查 userID 能否对 resource 执行 action
+func Enforce(userID, resource, action string) (bool, error) {
+	return enforcer.Enforce(userID, resource, action)
+}
+
 // AddPolicy 添加策略
 func AddPolicy(userID, resource, action string) (bool, error) {
 	return enforcer.AddPolicy(userID, resource, action)
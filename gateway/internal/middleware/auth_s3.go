@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	pack "github.com/waitform/micro-cloud-storage/internal/pack"
+	"github.com/waitform/micro-cloud-storage/internal/rpc"
+	userpb "github.com/waitform/micro-cloud-storage/protos/user/proto"
+	"github.com/waitform/micro-cloud-storage/utils"
+)
+
+// AuthS3Middleware S3 兼容接口鉴权中间件
+// 按请求中的访问密钥ID查出密钥和所属用户，校验 SigV4 签名后把用户ID写入上下文，
+// 请求体替换为边读边校验载荷摘要或块签名的 reader
+func AuthS3Middleware(userClient *rpc.UserServiceClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sig, err := utils.ParseSigV4(c.Request)
+		if err != nil {
+			abortS3(c, err)
+			return
+		}
+
+		resp, err := userClient.LookupAccessKey(context.Background(), &userpb.LookupAccessKeyRequest{AccessKeyId: sig.AccessKeyID})
+		if err != nil {
+			pack.WriteS3Error(c, http.StatusForbidden, "InvalidAccessKeyId", "The access key ID you provided does not exist in our records")
+			c.Abort()
+			return
+		}
+
+		if err := sig.Verify(c.Request, resp.GetSecretAccessKey(), time.Now()); err != nil {
+			abortS3(c, err)
+			return
+		}
+
+		c.Set("user_id", resp.GetUserId())
+		c.Next()
+	}
+}
+
+// abortS3 按签名错误对应的 S3 错误码响应并终止请求
+func abortS3(c *gin.Context, err error) {
+	var sigErr *utils.SigV4Error
+	if errors.As(err, &sigErr) {
+		pack.WriteS3Error(c, sigErr.Status, sigErr.Code, sigErr.Message)
+	} else {
+		pack.WriteS3Error(c, http.StatusForbidden, "AccessDenied", err.Error())
+	}
+	c.Abort()
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
@@ -32,15 +33,18 @@ func (cm *CasbinMiddleware) RequirePermission(objPrefix string, paramKey string,
 			return
 		}
 
-		userID, ok := userIDValue.(string)
+		userID, ok := subjectID(userIDValue)
 		if !ok {
 			pack.WriteError(c, http.StatusUnauthorized, "Invalid user ID")
 			c.Abort()
 			return
 		}
 
-		// 获取资源ID
+		// 获取资源ID，路径参数优先，其次是查询参数
 		resourceID := c.Param(paramKey)
+		if resourceID == "" {
+			resourceID = c.Query(paramKey)
+		}
 		if resourceID == "" {
 			pack.WriteError(c, http.StatusBadRequest, "Resource ID is required")
 			c.Abort()
@@ -67,3 +71,16 @@ func (cm *CasbinMiddleware) RequirePermission(objPrefix string, paramKey string,
 		c.Next()
 	}
 }
+
+// subjectID 把认证中间件写入的用户ID转换为 Casbin 策略中的主体
+func subjectID(v interface{}) (string, bool) {
+	switch id := v.(type) {
+	case uint:
+		return strconv.FormatUint(uint64(id), 10), true
+	case int64:
+		return strconv.FormatInt(id, 10), true
+	case string:
+		return id, id != ""
+	}
+	return "", false
+}
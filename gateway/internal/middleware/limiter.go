@@ -29,7 +29,12 @@ func TransferRateLimiterMiddleware(transferManager *utils.FairTransferManager) g
 		// 获取用户ID（如果已登录）
 		var userID uint
 		if userIDVal, exists := c.Get("user_id"); exists {
-			userID = uint(userIDVal.(int64))
+			switch id := userIDVal.(type) {
+			case uint:
+				userID = id
+			case int64:
+				userID = uint(id)
+			}
 		}
 
 		// 获取全局限速器
@@ -42,7 +47,8 @@ func TransferRateLimiterMiddleware(transferManager *utils.FairTransferManager) g
 		c.Set("global_limiter", globalLimiter)
 		c.Set("user_limiter", userLimiter)
 
-		// 继续处理请求
+		// 继续处理请求，传输结束后注销
 		c.Next()
+		transferManager.UnregisterTransfer(userID)
 	}
 }
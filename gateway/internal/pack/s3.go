@@ -0,0 +1,36 @@
+package pack
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// S3Error S3 兼容接口的错误响应格式
+type S3Error struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource"`
+	RequestID string   `xml:"RequestId"`
+}
+
+// WriteS3Error 写入 S3 风格的XML错误响应，code 为 S3 错误码
+func WriteS3Error(c *gin.Context, httpCode int, code, message string) {
+	c.XML(httpCode, S3Error{
+		Code:     code,
+		Message:  message,
+		Resource: c.Request.URL.Path,
+	})
+}
+
+// WriteS3XML 写入带XML声明的 S3 响应
+func WriteS3XML(c *gin.Context, httpCode int, data interface{}) {
+	body, err := xml.Marshal(data)
+	if err != nil {
+		WriteS3Error(c, http.StatusInternalServerError, "InternalError", "Failed to encode response")
+		return
+	}
+	c.Data(httpCode, "application/xml", append([]byte(xml.Header), body...))
+}
@@ -15,6 +15,7 @@ func RegisterRoutes(r *gin.Engine,
 	shareHandler *handler.ShareHandler,
 	fileHandler *handler.FileHandler,
 	shareClient *rpc.ShareServiceClient,
+	userClient *rpc.UserServiceClient,
 	ipRateLimiter *utils.IPRateLimiter) {
 
 	// 创建可复用的认证中间件实例
@@ -23,6 +24,9 @@ func RegisterRoutes(r *gin.Engine,
 	// 创建分享鉴权中间件实例
 	shareAuthMiddleware := middleware.AuthShareMiddleware(shareClient)
 
+	// 创建 S3 签名鉴权中间件实例
+	s3AuthMiddleware := middleware.AuthS3Middleware(userClient)
+
 	// 创建IP限流中间件实例
 	ipRateLimitMiddleware := middleware.IPRateLimitMiddleware(ipRateLimiter)
 
@@ -33,12 +37,20 @@ func RegisterRoutes(r *gin.Engine,
 	}
 	casbinMW.RequirePermission("file", "file_id", "download")
 
+	// 下载带宽公平分配中间件
+	transferRateLimitMiddleware := middleware.TransferRateLimiterMiddleware(utils.Manager)
+
 	// 注册用户相关路由
 	userGroup := r.Group("/api/user")
 	{
 		userGroup.POST("/register", ipRateLimitMiddleware, userHandler.HandleUserRegister)
 		userGroup.POST("/login", ipRateLimitMiddleware, userHandler.HandleUserLogin)
 		userGroup.GET("/info", userAuthMiddleware, userHandler.HandleGetUserInfo)
+
+		// S3 访问密钥
+		userGroup.POST("/access-key/create", userAuthMiddleware, userHandler.HandleCreateAccessKey)
+		userGroup.GET("/access-key/list", userAuthMiddleware, userHandler.HandleListAccessKeys)
+		userGroup.POST("/access-key/delete", userAuthMiddleware, userHandler.HandleDeleteAccessKey)
 	}
 
 	// 注册分享相关路由
@@ -58,17 +70,63 @@ func RegisterRoutes(r *gin.Engine,
 		fileGroup.POST("/upload/init", fileHandler.HandleInitUpload)
 		fileGroup.POST("/upload/part", fileHandler.HandleUploadPart)
 		fileGroup.POST("/upload/complete", fileHandler.HandleCompleteUpload)
-		fileGroup.GET("/info", casbinMW.RequirePermission("file", "file_id", "read"), fileHandler.HandleGetFileInfo)
+		fileGroup.POST("/upload/chunked/init", fileHandler.HandleInitChunkedUpload)
+		fileGroup.POST("/upload/import", fileHandler.HandleImportFromURL)
+		fileGroup.PUT("/upload/chunk", fileHandler.HandleUploadChunk)
+		fileGroup.GET("/info", casbinMW.RequirePermission("file:", "file_id", "read"), fileHandler.HandleGetFileInfo)
+		fileGroup.GET("/thumbnail", casbinMW.RequirePermission("file:", "file_id", "read"), fileHandler.HandleGetThumbnail)
 		fileGroup.POST("/presigned-url", fileHandler.HandleGeneratePresignedURL)
 		fileGroup.GET("/upload/progress", fileHandler.HandleGetUploadProgress)
 		fileGroup.POST("/upload/incomplete-parts", fileHandler.HandleGetIncompleteParts)
 		fileGroup.POST("/upload/cancel", fileHandler.HandleCancelUpload)
 		fileGroup.POST("/delete", fileHandler.HandleDeleteFile)
+		fileGroup.GET("/list", fileHandler.HandleListFiles)
+		fileGroup.GET("/search", fileHandler.HandleSearchFiles)
+		fileGroup.POST("/tags", fileHandler.HandleUpdateFileTags)
+		fileGroup.POST("/copy", fileHandler.HandleCopyFile)
+		fileGroup.POST("/move", fileHandler.HandleMoveFile)
+		fileGroup.POST("/extract", fileHandler.HandleExtractArchive)
+		fileGroup.GET("/download", transferRateLimitMiddleware, fileHandler.HandleDownloadFile)
+
+		// 文件夹
+		fileGroup.POST("/folder/create", fileHandler.HandleCreateFolder)
+		fileGroup.POST("/folder/rename", fileHandler.HandleRenameFolder)
+		fileGroup.POST("/folder/move", fileHandler.HandleMoveFolder)
+		fileGroup.POST("/folder/delete", fileHandler.HandleDeleteFolder)
+		fileGroup.GET("/folder/list", fileHandler.HandleListDirectory)
+
+		// 回收站
+		fileGroup.GET("/trash/list", fileHandler.HandleListTrash)
+		fileGroup.POST("/trash/restore", fileHandler.HandleRestoreFile)
+		fileGroup.POST("/trash/empty", fileHandler.HandleEmptyTrash)
+
+		// 文件版本
+		fileGroup.GET("/version/list", fileHandler.HandleListVersions)
+		fileGroup.GET("/version/info", fileHandler.HandleGetVersion)
+		fileGroup.POST("/version/restore", fileHandler.HandleRestoreVersion)
+		fileGroup.POST("/version/delete", fileHandler.HandleDeleteVersion)
+	}
+
+	// tus 可续传上传协议，OPTIONS 用于客户端发现服务端能力，不需要认证
+	tusGroup := r.Group("/api/file/tus")
+	{
+		tusGroup.OPTIONS("", fileHandler.HandleTusOptions)
+		tusGroup.OPTIONS("/:file_id", fileHandler.HandleTusOptions)
+		tusGroup.POST("", userAuthMiddleware, fileHandler.HandleTusCreate)
+		tusGroup.HEAD("/:file_id", userAuthMiddleware, fileHandler.HandleTusHead)
+		tusGroup.PATCH("/:file_id", userAuthMiddleware, fileHandler.HandleTusPatch)
+		tusGroup.DELETE("/:file_id", userAuthMiddleware, fileHandler.HandleTusDelete)
+		tusGroup.POST("/:file_id", userAuthMiddleware, fileHandler.HandleTusMethodOverride)
 	}
 
 	// 文件下载路由（支持分享链接访问）
 	downloadGroup := r.Group("/api/download")
 	{
-		downloadGroup.GET("", shareAuthMiddleware, fileHandler.HandleDownloadFile)
+		downloadGroup.GET("", shareAuthMiddleware, transferRateLimitMiddleware, fileHandler.HandleDownloadFile)
+		downloadGroup.POST("/archive", userAuthMiddleware, transferRateLimitMiddleware, fileHandler.HandleDownloadArchive)
 	}
+
+	// S3 兼容接口，使用访问密钥签名鉴权
+	r.Any("/s3", s3AuthMiddleware, transferRateLimitMiddleware, fileHandler.HandleS3)
+	r.Any("/s3/*path", s3AuthMiddleware, transferRateLimitMiddleware, fileHandler.HandleS3)
 }
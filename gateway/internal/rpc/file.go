@@ -54,6 +54,14 @@ func NewFileServiceClient(serviceClient *ServiceClient) (*FileServiceClient, err
 	}, nil
 }
 
+// NewFileServiceClientWithConn 使用已建立的连接创建文件服务客户端，不经过服务发现
+func NewFileServiceClientWithConn(conn *grpc.ClientConn) *FileServiceClient {
+	return &FileServiceClient{
+		grpcClient: filepb.NewFileServiceClient(conn),
+		conn:       conn,
+	}
+}
+
 // Close 关闭gRPC连接
 func (f *FileServiceClient) Close() error {
 	if f.conn != nil {
@@ -176,6 +184,28 @@ func (f *FileServiceClient) UploadPartStream(
 	return resp, nil
 }
 
+// InitChunkedUpload 初始化分块上传，返回服务端缺少的分块
+func (f *FileServiceClient) InitChunkedUpload(ctx context.Context, req *filepb.InitChunkedUploadRequest) (*filepb.InitChunkedUploadResponse, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.InitChunkedUpload(ctx, req)
+}
+
+// UploadChunk 上传一个分块
+func (f *FileServiceClient) UploadChunk(ctx context.Context, req *filepb.UploadChunkRequest) (*emptypb.Empty, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.UploadChunk(ctx, req)
+}
+
 // CompleteUpload 完成上传
 func (f *FileServiceClient) CompleteUpload(ctx context.Context, req *filepb.CompleteUploadRequest) (*filepb.CompleteUploadResponse, error) {
 	// 设置默认超时时间，完成上传可能需要合并大量分片，所以设置较长超时时间
@@ -200,6 +230,17 @@ func (f *FileServiceClient) GetFileInfo(ctx context.Context, req *filepb.GetFile
 	return f.grpcClient.GetFileInfo(ctx, req)
 }
 
+// GetThumbnail 获取缩略图
+func (f *FileServiceClient) GetThumbnail(ctx context.Context, req *filepb.GetThumbnailRequest) (*filepb.GetThumbnailResponse, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.GetThumbnail(ctx, req)
+}
+
 // GeneratePresignedURL 生成预签名URL
 func (f *FileServiceClient) GeneratePresignedURL(ctx context.Context, req *filepb.GeneratePresignedURLRequest) (*filepb.GeneratePresignedURLResponse, error) {
 	// 设置默认超时时间
@@ -236,6 +277,30 @@ func (f *FileServiceClient) GetIncompleteParts(ctx context.Context, req *filepb.
 	return f.grpcClient.GetIncompleteParts(ctx, req)
 }
 
+// ListUploadedParts 列出上传中版本已保存的分片
+func (f *FileServiceClient) ListUploadedParts(ctx context.Context, req *filepb.ListUploadedPartsRequest) (*filepb.ListUploadedPartsResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.ListUploadedParts(ctx, req)
+}
+
+// GetUploadOffset 获取按偏移续传的上传状态
+func (f *FileServiceClient) GetUploadOffset(ctx context.Context, req *filepb.GetUploadOffsetRequest) (*filepb.GetUploadOffsetResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.GetUploadOffset(ctx, req)
+}
+
 // CancelUpload 取消上传
 func (f *FileServiceClient) CancelUpload(ctx context.Context, req *filepb.CancelUploadRequest) (*emptypb.Empty, error) {
 	// 设置默认超时时间
@@ -259,3 +324,268 @@ func (f *FileServiceClient) DeleteFile(ctx context.Context, req *filepb.DeleteRe
 
 	return f.grpcClient.DeleteFile(ctx, req)
 }
+
+// CreateFolder 创建文件夹
+func (f *FileServiceClient) CreateFolder(ctx context.Context, req *filepb.CreateFolderRequest) (*filepb.CreateFolderResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.CreateFolder(ctx, req)
+}
+
+// RenameFolder 重命名文件夹
+func (f *FileServiceClient) RenameFolder(ctx context.Context, req *filepb.RenameFolderRequest) (*filepb.RenameFolderResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.RenameFolder(ctx, req)
+}
+
+// MoveFolder 移动文件夹
+func (f *FileServiceClient) MoveFolder(ctx context.Context, req *filepb.MoveFolderRequest) (*filepb.MoveFolderResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.MoveFolder(ctx, req)
+}
+
+// DeleteFolder 删除文件夹
+func (f *FileServiceClient) DeleteFolder(ctx context.Context, req *filepb.DeleteFolderRequest) (*emptypb.Empty, error) {
+	// 递归删除可能涉及大量文件，设置较长超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.DeleteFolder(ctx, req)
+}
+
+// ListDirectory 列出目录内容
+func (f *FileServiceClient) ListDirectory(ctx context.Context, req *filepb.ListDirectoryRequest) (*filepb.ListDirectoryResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.ListDirectory(ctx, req)
+}
+
+// LookupPath 按名称路径查找文件夹和文件
+func (f *FileServiceClient) LookupPath(ctx context.Context, req *filepb.LookupPathRequest) (*filepb.LookupPathResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.LookupPath(ctx, req)
+}
+
+// ListTrash 列出回收站
+func (f *FileServiceClient) ListTrash(ctx context.Context, req *filepb.ListTrashRequest) (*filepb.ListTrashResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.ListTrash(ctx, req)
+}
+
+// RestoreFile 从回收站恢复文件
+func (f *FileServiceClient) RestoreFile(ctx context.Context, req *filepb.RestoreFileRequest) (*filepb.RestoreFileResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.RestoreFile(ctx, req)
+}
+
+// EmptyTrash 清空回收站
+func (f *FileServiceClient) EmptyTrash(ctx context.Context, req *filepb.EmptyTrashRequest) (*filepb.EmptyTrashResponse, error) {
+	// 清空回收站需要逐个删除对象，设置较长超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.EmptyTrash(ctx, req)
+}
+
+// ListVersions 列出文件版本
+func (f *FileServiceClient) ListVersions(ctx context.Context, req *filepb.ListVersionsRequest) (*filepb.ListVersionsResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.ListVersions(ctx, req)
+}
+
+// GetVersion 获取文件版本
+func (f *FileServiceClient) GetVersion(ctx context.Context, req *filepb.GetVersionRequest) (*filepb.GetVersionResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.GetVersion(ctx, req)
+}
+
+// RestoreVersion 恢复历史版本
+func (f *FileServiceClient) RestoreVersion(ctx context.Context, req *filepb.RestoreVersionRequest) (*filepb.RestoreVersionResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.RestoreVersion(ctx, req)
+}
+
+// DeleteVersion 删除历史版本
+func (f *FileServiceClient) DeleteVersion(ctx context.Context, req *filepb.DeleteVersionRequest) (*emptypb.Empty, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.DeleteVersion(ctx, req)
+}
+
+// ListFiles 列出用户的所有文件
+func (f *FileServiceClient) ListFiles(ctx context.Context, req *filepb.ListFilesRequest) (*filepb.ListFilesResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.ListFiles(ctx, req)
+}
+
+// SearchFiles 搜索文件
+func (f *FileServiceClient) SearchFiles(ctx context.Context, req *filepb.SearchFilesRequest) (*filepb.SearchFilesResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.SearchFiles(ctx, req)
+}
+
+// UpdateFileTags 设置或删除文件标签
+func (f *FileServiceClient) UpdateFileTags(ctx context.Context, req *filepb.UpdateFileTagsRequest) (*filepb.UpdateFileTagsResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.UpdateFileTags(ctx, req)
+}
+
+// CopyFile 复制文件
+func (f *FileServiceClient) CopyFile(ctx context.Context, req *filepb.CopyFileRequest) (*filepb.CopyFileResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.CopyFile(ctx, req)
+}
+
+// MoveFile 移动文件或转移文件所有权
+func (f *FileServiceClient) MoveFile(ctx context.Context, req *filepb.MoveFileRequest) (*filepb.MoveFileResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+
+	return f.grpcClient.MoveFile(ctx, req)
+}
+
+// ExtractArchive 解压压缩包，耗时与压缩包大小有关，默认超时较长
+func (f *FileServiceClient) ExtractArchive(ctx context.Context, req *filepb.ExtractArchiveRequest) (*filepb.ExtractArchiveResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Minute)
+		defer cancel()
+	}
+
+	return f.grpcClient.ExtractArchive(ctx, req)
+}
+
+// ImportFromURL 从URL导入文件，远程内容在文件服务后台写入
+func (f *FileServiceClient) ImportFromURL(ctx context.Context, req *filepb.ImportFromURLRequest) (*filepb.ImportFromURLResponse, error) {
+	// 设置默认超时时间，只包括请求远程文件和创建上传会话
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 2*time.Minute)
+		defer cancel()
+	}
+
+	return f.grpcClient.ImportFromURL(ctx, req)
+}
+
+// ReadFile 按字节范围读取文件并写入 w，返回写入的字节数
+// 下载耗时与文件大小有关，不设置默认超时，由调用方通过 ctx 控制取消
+func (f *FileServiceClient) ReadFile(ctx context.Context, req *filepb.ReadFileRequest, w io.Writer) (int64, error) {
+	stream, err := f.grpcClient.ReadFile(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		n, err := w.Write(resp.GetData())
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+}
@@ -91,3 +91,51 @@ func (u *UserServiceClient) GetUserInfo(ctx context.Context, req *userpb.GetUser
 
 	return u.grpcClient.GetUserInfo(ctx, req)
 }
+
+// CreateAccessKey 创建 S3 访问密钥
+func (u *UserServiceClient) CreateAccessKey(ctx context.Context, req *userpb.CreateAccessKeyRequest) (*userpb.CreateAccessKeyResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+	}
+
+	return u.grpcClient.CreateAccessKey(ctx, req)
+}
+
+// ListAccessKeys 列出 S3 访问密钥
+func (u *UserServiceClient) ListAccessKeys(ctx context.Context, req *userpb.ListAccessKeysRequest) (*userpb.ListAccessKeysResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+	}
+
+	return u.grpcClient.ListAccessKeys(ctx, req)
+}
+
+// DeleteAccessKey 删除 S3 访问密钥
+func (u *UserServiceClient) DeleteAccessKey(ctx context.Context, req *userpb.DeleteAccessKeyRequest) (*userpb.DeleteAccessKeyResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+	}
+
+	return u.grpcClient.DeleteAccessKey(ctx, req)
+}
+
+// LookupAccessKey 查询访问密钥所属的用户和密钥
+func (u *UserServiceClient) LookupAccessKey(ctx context.Context, req *userpb.LookupAccessKeyRequest) (*userpb.LookupAccessKeyResponse, error) {
+	// 设置默认超时时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+	}
+
+	return u.grpcClient.LookupAccessKey(ctx, req)
+}
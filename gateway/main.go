@@ -103,7 +103,7 @@ func closeServiceClients() {
 
 // 初始化网关服务器
 func initGatewayServer() {
-	gatewayServer = api.NewGatewayServer(userClient, shareClient, fileClient)
+	gatewayServer = api.NewGatewayServer(userClient, shareClient, fileClient, globalCfg.Download)
 	utils.Info("gateway server initialized")
 }
 func initCasbin() {
@@ -7,12 +7,11 @@
 package userpb
 
 import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
-
-	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
-	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 )
 
 const (
@@ -898,74 +897,947 @@ func (x *CheckCapacityResponse) GetRemaining() int64 {
 	return 0
 }
 
-var File_user_proto protoreflect.FileDescriptor
+// 预留空间（初始化上传时调用），剩余容量不足时失败
+type ReserveSpaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_user_proto_rawDesc = "" +
-	"\n" +
-	"\n" +
-	"user.proto\x12\fuser_service\"\xde\x01\n" +
-	"\x04User\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
-	"\x05email\x18\x03 \x01(\tR\x05email\x12\x16\n" +
-	"\x06avatar\x18\x04 \x01(\tR\x06avatar\x12\x1f\n" +
-	"\vtotal_space\x18\x05 \x01(\x03R\n" +
-	"totalSpace\x12\x1d\n" +
-	"\n" +
-	"used_space\x18\x06 \x01(\x03R\tusedSpace\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\b \x01(\tR\tupdatedAt\"_\n" +
-	"\x0fRegisterRequest\x12\x1a\n" +
-	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x14\n" +
-	"\x05email\x18\x03 \x01(\tR\x05email\"n\n" +
-	"\x10RegisterResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
-	"\x04user\x18\x03 \x01(\v2\x12.user_service.UserR\x04user\"F\n" +
-	"\fLoginRequest\x12\x1a\n" +
-	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"r\n" +
-	"\rLoginResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x17\n" +
-	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x14\n" +
-	"\x05token\x18\x04 \x01(\tR\x05token\"-\n" +
-	"\x12GetUserInfoRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\"=\n" +
-	"\x13GetUserInfoResponse\x12&\n" +
-	"\x04user\x18\x01 \x01(\v2\x12.user_service.UserR\x04user\"^\n" +
-	"\x15UpdateUserInfoRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\x12\x16\n" +
-	"\x06avatar\x18\x03 \x01(\tR\x06avatar\"L\n" +
-	"\x16UpdateUserInfoResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"C\n" +
-	"\x12UpdateUsageRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x14\n" +
-	"\x05delta\x18\x02 \x01(\x03R\x05delta\"N\n" +
-	"\x13UpdateUsageResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1d\n" +
-	"\n" +
-	"used_space\x18\x02 \x01(\x03R\tusedSpace\"X\n" +
-	"\x15UpdateCapacityRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12&\n" +
-	"\x0fnew_total_space\x18\x02 \x01(\x03R\rnewTotalSpace\"m\n" +
-	"\x16UpdateCapacityResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
-	"\vtotal_space\x18\x03 \x01(\x03R\n" +
-	"totalSpace\"L\n" +
-	"\x14CheckCapacityRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
-	"\tfile_size\x18\x02 \x01(\x03R\bfileSize\"g\n" +
-	"\x15CheckCapacityResponse\x12\x16\n" +
-	"\x06enough\x18\x01 \x01(\bR\x06enough\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
-	"\tremaining\x18\x03 \x01(\x03R\tremaining2\xd6\x04\n" +
+func (x *ReserveSpaceRequest) Reset() {
+	*x = ReserveSpaceRequest{}
+	mi := &file_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveSpaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveSpaceRequest) ProtoMessage() {}
+
+func (x *ReserveSpaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveSpaceRequest.ProtoReflect.Descriptor instead.
+func (*ReserveSpaceRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReserveSpaceRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ReserveSpaceRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type ReserveSpaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	UsedSpace     int64                  `protobuf:"varint,3,opt,name=used_space,json=usedSpace,proto3" json:"used_space,omitempty"`
+	Remaining     int64                  `protobuf:"varint,4,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveSpaceResponse) Reset() {
+	*x = ReserveSpaceResponse{}
+	mi := &file_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveSpaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveSpaceResponse) ProtoMessage() {}
+
+func (x *ReserveSpaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveSpaceResponse.ProtoReflect.Descriptor instead.
+func (*ReserveSpaceResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ReserveSpaceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ReserveSpaceResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ReserveSpaceResponse) GetUsedSpace() int64 {
+	if x != nil {
+		return x.UsedSpace
+	}
+	return 0
+}
+
+func (x *ReserveSpaceResponse) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+// 设置已用空间（对账任务调用），只在当前已用空间仍为 expected_used_space 时设置
+type SetUsageRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UserId            int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UsedSpace         int64                  `protobuf:"varint,2,opt,name=used_space,json=usedSpace,proto3" json:"used_space,omitempty"`
+	ExpectedUsedSpace int64                  `protobuf:"varint,3,opt,name=expected_used_space,json=expectedUsedSpace,proto3" json:"expected_used_space,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SetUsageRequest) Reset() {
+	*x = SetUsageRequest{}
+	mi := &file_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUsageRequest) ProtoMessage() {}
+
+func (x *SetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUsageRequest.ProtoReflect.Descriptor instead.
+func (*SetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SetUsageRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SetUsageRequest) GetUsedSpace() int64 {
+	if x != nil {
+		return x.UsedSpace
+	}
+	return 0
+}
+
+func (x *SetUsageRequest) GetExpectedUsedSpace() int64 {
+	if x != nil {
+		return x.ExpectedUsedSpace
+	}
+	return 0
+}
+
+type SetUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Conflict      bool                   `protobuf:"varint,2,opt,name=conflict,proto3" json:"conflict,omitempty"` // 已用空间已被其他请求修改，未设置
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUsageResponse) Reset() {
+	*x = SetUsageResponse{}
+	mi := &file_user_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUsageResponse) ProtoMessage() {}
+
+func (x *SetUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUsageResponse.ProtoReflect.Descriptor instead.
+func (*SetUsageResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetUsageResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetUsageResponse) GetConflict() bool {
+	if x != nil {
+		return x.Conflict
+	}
+	return false
+}
+
+// 分页列出已用空间不为0的用户（对账任务调用）
+type ListUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AfterId       int64                  `protobuf:"varint,1,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"` // 只返回ID大于该值的用户
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsageRequest) Reset() {
+	*x = ListUsageRequest{}
+	mi := &file_user_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsageRequest) ProtoMessage() {}
+
+func (x *ListUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsageRequest.ProtoReflect.Descriptor instead.
+func (*ListUsageRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListUsageRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+func (x *ListUsageRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type UserUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UsedSpace     int64                  `protobuf:"varint,2,opt,name=used_space,json=usedSpace,proto3" json:"used_space,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserUsage) Reset() {
+	*x = UserUsage{}
+	mi := &file_user_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserUsage) ProtoMessage() {}
+
+func (x *UserUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserUsage.ProtoReflect.Descriptor instead.
+func (*UserUsage) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UserUsage) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UserUsage) GetUsedSpace() int64 {
+	if x != nil {
+		return x.UsedSpace
+	}
+	return 0
+}
+
+type ListUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Usages        []*UserUsage           `protobuf:"bytes,1,rep,name=usages,proto3" json:"usages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsageResponse) Reset() {
+	*x = ListUsageResponse{}
+	mi := &file_user_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsageResponse) ProtoMessage() {}
+
+func (x *ListUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsageResponse.ProtoReflect.Descriptor instead.
+func (*ListUsageResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListUsageResponse) GetUsages() []*UserUsage {
+	if x != nil {
+		return x.Usages
+	}
+	return nil
+}
+
+// S3 访问密钥，用于 SigV4 签名
+type AccessKey struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeyId     string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	SecretAccessKey string                 `protobuf:"bytes,2,opt,name=secret_access_key,json=secretAccessKey,proto3" json:"secret_access_key,omitempty"` // 只在创建时返回
+	CreatedAt       int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AccessKey) Reset() {
+	*x = AccessKey{}
+	mi := &file_user_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessKey) ProtoMessage() {}
+
+func (x *AccessKey) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessKey.ProtoReflect.Descriptor instead.
+func (*AccessKey) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AccessKey) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+func (x *AccessKey) GetSecretAccessKey() string {
+	if x != nil {
+		return x.SecretAccessKey
+	}
+	return ""
+}
+
+func (x *AccessKey) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type CreateAccessKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAccessKeyRequest) Reset() {
+	*x = CreateAccessKeyRequest{}
+	mi := &file_user_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccessKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccessKeyRequest) ProtoMessage() {}
+
+func (x *CreateAccessKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccessKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccessKeyRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CreateAccessKeyRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type CreateAccessKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessKey     *AccessKey             `protobuf:"bytes,1,opt,name=access_key,json=accessKey,proto3" json:"access_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAccessKeyResponse) Reset() {
+	*x = CreateAccessKeyResponse{}
+	mi := &file_user_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccessKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccessKeyResponse) ProtoMessage() {}
+
+func (x *CreateAccessKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccessKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAccessKeyResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CreateAccessKeyResponse) GetAccessKey() *AccessKey {
+	if x != nil {
+		return x.AccessKey
+	}
+	return nil
+}
+
+type ListAccessKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccessKeysRequest) Reset() {
+	*x = ListAccessKeysRequest{}
+	mi := &file_user_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccessKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccessKeysRequest) ProtoMessage() {}
+
+func (x *ListAccessKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccessKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAccessKeysRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListAccessKeysRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type ListAccessKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeys    []*AccessKey           `protobuf:"bytes,1,rep,name=access_keys,json=accessKeys,proto3" json:"access_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccessKeysResponse) Reset() {
+	*x = ListAccessKeysResponse{}
+	mi := &file_user_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccessKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccessKeysResponse) ProtoMessage() {}
+
+func (x *ListAccessKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccessKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAccessKeysResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListAccessKeysResponse) GetAccessKeys() []*AccessKey {
+	if x != nil {
+		return x.AccessKeys
+	}
+	return nil
+}
+
+type DeleteAccessKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AccessKeyId   string                 `protobuf:"bytes,2,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccessKeyRequest) Reset() {
+	*x = DeleteAccessKeyRequest{}
+	mi := &file_user_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccessKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccessKeyRequest) ProtoMessage() {}
+
+func (x *DeleteAccessKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccessKeyRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAccessKeyRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DeleteAccessKeyRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *DeleteAccessKeyRequest) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+type DeleteAccessKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccessKeyResponse) Reset() {
+	*x = DeleteAccessKeyResponse{}
+	mi := &file_user_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccessKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccessKeyResponse) ProtoMessage() {}
+
+func (x *DeleteAccessKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccessKeyResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAccessKeyResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DeleteAccessKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteAccessKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 查询访问密钥（网关校验签名时调用），返回所属用户和密钥
+type LookupAccessKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeyId   string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupAccessKeyRequest) Reset() {
+	*x = LookupAccessKeyRequest{}
+	mi := &file_user_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupAccessKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupAccessKeyRequest) ProtoMessage() {}
+
+func (x *LookupAccessKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupAccessKeyRequest.ProtoReflect.Descriptor instead.
+func (*LookupAccessKeyRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *LookupAccessKeyRequest) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+type LookupAccessKeyResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SecretAccessKey string                 `protobuf:"bytes,2,opt,name=secret_access_key,json=secretAccessKey,proto3" json:"secret_access_key,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LookupAccessKeyResponse) Reset() {
+	*x = LookupAccessKeyResponse{}
+	mi := &file_user_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupAccessKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupAccessKeyResponse) ProtoMessage() {}
+
+func (x *LookupAccessKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupAccessKeyResponse.ProtoReflect.Descriptor instead.
+func (*LookupAccessKeyResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *LookupAccessKeyResponse) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *LookupAccessKeyResponse) GetSecretAccessKey() string {
+	if x != nil {
+		return x.SecretAccessKey
+	}
+	return ""
+}
+
+var File_user_proto protoreflect.FileDescriptor
+
+const file_user_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"user.proto\x12\fuser_service\"\xde\x01\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x16\n" +
+	"\x06avatar\x18\x04 \x01(\tR\x06avatar\x12\x1f\n" +
+	"\vtotal_space\x18\x05 \x01(\x03R\n" +
+	"totalSpace\x12\x1d\n" +
+	"\n" +
+	"used_space\x18\x06 \x01(\x03R\tusedSpace\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\"_\n" +
+	"\x0fRegisterRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\"n\n" +
+	"\x10RegisterResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
+	"\x04user\x18\x03 \x01(\v2\x12.user_service.UserR\x04user\"F\n" +
+	"\fLoginRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"r\n" +
+	"\rLoginResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05token\x18\x04 \x01(\tR\x05token\"-\n" +
+	"\x12GetUserInfoRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"=\n" +
+	"\x13GetUserInfoResponse\x12&\n" +
+	"\x04user\x18\x01 \x01(\v2\x12.user_service.UserR\x04user\"^\n" +
+	"\x15UpdateUserInfoRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x16\n" +
+	"\x06avatar\x18\x03 \x01(\tR\x06avatar\"L\n" +
+	"\x16UpdateUserInfoResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"C\n" +
+	"\x12UpdateUsageRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x03R\x05delta\"N\n" +
+	"\x13UpdateUsageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1d\n" +
+	"\n" +
+	"used_space\x18\x02 \x01(\x03R\tusedSpace\"X\n" +
+	"\x15UpdateCapacityRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12&\n" +
+	"\x0fnew_total_space\x18\x02 \x01(\x03R\rnewTotalSpace\"m\n" +
+	"\x16UpdateCapacityResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
+	"\vtotal_space\x18\x03 \x01(\x03R\n" +
+	"totalSpace\"L\n" +
+	"\x14CheckCapacityRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tfile_size\x18\x02 \x01(\x03R\bfileSize\"g\n" +
+	"\x15CheckCapacityResponse\x12\x16\n" +
+	"\x06enough\x18\x01 \x01(\bR\x06enough\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
+	"\tremaining\x18\x03 \x01(\x03R\tremaining\"B\n" +
+	"\x13ReserveSpaceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\"\x87\x01\n" +
+	"\x14ReserveSpaceResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"used_space\x18\x03 \x01(\x03R\tusedSpace\x12\x1c\n" +
+	"\tremaining\x18\x04 \x01(\x03R\tremaining\"y\n" +
+	"\x0fSetUsageRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"used_space\x18\x02 \x01(\x03R\tusedSpace\x12.\n" +
+	"\x13expected_used_space\x18\x03 \x01(\x03R\x11expectedUsedSpace\"H\n" +
+	"\x10SetUsageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1a\n" +
+	"\bconflict\x18\x02 \x01(\bR\bconflict\"C\n" +
+	"\x10ListUsageRequest\x12\x19\n" +
+	"\bafter_id\x18\x01 \x01(\x03R\aafterId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"C\n" +
+	"\tUserUsage\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"used_space\x18\x02 \x01(\x03R\tusedSpace\"D\n" +
+	"\x11ListUsageResponse\x12/\n" +
+	"\x06usages\x18\x01 \x03(\v2\x17.user_service.UserUsageR\x06usages\"z\n" +
+	"\tAccessKey\x12\"\n" +
+	"\raccess_key_id\x18\x01 \x01(\tR\vaccessKeyId\x12*\n" +
+	"\x11secret_access_key\x18\x02 \x01(\tR\x0fsecretAccessKey\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\"1\n" +
+	"\x16CreateAccessKeyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"Q\n" +
+	"\x17CreateAccessKeyResponse\x126\n" +
+	"\n" +
+	"access_key\x18\x01 \x01(\v2\x17.user_service.AccessKeyR\taccessKey\"0\n" +
+	"\x15ListAccessKeysRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"R\n" +
+	"\x16ListAccessKeysResponse\x128\n" +
+	"\vaccess_keys\x18\x01 \x03(\v2\x17.user_service.AccessKeyR\n" +
+	"accessKeys\"U\n" +
+	"\x16DeleteAccessKeyRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\"\n" +
+	"\raccess_key_id\x18\x02 \x01(\tR\vaccessKeyId\"M\n" +
+	"\x17DeleteAccessKeyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"<\n" +
+	"\x16LookupAccessKeyRequest\x12\"\n" +
+	"\raccess_key_id\x18\x01 \x01(\tR\vaccessKeyId\"^\n" +
+	"\x17LookupAccessKeyResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12*\n" +
+	"\x11secret_access_key\x18\x02 \x01(\tR\x0fsecretAccessKey2\xc3\t\n" +
 	"\vUserService\x12I\n" +
 	"\bRegister\x12\x1d.user_service.RegisterRequest\x1a\x1e.user_service.RegisterResponse\x12@\n" +
 	"\x05Login\x12\x1a.user_service.LoginRequest\x1a\x1b.user_service.LoginResponse\x12R\n" +
@@ -973,7 +1845,14 @@ const file_user_proto_rawDesc = "" +
 	"\x0eUpdateUserInfo\x12#.user_service.UpdateUserInfoRequest\x1a$.user_service.UpdateUserInfoResponse\x12R\n" +
 	"\vUpdateUsage\x12 .user_service.UpdateUsageRequest\x1a!.user_service.UpdateUsageResponse\x12[\n" +
 	"\x0eUpdateCapacity\x12#.user_service.UpdateCapacityRequest\x1a$.user_service.UpdateCapacityResponse\x12X\n" +
-	"\rCheckCapacity\x12\".user_service.CheckCapacityRequest\x1a#.user_service.CheckCapacityResponseB\x15Z\x13/proto;user_serviceb\x06proto3"
+	"\rCheckCapacity\x12\".user_service.CheckCapacityRequest\x1a#.user_service.CheckCapacityResponse\x12U\n" +
+	"\fReserveSpace\x12!.user_service.ReserveSpaceRequest\x1a\".user_service.ReserveSpaceResponse\x12I\n" +
+	"\bSetUsage\x12\x1d.user_service.SetUsageRequest\x1a\x1e.user_service.SetUsageResponse\x12L\n" +
+	"\tListUsage\x12\x1e.user_service.ListUsageRequest\x1a\x1f.user_service.ListUsageResponse\x12^\n" +
+	"\x0fCreateAccessKey\x12$.user_service.CreateAccessKeyRequest\x1a%.user_service.CreateAccessKeyResponse\x12[\n" +
+	"\x0eListAccessKeys\x12#.user_service.ListAccessKeysRequest\x1a$.user_service.ListAccessKeysResponse\x12^\n" +
+	"\x0fDeleteAccessKey\x12$.user_service.DeleteAccessKeyRequest\x1a%.user_service.DeleteAccessKeyResponse\x12^\n" +
+	"\x0fLookupAccessKey\x12$.user_service.LookupAccessKeyRequest\x1a%.user_service.LookupAccessKeyResponseB\x0fZ\r/proto;userpbb\x06proto3"
 
 var (
 	file_user_proto_rawDescOnce sync.Once
@@ -987,46 +1866,79 @@ func file_user_proto_rawDescGZIP() []byte {
 	return file_user_proto_rawDescData
 }
 
-var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_user_proto_goTypes = []any{
-	(*User)(nil),                   // 0: user_service.User
-	(*RegisterRequest)(nil),        // 1: user_service.RegisterRequest
-	(*RegisterResponse)(nil),       // 2: user_service.RegisterResponse
-	(*LoginRequest)(nil),           // 3: user_service.LoginRequest
-	(*LoginResponse)(nil),          // 4: user_service.LoginResponse
-	(*GetUserInfoRequest)(nil),     // 5: user_service.GetUserInfoRequest
-	(*GetUserInfoResponse)(nil),    // 6: user_service.GetUserInfoResponse
-	(*UpdateUserInfoRequest)(nil),  // 7: user_service.UpdateUserInfoRequest
-	(*UpdateUserInfoResponse)(nil), // 8: user_service.UpdateUserInfoResponse
-	(*UpdateUsageRequest)(nil),     // 9: user_service.UpdateUsageRequest
-	(*UpdateUsageResponse)(nil),    // 10: user_service.UpdateUsageResponse
-	(*UpdateCapacityRequest)(nil),  // 11: user_service.UpdateCapacityRequest
-	(*UpdateCapacityResponse)(nil), // 12: user_service.UpdateCapacityResponse
-	(*CheckCapacityRequest)(nil),   // 13: user_service.CheckCapacityRequest
-	(*CheckCapacityResponse)(nil),  // 14: user_service.CheckCapacityResponse
+	(*User)(nil),                    // 0: user_service.User
+	(*RegisterRequest)(nil),         // 1: user_service.RegisterRequest
+	(*RegisterResponse)(nil),        // 2: user_service.RegisterResponse
+	(*LoginRequest)(nil),            // 3: user_service.LoginRequest
+	(*LoginResponse)(nil),           // 4: user_service.LoginResponse
+	(*GetUserInfoRequest)(nil),      // 5: user_service.GetUserInfoRequest
+	(*GetUserInfoResponse)(nil),     // 6: user_service.GetUserInfoResponse
+	(*UpdateUserInfoRequest)(nil),   // 7: user_service.UpdateUserInfoRequest
+	(*UpdateUserInfoResponse)(nil),  // 8: user_service.UpdateUserInfoResponse
+	(*UpdateUsageRequest)(nil),      // 9: user_service.UpdateUsageRequest
+	(*UpdateUsageResponse)(nil),     // 10: user_service.UpdateUsageResponse
+	(*UpdateCapacityRequest)(nil),   // 11: user_service.UpdateCapacityRequest
+	(*UpdateCapacityResponse)(nil),  // 12: user_service.UpdateCapacityResponse
+	(*CheckCapacityRequest)(nil),    // 13: user_service.CheckCapacityRequest
+	(*CheckCapacityResponse)(nil),   // 14: user_service.CheckCapacityResponse
+	(*ReserveSpaceRequest)(nil),     // 15: user_service.ReserveSpaceRequest
+	(*ReserveSpaceResponse)(nil),    // 16: user_service.ReserveSpaceResponse
+	(*SetUsageRequest)(nil),         // 17: user_service.SetUsageRequest
+	(*SetUsageResponse)(nil),        // 18: user_service.SetUsageResponse
+	(*ListUsageRequest)(nil),        // 19: user_service.ListUsageRequest
+	(*UserUsage)(nil),               // 20: user_service.UserUsage
+	(*ListUsageResponse)(nil),       // 21: user_service.ListUsageResponse
+	(*AccessKey)(nil),               // 22: user_service.AccessKey
+	(*CreateAccessKeyRequest)(nil),  // 23: user_service.CreateAccessKeyRequest
+	(*CreateAccessKeyResponse)(nil), // 24: user_service.CreateAccessKeyResponse
+	(*ListAccessKeysRequest)(nil),   // 25: user_service.ListAccessKeysRequest
+	(*ListAccessKeysResponse)(nil),  // 26: user_service.ListAccessKeysResponse
+	(*DeleteAccessKeyRequest)(nil),  // 27: user_service.DeleteAccessKeyRequest
+	(*DeleteAccessKeyResponse)(nil), // 28: user_service.DeleteAccessKeyResponse
+	(*LookupAccessKeyRequest)(nil),  // 29: user_service.LookupAccessKeyRequest
+	(*LookupAccessKeyResponse)(nil), // 30: user_service.LookupAccessKeyResponse
 }
 var file_user_proto_depIdxs = []int32{
 	0,  // 0: user_service.RegisterResponse.user:type_name -> user_service.User
 	0,  // 1: user_service.GetUserInfoResponse.user:type_name -> user_service.User
-	1,  // 2: user_service.UserService.Register:input_type -> user_service.RegisterRequest
-	3,  // 3: user_service.UserService.Login:input_type -> user_service.LoginRequest
-	5,  // 4: user_service.UserService.GetUserInfo:input_type -> user_service.GetUserInfoRequest
-	7,  // 5: user_service.UserService.UpdateUserInfo:input_type -> user_service.UpdateUserInfoRequest
-	9,  // 6: user_service.UserService.UpdateUsage:input_type -> user_service.UpdateUsageRequest
-	11, // 7: user_service.UserService.UpdateCapacity:input_type -> user_service.UpdateCapacityRequest
-	13, // 8: user_service.UserService.CheckCapacity:input_type -> user_service.CheckCapacityRequest
-	2,  // 9: user_service.UserService.Register:output_type -> user_service.RegisterResponse
-	4,  // 10: user_service.UserService.Login:output_type -> user_service.LoginResponse
-	6,  // 11: user_service.UserService.GetUserInfo:output_type -> user_service.GetUserInfoResponse
-	8,  // 12: user_service.UserService.UpdateUserInfo:output_type -> user_service.UpdateUserInfoResponse
-	10, // 13: user_service.UserService.UpdateUsage:output_type -> user_service.UpdateUsageResponse
-	12, // 14: user_service.UserService.UpdateCapacity:output_type -> user_service.UpdateCapacityResponse
-	14, // 15: user_service.UserService.CheckCapacity:output_type -> user_service.CheckCapacityResponse
-	9,  // [9:16] is the sub-list for method output_type
-	2,  // [2:9] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	20, // 2: user_service.ListUsageResponse.usages:type_name -> user_service.UserUsage
+	22, // 3: user_service.CreateAccessKeyResponse.access_key:type_name -> user_service.AccessKey
+	22, // 4: user_service.ListAccessKeysResponse.access_keys:type_name -> user_service.AccessKey
+	1,  // 5: user_service.UserService.Register:input_type -> user_service.RegisterRequest
+	3,  // 6: user_service.UserService.Login:input_type -> user_service.LoginRequest
+	5,  // 7: user_service.UserService.GetUserInfo:input_type -> user_service.GetUserInfoRequest
+	7,  // 8: user_service.UserService.UpdateUserInfo:input_type -> user_service.UpdateUserInfoRequest
+	9,  // 9: user_service.UserService.UpdateUsage:input_type -> user_service.UpdateUsageRequest
+	11, // 10: user_service.UserService.UpdateCapacity:input_type -> user_service.UpdateCapacityRequest
+	13, // 11: user_service.UserService.CheckCapacity:input_type -> user_service.CheckCapacityRequest
+	15, // 12: user_service.UserService.ReserveSpace:input_type -> user_service.ReserveSpaceRequest
+	17, // 13: user_service.UserService.SetUsage:input_type -> user_service.SetUsageRequest
+	19, // 14: user_service.UserService.ListUsage:input_type -> user_service.ListUsageRequest
+	23, // 15: user_service.UserService.CreateAccessKey:input_type -> user_service.CreateAccessKeyRequest
+	25, // 16: user_service.UserService.ListAccessKeys:input_type -> user_service.ListAccessKeysRequest
+	27, // 17: user_service.UserService.DeleteAccessKey:input_type -> user_service.DeleteAccessKeyRequest
+	29, // 18: user_service.UserService.LookupAccessKey:input_type -> user_service.LookupAccessKeyRequest
+	2,  // 19: user_service.UserService.Register:output_type -> user_service.RegisterResponse
+	4,  // 20: user_service.UserService.Login:output_type -> user_service.LoginResponse
+	6,  // 21: user_service.UserService.GetUserInfo:output_type -> user_service.GetUserInfoResponse
+	8,  // 22: user_service.UserService.UpdateUserInfo:output_type -> user_service.UpdateUserInfoResponse
+	10, // 23: user_service.UserService.UpdateUsage:output_type -> user_service.UpdateUsageResponse
+	12, // 24: user_service.UserService.UpdateCapacity:output_type -> user_service.UpdateCapacityResponse
+	14, // 25: user_service.UserService.CheckCapacity:output_type -> user_service.CheckCapacityResponse
+	16, // 26: user_service.UserService.ReserveSpace:output_type -> user_service.ReserveSpaceResponse
+	18, // 27: user_service.UserService.SetUsage:output_type -> user_service.SetUsageResponse
+	21, // 28: user_service.UserService.ListUsage:output_type -> user_service.ListUsageResponse
+	24, // 29: user_service.UserService.CreateAccessKey:output_type -> user_service.CreateAccessKeyResponse
+	26, // 30: user_service.UserService.ListAccessKeys:output_type -> user_service.ListAccessKeysResponse
+	28, // 31: user_service.UserService.DeleteAccessKey:output_type -> user_service.DeleteAccessKeyResponse
+	30, // 32: user_service.UserService.LookupAccessKey:output_type -> user_service.LookupAccessKeyResponse
+	19, // [19:33] is the sub-list for method output_type
+	5,  // [5:19] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_user_proto_init() }
@@ -1040,7 +1952,7 @@ func file_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_proto_rawDesc), len(file_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -20,13 +20,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	UserService_Register_FullMethodName       = "/user_service.UserService/Register"
-	UserService_Login_FullMethodName          = "/user_service.UserService/Login"
-	UserService_GetUserInfo_FullMethodName    = "/user_service.UserService/GetUserInfo"
-	UserService_UpdateUserInfo_FullMethodName = "/user_service.UserService/UpdateUserInfo"
-	UserService_UpdateUsage_FullMethodName    = "/user_service.UserService/UpdateUsage"
-	UserService_UpdateCapacity_FullMethodName = "/user_service.UserService/UpdateCapacity"
-	UserService_CheckCapacity_FullMethodName  = "/user_service.UserService/CheckCapacity"
+	UserService_Register_FullMethodName        = "/user_service.UserService/Register"
+	UserService_Login_FullMethodName           = "/user_service.UserService/Login"
+	UserService_GetUserInfo_FullMethodName     = "/user_service.UserService/GetUserInfo"
+	UserService_UpdateUserInfo_FullMethodName  = "/user_service.UserService/UpdateUserInfo"
+	UserService_UpdateUsage_FullMethodName     = "/user_service.UserService/UpdateUsage"
+	UserService_UpdateCapacity_FullMethodName  = "/user_service.UserService/UpdateCapacity"
+	UserService_CheckCapacity_FullMethodName   = "/user_service.UserService/CheckCapacity"
+	UserService_ReserveSpace_FullMethodName    = "/user_service.UserService/ReserveSpace"
+	UserService_SetUsage_FullMethodName        = "/user_service.UserService/SetUsage"
+	UserService_ListUsage_FullMethodName       = "/user_service.UserService/ListUsage"
+	UserService_CreateAccessKey_FullMethodName = "/user_service.UserService/CreateAccessKey"
+	UserService_ListAccessKeys_FullMethodName  = "/user_service.UserService/ListAccessKeys"
+	UserService_DeleteAccessKey_FullMethodName = "/user_service.UserService/DeleteAccessKey"
+	UserService_LookupAccessKey_FullMethodName = "/user_service.UserService/LookupAccessKey"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -42,6 +49,13 @@ type UserServiceClient interface {
 	UpdateUsage(ctx context.Context, in *UpdateUsageRequest, opts ...grpc.CallOption) (*UpdateUsageResponse, error)
 	UpdateCapacity(ctx context.Context, in *UpdateCapacityRequest, opts ...grpc.CallOption) (*UpdateCapacityResponse, error)
 	CheckCapacity(ctx context.Context, in *CheckCapacityRequest, opts ...grpc.CallOption) (*CheckCapacityResponse, error)
+	ReserveSpace(ctx context.Context, in *ReserveSpaceRequest, opts ...grpc.CallOption) (*ReserveSpaceResponse, error)
+	SetUsage(ctx context.Context, in *SetUsageRequest, opts ...grpc.CallOption) (*SetUsageResponse, error)
+	ListUsage(ctx context.Context, in *ListUsageRequest, opts ...grpc.CallOption) (*ListUsageResponse, error)
+	CreateAccessKey(ctx context.Context, in *CreateAccessKeyRequest, opts ...grpc.CallOption) (*CreateAccessKeyResponse, error)
+	ListAccessKeys(ctx context.Context, in *ListAccessKeysRequest, opts ...grpc.CallOption) (*ListAccessKeysResponse, error)
+	DeleteAccessKey(ctx context.Context, in *DeleteAccessKeyRequest, opts ...grpc.CallOption) (*DeleteAccessKeyResponse, error)
+	LookupAccessKey(ctx context.Context, in *LookupAccessKeyRequest, opts ...grpc.CallOption) (*LookupAccessKeyResponse, error)
 }
 
 type userServiceClient struct {
@@ -122,6 +136,76 @@ func (c *userServiceClient) CheckCapacity(ctx context.Context, in *CheckCapacity
 	return out, nil
 }
 
+func (c *userServiceClient) ReserveSpace(ctx context.Context, in *ReserveSpaceRequest, opts ...grpc.CallOption) (*ReserveSpaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReserveSpaceResponse)
+	err := c.cc.Invoke(ctx, UserService_ReserveSpace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetUsage(ctx context.Context, in *SetUsageRequest, opts ...grpc.CallOption) (*SetUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetUsageResponse)
+	err := c.cc.Invoke(ctx, UserService_SetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListUsage(ctx context.Context, in *ListUsageRequest, opts ...grpc.CallOption) (*ListUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsageResponse)
+	err := c.cc.Invoke(ctx, UserService_ListUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateAccessKey(ctx context.Context, in *CreateAccessKeyRequest, opts ...grpc.CallOption) (*CreateAccessKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAccessKeyResponse)
+	err := c.cc.Invoke(ctx, UserService_CreateAccessKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListAccessKeys(ctx context.Context, in *ListAccessKeysRequest, opts ...grpc.CallOption) (*ListAccessKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAccessKeysResponse)
+	err := c.cc.Invoke(ctx, UserService_ListAccessKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteAccessKey(ctx context.Context, in *DeleteAccessKeyRequest, opts ...grpc.CallOption) (*DeleteAccessKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAccessKeyResponse)
+	err := c.cc.Invoke(ctx, UserService_DeleteAccessKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) LookupAccessKey(ctx context.Context, in *LookupAccessKeyRequest, opts ...grpc.CallOption) (*LookupAccessKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupAccessKeyResponse)
+	err := c.cc.Invoke(ctx, UserService_LookupAccessKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
@@ -135,6 +219,13 @@ type UserServiceServer interface {
 	UpdateUsage(context.Context, *UpdateUsageRequest) (*UpdateUsageResponse, error)
 	UpdateCapacity(context.Context, *UpdateCapacityRequest) (*UpdateCapacityResponse, error)
 	CheckCapacity(context.Context, *CheckCapacityRequest) (*CheckCapacityResponse, error)
+	ReserveSpace(context.Context, *ReserveSpaceRequest) (*ReserveSpaceResponse, error)
+	SetUsage(context.Context, *SetUsageRequest) (*SetUsageResponse, error)
+	ListUsage(context.Context, *ListUsageRequest) (*ListUsageResponse, error)
+	CreateAccessKey(context.Context, *CreateAccessKeyRequest) (*CreateAccessKeyResponse, error)
+	ListAccessKeys(context.Context, *ListAccessKeysRequest) (*ListAccessKeysResponse, error)
+	DeleteAccessKey(context.Context, *DeleteAccessKeyRequest) (*DeleteAccessKeyResponse, error)
+	LookupAccessKey(context.Context, *LookupAccessKeyRequest) (*LookupAccessKeyResponse, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -166,6 +257,27 @@ func (UnimplementedUserServiceServer) UpdateCapacity(context.Context, *UpdateCap
 func (UnimplementedUserServiceServer) CheckCapacity(context.Context, *CheckCapacityRequest) (*CheckCapacityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckCapacity not implemented")
 }
+func (UnimplementedUserServiceServer) ReserveSpace(context.Context, *ReserveSpaceRequest) (*ReserveSpaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveSpace not implemented")
+}
+func (UnimplementedUserServiceServer) SetUsage(context.Context, *SetUsageRequest) (*SetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUsage not implemented")
+}
+func (UnimplementedUserServiceServer) ListUsage(context.Context, *ListUsageRequest) (*ListUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsage not implemented")
+}
+func (UnimplementedUserServiceServer) CreateAccessKey(context.Context, *CreateAccessKeyRequest) (*CreateAccessKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccessKey not implemented")
+}
+func (UnimplementedUserServiceServer) ListAccessKeys(context.Context, *ListAccessKeysRequest) (*ListAccessKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccessKeys not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteAccessKey(context.Context, *DeleteAccessKeyRequest) (*DeleteAccessKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccessKey not implemented")
+}
+func (UnimplementedUserServiceServer) LookupAccessKey(context.Context, *LookupAccessKeyRequest) (*LookupAccessKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupAccessKey not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -313,6 +425,132 @@ func _UserService_CheckCapacity_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_ReserveSpace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveSpaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ReserveSpace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ReserveSpace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ReserveSpace(ctx, req.(*ReserveSpaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetUsage(ctx, req.(*SetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUsage(ctx, req.(*ListUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateAccessKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccessKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateAccessKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateAccessKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateAccessKey(ctx, req.(*CreateAccessKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListAccessKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccessKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListAccessKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListAccessKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListAccessKeys(ctx, req.(*ListAccessKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteAccessKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAccessKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteAccessKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteAccessKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteAccessKey(ctx, req.(*DeleteAccessKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_LookupAccessKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupAccessKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).LookupAccessKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_LookupAccessKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).LookupAccessKey(ctx, req.(*LookupAccessKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -348,6 +586,34 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckCapacity",
 			Handler:    _UserService_CheckCapacity_Handler,
 		},
+		{
+			MethodName: "ReserveSpace",
+			Handler:    _UserService_ReserveSpace_Handler,
+		},
+		{
+			MethodName: "SetUsage",
+			Handler:    _UserService_SetUsage_Handler,
+		},
+		{
+			MethodName: "ListUsage",
+			Handler:    _UserService_ListUsage_Handler,
+		},
+		{
+			MethodName: "CreateAccessKey",
+			Handler:    _UserService_CreateAccessKey_Handler,
+		},
+		{
+			MethodName: "ListAccessKeys",
+			Handler:    _UserService_ListAccessKeys_Handler,
+		},
+		{
+			MethodName: "DeleteAccessKey",
+			Handler:    _UserService_DeleteAccessKey_Handler,
+		},
+		{
+			MethodName: "LookupAccessKey",
+			Handler:    _UserService_LookupAccessKey_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user.proto",
@@ -15,7 +15,7 @@ type FairTransferManager struct {
 	// 全局带宽限制 (bytes/s)
 	globalRateLimit int
 
-	// 每个用户的带宽限制 (bytes/s)
+	// 每个用户的带宽上限 (bytes/s)，实际分到的带宽见 UpdateRate
 	perUserRateLimit int
 
 	// 每个传输连接的最小带宽保证 (bytes/s)
@@ -86,6 +86,10 @@ func (m *FairTransferManager) GetUserLimiter(userID uint) *rate.Limiter {
 		limiter = rate.NewLimiter(rate.Limit(m.perUserRateLimit), 5*1024*1024)
 		m.userLimiters[userID] = limiter
 	}
+	if m.activeUsers[userID] == 0 {
+		m.activeCounter++
+		m.UpdateRate()
+	}
 	m.activeUsers[userID]++
 	return limiter
 }
@@ -155,13 +159,19 @@ func (r *FairRateLimitedReader) Read(p []byte) (int, error) {
 	return r.reader.Read(p)
 }
 
-// 自动更新速率
+// UpdateRate 按活跃用户数重新分配带宽，每个用户分到全局带宽的均分，但不超过配置的每用户上限
+// 调用方需持有 m.mutex
 func (m *FairTransferManager) UpdateRate() {
-	preRateLimit := m.globalRateLimit / m.activeCounter
-	m.perUserRateLimit = preRateLimit
+	if m.activeCounter <= 0 {
+		return
+	}
+	share := min(m.perUserRateLimit, m.globalRateLimit/m.activeCounter)
+	for _, limiter := range m.userLimiters {
+		limiter.SetLimit(rate.Limit(share))
+	}
 	for _, limiter := range m.transferLimiters {
 		if limiter != nil {
-			limiter.SetLimit(rate.Limit(preRateLimit))
+			limiter.SetLimit(rate.Limit(share))
 		}
 	}
 }
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func TestFairTransferManager_SharesGlobalRate(t *testing.T) {
+	m := NewFairTransferManager(1000, 400, 10)
+	limits := func(want rate.Limit, users ...uint) {
+		t.Helper()
+		for _, id := range users {
+			if got := m.userLimiters[id].Limit(); got != want {
+				t.Errorf("user %d limit = %v, want %v", id, got, want)
+			}
+		}
+	}
+
+	// 单个用户也不超过每用户上限
+	m.GetUserLimiter(1)
+	limits(400, 1)
+
+	// 均分仍大于上限时保持上限
+	m.GetUserLimiter(2)
+	limits(400, 1, 2)
+
+	m.GetUserLimiter(3)
+	limits(333, 1, 2, 3)
+
+	// 同一用户的第二个传输不改变活跃用户数
+	m.GetUserLimiter(3)
+	limits(333, 1, 2, 3)
+	m.UnregisterTransfer(3)
+	limits(333, 1, 2, 3)
+
+	m.UnregisterTransfer(3)
+	limits(400, 1, 2)
+	m.UnregisterTransfer(2)
+	m.UnregisterTransfer(1)
+
+	// 所有用户结束后重新开始，仍按上限计算
+	m.GetUserLimiter(4)
+	limits(400, 4)
+}
@@ -0,0 +1,505 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AWS Signature Version 4 校验，用于 S3 兼容接口
+// 支持 Authorization 头和预签名URL两种方式；载荷可以是 x-amz-content-sha256 声明的摘要、UNSIGNED-PAYLOAD，
+// 或 aws-chunked 编码的 STREAMING-AWS4-HMAC-SHA256-PAYLOAD（逐块校验签名）和 STREAMING-UNSIGNED-PAYLOAD-TRAILER（不校验尾部校验和）。
+const (
+	sigV4Algorithm     = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat    = "20060102T150405Z"
+	sigV4DateFormat    = "20060102"
+	sigV4MaxSkew       = 15 * time.Minute
+	sigV4MaxExpires    = 7 * 24 * time.Hour
+	unsignedPayload    = "UNSIGNED-PAYLOAD"
+	streamingPayload   = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	streamingUnsigned  = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+	emptySHA256        = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	maxChunkHeaderSize = 4096
+	maxChunkTrailers   = 16
+)
+
+// SigV4Error 签名校验失败，Status 和 Code 为对应的 S3 状态码和错误码
+type SigV4Error struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *SigV4Error) Error() string {
+	return e.Message
+}
+
+func sigV4Error(status int, code, format string, args ...interface{}) *SigV4Error {
+	return &SigV4Error{Status: status, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+var (
+	errSignatureMismatch = sigV4Error(http.StatusForbidden, "SignatureDoesNotMatch",
+		"The request signature we calculated does not match the signature you provided")
+	errContentSHA256Mismatch = sigV4Error(http.StatusBadRequest, "XAmzContentSHA256Mismatch",
+		"The provided 'x-amz-content-sha256' header does not match what was computed")
+	errChunkSignatureMismatch = sigV4Error(http.StatusForbidden, "SignatureDoesNotMatch",
+		"The chunk signature we calculated does not match the signature you provided")
+)
+
+// SigV4Request 从请求中解析出的签名信息
+type SigV4Request struct {
+	AccessKeyID   string
+	Date          time.Time
+	Scope         string // 日期/区域/服务/aws4_request
+	SignedHeaders []string
+	Signature     string
+	PayloadHash   string
+	Presigned     bool
+	Expires       time.Duration // 预签名URL的有效期
+}
+
+// ParseSigV4 解析 Authorization 头或预签名URL中的签名信息，请求没有签名时返回 AccessDenied
+func ParseSigV4(r *http.Request) (*SigV4Request, error) {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		return parseSigV4Header(r, auth)
+	}
+	if r.URL.Query().Get("X-Amz-Algorithm") != "" {
+		return parseSigV4Query(r)
+	}
+	return nil, sigV4Error(http.StatusForbidden, "AccessDenied", "Request is not signed")
+}
+
+func parseSigV4Header(r *http.Request, auth string) (*SigV4Request, error) {
+	algorithm, rest, _ := strings.Cut(auth, " ")
+	if algorithm != sigV4Algorithm {
+		return nil, sigV4Error(http.StatusBadRequest, "AuthorizationHeaderMalformed", "Unsupported authorization type %q", algorithm)
+	}
+	fields := make(map[string]string)
+	for _, field := range strings.Split(rest, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		fields[key] = value
+	}
+
+	s := &SigV4Request{
+		SignedHeaders: strings.Split(fields["SignedHeaders"], ";"),
+		Signature:     fields["Signature"],
+		PayloadHash:   r.Header.Get("X-Amz-Content-Sha256"),
+	}
+	if err := s.parseCredential(fields["Credential"]); err != nil {
+		return nil, err
+	}
+	if s.Signature == "" || fields["SignedHeaders"] == "" {
+		return nil, sigV4Error(http.StatusBadRequest, "AuthorizationHeaderMalformed", "Missing Signature or SignedHeaders")
+	}
+	if s.PayloadHash == "" {
+		return nil, sigV4Error(http.StatusBadRequest, "InvalidRequest", "Missing required header x-amz-content-sha256")
+	}
+	if !s.signsHost() {
+		return nil, sigV4Error(http.StatusBadRequest, "AuthorizationHeaderMalformed", "SignedHeaders must include host")
+	}
+
+	var err error
+	if amzDate := r.Header.Get("X-Amz-Date"); amzDate != "" {
+		s.Date, err = time.Parse(sigV4TimeFormat, amzDate)
+	} else {
+		s.Date, err = http.ParseTime(r.Header.Get("Date"))
+	}
+	if err != nil {
+		return nil, sigV4Error(http.StatusForbidden, "AccessDenied", "Missing or invalid X-Amz-Date")
+	}
+	return s, s.checkScope()
+}
+
+func parseSigV4Query(r *http.Request) (*SigV4Request, error) {
+	query := r.URL.Query()
+	if query.Get("X-Amz-Algorithm") != sigV4Algorithm {
+		return nil, sigV4Error(http.StatusBadRequest, "AuthorizationQueryParametersError", "Unsupported X-Amz-Algorithm")
+	}
+	s := &SigV4Request{
+		SignedHeaders: strings.Split(query.Get("X-Amz-SignedHeaders"), ";"),
+		Signature:     query.Get("X-Amz-Signature"),
+		PayloadHash:   unsignedPayload,
+		Presigned:     true,
+	}
+	if err := s.parseCredential(query.Get("X-Amz-Credential")); err != nil {
+		return nil, err
+	}
+	if hash := query.Get("X-Amz-Content-Sha256"); hash != "" {
+		s.PayloadHash = hash
+	}
+
+	var err error
+	if s.Date, err = time.Parse(sigV4TimeFormat, query.Get("X-Amz-Date")); err != nil {
+		return nil, sigV4Error(http.StatusBadRequest, "AuthorizationQueryParametersError", "Invalid X-Amz-Date")
+	}
+	expires, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+	if err != nil || expires <= 0 || time.Duration(expires)*time.Second > sigV4MaxExpires {
+		return nil, sigV4Error(http.StatusBadRequest, "AuthorizationQueryParametersError", "X-Amz-Expires must be between 1 and 604800 seconds")
+	}
+	s.Expires = time.Duration(expires) * time.Second
+	if s.Signature == "" {
+		return nil, sigV4Error(http.StatusBadRequest, "AuthorizationQueryParametersError", "Missing X-Amz-Signature")
+	}
+	if !s.signsHost() {
+		return nil, sigV4Error(http.StatusBadRequest, "AuthorizationQueryParametersError", "X-Amz-SignedHeaders must include host")
+	}
+	return s, s.checkScope()
+}
+
+// parseCredential 解析 "访问密钥ID/日期/区域/服务/aws4_request"
+func (s *SigV4Request) parseCredential(credential string) error {
+	id, scope, ok := strings.Cut(credential, "/")
+	if !ok || id == "" {
+		return sigV4Error(http.StatusBadRequest, "AuthorizationHeaderMalformed", "Invalid Credential %q", credential)
+	}
+	s.AccessKeyID = id
+	s.Scope = scope
+	return nil
+}
+
+// signsHost 签名必须覆盖 host 头，否则签名可以被重放到其他主机
+func (s *SigV4Request) signsHost() bool {
+	for _, name := range s.SignedHeaders {
+		if name == "host" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkScope 凭证范围的日期必须与请求时间一致，服务必须是 s3；区域不限
+func (s *SigV4Request) checkScope() error {
+	parts := strings.Split(s.Scope, "/")
+	if len(parts) != 4 || parts[2] != "s3" || parts[3] != "aws4_request" {
+		return sigV4Error(http.StatusBadRequest, "AuthorizationHeaderMalformed", "Invalid credential scope %q", s.Scope)
+	}
+	if parts[0] != s.Date.UTC().Format(sigV4DateFormat) {
+		return sigV4Error(http.StatusBadRequest, "AuthorizationHeaderMalformed", "Credential date does not match X-Amz-Date")
+	}
+	return nil
+}
+
+// Verify 用 secret 校验签名和请求时间，成功后 r.Body 替换为边读边校验载荷的 reader
+// aws-chunked 编码的请求 r.ContentLength 改为 x-amz-decoded-content-length，未提供时为 -1
+func (s *SigV4Request) Verify(r *http.Request, secret string, now time.Time) error {
+	if s.Presigned {
+		if now.Before(s.Date.Add(-sigV4MaxSkew)) {
+			return sigV4Error(http.StatusForbidden, "RequestTimeTooSkewed", "Request is not valid yet")
+		}
+		if now.After(s.Date.Add(s.Expires)) {
+			return sigV4Error(http.StatusForbidden, "AccessDenied", "Request has expired")
+		}
+	} else if d := now.Sub(s.Date); d > sigV4MaxSkew || d < -sigV4MaxSkew {
+		return sigV4Error(http.StatusForbidden, "RequestTimeTooSkewed", "The difference between the request time and the server's time is too large")
+	}
+
+	key := sigV4SigningKey(secret, s.Scope)
+	canonical := canonicalRequest(r, s.SignedHeaders, s.PayloadHash, s.Presigned)
+	expected := hex.EncodeToString(hmacSHA256(key, s.stringToSign(sha256Hex([]byte(canonical)))))
+	if !hmac.Equal([]byte(expected), []byte(s.Signature)) {
+		return errSignatureMismatch
+	}
+
+	switch s.PayloadHash {
+	case unsignedPayload:
+	case streamingPayload, streamingUnsigned:
+		if !strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") && r.Header.Get("X-Amz-Decoded-Content-Length") == "" {
+			return sigV4Error(http.StatusBadRequest, "InvalidRequest", "Streaming payload requires aws-chunked encoding")
+		}
+		r.ContentLength = -1
+		if n, err := strconv.ParseInt(r.Header.Get("X-Amz-Decoded-Content-Length"), 10, 64); err == nil {
+			r.ContentLength = n
+		}
+		chunked := &chunkedReader{r: bufio.NewReader(r.Body), closer: r.Body, hash: sha256.New()}
+		if s.PayloadHash == streamingPayload {
+			chunked.signer = &chunkSigner{key: key, amzDate: s.Date.UTC().Format(sigV4TimeFormat), scope: s.Scope, prev: s.Signature}
+		}
+		r.Body = chunked
+	default:
+		want, err := hex.DecodeString(s.PayloadHash)
+		if err != nil || len(want) != sha256.Size {
+			return sigV4Error(http.StatusBadRequest, "InvalidArgument", "Unsupported x-amz-content-sha256 %q", s.PayloadHash)
+		}
+		r.Body = &payloadReader{ReadCloser: r.Body, hash: sha256.New(), want: want}
+	}
+	return nil
+}
+
+func (s *SigV4Request) stringToSign(canonicalHash string) string {
+	return sigV4Algorithm + "\n" + s.Date.UTC().Format(sigV4TimeFormat) + "\n" + s.Scope + "\n" + canonicalHash
+}
+
+// canonicalRequest 按 SigV4 规则构造规范请求
+func canonicalRequest(r *http.Request, signedHeaders []string, payloadHash string, presigned bool) string {
+	var b strings.Builder
+	b.WriteString(r.Method)
+	b.WriteByte('\n')
+	path := r.URL.Path
+	if path == "" {
+		path = "/"
+	}
+	b.WriteString(sigV4Escape(path, false))
+	b.WriteByte('\n')
+	b.WriteString(canonicalQuery(r.URL.Query(), presigned))
+	b.WriteByte('\n')
+	for _, name := range signedHeaders {
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(canonicalHeaderValue(r, name))
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	b.WriteString(strings.Join(signedHeaders, ";"))
+	b.WriteByte('\n')
+	b.WriteString(payloadHash)
+	return b.String()
+}
+
+// canonicalQuery 按编码后的键和值排序，预签名URL不包含签名本身
+func canonicalQuery(query url.Values, presigned bool) string {
+	var pairs []string
+	for key, values := range query {
+		if presigned && key == "X-Amz-Signature" {
+			continue
+		}
+		for _, value := range values {
+			pairs = append(pairs, sigV4Escape(key, true)+"="+sigV4Escape(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// canonicalHeaderValue 多个值用逗号连接，去掉首尾空白并合并连续空格
+func canonicalHeaderValue(r *http.Request, name string) string {
+	var values []string
+	switch name {
+	case "host":
+		values = []string{r.Host}
+	case "content-length":
+		values = r.Header.Values("Content-Length")
+		if len(values) == 0 && r.ContentLength >= 0 {
+			values = []string{strconv.FormatInt(r.ContentLength, 10)}
+		}
+	default:
+		values = r.Header.Values(name)
+	}
+	for i, v := range values {
+		values[i] = strings.Join(strings.Fields(v), " ")
+	}
+	return strings.Join(values, ",")
+}
+
+// sigV4Escape 按 SigV4 规则编码：只保留字母、数字和 -._~，路径中的 / 不编码
+func sigV4Escape(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&15])
+		}
+	}
+	return b.String()
+}
+
+// sigV4SigningKey 由密钥和凭证范围派生签名密钥
+func sigV4SigningKey(secret, scope string) []byte {
+	key := []byte("AWS4" + secret)
+	for _, part := range strings.Split(scope, "/") {
+		key = hmacSHA256(key, part)
+	}
+	return key
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// payloadReader 读到末尾时校验内容的 SHA-256 与 x-amz-content-sha256 一致
+type payloadReader struct {
+	io.ReadCloser
+	hash hash.Hash
+	want []byte
+}
+
+func (p *payloadReader) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	p.hash.Write(b[:n])
+	if err == io.EOF && !bytes.Equal(p.hash.Sum(nil), p.want) {
+		return n, errContentSHA256Mismatch
+	}
+	return n, err
+}
+
+// chunkSigner 按 SigV4 流式上传规则逐块计算签名，每块的签名依赖上一块的签名
+type chunkSigner struct {
+	key     []byte
+	amzDate string
+	scope   string
+	prev    string
+}
+
+func (s *chunkSigner) verify(chunkHash, signature string) bool {
+	stringToSign := "AWS4-HMAC-SHA256-PAYLOAD\n" + s.amzDate + "\n" + s.scope + "\n" + s.prev + "\n" + emptySHA256 + "\n" + chunkHash
+	expected := hex.EncodeToString(hmacSHA256(s.key, stringToSign))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return false
+	}
+	s.prev = signature
+	return true
+}
+
+// chunkedReader 解码 aws-chunked 编码的内容：每块为 "十六进制大小[;chunk-signature=签名]\r\n内容\r\n"，
+// 以大小为0的块结束，之后可以有若干尾部字段，以空行结束。signer 不为空时逐块校验签名
+type chunkedReader struct {
+	r         *bufio.Reader
+	closer    io.Closer
+	signer    *chunkSigner
+	hash      hash.Hash
+	remaining int64
+	signature string
+	started   bool
+	err       error
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	if c.remaining == 0 {
+		if c.started {
+			if c.err = c.endChunk(); c.err != nil {
+				return 0, c.err
+			}
+		}
+		if c.err = c.nextChunk(); c.err != nil {
+			return 0, c.err
+		}
+		if c.remaining == 0 {
+			c.err = c.finish()
+			if c.err == nil {
+				c.err = io.EOF
+			}
+			return 0, c.err
+		}
+	}
+
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	c.hash.Write(p[:n])
+	c.remaining -= int64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		c.err = err
+	}
+	return n, err
+}
+
+func (c *chunkedReader) Close() error {
+	return c.closer.Close()
+}
+
+// nextChunk 读取块头
+func (c *chunkedReader) nextChunk() error {
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+	sizeStr, ext, _ := strings.Cut(line, ";")
+	size, err := strconv.ParseInt(strings.TrimSpace(sizeStr), 16, 64)
+	if err != nil || size < 0 {
+		return sigV4Error(http.StatusBadRequest, "IncompleteBody", "Invalid chunk size %q", sizeStr)
+	}
+	c.signature = ""
+	if name, value, ok := strings.Cut(ext, "="); ok && strings.TrimSpace(name) == "chunk-signature" {
+		c.signature = strings.TrimSpace(value)
+	}
+	if c.signer != nil && c.signature == "" {
+		return sigV4Error(http.StatusBadRequest, "IncompleteBody", "Missing chunk signature")
+	}
+	c.hash.Reset()
+	c.remaining = size
+	c.started = true
+	return nil
+}
+
+// endChunk 读取块内容后的换行并校验块签名
+func (c *chunkedReader) endChunk() error {
+	var crlf [2]byte
+	if _, err := io.ReadFull(c.r, crlf[:]); err != nil || crlf != [2]byte{'\r', '\n'} {
+		return sigV4Error(http.StatusBadRequest, "IncompleteBody", "Malformed chunk terminator")
+	}
+	return c.verifyChunk()
+}
+
+// finish 校验结束块的签名并跳过尾部字段
+func (c *chunkedReader) finish() error {
+	if err := c.verifyChunk(); err != nil {
+		return err
+	}
+	for i := 0; i < maxChunkTrailers; i++ {
+		line, err := c.readLine()
+		if err != nil {
+			return err
+		}
+		if line == "" {
+			return nil
+		}
+	}
+	return sigV4Error(http.StatusBadRequest, "IncompleteBody", "Too many trailing headers")
+}
+
+func (c *chunkedReader) verifyChunk() error {
+	if c.signer == nil {
+		return nil
+	}
+	if !c.signer.verify(hex.EncodeToString(c.hash.Sum(nil)), c.signature) {
+		return errChunkSignatureMismatch
+	}
+	return nil
+}
+
+func (c *chunkedReader) readLine() (string, error) {
+	line, err := c.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull || len(line) > maxChunkHeaderSize {
+		return "", sigV4Error(http.StatusBadRequest, "IncompleteBody", "Chunk header too long")
+	}
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
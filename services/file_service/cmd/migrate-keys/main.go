@@ -0,0 +1,48 @@
+// migrate-keys 一次性迁移命令：把以文件名为对象名的旧对象迁移到随机对象名下
+//
+// 用法（在 file_service 目录下执行，迁移期间应停止文件服务；还有未完成的上传时拒绝迁移）：
+//
+//	go run ./cmd/migrate-keys -dry-run
+//	go run ./cmd/migrate-keys
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"cloud-storage-file-service/config"
+	"cloud-storage-file-service/database"
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/internal/service"
+)
+
+func main() {
+	configPath := flag.String("config", "config/config.yaml", "配置文件路径")
+	dryRun := flag.Bool("dry-run", false, "只列出待迁移的对象，不做修改")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+
+	db, err := database.NewDB(cfg)
+	if err != nil {
+		log.Fatalf("连接数据库失败: %v", err)
+	}
+
+	store, err := blobstore.New(context.Background(), cfg, "cloud-storage")
+	if err != nil {
+		log.Fatalf("初始化对象存储失败: %v", err)
+	}
+
+	storageService := service.NewStorageService(store, "cloud-storage", model.NewFileDAO(db.DB))
+	n, err := storageService.MigrateObjectKeys(context.Background(), *dryRun)
+	if err != nil {
+		log.Fatalf("迁移中断, 已迁移 %d 个对象: %v", n, err)
+	}
+	fmt.Printf("迁移完成, 共 %d 个对象\n", n)
+}
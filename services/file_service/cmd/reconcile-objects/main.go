@@ -0,0 +1,80 @@
+// reconcile-objects 对账命令：列出对象存储中没有记录引用的孤立对象，以及对象已不存在的记录
+// 默认只报告不修改；加 -apply 时删除孤立对象，并处理对象缺失的记录（见 StorageService.ReconcileObjects）
+// 最近 -grace 内写入的对象可能属于进行中的上传，不会被视为孤立对象；可以在文件服务运行期间执行
+//
+// 用法（在 file_service 目录下执行）：
+//
+//	go run ./cmd/reconcile-objects
+//	go run ./cmd/reconcile-objects -apply -grace 48h
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud-storage-file-service/config"
+	"cloud-storage-file-service/database"
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/encryption"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/internal/service"
+)
+
+func main() {
+	configPath := flag.String("config", "config/config.yaml", "配置文件路径")
+	apply := flag.Bool("apply", false, "删除孤立对象并处理对象缺失的记录，不指定时只报告")
+	grace := flag.Duration("grace", 24*time.Hour, "不处理最近这段时间内写入的对象")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+
+	db, err := database.NewDB(cfg)
+	if err != nil {
+		log.Fatalf("连接数据库失败: %v", err)
+	}
+
+	store, err := blobstore.New(context.Background(), cfg, "cloud-storage")
+	if err != nil {
+		log.Fatalf("初始化对象存储失败: %v", err)
+	}
+
+	storageService := service.NewStorageService(store, "cloud-storage", model.NewFileDAO(db.DB))
+	// 重新校验加密的文件需要主密钥
+	if cfg.Encryption.MasterKey != "" {
+		masterKey, err := encryption.ParseKey(cfg.Encryption.MasterKey)
+		if err == nil {
+			err = storageService.SetMasterKey(masterKey)
+		}
+		if err != nil {
+			log.Fatalf("主密钥不合法: %v", err)
+		}
+	}
+
+	report, err := storageService.ReconcileObjects(context.Background(), service.ReconcileOptions{Apply: *apply, Grace: *grace})
+	if report != nil {
+		for _, info := range report.Orphans {
+			fmt.Printf("孤立对象\t%s\t%d\t%s\n", info.Key, info.Size, info.ModTime.Format(time.RFC3339))
+		}
+		for _, ref := range report.Missing {
+			fmt.Printf("对象缺失\t%s\t%d\t%s\n", ref.Kind, ref.ID, ref.ObjectName)
+		}
+	}
+	if err != nil {
+		log.Fatalf("对账中断: %v", err)
+	}
+
+	fmt.Printf("共 %d 个对象, 孤立对象 %d 个 (%d 字节), 对象缺失的记录 %d 条\n",
+		report.Objects, len(report.Orphans), report.OrphanBytes, len(report.Missing))
+	if !*apply {
+		fmt.Println("未指定 -apply, 没有做任何修改")
+		return
+	}
+	fmt.Printf("已删除孤立对象 %d 个, 记录问题的文件 %d 个, 重新生成衍生对象的 Blob %d 个, 删除分片记录 %d 条\n",
+		report.DeletedObjects, report.MarkedFiles, report.ResetBlobs, report.DeletedParts)
+}
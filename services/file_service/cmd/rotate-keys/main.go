@@ -0,0 +1,71 @@
+// rotate-keys 用户密钥轮换命令：为用户生成新密钥，并把旧密钥包装的数据密钥改由新密钥包装
+// 只重新包装数据密钥，不重写对象内容；可以在文件服务运行期间执行
+//
+// 用法（在 file_service 目录下执行）：
+//
+//	go run ./cmd/rotate-keys -user 42
+//	go run ./cmd/rotate-keys -all
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"cloud-storage-file-service/config"
+	"cloud-storage-file-service/database"
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/encryption"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/internal/service"
+)
+
+func main() {
+	configPath := flag.String("config", "config/config.yaml", "配置文件路径")
+	userID := flag.Int64("user", -1, "轮换指定用户的密钥")
+	all := flag.Bool("all", false, "轮换所有用户的密钥")
+	flag.Parse()
+
+	if *all == (*userID >= 0) {
+		log.Fatal("必须且只能指定 -user 或 -all 之一")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("加载配置失败: %v", err)
+	}
+	if cfg.Encryption.MasterKey == "" {
+		log.Fatal("未配置主密钥")
+	}
+	masterKey, err := encryption.ParseKey(cfg.Encryption.MasterKey)
+	if err != nil {
+		log.Fatalf("主密钥不合法: %v", err)
+	}
+
+	db, err := database.NewDB(cfg)
+	if err != nil {
+		log.Fatalf("连接数据库失败: %v", err)
+	}
+
+	store, err := blobstore.New(context.Background(), cfg, "cloud-storage")
+	if err != nil {
+		log.Fatalf("初始化对象存储失败: %v", err)
+	}
+
+	storageService := service.NewStorageService(store, "cloud-storage", model.NewFileDAO(db.DB))
+	if err := storageService.SetMasterKey(masterKey); err != nil {
+		log.Fatalf("主密钥不合法: %v", err)
+	}
+
+	var result service.KeyRotationResult
+	if *all {
+		result, err = storageService.RotateAllUserKeys(context.Background())
+	} else {
+		result, err = storageService.RotateUserKey(context.Background(), *userID)
+	}
+	if err != nil {
+		log.Fatalf("轮换中断, 已重新包装 %d 个数据密钥: %v", result.Rewrapped, err)
+	}
+	fmt.Printf("轮换完成, 重新包装 %d 个数据密钥, 删除旧密钥 %d 个\n", result.Rewrapped, result.RetiredKeys)
+}
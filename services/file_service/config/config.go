@@ -37,8 +37,17 @@ type RedisConfig struct {
 
 // StorageConfig 存储配置
 type StorageConfig struct {
-	Path     string `yaml:"path"`
+	Backend  string `yaml:"backend"`  // 存储后端：minio（默认）、local、memory
+	Path     string `yaml:"path"`     // local 后端的根目录
 	PartSize int64  `yaml:"partSize"` // 分片大小（以字节为单位）
+
+	TrashRetentionDays        int `yaml:"trashRetentionDays"`        // 回收站保留天数
+	TrashPurgeIntervalMinutes int `yaml:"trashPurgeIntervalMinutes"` // 回收站清理间隔（分钟）
+
+	UsageReconcileIntervalMinutes int `yaml:"usageReconcileIntervalMinutes"` // 用户已用空间对账间隔（分钟）
+
+	UploadTTLMinutes           int `yaml:"uploadTTLMinutes"`           // 上传会话有效期（分钟），小于0表示不过期
+	UploadSweepIntervalMinutes int `yaml:"uploadSweepIntervalMinutes"` // 过期上传清理间隔（分钟）
 }
 
 // LogConfig 日志配置
@@ -55,6 +64,40 @@ type MinioConfig struct {
 	UseSSL    bool   `yaml:"useSSL"`
 }
 
+// EncryptionConfig 静态加密配置
+type EncryptionConfig struct {
+	// MasterKey base64 编码的32字节主密钥，为空时不加密；环境变量 FILE_SERVICE_MASTER_KEY 优先
+	MasterKey string `yaml:"masterKey"`
+}
+
+// PreviewConfig 缩略图和文本预览配置
+type PreviewConfig struct {
+	ThumbnailSizes      []int `yaml:"thumbnailSizes"`      // 缩略图最长边像素
+	TextPreviewKB       int   `yaml:"textPreviewKB"`       // 文本预览取文件开头的大小（KB）
+	MaxImageMB          int   `yaml:"maxImageMB"`          // 超过该大小的图片不生成缩略图（MB）
+	ScanIntervalMinutes int   `yaml:"scanIntervalMinutes"` // 扫描待生成内容的间隔（分钟）
+}
+
+// ScrubConfig 完整性校验配置
+type ScrubConfig struct {
+	IntervalMinutes int `yaml:"intervalMinutes"` // 校验间隔（分钟），小于0表示不启用
+	BatchSize       int `yaml:"batchSize"`       // 每次校验的文件数
+	RescanDays      int `yaml:"rescanDays"`      // 同一文件重新校验的周期（天）
+}
+
+// ExtractConfig 服务端解压配置
+type ExtractConfig struct {
+	MaxEntries    int `yaml:"maxEntries"`    // 压缩包的条目数上限，包括目录
+	MaxExpandedMB int `yaml:"maxExpandedMB"` // 解压后的总大小上限（MB）
+	MaxDepth      int `yaml:"maxDepth"`      // 条目路径的层数上限
+}
+
+// ImportConfig 从URL导入配置
+type ImportConfig struct {
+	MaxSizeMB            int  `yaml:"maxSizeMB"`            // 远程文件大小上限（MB），小于0表示不限制
+	AllowPrivateNetworks bool `yaml:"allowPrivateNetworks"` // 是否允许访问回环、私有网段等内网地址
+}
+
 // Config 服务配置结构
 type Config struct {
 	Server   ServerConfig   `yaml:"server"`
@@ -64,6 +107,12 @@ type Config struct {
 	Storage  StorageConfig  `yaml:"storage"`
 	Log      LogConfig      `yaml:"log"`
 	Minio    MinioConfig    `yaml:"minio"`
+
+	Encryption EncryptionConfig `yaml:"encryption"`
+	Preview    PreviewConfig    `yaml:"preview"`
+	Scrub      ScrubConfig      `yaml:"scrub"`
+	Extract    ExtractConfig    `yaml:"extract"`
+	Import     ImportConfig     `yaml:"import"`
 }
 
 // LoadConfig 加载配置文件
@@ -93,10 +142,53 @@ func LoadConfig(configPath string) (*Config, error) {
 	}
 
 	// 设置默认值
+	if config.Storage.Backend == "" {
+		config.Storage.Backend = "minio"
+	}
 	if config.Storage.PartSize == 0 {
 		// 默认分片大小为5MB
 		config.Storage.PartSize = 5 * 1024 * 1024
 	}
+	if config.Storage.TrashRetentionDays == 0 {
+		// 默认在回收站保留30天
+		config.Storage.TrashRetentionDays = 30
+	}
+	if config.Storage.TrashPurgeIntervalMinutes == 0 {
+		config.Storage.TrashPurgeIntervalMinutes = 60
+	}
+	if config.Storage.UsageReconcileIntervalMinutes == 0 {
+		// 默认每6小时对账一次
+		config.Storage.UsageReconcileIntervalMinutes = 360
+	}
+	if config.Storage.UploadTTLMinutes == 0 {
+		// 默认上传会话24小时内没有新分片即过期
+		config.Storage.UploadTTLMinutes = 24 * 60
+	}
+	if config.Storage.UploadSweepIntervalMinutes == 0 {
+		config.Storage.UploadSweepIntervalMinutes = 30
+	}
+
+	if config.Preview.ScanIntervalMinutes == 0 {
+		config.Preview.ScanIntervalMinutes = 10
+	}
+	if config.Scrub.IntervalMinutes == 0 {
+		config.Scrub.IntervalMinutes = 60
+	}
+	if config.Scrub.BatchSize == 0 {
+		config.Scrub.BatchSize = 50
+	}
+	if config.Scrub.RescanDays == 0 {
+		// 默认每30天完整校验一遍
+		config.Scrub.RescanDays = 30
+	}
+	if config.Import.MaxSizeMB == 0 {
+		// 默认最多导入10GB
+		config.Import.MaxSizeMB = 10240
+	}
+
+	if key := os.Getenv("FILE_SERVICE_MASTER_KEY"); key != "" {
+		config.Encryption.MasterKey = key
+	}
 
 	return &config, nil
 }
\ No newline at end of file
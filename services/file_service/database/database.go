@@ -28,6 +28,8 @@ func NewDB(cfg *config.Config) (*DB, error) {
 	// 配置GORM
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// 唯一索引冲突转换为 gorm.ErrDuplicatedKey
+		TranslateError: true,
 	}
 
 	// 连接数据库
@@ -0,0 +1,30 @@
+package api
+
+import (
+	filepb "cloud-storage-file-service/proto"
+	"context"
+)
+
+// 复制文件
+func (s *FileServiceServer) CopyFile(ctx context.Context, req *filepb.CopyFileRequest) (*filepb.CopyFileResponse, error) {
+	file, err := s.storage.CopyFile(ctx, req.UserId, req.FileId, req.FolderId, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &filepb.CopyFileResponse{
+		File: toFileInfo(file),
+	}, nil
+}
+
+// 移动文件或转移文件所有权
+func (s *FileServiceServer) MoveFile(ctx context.Context, req *filepb.MoveFileRequest) (*filepb.MoveFileResponse, error) {
+	file, err := s.storage.MoveFile(ctx, req.UserId, req.FileId, req.FolderId, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &filepb.MoveFileResponse{
+		File: toFileInfo(file),
+	}, nil
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	filepb "cloud-storage-file-service/proto"
+	"context"
+)
+
+// 解压压缩包
+func (s *FileServiceServer) ExtractArchive(ctx context.Context, req *filepb.ExtractArchiveRequest) (*filepb.ExtractArchiveResponse, error) {
+	result, err := s.storage.ExtractArchive(ctx, req.UserId, req.FileId, req.FolderId)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*filepb.FileInfo, 0, len(result.Files))
+	for i := range result.Files {
+		files = append(files, toFileInfo(&result.Files[i]))
+	}
+	return &filepb.ExtractArchiveResponse{
+		Files:          files,
+		FoldersCreated: int32(result.Folders),
+		Skipped:        result.Skipped,
+	}, nil
+}
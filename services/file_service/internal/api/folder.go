@@ -0,0 +1,92 @@
+package api
+
+import (
+	"cloud-storage-file-service/internal/model"
+	filepb "cloud-storage-file-service/proto"
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// toFolderInfo 将文件夹记录转换为 proto 中的 FolderInfo
+func toFolderInfo(folder *model.Folder) *filepb.FolderInfo {
+	return &filepb.FolderInfo{
+		Id:        folder.ID,
+		Name:      folder.Name,
+		ParentId:  folder.ParentID,
+		UserId:    folder.UserID,
+		CreatedAt: folder.CreatedAt.Unix(),
+		UpdatedAt: folder.UpdatedAt.Unix(),
+	}
+}
+
+// 创建文件夹
+func (s *FileServiceServer) CreateFolder(ctx context.Context, req *filepb.CreateFolderRequest) (*filepb.CreateFolderResponse, error) {
+	folder, err := s.storage.CreateFolder(ctx, req.UserId, req.ParentId, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &filepb.CreateFolderResponse{Folder: toFolderInfo(folder)}, nil
+}
+
+// 重命名文件夹
+func (s *FileServiceServer) RenameFolder(ctx context.Context, req *filepb.RenameFolderRequest) (*filepb.RenameFolderResponse, error) {
+	folder, err := s.storage.RenameFolder(ctx, req.UserId, req.FolderId, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &filepb.RenameFolderResponse{Folder: toFolderInfo(folder)}, nil
+}
+
+// 移动文件夹
+func (s *FileServiceServer) MoveFolder(ctx context.Context, req *filepb.MoveFolderRequest) (*filepb.MoveFolderResponse, error) {
+	folder, err := s.storage.MoveFolder(ctx, req.UserId, req.FolderId, req.TargetParentId)
+	if err != nil {
+		return nil, err
+	}
+	return &filepb.MoveFolderResponse{Folder: toFolderInfo(folder)}, nil
+}
+
+// 删除文件夹
+func (s *FileServiceServer) DeleteFolder(ctx context.Context, req *filepb.DeleteFolderRequest) (*emptypb.Empty, error) {
+	if err := s.storage.DeleteFolder(ctx, req.UserId, req.FolderId, req.Recursive); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// 列出目录内容
+func (s *FileServiceServer) ListDirectory(ctx context.Context, req *filepb.ListDirectoryRequest) (*filepb.ListDirectoryResponse, error) {
+	folders, files, total, err := s.storage.ListDirectory(ctx, req.UserId, req.FolderId,
+		int(req.Page), int(req.PageSize), req.SortBy, req.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &filepb.ListDirectoryResponse{
+		Folders: make([]*filepb.FolderInfo, 0, len(folders)),
+		Files:   make([]*filepb.FileInfo, 0, len(files)),
+		Total:   total,
+	}
+	for i := range folders {
+		resp.Folders = append(resp.Folders, toFolderInfo(&folders[i]))
+	}
+	for i := range files {
+		resp.Files = append(resp.Files, toFileInfo(&files[i]))
+	}
+	return resp, nil
+}
+
+// 按名称路径查找文件夹和文件
+func (s *FileServiceServer) LookupPath(ctx context.Context, req *filepb.LookupPathRequest) (*filepb.LookupPathResponse, error) {
+	folderID, found, file, err := s.storage.LookupPath(ctx, req.UserId, req.FolderId, req.Folders, req.Name, req.Create)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &filepb.LookupPathResponse{FolderId: folderID, Found: found}
+	if file != nil {
+		resp.File = toFileInfo(file)
+	}
+	return resp, nil
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	filepb "cloud-storage-file-service/proto"
+	"context"
+)
+
+// 从URL导入文件
+func (s *FileServiceServer) ImportFromURL(ctx context.Context, req *filepb.ImportFromURLRequest) (*filepb.ImportFromURLResponse, error) {
+	file, err := s.storage.ImportFromURL(ctx, req.UserId, req.FolderId, req.Url, req.Name, toFileMeta(req.Mtime, req.Tags))
+	if err != nil {
+		return nil, err
+	}
+
+	return &filepb.ImportFromURLResponse{
+		File: toFileInfo(file),
+	}, nil
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"cloud-storage-file-service/internal/service"
+	filepb "cloud-storage-file-service/proto"
+	"context"
+	"time"
+)
+
+// fromUnix 将时间戳（秒）转换为时间，0 表示未提供
+func fromUnix(ts int64) *time.Time {
+	if ts <= 0 {
+		return nil
+	}
+	t := time.Unix(ts, 0)
+	return &t
+}
+
+// unixOrZero 将可能为空的时间转换为时间戳（秒）
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+// toFileMeta 转换上传请求中的元数据
+func toFileMeta(mtime int64, tags map[string]string) service.FileMeta {
+	return service.FileMeta{Mtime: fromUnix(mtime), Tags: tags}
+}
+
+// 设置或删除文件标签
+func (s *FileServiceServer) UpdateFileTags(ctx context.Context, req *filepb.UpdateFileTagsRequest) (*filepb.UpdateFileTagsResponse, error) {
+	file, err := s.storage.UpdateFileTags(ctx, req.UserId, req.FileId, req.Set, req.Remove)
+	if err != nil {
+		return nil, err
+	}
+
+	return &filepb.UpdateFileTagsResponse{
+		File: toFileInfo(file),
+	}, nil
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"cloud-storage-file-service/internal/service"
+	filepb "cloud-storage-file-service/proto"
+	"context"
+	"time"
+)
+
+// defaultReconcileListLimit 对账结果中每类默认返回的条目数
+const defaultReconcileListLimit = 1000
+
+// 对账对象存储和数据库
+func (s *FileServiceServer) ReconcileObjects(ctx context.Context, req *filepb.ReconcileObjectsRequest) (*filepb.ReconcileObjectsResponse, error) {
+	report, err := s.storage.ReconcileObjects(ctx, service.ReconcileOptions{
+		Apply: req.Apply,
+		Grace: time.Duration(req.GraceSeconds) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultReconcileListLimit
+	}
+	resp := &filepb.ReconcileObjectsResponse{
+		Objects:        int64(report.Objects),
+		OrphanCount:    int64(len(report.Orphans)),
+		OrphanBytes:    report.OrphanBytes,
+		MissingCount:   int64(len(report.Missing)),
+		DeletedObjects: int64(report.DeletedObjects),
+		MarkedFiles:    int64(report.MarkedFiles),
+		ResetBlobs:     int64(report.ResetBlobs),
+		DeletedParts:   int64(report.DeletedParts),
+	}
+	for _, info := range report.Orphans[:min(limit, len(report.Orphans))] {
+		orphan := &filepb.OrphanObject{Key: info.Key, Size: info.Size}
+		if !info.ModTime.IsZero() {
+			orphan.ModifiedAt = info.ModTime.Unix()
+		}
+		resp.Orphans = append(resp.Orphans, orphan)
+	}
+	for _, ref := range report.Missing[:min(limit, len(report.Missing))] {
+		resp.Missing = append(resp.Missing, &filepb.MissingObject{
+			Kind:       ref.Kind,
+			Id:         ref.ID,
+			ObjectName: ref.ObjectName,
+		})
+	}
+	return resp, nil
+}
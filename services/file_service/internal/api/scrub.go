@@ -0,0 +1,57 @@
+package api
+
+import (
+	"cloud-storage-file-service/internal/model"
+	filepb "cloud-storage-file-service/proto"
+	"context"
+)
+
+// toScrubFindings 转换完整性校验记录
+func toScrubFindings(findings []model.ScrubFinding) []*filepb.ScrubFinding {
+	infos := make([]*filepb.ScrubFinding, 0, len(findings))
+	for _, f := range findings {
+		infos = append(infos, &filepb.ScrubFinding{
+			Id:          f.ID,
+			FileId:      f.FileID,
+			VersionId:   f.VersionID,
+			ObjectName:  f.ObjectName,
+			Problem:     f.Problem,
+			Detail:      f.Detail,
+			FirstSeenAt: f.FirstSeenAt.Unix(),
+			LastSeenAt:  f.LastSeenAt.Unix(),
+		})
+	}
+	return infos
+}
+
+// 列出有未解决问题的文件
+func (s *FileServiceServer) ListCorruptedFiles(ctx context.Context, req *filepb.ListCorruptedFilesRequest) (*filepb.ListCorruptedFilesResponse, error) {
+	files, next, err := s.storage.ListCorruptedFiles(ctx, req.Cursor, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &filepb.ListCorruptedFilesResponse{
+		Files:      make([]*filepb.CorruptedFile, 0, len(files)),
+		NextCursor: next,
+	}
+	for _, f := range files {
+		resp.Files = append(resp.Files, &filepb.CorruptedFile{
+			File:     toFileInfo(f.File),
+			Findings: toScrubFindings(f.Findings),
+		})
+	}
+	return resp, nil
+}
+
+// 立即校验一个文件
+func (s *FileServiceServer) ScrubFile(ctx context.Context, req *filepb.ScrubFileRequest) (*filepb.ScrubFileResponse, error) {
+	findings, err := s.storage.ScrubFile(ctx, req.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &filepb.ScrubFileResponse{
+		Findings: toScrubFindings(findings),
+	}, nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"cloud-storage-file-service/internal/model"
+	filepb "cloud-storage-file-service/proto"
+	"context"
+	"time"
+)
+
+// toFileInfos 批量转换文件记录
+func toFileInfos(files []model.File) []*filepb.FileInfo {
+	infos := make([]*filepb.FileInfo, 0, len(files))
+	for i := range files {
+		infos = append(infos, toFileInfo(&files[i]))
+	}
+	return infos
+}
+
+// 列出用户的所有文件
+func (s *FileServiceServer) ListFiles(ctx context.Context, req *filepb.ListFilesRequest) (*filepb.ListFilesResponse, error) {
+	files, next, err := s.storage.ListFiles(ctx, req.UserId, req.Cursor, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return &filepb.ListFilesResponse{
+		Files:      toFileInfos(files),
+		NextCursor: next,
+	}, nil
+}
+
+// 搜索文件
+func (s *FileServiceServer) SearchFiles(ctx context.Context, req *filepb.SearchFilesRequest) (*filepb.SearchFilesResponse, error) {
+	filter := model.FileFilter{
+		Name:     req.Name,
+		MinSize:  req.MinSize,
+		MaxSize:  req.MaxSize,
+		MimeType: req.MimeType,
+	}
+	if req.Status != nil {
+		status := int(*req.Status)
+		filter.Status = &status
+	}
+	if req.CreatedAfter > 0 {
+		t := time.Unix(req.CreatedAfter, 0)
+		filter.CreatedAfter = &t
+	}
+	if req.CreatedBefore > 0 {
+		t := time.Unix(req.CreatedBefore, 0)
+		filter.CreatedBefore = &t
+	}
+	filter.UpdatedAfter = fromUnix(req.UpdatedAfter)
+	filter.UpdatedBefore = fromUnix(req.UpdatedBefore)
+	filter.MtimeAfter = fromUnix(req.MtimeAfter)
+	filter.MtimeBefore = fromUnix(req.MtimeBefore)
+	filter.Tags = req.Tags
+
+	files, next, err := s.storage.SearchFiles(ctx, req.UserId, filter, req.Cursor, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return &filepb.SearchFilesResponse{
+		Files:      toFileInfos(files),
+		NextCursor: next,
+	}, nil
+}
@@ -1,13 +1,19 @@
 package api
 
 import (
+	"cloud-storage-file-service/internal/model"
 	"cloud-storage-file-service/internal/service"
 	filepb "cloud-storage-file-service/proto"
 	"cloud-storage-file-service/utils"
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -22,24 +28,67 @@ func NewFileServiceServer(storage *service.StorageService) filepb.FileServiceSer
 	}
 }
 
+// toFileInfo 将文件记录转换为 proto 中的 FileInfo
+func toFileInfo(file *model.File) *filepb.FileInfo {
+	info := &filepb.FileInfo{
+		Id:          file.ID,
+		Name:        file.FileName,
+		Size:        file.Size,
+		UserID:      file.UserID,
+		Md5:         file.Md5,
+		Status:      int32(file.Status),
+		FolderId:    file.FolderID,
+		VersionId:   file.CurrentVersionID,
+		Sha256:      file.Sha256,
+		MimeType:    file.MimeType,
+		CreatedAt:   file.CreatedAt.Unix(),
+		Compression: file.Compression,
+		UpdatedAt:   file.UpdatedAt.Unix(),
+		Tags:        file.Tags,
+	}
+	if file.TrashedAt != nil {
+		info.TrashedAt = file.TrashedAt.Unix()
+	}
+	if file.Mtime != nil {
+		info.Mtime = file.Mtime.Unix()
+	}
+	return info
+}
+
 func (s *FileServiceServer) InitUpload(ctx context.Context, req *filepb.InitUploadRequest) (*filepb.InitUploadResponse, error) {
-	file, err := s.storage.InitUpload(ctx, req.FileName, req.Size, req.Md5, req.UserID)
+	file, err := s.storage.InitUpload(ctx, req.FileName, req.Size, req.Md5, req.Sha256, req.UserID, req.FolderId, req.Compression, toFileMeta(req.Mtime, req.Tags))
 	if err != nil {
 		return nil, err
 	}
 
 	return &filepb.InitUploadResponse{
-		File: &filepb.FileInfo{
-			Id:     file.ID,
-			Name:   file.FileName,
-			UserID: file.UserID,
-			Size:   file.Size,
-			Md5:    file.Md5,
-			Status: int32(file.Status),
-		},
+		File: toFileInfo(file),
+	}, nil
+}
+
+func (s *FileServiceServer) InitChunkedUpload(ctx context.Context, req *filepb.InitChunkedUploadRequest) (*filepb.InitChunkedUploadResponse, error) {
+	chunks := make([]service.ChunkRef, 0, len(req.Chunks))
+	for _, c := range req.Chunks {
+		chunks = append(chunks, service.ChunkRef{Sha256: c.Sha256, Size: c.Size})
+	}
+	file, missing, err := s.storage.InitChunkedUpload(ctx, req.FileName, req.Size, req.Md5, req.Sha256, req.UserID, req.FolderId, chunks, toFileMeta(req.Mtime, req.Tags))
+	if err != nil {
+		return nil, err
+	}
+
+	return &filepb.InitChunkedUploadResponse{
+		File:          toFileInfo(file),
+		MissingChunks: missing,
 	}, nil
 }
 
+func (s *FileServiceServer) UploadChunk(ctx context.Context, req *filepb.UploadChunkRequest) (*emptypb.Empty, error) {
+	if err := s.storage.UploadChunk(ctx, req.FileId, req.Sha256, req.Data); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
 func (s *FileServiceServer) UploadPart(stream filepb.FileService_UploadPartServer) error {
 	var (
 		fileID     int64
@@ -138,29 +187,45 @@ func (s *FileServiceServer) CompleteUpload(ctx context.Context, req *filepb.Comp
 	}
 
 	return &filepb.CompleteUploadResponse{
-		File: &filepb.FileInfo{
-			Id:     file.ID,
-			Name:   file.FileName,
-			Size:   file.Size,
-			UserID: file.UserID,
-			Md5:    file.Md5,
-			Status: int32(file.Status),
-		},
+		File: toFileInfo(file),
 	}, nil
 }
 
+// 流式下载分片，数据按 streamChunkSize 分块发送，最后一条消息携带本次数据的 MD5
 func (s *FileServiceServer) DownloadPart(req *filepb.DownloadRequest, stream filepb.FileService_DownloadPartServer) error {
-	chunkData, md5, err := s.storage.DownloadChunk(stream.Context(), req.FileId, int(req.PartNumber), 0)
+	w := &chunkWriter{send: func(data []byte) error {
+		return stream.Send(&filepb.DownloadResponse{Data: data})
+	}}
+	hash := md5.New()
+
+	size, etag, err := s.storage.DownloadChunk(stream.Context(), req.FileId, req.VersionId, int(req.PartNumber),
+		req.Offset, req.Length, io.MultiWriter(w, hash))
 	if err != nil {
+		utils.Error("[DownloadPart] 文件ID=%d 分片=%d 下载失败: %v", req.FileId, req.PartNumber, err)
 		return err
 	}
-
-	resp := &filepb.DownloadResponse{
-		Data: chunkData,
-		Md5:  md5,
+	if err := w.Flush(); err != nil {
+		return err
 	}
 
-	return stream.Send(resp)
+	return stream.Send(&filepb.DownloadResponse{
+		Md5:      hex.EncodeToString(hash.Sum(nil)),
+		Last:     true,
+		Size:     size,
+		PartEtag: etag,
+	})
+}
+
+// 按字节范围流式读取文件，每条消息不超过 streamChunkSize
+func (s *FileServiceServer) ReadFile(req *filepb.ReadFileRequest, stream filepb.FileService_ReadFileServer) error {
+	w := &chunkWriter{send: func(data []byte) error {
+		return stream.Send(&filepb.ReadFileResponse{Data: data})
+	}}
+	if err := s.storage.ReadFile(stream.Context(), req.FileId, req.VersionId, req.Offset, req.Length, w); err != nil {
+		utils.Error("[ReadFile] 文件ID=%d 读取失败: %v", req.FileId, err)
+		return err
+	}
+	return w.Flush()
 }
 
 func (s *FileServiceServer) GetFileInfo(ctx context.Context, req *filepb.GetFileInfoRequest) (*filepb.GetFileInfoResponse, error) {
@@ -170,20 +235,31 @@ func (s *FileServiceServer) GetFileInfo(ctx context.Context, req *filepb.GetFile
 	}
 
 	return &filepb.GetFileInfoResponse{
-		File: &filepb.FileInfo{
-			Id:     file.ID,
-			Name:   file.FileName,
-			Size:   file.Size,
-			UserID: file.UserID,
-			Md5:    file.Md5,
-			Status: int32(file.Status),
-		},
+		File: toFileInfo(file),
 	}, nil
 }
 
-// 生成预签名URL
+// 获取缩略图
+func (s *FileServiceServer) GetThumbnail(ctx context.Context, req *filepb.GetThumbnailRequest) (*filepb.GetThumbnailResponse, error) {
+	thumb, err := s.storage.GetThumbnail(ctx, req.FileId, int(req.Size))
+	if err != nil {
+		return nil, err
+	}
+
+	return &filepb.GetThumbnailResponse{
+		Data:     thumb.Data,
+		MimeType: thumb.MimeType,
+		Size:     int32(thumb.Size),
+		Pending:  thumb.Pending,
+	}, nil
+}
+
+// 生成预签名URL，不支持预签名下载时返回 FailedPrecondition，调用方可改为代理下载
 func (s *FileServiceServer) GeneratePresignedURL(ctx context.Context, req *filepb.GeneratePresignedURLRequest) (*filepb.GeneratePresignedURLResponse, error) {
-	url, expireAt, err := s.storage.GeneratePresignedURL(ctx, req.FileId, req.ExpireSeconds)
+	url, expireAt, err := s.storage.GeneratePresignedURL(ctx, req.FileId, req.VersionId, req.ExpireSeconds)
+	if errors.Is(err, service.ErrPresignUnsupported) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -194,9 +270,9 @@ func (s *FileServiceServer) GeneratePresignedURL(ctx context.Context, req *filep
 	}, nil
 }
 
-// 删除文件
+// 删除文件（移入回收站）
 func (s *FileServiceServer) DeleteFile(ctx context.Context, req *filepb.DeleteRequest) (*emptypb.Empty, error) {
-	err := s.storage.DeleteFile(ctx, req.FileId)
+	err := s.storage.TrashFile(ctx, req.UserId, req.FileId)
 	if err != nil {
 		return nil, err
 	}
@@ -205,8 +281,10 @@ func (s *FileServiceServer) DeleteFile(ctx context.Context, req *filepb.DeleteRe
 
 // 获取上传进度
 func (s *FileServiceServer) GetUploadProgress(ctx context.Context, req *filepb.GetUploadProgressRequest) (*filepb.GetUploadProgressResponse, error) {
+	// 导入失败时上传已被取消，仍返回导入状态
+	importStatus, importError := s.storage.ImportStatus(req.FileId)
 	uploadedSize, totalSize, err := s.storage.GetUploadProgress(req.FileId)
-	if err != nil {
+	if err != nil && importStatus == "" {
 		return nil, err
 	}
 
@@ -220,6 +298,8 @@ func (s *FileServiceServer) GetUploadProgress(ctx context.Context, req *filepb.G
 		UploadedSize: uploadedSize,
 		TotalSize:    totalSize,
 		Progress:     progress,
+		ImportStatus: importStatus,
+		ImportError:  importError,
 	}, nil
 }
 
@@ -241,9 +321,46 @@ func (s *FileServiceServer) GetIncompleteParts(ctx context.Context, req *filepb.
 	}, nil
 }
 
+// 列出已上传的分片
+func (s *FileServiceServer) ListUploadedParts(ctx context.Context, req *filepb.ListUploadedPartsRequest) (*filepb.ListUploadedPartsResponse, error) {
+	parts, err := s.storage.ListUploadedParts(req.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &filepb.ListUploadedPartsResponse{Parts: make([]*filepb.UploadedPart, 0, len(parts))}
+	for _, p := range parts {
+		resp.Parts = append(resp.Parts, &filepb.UploadedPart{
+			PartNumber: int32(p.PartNumber),
+			Size:       p.Size,
+			Md5:        p.Md5,
+		})
+	}
+	return resp, nil
+}
+
+// 获取按偏移续传的上传状态
+func (s *FileServiceServer) GetUploadOffset(ctx context.Context, req *filepb.GetUploadOffsetRequest) (*filepb.GetUploadOffsetResponse, error) {
+	state, err := s.storage.GetUploadOffset(req.UserId, req.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &filepb.GetUploadOffsetResponse{
+		Offset:    state.Offset,
+		TotalSize: state.Size,
+		PartSize:  state.PartSize,
+		Completed: state.Completed,
+	}
+	if state.ExpiresAt != nil {
+		resp.ExpiresAt = state.ExpiresAt.Unix()
+	}
+	return resp, nil
+}
+
 // 取消上传
 func (s *FileServiceServer) CancelUpload(ctx context.Context, req *filepb.CancelUploadRequest) (*emptypb.Empty, error) {
-	err := s.storage.DeleteFile(ctx, req.FileId)
+	err := s.storage.CancelUpload(ctx, req.FileId)
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +26,7 @@ func (m *MockStorageService) UploadPartStream(ctx context.Context, fileID int64,
 }
 
 // 其他必须实现的方法
-func (m *MockStorageService) InitUpload(ctx context.Context, fileName string, size int64, md5 string, userID int64) (*model.File, error) {
+func (m *MockStorageService) InitUpload(ctx context.Context, fileName string, size int64, md5, sha256 string, userID int64, folderID int64) (*model.File, error) {
 	return &model.File{}, nil
 }
 
@@ -38,15 +38,15 @@ func (m *MockStorageService) UploadComplete(ctx context.Context, fileID int64) e
 	return nil
 }
 
-func (m *MockStorageService) DownloadChunk(ctx context.Context, fileID int64, chunkIndex int, startOffset int64) ([]byte, string, error) {
-	return []byte{}, "", nil
+func (m *MockStorageService) DownloadChunk(ctx context.Context, fileID, versionID int64, chunkIndex int, startOffset, length int64, w io.Writer) (int64, string, error) {
+	return 0, "", nil
 }
 
 func (m *MockStorageService) GetFileInfo(ctx context.Context, fileID int64) (*model.File, error) {
 	return &model.File{}, nil
 }
 
-func (m *MockStorageService) GeneratePresignedURL(ctx context.Context, fileID int64, expireSeconds int32) (string, int64, error) {
+func (m *MockStorageService) GeneratePresignedURL(ctx context.Context, fileID, versionID int64, expireSeconds int32) (string, int64, error) {
 	return "", 0, nil
 }
 
@@ -0,0 +1,42 @@
+package api
+
+// streamChunkSize 流式响应中单条消息携带的最大数据量
+const streamChunkSize = 256 * 1024
+
+// chunkWriter 把写入的数据攒成不超过 streamChunkSize 的块逐条发送，内存占用与文件大小无关
+type chunkWriter struct {
+	send func(data []byte) error
+	buf  []byte
+}
+
+// Write 实现 io.Writer 接口
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	if w.buf == nil {
+		w.buf = make([]byte, 0, streamChunkSize)
+	}
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+		if len(w.buf) == cap(w.buf) {
+			if err := w.Flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Flush 发送缓冲区中剩余的数据
+func (w *chunkWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	// 发送后会复用缓冲区，需要拷贝一份
+	data := make([]byte, len(w.buf))
+	copy(data, w.buf)
+	w.buf = w.buf[:0]
+	return w.send(data)
+}
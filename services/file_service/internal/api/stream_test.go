@@ -0,0 +1,47 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestChunkWriter_SplitsIntoBoundedMessages(t *testing.T) {
+	var msgs [][]byte
+	w := &chunkWriter{send: func(data []byte) error {
+		msgs = append(msgs, data)
+		return nil
+	}}
+
+	src := bytes.Repeat([]byte("0123456789"), streamChunkSize/4)
+	if _, err := io.CopyBuffer(w, bytes.NewReader(src), make([]byte, 3000)); err != nil {
+		t.Fatalf("copy error = %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	var got []byte
+	for i, m := range msgs {
+		if len(m) > streamChunkSize {
+			t.Errorf("message %d has %d bytes, want <= %d", i, len(m), streamChunkSize)
+		}
+		if i < len(msgs)-1 && len(m) != streamChunkSize {
+			t.Errorf("message %d has %d bytes, want full chunk", i, len(m))
+		}
+		got = append(got, m...)
+	}
+	if !bytes.Equal(got, src) {
+		t.Errorf("reassembled %d bytes, want %d", len(got), len(src))
+	}
+}
+
+func TestChunkWriter_FlushEmpty(t *testing.T) {
+	w := &chunkWriter{send: func(data []byte) error {
+		t.Errorf("send called with %d bytes on empty writer", len(data))
+		return nil
+	}}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+}
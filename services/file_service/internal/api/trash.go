@@ -0,0 +1,44 @@
+package api
+
+import (
+	filepb "cloud-storage-file-service/proto"
+	"context"
+)
+
+// 列出回收站
+func (s *FileServiceServer) ListTrash(ctx context.Context, req *filepb.ListTrashRequest) (*filepb.ListTrashResponse, error) {
+	files, total, err := s.storage.ListTrash(ctx, req.UserId, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &filepb.ListTrashResponse{
+		Files: make([]*filepb.FileInfo, 0, len(files)),
+		Total: total,
+	}
+	for i := range files {
+		resp.Files = append(resp.Files, toFileInfo(&files[i]))
+	}
+	return resp, nil
+}
+
+// 从回收站恢复文件
+func (s *FileServiceServer) RestoreFile(ctx context.Context, req *filepb.RestoreFileRequest) (*filepb.RestoreFileResponse, error) {
+	file, err := s.storage.RestoreFile(ctx, req.UserId, req.FileId)
+	if err != nil {
+		return nil, err
+	}
+	return &filepb.RestoreFileResponse{File: toFileInfo(file)}, nil
+}
+
+// 清空回收站
+func (s *FileServiceServer) EmptyTrash(ctx context.Context, req *filepb.EmptyTrashRequest) (*filepb.EmptyTrashResponse, error) {
+	count, freed, err := s.storage.EmptyTrash(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &filepb.EmptyTrashResponse{
+		PurgedCount: count,
+		FreedSize:   freed,
+	}, nil
+}
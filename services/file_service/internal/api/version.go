@@ -0,0 +1,68 @@
+package api
+
+import (
+	"cloud-storage-file-service/internal/model"
+	filepb "cloud-storage-file-service/proto"
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// toVersionInfo 将版本记录转换为 proto 中的 VersionInfo
+func toVersionInfo(file *model.File, version *model.FileVersion) *filepb.VersionInfo {
+	return &filepb.VersionInfo{
+		Id:        version.ID,
+		FileId:    version.FileID,
+		Version:   int32(version.VersionNo),
+		Size:      version.Size,
+		Md5:       version.Md5,
+		Status:    int32(version.Status),
+		CreatedAt: version.CreatedAt.Unix(),
+		IsCurrent: file.CurrentVersionID == version.ID,
+		Sha256:    version.Sha256,
+		MimeType:  version.MimeType,
+		Mtime:     unixOrZero(version.Mtime),
+	}
+}
+
+// 列出文件版本
+func (s *FileServiceServer) ListVersions(ctx context.Context, req *filepb.ListVersionsRequest) (*filepb.ListVersionsResponse, error) {
+	file, versions, err := s.storage.ListVersions(ctx, req.UserId, req.FileId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &filepb.ListVersionsResponse{
+		Versions: make([]*filepb.VersionInfo, 0, len(versions)),
+	}
+	for i := range versions {
+		resp.Versions = append(resp.Versions, toVersionInfo(file, &versions[i]))
+	}
+	return resp, nil
+}
+
+// 获取文件版本
+func (s *FileServiceServer) GetVersion(ctx context.Context, req *filepb.GetVersionRequest) (*filepb.GetVersionResponse, error) {
+	file, version, err := s.storage.GetVersion(ctx, req.UserId, req.FileId, req.VersionId)
+	if err != nil {
+		return nil, err
+	}
+	return &filepb.GetVersionResponse{Version: toVersionInfo(file, version)}, nil
+}
+
+// 恢复历史版本
+func (s *FileServiceServer) RestoreVersion(ctx context.Context, req *filepb.RestoreVersionRequest) (*filepb.RestoreVersionResponse, error) {
+	file, err := s.storage.RestoreVersion(ctx, req.UserId, req.FileId, req.VersionId)
+	if err != nil {
+		return nil, err
+	}
+	return &filepb.RestoreVersionResponse{File: toFileInfo(file)}, nil
+}
+
+// 删除历史版本
+func (s *FileServiceServer) DeleteVersion(ctx context.Context, req *filepb.DeleteVersionRequest) (*emptypb.Empty, error) {
+	if err := s.storage.DeleteVersion(ctx, req.UserId, req.FileId, req.VersionId); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
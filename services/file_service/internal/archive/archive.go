@@ -0,0 +1,244 @@
+// Package archive 读取 ZIP 和 tar（可用 gzip 压缩）压缩包的条目，用于在服务端解压
+//
+// 条目路径统一使用 / 分隔，绝对路径、盘符和包含 .. 的路径一律拒绝；条目数、解压后总大小和目录层数超过限制时停止读取。
+// 只返回普通文件和目录，符号链接、硬链接和设备文件等特殊条目标记为 TypeOther，由调用方跳过。
+// 压缩包内的压缩包不会继续展开。
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// 压缩包格式
+const (
+	FormatZip   = "zip"
+	FormatTar   = "tar"
+	FormatTarGz = "tar.gz"
+)
+
+// HeadLen Detect 需要的开头字节数
+const HeadLen = 512
+
+var (
+	ErrUnsupported = errors.New("unsupported archive format")
+	ErrUnsafePath  = errors.New("unsafe entry path")
+	ErrTooMany     = errors.New("too many entries")
+	ErrTooLarge    = errors.New("expanded size exceeds limit")
+	ErrTooDeep     = errors.New("entry nested too deep")
+)
+
+// 条目类型
+const (
+	TypeFile  = iota // 普通文件
+	TypeDir          // 目录
+	TypeOther        // 符号链接等特殊条目
+)
+
+// Entry 压缩包中的一个条目
+type Entry struct {
+	Path    string // 清理后的相对路径，使用 / 分隔
+	Type    int
+	Size    int64 // 解压后的大小，目录为0
+	ModTime time.Time
+}
+
+// Limits 解压限制，不大于0的项不限制
+type Limits struct {
+	MaxEntries  int   // 条目数，包括目录
+	MaxExpanded int64 // 普通文件解压后的总大小
+	MaxDepth    int   // 路径的层数，a/b.txt 为2
+}
+
+// Source 压缩包内容：ZIP 需要按偏移随机读取，tar 从头顺序读取
+type Source interface {
+	io.ReaderAt
+	Size() int64
+	// Open 从头顺序读取全部内容
+	Open() (io.ReadCloser, error)
+}
+
+// Detect 按开头的魔数识别格式，gzip 压缩的内容视为 tar.gz，无法识别时返回空字符串
+func Detect(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		return FormatZip
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return FormatTarGz
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		return FormatTar
+	}
+	return ""
+}
+
+// CleanPath 清理条目路径，返回的路径为空表示压缩包根目录本身
+// 使用 \ 分隔的路径按 / 处理；绝对路径、盘符和 .. 返回 ErrUnsafePath
+func CleanPath(name string) (string, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if strings.HasPrefix(name, "/") || (len(name) >= 2 && name[1] == ':') {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+	}
+	var parts []string
+	for _, part := range strings.Split(name, "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "/"), nil
+}
+
+// Walk 按压缩包中的顺序对每个条目调用 fn，r 为条目内容，只有普通文件可读
+// 不读取 r 时跳过条目内容；fn 返回错误时停止并返回该错误
+func Walk(format string, src Source, limits Limits, fn func(e Entry, r io.Reader) error) error {
+	w := &walker{limits: limits, fn: fn}
+	switch format {
+	case FormatZip:
+		return w.zip(src)
+	case FormatTar, FormatTarGz:
+		return w.tar(src, format == FormatTarGz)
+	}
+	return ErrUnsupported
+}
+
+type walker struct {
+	limits   Limits
+	fn       func(Entry, io.Reader) error
+	entries  int
+	expanded int64
+}
+
+// visit 校验条目路径和限制后调用 fn
+func (w *walker) visit(name string, typ int, size int64, modTime time.Time, r io.Reader) error {
+	p, err := CleanPath(name)
+	if err != nil {
+		return err
+	}
+	if p == "" {
+		return nil
+	}
+	w.entries++
+	if w.limits.MaxEntries > 0 && w.entries > w.limits.MaxEntries {
+		return fmt.Errorf("%w: more than %d", ErrTooMany, w.limits.MaxEntries)
+	}
+	if depth := strings.Count(p, "/") + 1; w.limits.MaxDepth > 0 && depth > w.limits.MaxDepth {
+		return fmt.Errorf("%w: %s", ErrTooDeep, p)
+	}
+	if typ != TypeFile {
+		size = 0
+		r = bytes.NewReader(nil)
+	}
+	if size < 0 {
+		return fmt.Errorf("%w: negative size for %s", ErrUnsupported, p)
+	}
+	w.expanded += size
+	if w.limits.MaxExpanded > 0 && w.expanded > w.limits.MaxExpanded {
+		return fmt.Errorf("%w: more than %d bytes", ErrTooLarge, w.limits.MaxExpanded)
+	}
+	return w.fn(Entry{Path: p, Type: typ, Size: size, ModTime: modTime}, r)
+}
+
+func (w *walker) zip(src Source) error {
+	zr, err := zip.NewReader(src, src.Size())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	for _, f := range zr.File {
+		typ := TypeFile
+		switch mode := f.Mode(); {
+		case mode.IsDir():
+			typ = TypeDir
+		case !mode.IsRegular():
+			typ = TypeOther
+		}
+		r := &lazyReader{open: f.Open}
+		err := w.visit(f.Name, typ, int64(f.UncompressedSize64), f.Modified, r)
+		r.close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) tar(src Source, gzipped bool) error {
+	rc, err := src.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	var r io.Reader = rc
+	if gzipped {
+		gz, err := gzip.NewReader(rc)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnsupported, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read tar: %w", err)
+		}
+		typ := TypeOther
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			typ = TypeFile
+		case tar.TypeDir:
+			typ = TypeDir
+		case tar.TypeXGlobalHeader:
+			continue
+		}
+		if err := w.visit(hdr.Name, typ, hdr.Size, hdr.ModTime, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// lazyReader 第一次读取时才打开 ZIP 条目，只列举条目时不解压
+type lazyReader struct {
+	open func() (io.ReadCloser, error)
+	rc   io.ReadCloser
+	err  error
+}
+
+func (l *lazyReader) Read(p []byte) (int, error) {
+	if l.rc == nil && l.err == nil {
+		l.rc, l.err = l.open()
+	}
+	if l.err != nil {
+		return 0, l.err
+	}
+	return l.rc.Read(p)
+}
+
+func (l *lazyReader) close() {
+	if l.rc != nil {
+		l.rc.Close()
+	}
+}
+
+// Dir 返回条目所在目录的路径，位于根目录时为空
+func Dir(p string) string {
+	dir := path.Dir(p)
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
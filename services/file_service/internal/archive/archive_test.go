@@ -0,0 +1,142 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"testing"
+)
+
+type memSource struct{ *bytes.Reader }
+
+func (m memSource) Open() (io.ReadCloser, error) {
+	return io.NopCloser(io.NewSectionReader(m.Reader, 0, m.Reader.Size())), nil
+}
+
+func newSource(data []byte) memSource { return memSource{bytes.NewReader(data)} }
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func buildTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))})
+		tw.Write([]byte(content))
+	}
+	tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"})
+	tw.Close()
+	gw.Close()
+	return buf.Bytes()
+}
+
+func collect(t *testing.T, data []byte, limits Limits) (map[string]string, []string, error) {
+	t.Helper()
+	format := Detect(data[:min(len(data), HeadLen)])
+	files := make(map[string]string)
+	var others []string
+	err := Walk(format, newSource(data), limits, func(e Entry, r io.Reader) error {
+		switch e.Type {
+		case TypeFile:
+			content, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			files[e.Path] = string(content)
+		case TypeOther:
+			others = append(others, e.Path)
+		}
+		return nil
+	})
+	return files, others, err
+}
+
+func TestWalk(t *testing.T) {
+	want := map[string]string{"a.txt": "hello", "dir/sub/b.txt": "world", "./c.txt": "!"}
+	for _, tc := range []struct {
+		name   string
+		data   []byte
+		format string
+	}{
+		{"zip", buildZip(t, want), FormatZip},
+		{"tar.gz", buildTarGz(t, want), FormatTarGz},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Detect(tc.data[:min(len(tc.data), HeadLen)]); got != tc.format {
+				t.Fatalf("Detect = %q, want %q", got, tc.format)
+			}
+			files, others, err := collect(t, tc.data, Limits{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != 3 || files["a.txt"] != "hello" || files["dir/sub/b.txt"] != "world" || files["c.txt"] != "!" {
+				t.Fatalf("unexpected files: %v", files)
+			}
+			if tc.format == FormatTarGz && (len(others) != 1 || others[0] != "link") {
+				t.Fatalf("symlink should be reported as other, got %v", others)
+			}
+		})
+	}
+}
+
+func TestWalkLimits(t *testing.T) {
+	data := buildZip(t, map[string]string{"a/b/c/d.txt": "1234", "e.txt": "5678"})
+	for _, tc := range []struct {
+		limits Limits
+		want   error
+	}{
+		{Limits{MaxEntries: 1}, ErrTooMany},
+		{Limits{MaxExpanded: 7}, ErrTooLarge},
+		{Limits{MaxDepth: 3}, ErrTooDeep},
+	} {
+		if _, _, err := collect(t, data, tc.limits); !errors.Is(err, tc.want) {
+			t.Errorf("limits %+v: err = %v, want %v", tc.limits, err, tc.want)
+		}
+	}
+	if _, _, err := collect(t, data, Limits{MaxEntries: 2, MaxExpanded: 8, MaxDepth: 4}); err != nil {
+		t.Errorf("archive within limits: %v", err)
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	for name, want := range map[string]string{
+		"a/b.txt":    "a/b.txt",
+		"./a//b.txt": "a/b.txt",
+		`a\b.txt`:    "a/b.txt",
+		"dir/":       "dir",
+		"./":         "",
+	} {
+		if got, err := CleanPath(name); err != nil || got != want {
+			t.Errorf("CleanPath(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	for _, name := range []string{"../x", "a/../../x", "/etc/passwd", `C:\x`, `..\x`} {
+		if _, err := CleanPath(name); !errors.Is(err, ErrUnsafePath) {
+			t.Errorf("CleanPath(%q) err = %v, want ErrUnsafePath", name, err)
+		}
+	}
+	data := buildZip(t, map[string]string{"../evil.txt": "x"})
+	if _, _, err := collect(t, data, Limits{}); !errors.Is(err, ErrUnsafePath) {
+		t.Errorf("traversal entry: err = %v, want ErrUnsafePath", err)
+	}
+}
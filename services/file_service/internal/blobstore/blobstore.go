@@ -0,0 +1,106 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"cloud-storage-file-service/config"
+)
+
+var (
+	// ErrNotFound 对象或分片上传不存在
+	ErrNotFound = errors.New("blobstore: object not found")
+	// ErrPresignUnsupported 后端不支持生成预签名链接
+	ErrPresignUnsupported = errors.New("blobstore: presigned url not supported")
+	// ErrBadDigest 写入内容与声明的 MD5 不一致
+	ErrBadDigest = errors.New("blobstore: content md5 mismatch")
+)
+
+// ObjectInfo 对象信息
+type ObjectInfo struct {
+	Key     string
+	Size    int64
+	ETag    string    // 部分后端在 Stat 时不返回
+	ModTime time.Time // 最后写入时间，只在 List 时返回，内存后端不返回
+}
+
+// PartInfo 分片上传中的一个分片
+type PartInfo struct {
+	PartNumber int
+	ETag       string
+	Size       int64
+}
+
+// BlobStore 对象存储后端，所有 key 都位于同一个存储空间下
+type BlobStore interface {
+	// Put 写入对象，size 为 -1 表示大小未知
+	Put(ctx context.Context, key string, r io.Reader, size int64) (ObjectInfo, error)
+	// Get 读取对象从 offset 开始的 length 字节，length 为0时读到末尾
+	Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	// Stat 获取对象信息，对象不存在时返回 ErrNotFound
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Delete 删除对象，对象不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// Compose 按顺序拼接 srcs 写入 dst
+	Compose(ctx context.Context, dst string, srcs []string) error
+	// List 按 key 的字典序对前缀为 prefix 的每个对象调用 fn，不包括未完成的分片上传，fn 返回错误时停止并返回该错误
+	List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+
+	// NewMultipartUpload 开始一次分片上传，返回上传ID
+	NewMultipartUpload(ctx context.Context, key string) (string, error)
+	// PutPart 写入一个分片，md5Hex 非空时校验内容
+	PutPart(ctx context.Context, key, uploadID string, partNumber int, r io.Reader, size int64, md5Hex string) (PartInfo, error)
+	// CompleteMultipartUpload 按 parts 的顺序合并分片为对象
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []PartInfo) (ObjectInfo, error)
+	// AbortMultipartUpload 中止分片上传并丢弃已上传的分片，上传不存在时不报错
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+
+	// PresignGet 生成临时下载链接，contentType 非空时链接以该类型返回内容，不支持时返回 ErrPresignUnsupported
+	PresignGet(ctx context.Context, key string, expiry time.Duration, contentType string) (string, error)
+}
+
+// New 按配置创建存储后端，storage.backend 可选 minio（默认）、local、memory
+func New(ctx context.Context, cfg *config.Config, bucket string) (BlobStore, error) {
+	switch cfg.Storage.Backend {
+	case "", "minio":
+		return NewMinioStore(ctx, cfg.Minio, bucket)
+	case "local":
+		return NewLocalStore(cfg.Storage.Path)
+	case "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", cfg.Storage.Backend)
+	}
+}
+
+// newUploadID 生成分片上传ID
+func newUploadID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Errorf("blobstore: generate upload id: %w", err))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// copyChecked 把 r 写入 w 并计算 MD5，md5Hex 非空时校验，size 非负时校验长度
+func copyChecked(w io.Writer, r io.Reader, size int64, md5Hex string) (int64, string, error) {
+	hash := md5.New()
+	n, err := io.Copy(io.MultiWriter(w, hash), r)
+	if err != nil {
+		return n, "", err
+	}
+	if size >= 0 && n != size {
+		return n, "", fmt.Errorf("blobstore: wrote %d bytes, want %d", n, size)
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if md5Hex != "" && sum != md5Hex {
+		return n, "", ErrBadDigest
+	}
+	return n, sum, nil
+}
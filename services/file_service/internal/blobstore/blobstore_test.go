@@ -0,0 +1,254 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// backends 返回需要测试的后端，MinIO 需要真实服务，不在单元测试中覆盖
+func backends(t *testing.T) map[string]BlobStore {
+	local, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	return map[string]BlobStore{
+		"local":  local,
+		"memory": NewMemoryStore(),
+	}
+}
+
+func readAll(t *testing.T, store BlobStore, key string, offset, length int64) string {
+	t.Helper()
+	r, err := store.Get(context.Background(), key, offset, length)
+	if err != nil {
+		t.Fatalf("Get(%q, %d, %d) error = %v", key, offset, length, err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+	return string(data)
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestBlobStore_PutGetStatDelete(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			info, err := store.Put(ctx, "objects/ab/cd/abcd", strings.NewReader("hello world"), 11)
+			if err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			if info.Size != 11 {
+				t.Errorf("Put() size = %d, want 11", info.Size)
+			}
+
+			if got := readAll(t, store, "objects/ab/cd/abcd", 0, 0); got != "hello world" {
+				t.Errorf("Get() = %q", got)
+			}
+			if got := readAll(t, store, "objects/ab/cd/abcd", 6, 0); got != "world" {
+				t.Errorf("Get(offset) = %q", got)
+			}
+			if got := readAll(t, store, "objects/ab/cd/abcd", 2, 3); got != "llo" {
+				t.Errorf("Get(range) = %q", got)
+			}
+
+			stat, err := store.Stat(ctx, "objects/ab/cd/abcd")
+			if err != nil || stat.Size != 11 {
+				t.Errorf("Stat() = %+v, %v", stat, err)
+			}
+
+			if err := store.Delete(ctx, "objects/ab/cd/abcd"); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if err := store.Delete(ctx, "objects/ab/cd/abcd"); err != nil {
+				t.Errorf("Delete() of missing object error = %v", err)
+			}
+			if _, err := store.Stat(ctx, "objects/ab/cd/abcd"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Stat() after delete error = %v, want ErrNotFound", err)
+			}
+			if _, err := store.Get(ctx, "objects/ab/cd/abcd", 0, 0); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get() after delete error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestBlobStore_Multipart(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			uploadID, err := store.NewMultipartUpload(ctx, "big")
+			if err != nil {
+				t.Fatalf("NewMultipartUpload() error = %v", err)
+			}
+
+			// 分片可以乱序上传，重复上传同一分片以最后一次为准
+			chunks := map[int]string{2: "second-", 1: "first-", 3: "third"}
+			var parts []PartInfo
+			for _, n := range []int{2, 1, 3} {
+				if _, err := store.PutPart(ctx, "big", uploadID, n, strings.NewReader("stale"), -1, ""); err != nil {
+					t.Fatalf("PutPart(%d) error = %v", n, err)
+				}
+				p, err := store.PutPart(ctx, "big", uploadID, n, strings.NewReader(chunks[n]), int64(len(chunks[n])), md5Hex(chunks[n]))
+				if err != nil {
+					t.Fatalf("PutPart(%d) error = %v", n, err)
+				}
+				parts = append(parts, p)
+			}
+			parts[0], parts[1] = parts[1], parts[0]
+
+			info, err := store.CompleteMultipartUpload(ctx, "big", uploadID, parts)
+			if err != nil {
+				t.Fatalf("CompleteMultipartUpload() error = %v", err)
+			}
+			if info.Size != int64(len("first-second-third")) {
+				t.Errorf("complete size = %d", info.Size)
+			}
+			if got := readAll(t, store, "big", 0, 0); got != "first-second-third" {
+				t.Errorf("Get() = %q", got)
+			}
+		})
+	}
+}
+
+func TestBlobStore_PutPartRejectsBadDigest(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			uploadID, err := store.NewMultipartUpload(ctx, "obj")
+			if err != nil {
+				t.Fatalf("NewMultipartUpload() error = %v", err)
+			}
+			_, err = store.PutPart(ctx, "obj", uploadID, 1, strings.NewReader("data"), 4, md5Hex("other"))
+			if !errors.Is(err, ErrBadDigest) {
+				t.Errorf("PutPart() error = %v, want ErrBadDigest", err)
+			}
+		})
+	}
+}
+
+func TestBlobStore_AbortMultipart(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			uploadID, err := store.NewMultipartUpload(ctx, "obj")
+			if err != nil {
+				t.Fatalf("NewMultipartUpload() error = %v", err)
+			}
+			if _, err := store.PutPart(ctx, "obj", uploadID, 1, strings.NewReader("data"), 4, ""); err != nil {
+				t.Fatalf("PutPart() error = %v", err)
+			}
+			if err := store.AbortMultipartUpload(ctx, "obj", uploadID); err != nil {
+				t.Fatalf("AbortMultipartUpload() error = %v", err)
+			}
+			if err := store.AbortMultipartUpload(ctx, "obj", uploadID); err != nil {
+				t.Errorf("AbortMultipartUpload() twice error = %v", err)
+			}
+			if _, err := store.PutPart(ctx, "obj", uploadID, 2, strings.NewReader("data"), 4, ""); !errors.Is(err, ErrNotFound) {
+				t.Errorf("PutPart() after abort error = %v, want ErrNotFound", err)
+			}
+			if _, err := store.Stat(ctx, "obj"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Stat() after abort error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestBlobStore_Compose(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			for key, data := range map[string]string{"a": "foo", "b": "bar"} {
+				if _, err := store.Put(ctx, key, bytes.NewReader([]byte(data)), int64(len(data))); err != nil {
+					t.Fatalf("Put(%q) error = %v", key, err)
+				}
+			}
+			if err := store.Compose(ctx, "ab", []string{"a", "b"}); err != nil {
+				t.Fatalf("Compose() error = %v", err)
+			}
+			if got := readAll(t, store, "ab", 0, 0); got != "foobar" {
+				t.Errorf("Get() = %q, want foobar", got)
+			}
+			if err := store.Compose(ctx, "x", []string{"a", "missing"}); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Compose() with missing source error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestBlobStore_List(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{"objects/b/2", "objects/a/1", "chunks/x", "objects/a/3"} {
+				if _, err := store.Put(ctx, key, strings.NewReader(key), int64(len(key))); err != nil {
+					t.Fatalf("Put(%q) error = %v", key, err)
+				}
+			}
+			// 未完成的分片上传不会被列出
+			uploadID, err := store.NewMultipartUpload(ctx, "objects/c")
+			if err != nil {
+				t.Fatalf("NewMultipartUpload() error = %v", err)
+			}
+			if _, err := store.PutPart(ctx, "objects/c", uploadID, 1, strings.NewReader("data"), 4, ""); err != nil {
+				t.Fatalf("PutPart() error = %v", err)
+			}
+
+			var keys []string
+			err = store.List(ctx, "objects/", func(info ObjectInfo) error {
+				if info.Size != int64(len(info.Key)) {
+					t.Errorf("List() %s size = %d", info.Key, info.Size)
+				}
+				keys = append(keys, info.Key)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if got := strings.Join(keys, ","); got != "objects/a/1,objects/a/3,objects/b/2" {
+				t.Errorf("List() = %s", got)
+			}
+
+			stop := errors.New("stop")
+			calls := 0
+			err = store.List(ctx, "", func(ObjectInfo) error {
+				calls++
+				return stop
+			})
+			if !errors.Is(err, stop) || calls != 1 {
+				t.Errorf("List() stop = %v after %d calls", err, calls)
+			}
+		})
+	}
+}
+
+func TestLocalStore_RejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	for _, key := range []string{"", "/", ".multipart/x"} {
+		if _, err := store.Put(context.Background(), key, strings.NewReader("x"), 1); err == nil {
+			t.Errorf("Put(%q) succeeded, want error", key)
+		}
+	}
+	// 以 .. 开头的 key 被限制在根目录内
+	if _, err := store.Put(context.Background(), "../../escape", strings.NewReader("x"), 1); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if got := readAll(t, store, "escape", 0, 0); got != "x" {
+		t.Errorf("escaping key was not confined to root, got %q", got)
+	}
+}
@@ -0,0 +1,284 @@
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// multipartDir 本地后端存放未完成分片上传的目录
+const multipartDir = ".multipart"
+
+// LocalStore 基于本地文件系统的存储后端，适合单机部署
+// 对象保存在 root 下与 key 同名的路径，写入先落临时文件再改名，读取方不会看到写了一半的对象
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore 以 root 为根目录创建本地存储后端，目录不存在时创建
+func NewLocalStore(root string) (*LocalStore, error) {
+	if root == "" {
+		return nil, fmt.Errorf("local storage backend requires storage.path")
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(root, multipartDir), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStore{root: root}, nil
+}
+
+// path 返回 key 对应的文件路径，拒绝跳出根目录的 key
+func (l *LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.HasPrefix(clean, "/"+multipartDir+"/") {
+		return "", fmt.Errorf("blobstore: invalid key %q", key)
+	}
+	return filepath.Join(l.root, filepath.FromSlash(clean)), nil
+}
+
+// uploadDir 返回分片上传的目录，拒绝不合法的上传ID
+func (l *LocalStore) uploadDir(uploadID string) (string, error) {
+	if uploadID == "" || strings.ContainsAny(uploadID, `/\.`) {
+		return "", fmt.Errorf("blobstore: invalid upload id %q", uploadID)
+	}
+	return filepath.Join(l.root, multipartDir, uploadID), nil
+}
+
+// writeFile 把 r 写入临时文件后改名为 path
+func writeFile(path string, r io.Reader, size int64, md5Hex string) (int64, string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return 0, "", err
+	}
+	n, sum, err := copyChecked(tmp, r, size, md5Hex)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return 0, "", err
+	}
+	return n, sum, nil
+}
+
+func (l *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64) (ObjectInfo, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	n, sum, err := writeFile(path, r, size, "")
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Key: key, Size: n, ETag: sum}, nil
+}
+
+// fileReader 只读取文件一段内容的 ReadCloser
+type fileReader struct {
+	io.Reader
+	f *os.File
+}
+
+func (r *fileReader) Close() error {
+	return r.f.Close()
+}
+
+func (l *LocalStore) Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	var reader io.Reader = f
+	if length > 0 {
+		reader = io.LimitReader(f, length)
+	}
+	return &fileReader{Reader: reader, f: f}, nil
+}
+
+func (l *LocalStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return ObjectInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Key: key, Size: info.Size()}, nil
+}
+
+// List 遍历根目录，跳过未完成的分片上传和写了一半的临时文件
+func (l *LocalStore) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	return filepath.WalkDir(l.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == filepath.Join(l.root, multipartDir) {
+				return filepath.SkipDir
+			}
+			return ctx.Err()
+		}
+		if strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if os.IsNotExist(err) {
+			// 遍历期间被删除
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return fn(ObjectInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()})
+	})
+}
+
+func (l *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// concat 依次读取 paths 拼接写入 dst
+func concat(dst string, paths []string) (int64, string, error) {
+	readers := make([]io.Reader, 0, len(paths))
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if os.IsNotExist(err) {
+			return 0, "", ErrNotFound
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		defer f.Close()
+		readers = append(readers, f)
+	}
+	return writeFile(dst, io.MultiReader(readers...), -1, "")
+}
+
+func (l *LocalStore) Compose(ctx context.Context, dst string, srcs []string) error {
+	dstPath, err := l.path(dst)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(srcs))
+	for _, src := range srcs {
+		p, err := l.path(src)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, p)
+	}
+	_, _, err = concat(dstPath, paths)
+	return err
+}
+
+func (l *LocalStore) NewMultipartUpload(ctx context.Context, key string) (string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+	uploadID := newUploadID()
+	dir, _ := l.uploadDir(uploadID)
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return "", err
+	}
+	return uploadID, nil
+}
+
+func (l *LocalStore) PutPart(ctx context.Context, key, uploadID string, partNumber int, r io.Reader, size int64, md5Hex string) (PartInfo, error) {
+	dir, err := l.uploadDir(uploadID)
+	if err != nil {
+		return PartInfo{}, err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return PartInfo{}, ErrNotFound
+	}
+	n, sum, err := writeFile(filepath.Join(dir, strconv.Itoa(partNumber)), r, size, md5Hex)
+	if err != nil {
+		return PartInfo{}, err
+	}
+	return PartInfo{PartNumber: partNumber, ETag: sum, Size: n}, nil
+}
+
+func (l *LocalStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []PartInfo) (ObjectInfo, error) {
+	dstPath, err := l.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	dir, err := l.uploadDir(uploadID)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if !sort.SliceIsSorted(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber }) {
+		return ObjectInfo{}, fmt.Errorf("blobstore: parts must be in ascending order")
+	}
+
+	paths := make([]string, 0, len(parts))
+	for _, p := range parts {
+		paths = append(paths, filepath.Join(dir, strconv.Itoa(p.PartNumber)))
+	}
+	n, sum, err := concat(dstPath, paths)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	os.RemoveAll(dir)
+	return ObjectInfo{Key: key, Size: n, ETag: sum}, nil
+}
+
+func (l *LocalStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	dir, err := l.uploadDir(uploadID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (l *LocalStore) PresignGet(ctx context.Context, key string, expiry time.Duration, contentType string) (string, error) {
+	return "", ErrPresignUnsupported
+}
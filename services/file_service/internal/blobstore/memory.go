@@ -0,0 +1,164 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore 内存存储后端，进程退出后数据丢失，用于测试
+type MemoryStore struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+}
+
+// NewMemoryStore 创建内存存储后端
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		objects: make(map[string][]byte),
+		uploads: make(map[string]map[int][]byte),
+	}
+}
+
+func (m *MemoryStore) Put(ctx context.Context, key string, r io.Reader, size int64) (ObjectInfo, error) {
+	var buf bytes.Buffer
+	n, sum, err := copyChecked(&buf, r, size, "")
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	m.mu.Lock()
+	m.objects[key] = buf.Bytes()
+	m.mu.Unlock()
+	return ObjectInfo{Key: key, Size: n, ETag: sum}, nil
+}
+
+func (m *MemoryStore) Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	m.mu.RLock()
+	data, ok := m.objects[key]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if offset > int64(len(data)) {
+		return nil, fmt.Errorf("blobstore: offset %d beyond object size %d", offset, len(data))
+	}
+	data = data[offset:]
+	if length > 0 && length < int64(len(data)) {
+		data = data[:length]
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *MemoryStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	m.mu.RLock()
+	data, ok := m.objects[key]
+	m.mu.RUnlock()
+	if !ok {
+		return ObjectInfo{}, ErrNotFound
+	}
+	return ObjectInfo{Key: key, Size: int64(len(data))}, nil
+}
+
+func (m *MemoryStore) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	m.mu.RLock()
+	infos := make([]ObjectInfo, 0, len(m.objects))
+	for key, data := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			infos = append(infos, ObjectInfo{Key: key, Size: int64(len(data))})
+		}
+	}
+	m.mu.RUnlock()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Key < infos[j].Key })
+	for _, info := range infos {
+		if err := fn(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.objects, key)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryStore) Compose(ctx context.Context, dst string, srcs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var buf bytes.Buffer
+	for _, src := range srcs {
+		data, ok := m.objects[src]
+		if !ok {
+			return ErrNotFound
+		}
+		buf.Write(data)
+	}
+	m.objects[dst] = buf.Bytes()
+	return nil
+}
+
+func (m *MemoryStore) NewMultipartUpload(ctx context.Context, key string) (string, error) {
+	uploadID := newUploadID()
+	m.mu.Lock()
+	m.uploads[uploadID] = make(map[int][]byte)
+	m.mu.Unlock()
+	return uploadID, nil
+}
+
+func (m *MemoryStore) PutPart(ctx context.Context, key, uploadID string, partNumber int, r io.Reader, size int64, md5Hex string) (PartInfo, error) {
+	var buf bytes.Buffer
+	n, sum, err := copyChecked(&buf, r, size, md5Hex)
+	if err != nil {
+		return PartInfo{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	parts, ok := m.uploads[uploadID]
+	if !ok {
+		return PartInfo{}, ErrNotFound
+	}
+	parts[partNumber] = buf.Bytes()
+	return PartInfo{PartNumber: partNumber, ETag: sum, Size: n}, nil
+}
+
+func (m *MemoryStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []PartInfo) (ObjectInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	uploaded, ok := m.uploads[uploadID]
+	if !ok {
+		return ObjectInfo{}, ErrNotFound
+	}
+	var buf bytes.Buffer
+	for i, p := range parts {
+		if i > 0 && p.PartNumber <= parts[i-1].PartNumber {
+			return ObjectInfo{}, fmt.Errorf("blobstore: parts must be in ascending order")
+		}
+		data, ok := uploaded[p.PartNumber]
+		if !ok {
+			return ObjectInfo{}, fmt.Errorf("blobstore: part %d not uploaded", p.PartNumber)
+		}
+		buf.Write(data)
+	}
+	m.objects[key] = buf.Bytes()
+	delete(m.uploads, uploadID)
+	return ObjectInfo{Key: key, Size: int64(buf.Len())}, nil
+}
+
+func (m *MemoryStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	m.mu.Lock()
+	delete(m.uploads, uploadID)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryStore) PresignGet(ctx context.Context, key string, expiry time.Duration, contentType string) (string, error) {
+	return "", ErrPresignUnsupported
+}
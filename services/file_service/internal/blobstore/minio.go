@@ -0,0 +1,188 @@
+package blobstore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"cloud-storage-file-service/config"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// MinioStore 基于 MinIO（S3 兼容）的存储后端
+type MinioStore struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewMinioStore 连接 MinIO，存储桶不存在时创建
+func NewMinioStore(ctx context.Context, cfg config.MinioConfig, bucket string) (*MinioStore, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create minio client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket: %w", err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket: %w", err)
+		}
+	}
+	return NewMinioStoreWithClient(client, bucket), nil
+}
+
+// NewMinioStoreWithClient 使用已有的 MinIO 客户端
+func NewMinioStoreWithClient(client *minio.Client, bucket string) *MinioStore {
+	return &MinioStore{client: client, bucket: bucket}
+}
+
+func (m *MinioStore) core() minio.Core {
+	return minio.Core{Client: m.client}
+}
+
+// toStoreError 把 S3 的不存在错误转换为 ErrNotFound
+func toStoreError(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NoSuchUpload":
+		return ErrNotFound
+	case "BadDigest", "InvalidDigest":
+		return ErrBadDigest
+	}
+	return err
+}
+
+// contentMD5 把十六进制的 MD5 转换为 Content-MD5 头使用的 base64 形式
+func contentMD5(md5Hex string) string {
+	raw, err := hex.DecodeString(md5Hex)
+	if err != nil || len(raw) != 16 {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func (m *MinioStore) Put(ctx context.Context, key string, r io.Reader, size int64) (ObjectInfo, error) {
+	info, err := m.client.PutObject(ctx, m.bucket, key, r, size, minio.PutObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, toStoreError(err)
+	}
+	return ObjectInfo{Key: key, Size: info.Size, ETag: info.ETag}, nil
+}
+
+func (m *MinioStore) Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{}
+	if length > 0 {
+		if err := opts.SetRange(offset, offset+length-1); err != nil {
+			return nil, err
+		}
+	} else if offset > 0 {
+		if err := opts.SetRange(offset, 0); err != nil {
+			return nil, err
+		}
+	}
+	object, err := m.client.GetObject(ctx, m.bucket, key, opts)
+	if err != nil {
+		return nil, toStoreError(err)
+	}
+	// GetObject 是惰性的，先取一次对象信息以便尽早发现对象不存在
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, toStoreError(err)
+	}
+	return object, nil
+}
+
+func (m *MinioStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	info, err := m.client.StatObject(ctx, m.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, toStoreError(err)
+	}
+	return ObjectInfo{Key: key, Size: info.Size, ETag: info.ETag}, nil
+}
+
+func (m *MinioStore) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	// 提前返回时取消列举，结束 ListObjects 的协程
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for obj := range m.client.ListObjects(ctx, m.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return toStoreError(obj.Err)
+		}
+		if err := fn(ObjectInfo{Key: obj.Key, Size: obj.Size, ETag: obj.ETag, ModTime: obj.LastModified}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MinioStore) Delete(ctx context.Context, key string) error {
+	return m.client.RemoveObject(ctx, m.bucket, key, minio.RemoveObjectOptions{})
+}
+
+// Compose 使用服务端拼接，大于5GB的源对象会自动分段复制
+func (m *MinioStore) Compose(ctx context.Context, dst string, srcs []string) error {
+	sources := make([]minio.CopySrcOptions, 0, len(srcs))
+	for _, src := range srcs {
+		sources = append(sources, minio.CopySrcOptions{Bucket: m.bucket, Object: src})
+	}
+	_, err := m.client.ComposeObject(ctx, minio.CopyDestOptions{Bucket: m.bucket, Object: dst}, sources...)
+	return toStoreError(err)
+}
+
+func (m *MinioStore) NewMultipartUpload(ctx context.Context, key string) (string, error) {
+	return m.core().NewMultipartUpload(ctx, m.bucket, key, minio.PutObjectOptions{})
+}
+
+func (m *MinioStore) PutPart(ctx context.Context, key, uploadID string, partNumber int, r io.Reader, size int64, md5Hex string) (PartInfo, error) {
+	part, err := m.core().PutObjectPart(ctx, m.bucket, key, uploadID, partNumber, r, size,
+		minio.PutObjectPartOptions{Md5Base64: contentMD5(md5Hex)})
+	if err != nil {
+		return PartInfo{}, toStoreError(err)
+	}
+	return PartInfo{PartNumber: partNumber, ETag: part.ETag, Size: part.Size}, nil
+}
+
+func (m *MinioStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []PartInfo) (ObjectInfo, error) {
+	completed := make([]minio.CompletePart, 0, len(parts))
+	for _, p := range parts {
+		completed = append(completed, minio.CompletePart{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+	info, err := m.core().CompleteMultipartUpload(ctx, m.bucket, key, uploadID, completed, minio.PutObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, toStoreError(err)
+	}
+	return ObjectInfo{Key: key, Size: info.Size, ETag: info.ETag}, nil
+}
+
+func (m *MinioStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	err := m.core().AbortMultipartUpload(ctx, m.bucket, key, uploadID)
+	if err != nil && toStoreError(err) != ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+func (m *MinioStore) PresignGet(ctx context.Context, key string, expiry time.Duration, contentType string) (string, error) {
+	var params url.Values
+	if contentType != "" {
+		params = url.Values{"response-content-type": {contentType}}
+	}
+	u, err := m.client.PresignedGetObject(ctx, m.bucket, key, expiry, params)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,100 @@
+// Package chunker 实现 FastCDC 内容定义分块
+//
+// 分块边界由内容决定：在文件中插入或删除少量字节只会改变附近一两个分块，其余分块的哈希保持不变，
+// 客户端据此只上传服务端缺少的分块。Gear 表由以0为种子的 splitmix64 生成，其他语言的客户端可以按同样方式复现。
+package chunker
+
+import (
+	"io"
+)
+
+// 分块大小的下限、期望值和上限
+const (
+	MinSize = 256 << 10
+	AvgSize = 1 << 20
+	MaxSize = 4 << 20
+)
+
+// 归一化分块：未到期望大小时用更严格的掩码，超过后用更宽松的掩码，使分块大小集中在 AvgSize 附近
+// 掩码取哈希的高位，使边界取决于最近64个字节
+const (
+	maskS uint64 = (1<<22 - 1) << (64 - 22)
+	maskL uint64 = (1<<18 - 1) << (64 - 18)
+)
+
+var gear [256]uint64
+
+func init() {
+	var state uint64
+	for i := range gear {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gear[i] = z ^ (z >> 31)
+	}
+}
+
+// Cut 返回 data 开头第一个分块的长度，data 不足 MinSize 时整体作为一个分块
+func Cut(data []byte) int {
+	n := len(data)
+	if n <= MinSize {
+		return n
+	}
+	if n > MaxSize {
+		n = MaxSize
+	}
+	normal := min(AvgSize, n)
+
+	var fp uint64
+	i := MinSize
+	for ; i < normal; i++ {
+		fp = (fp << 1) + gear[data[i]]
+		if fp&maskS == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		fp = (fp << 1) + gear[data[i]]
+		if fp&maskL == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
+
+// Chunker 把数据流切分为分块
+type Chunker struct {
+	r    io.Reader
+	buf  []byte
+	data []byte // buf 中尚未返回的部分
+	eof  bool
+}
+
+// New 返回切分 r 的 Chunker
+func New(r io.Reader) *Chunker {
+	return &Chunker{r: r, buf: make([]byte, 2*MaxSize)}
+}
+
+// Next 返回下一个分块，内容在下次调用前有效，没有更多数据时返回 io.EOF
+func (c *Chunker) Next() ([]byte, error) {
+	if len(c.data) < MaxSize && !c.eof {
+		n := copy(c.buf, c.data)
+		m, err := io.ReadFull(c.r, c.buf[n:])
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			c.eof = true
+		default:
+			return nil, err
+		}
+		c.data = c.buf[:n+m]
+	}
+	if len(c.data) == 0 {
+		return nil, io.EOF
+	}
+	cut := Cut(c.data)
+	chunk := c.data[:cut]
+	c.data = c.data[cut:]
+	return chunk, nil
+}
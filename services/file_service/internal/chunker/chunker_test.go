@@ -0,0 +1,61 @@
+package chunker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func split(t *testing.T, data []byte) [][32]byte {
+	t.Helper()
+	c := New(bytes.NewReader(data))
+	var hashes [][32]byte
+	var joined []byte
+	for {
+		chunk, err := c.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(chunk) > MaxSize {
+			t.Fatalf("chunk of %d bytes exceeds MaxSize", len(chunk))
+		}
+		joined = append(joined, chunk...)
+		hashes = append(hashes, sha256.Sum256(chunk))
+	}
+	if !bytes.Equal(joined, data) {
+		t.Fatal("chunks do not reassemble the input")
+	}
+	return hashes
+}
+
+// 在开头附近插入一个字节后，绝大多数分块应保持不变
+func TestInsertKeepsChunks(t *testing.T) {
+	data := make([]byte, 32<<20)
+	rand.New(rand.NewSource(1)).Read(data)
+	edited := append(append(append([]byte{}, data[:1000]...), 'x'), data[1000:]...)
+
+	before := split(t, data)
+	after := split(t, edited)
+
+	known := make(map[[32]byte]bool)
+	for _, h := range before {
+		known[h] = true
+	}
+	changed := 0
+	for _, h := range after {
+		if !known[h] {
+			changed++
+		}
+	}
+	if len(before) < 8 {
+		t.Fatalf("only %d chunks for 32MB", len(before))
+	}
+	if changed > 2 {
+		t.Fatalf("%d of %d chunks changed after a one-byte insert", changed, len(after))
+	}
+}
@@ -0,0 +1,292 @@
+// Package compression 实现按块压缩的对象格式
+//
+// 明文按 BlockSize 切成块分别压缩后依次拼接，对象末尾是块索引：
+//
+//	[块1][块2]...[块n][索引][索引长度 8字节][Magic 4字节]
+//
+// 索引记录每块的明文大小和存储大小，读取任意范围时只需解压覆盖该范围的块。
+// 压缩后不比原文小的块按原文存储，此时存储大小等于明文大小。
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// 支持的压缩方式
+const (
+	Zstd = "zstd"
+	Gzip = "gzip"
+)
+
+// BlockSize 每块的明文大小
+const BlockSize = 1 << 20
+
+// TrailerSize 对象末尾固定长度的尾部：索引长度和 Magic
+const TrailerSize = 12
+
+var magic = []byte("CBI1")
+
+// ErrCorruptIndex 块索引损坏
+var ErrCorruptIndex = errors.New("compression: corrupt block index")
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// Valid 是否为支持的压缩方式，空字符串表示不压缩
+func Valid(codec string) bool {
+	return codec == "" || codec == Zstd || codec == Gzip
+}
+
+// compressedTypes 本身已经压缩、再压缩没有收益的类型
+var compressedTypes = map[string]bool{
+	"application/zip":              true,
+	"application/gzip":             true,
+	"application/x-gzip":           true,
+	"application/x-bzip2":          true,
+	"application/x-xz":             true,
+	"application/x-7z-compressed":  true,
+	"application/x-rar-compressed": true,
+	"application/vnd.rar":          true,
+	"application/zstd":             true,
+	"application/java-archive":     true,
+	"application/epub+zip":         true,
+	"application/pdf":              true,
+	"font/woff":                    true,
+	"font/woff2":                   true,
+}
+
+// Compressible 该 MIME 类型的内容是否值得压缩
+func Compressible(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	switch {
+	case compressedTypes[mimeType]:
+		return false
+	case strings.HasPrefix(mimeType, "application/vnd.openxmlformats-"),
+		strings.HasPrefix(mimeType, "application/vnd.oasis.opendocument."):
+		// Office 文档是 zip 容器
+		return false
+	case mimeType == "image/svg+xml", mimeType == "image/bmp", mimeType == "audio/wav", mimeType == "audio/x-wav":
+		return true
+	case strings.HasPrefix(mimeType, "image/"), strings.HasPrefix(mimeType, "video/"), strings.HasPrefix(mimeType, "audio/"):
+		return false
+	}
+	return true
+}
+
+// Block 一个块的明文大小和存储大小
+type Block struct {
+	Plain  uint32
+	Stored uint32
+}
+
+// Compress 把 data 按块压缩，返回压缩后的内容和块列表
+func Compress(codec string, data []byte) ([]byte, []Block, error) {
+	var out bytes.Buffer
+	var blocks []Block
+	for start := 0; start < len(data); start += BlockSize {
+		plain := data[start:min(start+BlockSize, len(data))]
+		packed, err := compressBlock(codec, plain)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(packed) >= len(plain) {
+			packed = plain
+		}
+		out.Write(packed)
+		blocks = append(blocks, Block{Plain: uint32(len(plain)), Stored: uint32(len(packed))})
+	}
+	return out.Bytes(), blocks, nil
+}
+
+func compressBlock(codec string, plain []byte) ([]byte, error) {
+	switch codec {
+	case Zstd:
+		return zstdEncoder.EncodeAll(plain, nil), nil
+	case Gzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(plain); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("compression: unsupported codec %q", codec)
+}
+
+func decompressBlock(codec string, packed []byte, plainSize uint32) ([]byte, error) {
+	if uint32(len(packed)) == plainSize {
+		return packed, nil
+	}
+	var plain []byte
+	var err error
+	switch codec {
+	case Zstd:
+		plain, err = zstdDecoder.DecodeAll(packed, make([]byte, 0, plainSize))
+	case Gzip:
+		var r *gzip.Reader
+		if r, err = gzip.NewReader(bytes.NewReader(packed)); err == nil {
+			plain, err = io.ReadAll(r)
+		}
+	default:
+		return nil, fmt.Errorf("compression: unsupported codec %q", codec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compression: decompress block: %w", err)
+	}
+	if uint32(len(plain)) != plainSize {
+		return nil, ErrCorruptIndex
+	}
+	return plain, nil
+}
+
+// EncodeBlocks 编码块列表，用于保存分片的块信息和对象末尾的索引
+func EncodeBlocks(blocks []Block) []byte {
+	buf := make([]byte, 4+8*len(blocks))
+	binary.BigEndian.PutUint32(buf, uint32(len(blocks)))
+	for i, b := range blocks {
+		binary.BigEndian.PutUint32(buf[4+8*i:], b.Plain)
+		binary.BigEndian.PutUint32(buf[8+8*i:], b.Stored)
+	}
+	return buf
+}
+
+// DecodeBlocks 解码 EncodeBlocks 编码的块列表
+func DecodeBlocks(buf []byte) ([]Block, error) {
+	if len(buf) < 4 {
+		return nil, ErrCorruptIndex
+	}
+	n := binary.BigEndian.Uint32(buf)
+	if uint64(len(buf)) != 4+8*uint64(n) {
+		return nil, ErrCorruptIndex
+	}
+	blocks := make([]Block, n)
+	for i := range blocks {
+		blocks[i].Plain = binary.BigEndian.Uint32(buf[4+8*i:])
+		blocks[i].Stored = binary.BigEndian.Uint32(buf[8+8*i:])
+	}
+	return blocks, nil
+}
+
+// Footer 返回写在所有块之后的索引和尾部
+func Footer(blocks []Block) []byte {
+	index := EncodeBlocks(blocks)
+	footer := make([]byte, len(index)+TrailerSize)
+	copy(footer, index)
+	binary.BigEndian.PutUint64(footer[len(index):], uint64(len(index)))
+	copy(footer[len(index)+8:], magic)
+	return footer
+}
+
+// ParseTrailer 解析对象末尾的 TrailerSize 字节，返回索引长度
+func ParseTrailer(trailer []byte) (int64, error) {
+	if len(trailer) != TrailerSize || !bytes.Equal(trailer[8:], magic) {
+		return 0, ErrCorruptIndex
+	}
+	return int64(binary.BigEndian.Uint64(trailer)), nil
+}
+
+// Index 对象的块索引
+type Index struct {
+	Blocks []Block
+	plain  []int64 // 每块在明文中的起始位置，最后一项为明文总大小
+	stored []int64 // 每块在对象中的起始位置，最后一项为所有块的总大小
+}
+
+// NewIndex 由块列表建立索引
+func NewIndex(blocks []Block) *Index {
+	idx := &Index{
+		Blocks: blocks,
+		plain:  make([]int64, len(blocks)+1),
+		stored: make([]int64, len(blocks)+1),
+	}
+	for i, b := range blocks {
+		idx.plain[i+1] = idx.plain[i] + int64(b.Plain)
+		idx.stored[i+1] = idx.stored[i] + int64(b.Stored)
+	}
+	return idx
+}
+
+// PlainSize 明文总大小
+func (idx *Index) PlainSize() int64 {
+	return idx.plain[len(idx.plain)-1]
+}
+
+// StoredSize 所有块的总大小，不含索引
+func (idx *Index) StoredSize() int64 {
+	return idx.stored[len(idx.stored)-1]
+}
+
+// Range 返回覆盖明文 [offset, offset+length) 的块范围 [first, last)，及其在对象中的起止位置
+// length 为0表示读到末尾
+func (idx *Index) Range(offset, length int64) (first, last int, storedStart, storedEnd int64) {
+	end := idx.PlainSize()
+	if length > 0 && offset+length < end {
+		end = offset + length
+	}
+	for first < len(idx.Blocks) && idx.plain[first+1] <= offset {
+		first++
+	}
+	last = first
+	for last < len(idx.Blocks) && idx.plain[last] < end {
+		last++
+	}
+	return first, last, idx.stored[first], idx.stored[last]
+}
+
+// Reader 解压块 [first, last) 并跳过第一块开头的部分，使第一个字节位于明文 offset 处
+type Reader struct {
+	r      io.Reader
+	codec  string
+	blocks []Block
+	buf    []byte
+	skip   int64
+}
+
+// NewReader 返回解压 r 的 Reader，r 必须从第 first 块的开头开始
+func (idx *Index) NewReader(r io.Reader, codec string, first, last int, offset int64) *Reader {
+	return &Reader{
+		r:      r,
+		codec:  codec,
+		blocks: idx.Blocks[first:last],
+		skip:   offset - idx.plain[first],
+	}
+}
+
+func (c *Reader) Read(p []byte) (int, error) {
+	for len(c.buf) == 0 {
+		if len(c.blocks) == 0 {
+			return 0, io.EOF
+		}
+		block := c.blocks[0]
+		c.blocks = c.blocks[1:]
+		packed := make([]byte, block.Stored)
+		if _, err := io.ReadFull(c.r, packed); err != nil {
+			return 0, fmt.Errorf("compression: read block: %w", err)
+		}
+		plain, err := decompressBlock(c.codec, packed, block.Plain)
+		if err != nil {
+			return 0, err
+		}
+		if c.skip > 0 {
+			plain = plain[min(c.skip, int64(len(plain))):]
+			c.skip = 0
+		}
+		c.buf = plain
+	}
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
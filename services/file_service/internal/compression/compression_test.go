@@ -0,0 +1,84 @@
+package compression
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"strings"
+	"testing"
+)
+
+// 可压缩的文本和不可压缩的随机数据混合，覆盖压缩块和原样存储的块
+func testData() []byte {
+	text := []byte(strings.Repeat("timestamp,level,message\n", BlockSize/12))
+	noise := make([]byte, BlockSize+123)
+	rand.Read(noise)
+	return append(text, noise...)
+}
+
+func TestRangeRead(t *testing.T) {
+	data := testData()
+	for _, codec := range []string{Zstd, Gzip} {
+		packed, blocks, err := Compress(codec, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(packed) >= len(data) {
+			t.Fatalf("%s: compressed %d bytes to %d", codec, len(data), len(packed))
+		}
+
+		// 通过对象末尾的索引还原
+		object := append(packed, Footer(blocks)...)
+		indexLen, err := ParseTrailer(object[len(object)-TrailerSize:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := DecodeBlocks(object[len(object)-TrailerSize-int(indexLen) : len(object)-TrailerSize])
+		if err != nil {
+			t.Fatal(err)
+		}
+		idx := NewIndex(parsed)
+		if idx.PlainSize() != int64(len(data)) || idx.StoredSize() != int64(len(packed)) {
+			t.Fatalf("%s: index sizes %d/%d", codec, idx.PlainSize(), idx.StoredSize())
+		}
+
+		for _, r := range [][2]int64{{0, 0}, {10, 100}, {BlockSize - 5, 10}, {BlockSize + 7, 0}, {int64(len(data)) - 1, 0}} {
+			offset, length := r[0], r[1]
+			first, last, start, end := idx.Range(offset, length)
+			reader := idx.NewReader(bytes.NewReader(object[start:end]), codec, first, last, offset)
+			var got []byte
+			if length > 0 {
+				got, err = io.ReadAll(io.LimitReader(reader, length))
+			} else {
+				got, err = io.ReadAll(reader)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := data[offset:]
+			if length > 0 {
+				want = want[:length]
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%s: range %d+%d mismatch", codec, offset, length)
+			}
+		}
+	}
+}
+
+func TestCompressible(t *testing.T) {
+	for mime, want := range map[string]bool{
+		"text/plain; charset=utf-8": true,
+		"text/csv":                  true,
+		"application/json":          true,
+		"image/svg+xml":             true,
+		"image/jpeg":                false,
+		"video/mp4":                 false,
+		"application/zip":           false,
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": false,
+	} {
+		if got := Compressible(mime); got != want {
+			t.Errorf("Compressible(%q) = %v, want %v", mime, got, want)
+		}
+	}
+}
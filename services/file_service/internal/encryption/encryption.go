@@ -0,0 +1,197 @@
+// Package encryption 实现文件内容的信封加密
+//
+// 每个对象使用独立的数据密钥（AES-256-CTR）加密，数据密钥由用户密钥包装（AES-256-GCM），
+// 用户密钥再由主密钥包装后保存在数据库中。CTR 模式下密文与明文等长，可以从任意位置开始解密，
+// 分片上传的每个分片使用各自的计数器空间，因此可以独立、乱序地加密。
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// KeySize 所有密钥均为 AES-256
+const KeySize = 32
+
+// ErrInvalidKey 密钥长度不正确
+var ErrInvalidKey = errors.New("encryption: key must be 32 bytes")
+
+// GenerateKey 生成随机密钥
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("encryption: generate key: %w", err)
+	}
+	return key, nil
+}
+
+// ParseKey 解析 base64 编码的密钥
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: decode key: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// Wrap 用 kek 包装密钥，返回 base64(nonce || 密文)
+func Wrap(kek, key []byte) (string, error) {
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("encryption: generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, key, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Unwrap 用 kek 解开 Wrap 包装的密钥，kek 不正确或数据被篡改时返回错误
+func Unwrap(kek []byte, wrapped string) ([]byte, error) {
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: decode wrapped key: %w", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encryption: wrapped key too short")
+	}
+	key, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: unwrap key: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Part 对象中一个独立加密的分片
+type Part struct {
+	Number int
+	Size   int64
+}
+
+// Layout 描述对象由哪些独立加密的分片依次拼接而成
+type Layout struct {
+	// PartSize 分片编号从1开始连续、除最后一个外大小都是 PartSize
+	// 为0且 Parts 为空时整个对象是编号为0的单个分片
+	PartSize int64
+	// Parts 非空时按顺序给出每个分片，优先于 PartSize
+	Parts []Part
+}
+
+// locate 返回 offset 所在分片的编号和起止位置，end 为 -1 表示分片一直延伸到对象末尾
+func (l Layout) locate(offset int64) (part int, start, end int64) {
+	if len(l.Parts) > 0 {
+		for i, p := range l.Parts {
+			if i == len(l.Parts)-1 {
+				return p.Number, start, -1
+			}
+			if offset < start+p.Size {
+				return p.Number, start, start + p.Size
+			}
+			start += p.Size
+		}
+	}
+	if l.PartSize <= 0 {
+		return 0, 0, -1
+	}
+	index := offset / l.PartSize
+	return int(index) + 1, index * l.PartSize, (index + 1) * l.PartSize
+}
+
+// counterBlock 分片 part 中第 block 个 AES 块的计数器：4字节分片编号 + 4字节0 + 8字节块序号
+func counterBlock(part int, block int64) []byte {
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint32(iv[0:4], uint32(part))
+	binary.BigEndian.PutUint64(iv[8:16], uint64(block))
+	return iv
+}
+
+// Reader 对底层数据做 CTR 加解密，加密和解密是同一个操作
+type Reader struct {
+	r      io.Reader
+	block  cipher.Block
+	layout Layout
+	offset int64 // 下一个字节在对象中的位置
+	end    int64 // 当前分片的结束位置，-1 表示没有边界
+	stream cipher.Stream
+}
+
+// NewReader 返回从对象 offset 处开始加解密 r 的 Reader，r 的第一个字节必须位于 offset
+func NewReader(r io.Reader, key []byte, layout Layout, offset int64) (*Reader, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{r: r, block: block, layout: layout, offset: offset}, nil
+}
+
+// NewPartReader 返回加解密分片 part 内容的 Reader，用于分片上传
+func NewPartReader(r io.Reader, key []byte, part int) (*Reader, error) {
+	reader, err := NewReader(r, key, Layout{}, 0)
+	if err != nil {
+		return nil, err
+	}
+	reader.stream = cipher.NewCTR(reader.block, counterBlock(part, 0))
+	reader.end = -1
+	return reader, nil
+}
+
+// seek 为 offset 所在的分片建立密钥流
+func (c *Reader) seek() {
+	part, start, end := c.layout.locate(c.offset)
+	inPart := c.offset - start
+	c.stream = cipher.NewCTR(c.block, counterBlock(part, inPart/aes.BlockSize))
+	if skip := inPart % aes.BlockSize; skip > 0 {
+		scratch := make([]byte, skip)
+		c.stream.XORKeyStream(scratch, scratch)
+	}
+	c.end = end
+}
+
+func (c *Reader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	for i := 0; i < n; {
+		if c.stream == nil || c.offset == c.end {
+			c.seek()
+		}
+		k := int64(n - i)
+		if c.end >= 0 && c.end-c.offset < k {
+			k = c.end - c.offset
+		}
+		c.stream.XORKeyStream(p[i:i+int(k)], p[i:i+int(k)])
+		i += int(k)
+		c.offset += k
+	}
+	return n, err
+}
@@ -0,0 +1,80 @@
+package encryption
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestWrapUnwrap(t *testing.T) {
+	kek, _ := GenerateKey()
+	key, _ := GenerateKey()
+	wrapped, err := Wrap(kek, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Unwrap(kek, wrapped)
+	if err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("unwrap = %x, %v", got, err)
+	}
+
+	other, _ := GenerateKey()
+	if _, err := Unwrap(other, wrapped); err == nil {
+		t.Fatal("unwrap with wrong kek should fail")
+	}
+}
+
+func crypt(t *testing.T, key, data []byte, layout Layout, offset int64) []byte {
+	t.Helper()
+	r, err := NewReader(bytes.NewReader(data), key, layout, offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// 分片独立加密后拼接，按 Layout 从任意位置解密都应得到原文
+func TestLayoutRangeDecrypt(t *testing.T) {
+	key, _ := GenerateKey()
+	plain := make([]byte, 1000)
+	for i := range plain {
+		plain[i] = byte(i * 7)
+	}
+
+	var cipherText []byte
+	sizes := []int{300, 300, 300, 100}
+	var parts []Part
+	pos := 0
+	for i, size := range sizes {
+		r, err := NewPartReader(bytes.NewReader(plain[pos:pos+size]), key, i+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		enc, _ := io.ReadAll(r)
+		cipherText = append(cipherText, enc...)
+		parts = append(parts, Part{Number: i + 1, Size: int64(size)})
+		pos += size
+	}
+	if bytes.Equal(cipherText, plain) {
+		t.Fatal("ciphertext equals plaintext")
+	}
+
+	for _, layout := range []Layout{{PartSize: 300}, {Parts: parts}} {
+		for _, offset := range []int64{0, 1, 15, 16, 299, 300, 317, 650, 999} {
+			got := crypt(t, key, cipherText[offset:], layout, offset)
+			if !bytes.Equal(got, plain[offset:]) {
+				t.Fatalf("layout %+v offset %d: decrypted data mismatch", layout, offset)
+			}
+		}
+	}
+
+	// 单个连续分片
+	whole := crypt(t, key, plain, Layout{}, 0)
+	if got := crypt(t, key, whole[123:], Layout{}, 123); !bytes.Equal(got, plain[123:]) {
+		t.Fatal("stream layout: decrypted data mismatch")
+	}
+}
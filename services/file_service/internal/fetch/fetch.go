@@ -0,0 +1,157 @@
+// Package fetch 通过 HTTP(S) 下载远程文件，用于从URL导入
+//
+// 默认拒绝连接回环、私有、链路本地等内网地址，检查在解析出IP后、建立连接前进行，重定向和 DNS 重绑定同样受限。
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrBlockedAddress = errors.New("address not allowed")
+	ErrTooLarge       = errors.New("remote file exceeds size limit")
+	ErrBadURL         = errors.New("only http and https URLs are supported")
+)
+
+// Client 远程文件下载客户端
+type Client struct {
+	maxSize int64 // 不大于0表示不限制
+	http    *http.Client
+}
+
+// NewClient 创建下载客户端，allowPrivate 为 true 时允许连接内网地址，maxSize 不大于0表示不限制大小
+func NewClient(allowPrivate bool, maxSize int64) *Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = denyPrivate
+	}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   30 * time.Second,
+		ResponseHeaderTimeout: 60 * time.Second,
+		MaxIdleConns:          16,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	return &Client{
+		maxSize: maxSize,
+		http: &http.Client{
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 10 {
+					return errors.New("stopped after 10 redirects")
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return ErrBadURL
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// Response 远程文件
+type Response struct {
+	Body         io.ReadCloser // 超过大小限制时读取返回 ErrTooLarge
+	Size         int64         // 远程声明的大小，-1 表示未知
+	Name         string        // 取自 Content-Disposition 或 URL 路径，可能为空
+	ContentType  string
+	LastModified time.Time // 远程未提供时为零值
+}
+
+// Open 请求远程文件，状态码不是 200 时返回错误
+func (c *Client) Open(ctx context.Context, rawURL string) (*Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrBadURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("remote returned %s", resp.Status)
+	}
+	if c.maxSize > 0 && resp.ContentLength > c.maxSize {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %d bytes", ErrTooLarge, resp.ContentLength)
+	}
+
+	r := &Response{
+		Body:        resp.Body,
+		Size:        resp.ContentLength,
+		Name:        fileName(resp),
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if c.maxSize > 0 {
+		r.Body = &limitedBody{ReadCloser: resp.Body, remaining: c.maxSize}
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		r.LastModified = t
+	}
+	return r, nil
+}
+
+// fileName 优先取 Content-Disposition 中的文件名，其次取最终 URL 路径的最后一段
+func fileName(resp *http.Response) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		if name := path.Base(strings.ReplaceAll(params["filename"], `\`, "/")); name != "." && name != "/" {
+			return name
+		}
+	}
+	name := path.Base(resp.Request.URL.Path)
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+// limitedBody 读取超过 remaining 字节时返回 ErrTooLarge，用于远程未声明大小或声明不实的情况
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.ReadCloser.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrTooLarge
+	}
+	return n, err
+}
+
+// denyPrivate 拒绝连接回环、私有、链路本地、组播和未指定地址
+func denyPrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
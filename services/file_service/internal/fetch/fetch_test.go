@@ -0,0 +1,72 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data/report.csv":
+			w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+			io.WriteString(w, "a,b\n1,2\n")
+		case "/download":
+			w.Header().Set("Content-Disposition", `attachment; filename="../named.txt"`)
+			io.WriteString(w, "named")
+		case "/stream":
+			// 分块传输，不声明大小
+			w.(http.Flusher).Flush()
+			io.WriteString(w, strings.Repeat("x", 100))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	if _, err := NewClient(false, 0).Open(ctx, srv.URL+"/data/report.csv"); !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("loopback should be blocked by default, got %v", err)
+	}
+
+	c := NewClient(true, 64)
+	resp, err := c.Open(ctx, srv.URL+"/data/report.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(body) != "a,b\n1,2\n" || resp.Size != 8 || resp.Name != "report.csv" || resp.LastModified.Year() != 2015 {
+		t.Fatalf("unexpected response: %+v body=%q err=%v", resp, body, err)
+	}
+
+	resp, err = c.Open(ctx, srv.URL+"/download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Name != "named.txt" {
+		t.Fatalf("Name = %q, want named.txt", resp.Name)
+	}
+
+	resp, err = c.Open(ctx, srv.URL+"/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(resp.Body); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("body over the limit: err = %v, want ErrTooLarge", err)
+	}
+	resp.Body.Close()
+
+	if _, err := c.Open(ctx, srv.URL+"/missing"); err == nil {
+		t.Fatal("404 should be an error")
+	}
+	if _, err := c.Open(ctx, "ftp://example.com/x"); !errors.Is(err, ErrBadURL) {
+		t.Fatalf("ftp URL: err = %v, want ErrBadURL", err)
+	}
+}
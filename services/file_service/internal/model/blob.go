@@ -0,0 +1,199 @@
+package model
+
+import (
+	"time"
+
+	"cloud-storage-file-service/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Blob 按内容寻址的存储对象，多个文件版本内容相同时共享同一个 Blob
+type Blob struct {
+	ID           int64  `gorm:"primaryKey"`
+	Sha256       string `gorm:"size:64;index:idx_blob_hash"`
+	Md5          string `gorm:"size:32"`
+	Size         int64  `gorm:"index:idx_blob_hash"`
+	Bucket       string
+	ObjectName   string `gorm:"size:512"`
+	RefCount     int64
+	CreatedAt    time.Time
+	Encryption   Encryption `gorm:"embedded"`
+	Compression  string     `gorm:"size:16"` // 为空表示未压缩，否则对象为按块压缩的格式
+	Chunked      bool       // 内容由分块清单描述，没有对象
+	DeriveStatus int        // 缩略图和预览的生成状态，见 DerivePending 等
+}
+
+// AcquireBlob 引用内容相同的 Blob，不存在时以 blob 新建，返回实际引用的 Blob 以及是否复用了已有 Blob
+// SHA-256、MD5 和大小必须全部一致才视为相同内容
+func (dao *fileDAOImpl) AcquireBlob(blob *Blob) (*Blob, bool, error) {
+	var existing Blob
+	reused := false
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("sha256 = ? AND size = ? AND md5 = ? AND sha256 <> ''", blob.Sha256, blob.Size, blob.Md5).
+			First(&existing).Error
+		if err == nil {
+			reused = true
+			existing.RefCount++
+			return tx.Model(&Blob{}).Where("id = ?", existing.ID).
+				Update("ref_count", gorm.Expr("ref_count + 1")).Error
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+		blob.RefCount = 1
+		if err := tx.Create(blob).Error; err != nil {
+			return err
+		}
+		existing = *blob
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &existing, reused, nil
+}
+
+// ReuseBlob 为 userID 自己的已完成版本引用着的、内容相同的 Blob 增加一次引用，不存在时返回 gorm.ErrRecordNotFound
+// 客户端只声明了校验值，并未证明持有内容，因此不能引用其他用户的 Blob
+func (dao *fileDAOImpl) ReuseBlob(userID int64, sha256, md5 string, size int64) (*Blob, error) {
+	var blob Blob
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		owned := tx.Model(&FileVersion{}).Select("file_versions.blob_id").
+			Joins("JOIN files ON files.id = file_versions.file_id").
+			Where("files.user_id = ? AND file_versions.status = 1", userID)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("sha256 = ? AND size = ? AND md5 = ? AND sha256 <> ''", sha256, size, md5).
+			Where("id IN (?)", owned).
+			First(&blob).Error
+		if err != nil {
+			return err
+		}
+		blob.RefCount++
+		return tx.Model(&Blob{}).Where("id = ?", blob.ID).
+			Update("ref_count", gorm.Expr("ref_count + 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// ReleaseBlob 释放一次引用，引用归零时删除 Blob 记录并返回它，调用方负责删除对象
+func (dao *fileDAOImpl) ReleaseBlob(id int64) (*Blob, error) {
+	var released *Blob
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		var blob Blob
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&blob, id).Error; err != nil {
+			return err
+		}
+		if blob.RefCount > 1 {
+			return tx.Model(&Blob{}).Where("id = ?", id).
+				Update("ref_count", gorm.Expr("ref_count - 1")).Error
+		}
+		if err := tx.Delete(&Blob{}, id).Error; err != nil {
+			return err
+		}
+		released = &blob
+		return nil
+	})
+	return released, err
+}
+
+// RetainBlob 为 Blob 增加一次引用，Blob 已被删除时返回 gorm.ErrRecordNotFound
+func (dao *fileDAOImpl) RetainBlob(id int64) (*Blob, error) {
+	var blob Blob
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&blob, id).Error; err != nil {
+			return err
+		}
+		blob.RefCount++
+		return tx.Model(&Blob{}).Where("id = ?", blob.ID).
+			Update("ref_count", gorm.Expr("ref_count + 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// GetBlobByID 获取 Blob
+func (dao *fileDAOImpl) GetBlobByID(id int64) (*Blob, error) {
+	var blob Blob
+	err := dao.db.First(&blob, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// ListBlobsOutsidePrefix 按 ID 升序列出对象名不以 prefix 开头的 Blob，不含分块存储的 Blob
+func (dao *fileDAOImpl) ListBlobsOutsidePrefix(prefix string, afterID int64, limit int) ([]Blob, error) {
+	var blobs []Blob
+	err := dao.db.Where("id > ? AND chunked = ? AND object_name NOT LIKE ? ESCAPE '!'", afterID, false, escapeLike(prefix)+"%").
+		Order("id asc").Limit(limit).Find(&blobs).Error
+	return blobs, err
+}
+
+// MoveBlobObject 把 Blob 以及引用它的版本和文件的对象名从 oldName 改为 newName
+func (dao *fileDAOImpl) MoveBlobObject(id int64, oldName, newName string) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Blob{}).Where("id = ? AND object_name = ?", id, oldName).
+			Update("object_name", newName)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(&FileVersion{}).Where("object_name = ? AND status = 1", oldName).
+			Update("object_name", newName).Error; err != nil {
+			return err
+		}
+		return tx.Model(&File{}).Where("object_name = ?", oldName).
+			Update("object_name", newName).Error
+	})
+}
+
+// backfillBlobs 为 Blob 表上线前已完成的版本建立 Blob，共享同一对象的版本合并为一个 Blob
+// 这些历史 Blob 没有 SHA-256，不会参与新的去重
+func (dao *fileDAOImpl) backfillBlobs() {
+	var versions []FileVersion
+	if err := dao.db.Where("blob_id = 0 AND status = 1").Order("id asc").Find(&versions).Error; err != nil {
+		utils.Error("[Blob] 查询待补建 Blob 的版本失败: %v", err)
+		return
+	}
+
+	blobs := make(map[string]*Blob)
+	for _, v := range versions {
+		blob, ok := blobs[v.ObjectName]
+		if !ok {
+			var file File
+			if err := dao.db.First(&file, v.FileID).Error; err != nil {
+				continue
+			}
+			blob = &Blob{
+				Md5:        v.Md5,
+				Size:       v.Size,
+				Bucket:     file.Bucket,
+				ObjectName: v.ObjectName,
+				CreatedAt:  v.CreatedAt,
+			}
+			if err := dao.db.Create(blob).Error; err != nil {
+				utils.Error("[Blob] 对象 %s 补建 Blob 失败: %v", v.ObjectName, err)
+				continue
+			}
+			blobs[v.ObjectName] = blob
+		}
+		blob.RefCount++
+		dao.db.Model(&FileVersion{}).Where("id = ?", v.ID).Update("blob_id", blob.ID)
+	}
+	for _, blob := range blobs {
+		dao.db.Model(&Blob{}).Where("id = ?", blob.ID).Update("ref_count", blob.RefCount)
+	}
+	if len(blobs) > 0 {
+		utils.Info("[Blob] 已为 %d 个版本补建 %d 个 Blob", len(versions), len(blobs))
+	}
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDAO 使用内存 SQLite 数据库创建 DAO
+func newTestDAO(t *testing.T) *fileDAOImpl {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite error = %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db.DB() error = %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return NewFileDAO(db)
+}
+
+// createFileVersion 为 userID 新建文件和一个版本
+func createFileVersion(t *testing.T, dao *fileDAOImpl, userID int64, name string, version *FileVersion) {
+	t.Helper()
+	file := &File{FileName: name, UserID: userID, Status: 1, CreatedAt: time.Now()}
+	if err := dao.CreateFile(file); err != nil {
+		t.Fatalf("CreateFile() error = %v", err)
+	}
+	version.FileID = file.ID
+	if err := dao.CreateVersion(version); err != nil {
+		t.Fatalf("CreateVersion() error = %v", err)
+	}
+}
+
+func TestBlob_AcquireRelease(t *testing.T) {
+	dao := newTestDAO(t)
+	blob, reused, err := dao.AcquireBlob(&Blob{Sha256: "s", Md5: "m", Size: 10, ObjectName: "o1"})
+	if err != nil || reused || blob.RefCount != 1 {
+		t.Fatalf("AcquireBlob() = %+v, %v, %v", blob, reused, err)
+	}
+	again, reused, err := dao.AcquireBlob(&Blob{Sha256: "s", Md5: "m", Size: 10, ObjectName: "o2"})
+	if err != nil || !reused || again.ID != blob.ID || again.ObjectName != "o1" || again.RefCount != 2 {
+		t.Fatalf("AcquireBlob() = %+v, %v, %v", again, reused, err)
+	}
+
+	if released, err := dao.ReleaseBlob(blob.ID); err != nil || released != nil {
+		t.Fatalf("ReleaseBlob() = %v, %v, want nil", released, err)
+	}
+	released, err := dao.ReleaseBlob(blob.ID)
+	if err != nil || released == nil || released.ObjectName != "o1" {
+		t.Fatalf("ReleaseBlob() = %v, %v", released, err)
+	}
+	if _, err := dao.GetBlobByID(blob.ID); err == nil {
+		t.Error("blob should be deleted after last release")
+	}
+}
+
+func TestBlob_ReuseOnlyOwnBlobs(t *testing.T) {
+	dao := newTestDAO(t)
+	blob, _, err := dao.AcquireBlob(&Blob{Sha256: "s", Md5: "m", Size: 10, ObjectName: "o1"})
+	if err != nil {
+		t.Fatalf("AcquireBlob() error = %v", err)
+	}
+	createFileVersion(t, dao, 1, "a", &FileVersion{BlobID: blob.ID, Size: 10, Status: 1})
+
+	if _, err := dao.ReuseBlob(2, "s", "m", 10); err != gorm.ErrRecordNotFound {
+		t.Errorf("ReuseBlob() by another user error = %v, want ErrRecordNotFound", err)
+	}
+	if _, err := dao.ReuseBlob(1, "s", "other", 10); err != gorm.ErrRecordNotFound {
+		t.Errorf("ReuseBlob() with different md5 error = %v, want ErrRecordNotFound", err)
+	}
+	reused, err := dao.ReuseBlob(1, "s", "m", 10)
+	if err != nil || reused.ID != blob.ID || reused.RefCount != 2 {
+		t.Fatalf("ReuseBlob() = %+v, %v", reused, err)
+	}
+}
+
+func TestVersion_SumUsageByUser(t *testing.T) {
+	dao := newTestDAO(t)
+	createFileVersion(t, dao, 1, "a", &FileVersion{Size: 100, Status: 1})
+	createFileVersion(t, dao, 1, "b", &FileVersion{Size: 50, Reserved: 70, Status: 0})
+	createFileVersion(t, dao, 2, "c", &FileVersion{Size: 30, Status: 1})
+
+	usages, err := dao.SumUsageByUser()
+	if err != nil {
+		t.Fatalf("SumUsageByUser() error = %v", err)
+	}
+	got := make(map[int64]int64)
+	for _, u := range usages {
+		got[u.UserID] = u.UsedSpace
+	}
+	if got[1] != 170 || got[2] != 30 || len(got) != 2 {
+		t.Errorf("SumUsageByUser() = %v, want map[1:170 2:30]", got)
+	}
+}
@@ -0,0 +1,197 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Chunk 内容定义分块上传的分块对象，按 SHA-256 寻址，被所有引用它的分块清单共享
+// RefCount 为引用它的清单项数，归零时删除
+type Chunk struct {
+	ID         int64  `gorm:"primaryKey"`
+	Sha256     string `gorm:"size:64;uniqueIndex"`
+	Size       int64
+	Bucket     string
+	ObjectName string `gorm:"size:512"`
+	RefCount   int64
+	CreatedAt  time.Time
+	Encryption Encryption `gorm:"embedded"`
+}
+
+// ManifestEntry 分块清单中的一项，上传中属于版本，完成后属于 Blob
+type ManifestEntry struct {
+	ID        int64 `gorm:"primaryKey"`
+	VersionID int64 `gorm:"index"` // 上传中的版本，完成后为0
+	BlobID    int64 `gorm:"index"` // 完成后所属的 Blob
+	Seq       int   // 在文件中的顺序，从0开始
+	Offset    int64 // 在文件中的起始位置
+	Size      int64
+	Sha256    string `gorm:"size:64;index"`
+	ChunkID   int64  `gorm:"index"` // 分块尚未上传时为0
+}
+
+// CreateManifest 批量创建分块清单
+func (dao *fileDAOImpl) CreateManifest(entries []ManifestEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return dao.db.CreateInBatches(entries, 500).Error
+}
+
+// ListVersionManifest 按顺序列出上传中版本的分块清单
+func (dao *fileDAOImpl) ListVersionManifest(versionID int64) ([]ManifestEntry, error) {
+	var entries []ManifestEntry
+	err := dao.db.Where("version_id = ?", versionID).Order("seq asc").Find(&entries).Error
+	return entries, err
+}
+
+// ListBlobManifest 按顺序列出 Blob 的分块清单
+func (dao *fileDAOImpl) ListBlobManifest(blobID int64) ([]ManifestEntry, error) {
+	var entries []ManifestEntry
+	err := dao.db.Where("blob_id = ?", blobID).Order("seq asc").Find(&entries).Error
+	return entries, err
+}
+
+// MissingChunks 列出上传中版本还缺少的分块哈希，重复的分块只列一次
+func (dao *fileDAOImpl) MissingChunks(versionID int64) ([]string, error) {
+	var hashes []string
+	err := dao.db.Model(&ManifestEntry{}).Where("version_id = ? AND chunk_id = 0", versionID).
+		Distinct("sha256").Order("sha256 asc").Pluck("sha256", &hashes).Error
+	return hashes, err
+}
+
+// LinkChunks 把上传中版本清单里 userID 已持有的分块关联到清单项并增加引用
+// 清单只声明了哈希，因此只关联用户自己的上传或文件引用着的分块，其他分块需要重新上传
+func (dao *fileDAOImpl) LinkChunks(userID, versionID int64) error {
+	hashes, err := dao.MissingChunks(versionID)
+	if err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		err := dao.db.Transaction(func(tx *gorm.DB) error {
+			var chunk Chunk
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("sha256 = ? AND id IN (?)", hash, dao.userChunkIDs(userID, versionID)).
+				First(&chunk).Error
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return linkChunk(tx, versionID, &chunk)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// userChunkIDs 用户其他上传中版本或已完成版本的分块清单引用的分块ID，用作子查询
+func (dao *fileDAOImpl) userChunkIDs(userID, excludeVersionID int64) *gorm.DB {
+	versions := dao.db.Model(&FileVersion{}).Select("file_versions.id").
+		Joins("JOIN files ON files.id = file_versions.file_id").
+		Where("files.user_id = ? AND file_versions.id <> ?", userID, excludeVersionID)
+	blobs := dao.db.Model(&FileVersion{}).Select("file_versions.blob_id").
+		Joins("JOIN files ON files.id = file_versions.file_id").
+		Where("files.user_id = ? AND file_versions.status = 1 AND file_versions.blob_id <> 0", userID)
+	return dao.db.Model(&ManifestEntry{}).Select("chunk_id").
+		Where("chunk_id <> 0 AND (version_id IN (?) OR blob_id IN (?))", versions, blobs)
+}
+
+// linkChunk 把版本清单中哈希相同的未关联项指向 chunk，并按关联的项数增加引用
+func linkChunk(tx *gorm.DB, versionID int64, chunk *Chunk) error {
+	result := tx.Model(&ManifestEntry{}).Where("version_id = ? AND sha256 = ? AND chunk_id = 0", versionID, chunk.Sha256).
+		Update("chunk_id", chunk.ID)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	chunk.RefCount += result.RowsAffected
+	return tx.Model(&Chunk{}).Where("id = ?", chunk.ID).
+		Update("ref_count", gorm.Expr("ref_count + ?", result.RowsAffected)).Error
+}
+
+// SaveChunk 登记新上传的分块并关联到版本清单，哈希相同的分块已存在时改为关联已有分块
+// 返回实际关联的分块以及是否新建
+func (dao *fileDAOImpl) SaveChunk(versionID int64, chunk *Chunk) (*Chunk, bool, error) {
+	var saved Chunk
+	created := false
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("sha256 = ?", chunk.Sha256).First(&saved).Error
+		if err == gorm.ErrRecordNotFound {
+			chunk.RefCount = 0
+			if err := tx.Create(chunk).Error; err != nil {
+				return err
+			}
+			saved = *chunk
+			created = true
+		} else if err != nil {
+			return err
+		}
+		return linkChunk(tx, versionID, &saved)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &saved, created, nil
+}
+
+// GetChunks 按 ID 获取分块
+func (dao *fileDAOImpl) GetChunks(ids []int64) ([]Chunk, error) {
+	var chunks []Chunk
+	if len(ids) == 0 {
+		return chunks, nil
+	}
+	err := dao.db.Where("id IN ?", ids).Find(&chunks).Error
+	return chunks, err
+}
+
+// MoveManifestToBlob 上传完成后把版本的分块清单转给新建的 Blob
+func (dao *fileDAOImpl) MoveManifestToBlob(versionID, blobID int64) error {
+	return dao.db.Model(&ManifestEntry{}).Where("version_id = ?", versionID).
+		Updates(map[string]interface{}{"version_id": 0, "blob_id": blobID}).Error
+}
+
+// ReleaseManifest 删除版本或 Blob 的分块清单并释放其引用的分块，返回引用归零被删除的分块，调用方负责删除对象
+func (dao *fileDAOImpl) ReleaseManifest(versionID, blobID int64) ([]Chunk, error) {
+	var released []Chunk
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		owner, ownerID := "version_id = ?", versionID
+		if blobID != 0 {
+			owner, ownerID = "blob_id = ?", blobID
+		}
+		var refs []struct {
+			ChunkID int64
+			N       int64
+		}
+		if err := tx.Model(&ManifestEntry{}).Select("chunk_id, COUNT(*) AS n").
+			Where(owner, ownerID).Where("chunk_id <> 0").Group("chunk_id").Scan(&refs).Error; err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			var chunk Chunk
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&chunk, ref.ChunkID).Error; err != nil {
+				if err == gorm.ErrRecordNotFound {
+					continue
+				}
+				return err
+			}
+			if chunk.RefCount > ref.N {
+				if err := tx.Model(&Chunk{}).Where("id = ?", chunk.ID).
+					Update("ref_count", gorm.Expr("ref_count - ?", ref.N)).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Delete(&Chunk{}, chunk.ID).Error; err != nil {
+				return err
+			}
+			released = append(released, chunk)
+		}
+		return tx.Where(owner, ownerID).Delete(&ManifestEntry{}).Error
+	})
+	return released, err
+}
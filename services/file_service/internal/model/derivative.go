@@ -0,0 +1,99 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Blob 衍生对象的生成状态
+const (
+	DerivePending = 0 // 待生成
+	DeriveDone    = 1 // 已生成
+	DeriveSkipped = 2 // 内容不支持或生成失败，不再重试
+)
+
+// Derivative 由 Blob 内容生成的衍生对象：图片缩略图或文本预览
+// 与 Blob 一一关联，内容相同的文件共享同一组衍生对象，Blob 删除时一并删除
+type Derivative struct {
+	ID         int64  `gorm:"primaryKey"`
+	BlobID     int64  `gorm:"uniqueIndex:idx_derivative"`
+	Kind       string `gorm:"size:16;uniqueIndex:idx_derivative"` // thumbnail 或 preview
+	Size       int    `gorm:"uniqueIndex:idx_derivative"`         // 缩略图的最长边像素，预览为0
+	MimeType   string `gorm:"size:127"`
+	Bucket     string
+	ObjectName string `gorm:"size:512"`
+	Bytes      int64
+	CreatedAt  time.Time
+	Encryption Encryption `gorm:"embedded"`
+}
+
+// 衍生对象的种类
+const (
+	DerivativeThumbnail = "thumbnail"
+	DerivativePreview   = "preview"
+)
+
+// ListPendingDerivations 按 ID 升序列出还没有生成衍生对象的 Blob
+func (dao *fileDAOImpl) ListPendingDerivations(afterID int64, limit int) ([]Blob, error) {
+	var blobs []Blob
+	err := dao.db.Where("id > ? AND derive_status = ?", afterID, DerivePending).
+		Order("id asc").Limit(limit).Find(&blobs).Error
+	return blobs, err
+}
+
+// SaveDerivatives 登记 Blob 的衍生对象并更新其生成状态
+// Blob 已被删除时不登记，返回 false，调用方负责删除已上传的对象
+func (dao *fileDAOImpl) SaveDerivatives(blobID int64, derivatives []Derivative, status int) (bool, error) {
+	saved := false
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Blob{}).Where("id = ? AND derive_status = ?", blobID, DerivePending).
+			Update("derive_status", status)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if len(derivatives) > 0 {
+			if err := tx.Create(&derivatives).Error; err != nil {
+				return err
+			}
+		}
+		saved = true
+		return nil
+	})
+	return saved, err
+}
+
+// ListDerivatives 列出 Blob 的衍生对象
+func (dao *fileDAOImpl) ListDerivatives(blobID int64) ([]Derivative, error) {
+	var derivatives []Derivative
+	err := dao.db.Where("blob_id = ?", blobID).Order("kind asc, size asc").Find(&derivatives).Error
+	return derivatives, err
+}
+
+// DeleteDerivatives 删除 Blob 的衍生对象记录并返回它们，调用方负责删除对象
+func (dao *fileDAOImpl) DeleteDerivatives(blobID int64) ([]Derivative, error) {
+	var derivatives []Derivative
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("blob_id = ?", blobID).Find(&derivatives).Error; err != nil {
+			return err
+		}
+		if len(derivatives) == 0 {
+			return nil
+		}
+		return tx.Where("blob_id = ?", blobID).Delete(&Derivative{}).Error
+	})
+	return derivatives, err
+}
+
+// ListDerivativesByKey 按 ID 升序列出数据密钥由 keyID 包装的衍生对象
+func (dao *fileDAOImpl) ListDerivativesByKey(keyID, afterID int64, limit int) ([]Derivative, error) {
+	var derivatives []Derivative
+	err := dao.db.Where("key_id = ? AND id > ?", keyID, afterID).Order("id asc").Limit(limit).Find(&derivatives).Error
+	return derivatives, err
+}
+
+// RewrapDerivativeKey 把衍生对象的数据密钥从 oldKeyID 换成由 newKeyID 包装的 dataKey
+func (dao *fileDAOImpl) RewrapDerivativeKey(id, oldKeyID, newKeyID int64, dataKey string) error {
+	return dao.db.Model(&Derivative{}).Where("id = ? AND key_id = ?", id, oldKeyID).
+		Updates(map[string]interface{}{"key_id": newKeyID, "data_key": dataKey}).Error
+}
@@ -10,24 +10,45 @@ import (
 
 // File 文件元数据
 type File struct {
-	ID         int64  `gorm:"primaryKey"`
-	FileName   string `gorm:"size:255"`
-	Bucket     string `gorm:"size:255"`
-	ObjectName string `gorm:"size:512"` // 最终合并对象名
-	UserID     int64  `gorm:"index"`
-	Size       int64
-	Md5        string
-	Status     int // 0 = uploading, 1 = completed
-	CreatedAt  time.Time
+	ID          int64  `gorm:"primaryKey"`
+	FileName    string `gorm:"size:255;uniqueIndex:idx_file_live_name,priority:3"`
+	Bucket      string `gorm:"size:255"`
+	ObjectName  string `gorm:"size:512"` // 最终合并对象名
+	UserID      int64  `gorm:"index;uniqueIndex:idx_file_live_name,priority:1"`
+	FolderID    int64  `gorm:"index;uniqueIndex:idx_file_live_name,priority:2"` // 所在文件夹，0 表示根目录
+	Size        int64
+	Md5         string
+	Sha256      string `gorm:"size:64"`
+	MimeType    string `gorm:"size:127;index"`
+	Compression string `gorm:"size:16"` // 当前版本的存储压缩方式，为空表示未压缩
+	Status      int    // 0 = uploading, 1 = completed, 2 = aborted
+	CreatedAt   time.Time
+	UpdatedAt   time.Time  `gorm:"index"`
+	Mtime       *time.Time `gorm:"index"` // 当前版本上传时客户端提供的原始修改时间
+	TrashedAt   *time.Time `gorm:"index"` // 移入回收站的时间，nil 表示未删除
+	ScrubbedAt  *time.Time `gorm:"index"` // 上次完整性校验的时间
+
+	Tags map[string]string `gorm:"-"` // 用户标签，按需加载
+
+	// 未删除时为 true，移入回收站后为 NULL，与唯一索引一起保证目录下未删除的文件不重名
+	Live *bool `gorm:"uniqueIndex:idx_file_live_name,priority:4"`
+
+	// 当前版本，ObjectName/Size/Md5/Sha256/Mtime 始终与当前版本保持一致
+	CurrentVersionID int64
 }
 
-// FilePart 分片信息
+// FilePart 分片信息，属于某个文件版本的一次上传
+// 原生分片上传的分片没有独立对象，ObjectName 为空
 type FilePart struct {
 	ID         int64 `gorm:"primaryKey"`
 	FileID     int64 `gorm:"index"`
+	VersionID  int64 `gorm:"index"`
 	PartNumber int
+	ObjectName string `gorm:"size:512"`
 	ETag       string `gorm:"size:255"` // MinIO 返回的 ETag
-	Size       int64
+	Md5        string `gorm:"size:32"`  // 明文内容的 MD5
+	Size       int64  // 明文大小
+	Blocks     []byte // 压缩分片的块列表，见 compression.EncodeBlocks
 	UploadedAt time.Time
 }
 
@@ -38,10 +59,108 @@ type FileDAO interface {
 	GetFileByID(id int64) (*File, error)
 	UpdateFileStatus(id int64, status int) error
 	SavePart(part *FilePart) error
-	ListParts(fileID int64) ([]FilePart, error)
-	DeleteParts(fileID int64) error
-	GetPart(fileID int64, partNumber int) (*FilePart, error)
+	ListParts(versionID int64) ([]FilePart, error)
+	DeleteParts(versionID int64) error
+	DeleteFile(fileID int64) error
+	GetPart(versionID int64, partNumber int) (*FilePart, error)
 	GetFileByMD5(md5 string) (*File, error)
+	GetFileByName(userID, folderID int64, name string) (*File, error)
+	SearchFiles(userID int64, filter FileFilter, afterID int64, limit int) ([]File, error)
+
+	// 元数据
+	SetVersionMimeType(id int64, mimeType string) error
+	UpdateFileTags(fileID int64, set map[string]string, remove []string) error
+	ListFileTags(fileIDs []int64) (map[int64]map[string]string, error)
+
+	// 文件夹
+	CreateFolder(folder *Folder) error
+	GetFolderByID(id int64) (*Folder, error)
+	UpdateFolder(folder *Folder) error
+	DeleteFolder(id int64) error
+	ListSubFolders(userID, parentID int64) ([]Folder, error)
+	ListFolderFiles(userID, folderID int64) ([]File, error)
+	NameExists(userID, folderID int64, name string) (bool, error)
+	ListDirectory(userID, folderID int64, opts ListOptions) ([]Folder, []File, int64, error)
+	MoveFile(id, folderID int64, name string) error
+
+	// 回收站
+	MoveFileToTrash(id int64, at time.Time) error
+	RestoreFileFromTrash(id, folderID int64, name string) error
+	ListTrash(userID int64, offset, limit int) ([]File, int64, error)
+	ListUserTrash(userID int64) ([]File, error)
+	ListTrashedBefore(before time.Time, limit int) ([]File, error)
+
+	// 版本
+	CreateVersion(version *FileVersion) error
+	GetVersionByID(id int64) (*FileVersion, error)
+	GetPendingVersion(fileID int64) (*FileVersion, error)
+	ListVersions(fileID int64) ([]FileVersion, error)
+	MaxVersionNo(fileID int64) (int, error)
+	UpdateVersionStatus(id int64, status int) error
+	DeleteVersion(id int64) error
+	SetCurrentVersion(fileID int64, version *FileVersion) error
+	UpdateVersion(version *FileVersion) error
+	AddVersionReserved(id, delta int64) (bool, error)
+	SumUsageByUser() ([]UserUsage, error)
+	TouchVersion(id int64, expiresAt time.Time) error
+	ListExpiredVersions(now, createdBefore time.Time, limit int) ([]FileVersion, error)
+	AbortVersion(id int64) error
+	CountPendingVersions() (int64, error)
+
+	// 内容寻址的 Blob
+	AcquireBlob(blob *Blob) (*Blob, bool, error)
+	ReuseBlob(userID int64, sha256, md5 string, size int64) (*Blob, error)
+	ReleaseBlob(id int64) (*Blob, error)
+	RetainBlob(id int64) (*Blob, error)
+	GetBlobByID(id int64) (*Blob, error)
+	ListBlobsOutsidePrefix(prefix string, afterID int64, limit int) ([]Blob, error)
+	MoveBlobObject(id int64, oldName, newName string) error
+
+	// 加密密钥
+	GetActiveUserKey(userID int64) (*UserKey, error)
+	GetUserKey(id int64) (*UserKey, error)
+	CreateUserKey(key *UserKey) error
+	ListKeyOwners() ([]int64, error)
+	ListRetiredUserKeys(userID int64) ([]UserKey, error)
+	DeleteUserKey(id int64) (bool, error)
+	ListBlobsByKey(keyID, afterID int64, limit int) ([]Blob, error)
+	ListVersionsByKey(keyID, afterID int64, limit int) ([]FileVersion, error)
+	RewrapBlobKey(id, oldKeyID, newKeyID int64, dataKey string) error
+	RewrapVersionKey(id, oldKeyID, newKeyID int64, dataKey string) error
+	ListChunksByKey(keyID, afterID int64, limit int) ([]Chunk, error)
+	RewrapChunkKey(id, oldKeyID, newKeyID int64, dataKey string) error
+
+	// 内容定义分块
+	CreateManifest(entries []ManifestEntry) error
+	ListVersionManifest(versionID int64) ([]ManifestEntry, error)
+	ListBlobManifest(blobID int64) ([]ManifestEntry, error)
+	MissingChunks(versionID int64) ([]string, error)
+	LinkChunks(userID, versionID int64) error
+	SaveChunk(versionID int64, chunk *Chunk) (*Chunk, bool, error)
+	GetChunks(ids []int64) ([]Chunk, error)
+	MoveManifestToBlob(versionID, blobID int64) error
+	ReleaseManifest(versionID, blobID int64) ([]Chunk, error)
+
+	// 缩略图和预览
+	ListPendingDerivations(afterID int64, limit int) ([]Blob, error)
+	SaveDerivatives(blobID int64, derivatives []Derivative, status int) (bool, error)
+	ListDerivatives(blobID int64) ([]Derivative, error)
+	DeleteDerivatives(blobID int64) ([]Derivative, error)
+	ListDerivativesByKey(keyID, afterID int64, limit int) ([]Derivative, error)
+	RewrapDerivativeKey(id, oldKeyID, newKeyID int64, dataKey string) error
+
+	// 完整性校验
+	ListFilesToScrub(before time.Time, limit int) ([]File, error)
+	SaveScrubResult(fileID int64, findings []ScrubFinding, at time.Time) error
+	ListCorruptedFileIDs(afterID int64, limit int) ([]int64, error)
+	ListOpenFindings(fileIDs []int64) ([]ScrubFinding, error)
+
+	// 数据库与对象存储对账
+	ListObjectRefs(kind string, afterID int64, limit int) ([]ObjectRef, error)
+	ListFileIDsByBlob(blobID int64) ([]int64, error)
+	ListBlobIDsByChunk(chunkID int64) ([]int64, error)
+	ResetDerivations(blobID int64) ([]Derivative, error)
+	DeletePart(id int64) error
 }
 
 // -------------------- DAO 实现 --------------------
@@ -53,13 +172,28 @@ type fileDAOImpl struct {
 // NewFileDAO 创建 DAO 实例
 func NewFileDAO(db *gorm.DB) *fileDAOImpl {
 	// 自动迁移表
-	db.AutoMigrate(&File{}, &FilePart{})
-	return &fileDAOImpl{db: db}
+	db.AutoMigrate(&File{}, &FilePart{}, &Folder{}, &FileVersion{}, &Blob{}, &UserKey{}, &Chunk{}, &ManifestEntry{}, &Derivative{}, &FileTag{}, &ScrubFinding{})
+	dao := &fileDAOImpl{db: db}
+	dao.backfillVersions()
+	dao.backfillPartObjects()
+	dao.backfillBlobs()
+	dao.backfillMimeTypes()
+	dao.backfillUpdatedAt()
+	dao.backfillLiveNames()
+	return dao
 }
 
-// CreateFile 创建文件记录
+// CreateFile 创建文件记录，目录下已有未删除的同名文件时返回 ErrNameExists
 func (dao *fileDAOImpl) CreateFile(file *File) error {
-	return dao.db.Create(file).Error
+	if file.TrashedAt == nil {
+		live := true
+		file.Live = &live
+	}
+	err := dao.db.Create(file).Error
+	if err != nil && isDuplicateKey(err) {
+		return ErrNameExists
+	}
+	return err
 }
 
 // GetFileByID 获取文件信息
@@ -81,38 +215,50 @@ func (dao *fileDAOImpl) UpdateFileStatus(id int64, status int) error {
 func (dao *fileDAOImpl) SavePart(part *FilePart) error {
 	// 如果分片已存在，先删除再保存
 	var existing FilePart
-	if err := dao.db.Where("file_id = ? AND part_number = ?", part.FileID, part.PartNumber).First(&existing).Error; err == nil {
+	if err := dao.db.Where("version_id = ? AND part_number = ?", part.VersionID, part.PartNumber).First(&existing).Error; err == nil {
 		dao.db.Delete(&existing)
 	}
 	return dao.db.Create(part).Error
 }
 
 // GetPart 获取分片信息
-func (dao *fileDAOImpl) GetPart(fileID int64, partNumber int) (*FilePart, error) {
+func (dao *fileDAOImpl) GetPart(versionID int64, partNumber int) (*FilePart, error) {
 	var part FilePart
-	err := dao.db.Where("file_id = ? AND part_number = ?", fileID, partNumber).First(&part).Error
+	err := dao.db.Where("version_id = ? AND part_number = ?", versionID, partNumber).First(&part).Error
 	if err != nil {
 		return nil, err
 	}
 	return &part, err
 }
 
-// ListParts 查询所有已上传分片
-func (dao *fileDAOImpl) ListParts(fileID int64) ([]FilePart, error) {
+// ListParts 查询版本所有已上传分片
+func (dao *fileDAOImpl) ListParts(versionID int64) ([]FilePart, error) {
 	var parts []FilePart
-	err := dao.db.Where("file_id = ?", fileID).Order("part_number asc").Find(&parts).Error
+	err := dao.db.Where("version_id = ?", versionID).Order("part_number asc").Find(&parts).Error
 	if err != nil {
 		return nil, err
 	}
 	return parts, err
 }
 
-// DeleteParts 删除文件所有分片（取消上传或上传失败时使用）
-func (dao *fileDAOImpl) DeleteParts(fileID int64) error {
-	return dao.db.Where("file_id = ?", fileID).Delete(&FilePart{}).Error
+// DeleteParts 删除版本所有分片（取消上传或上传失败时使用）
+func (dao *fileDAOImpl) DeleteParts(versionID int64) error {
+	return dao.db.Where("version_id = ?", versionID).Delete(&FilePart{}).Error
 }
+
+// DeleteFile 删除文件记录
 func (dao *fileDAOImpl) DeleteFile(fileID int64) error {
-	return dao.db.Where("file_id = ?", fileID).Delete(&FilePart{}).Error
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("file_id = ?", fileID).Delete(&FileTag{}).Error; err != nil {
+			return err
+		}
+		// 文件删除后未解决的问题不再需要处理，保留记录备查
+		if err := tx.Model(&ScrubFinding{}).Where("file_id = ? AND resolved_at IS NULL", fileID).
+			Update("resolved_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&File{}, fileID).Error
+	})
 }
 func (dao *fileDAOImpl) GetFileByMD5(MD5 string) (*File, error) {
 	var file File
@@ -122,3 +268,14 @@ func (dao *fileDAOImpl) GetFileByMD5(MD5 string) (*File, error) {
 	}
 	return &file, err
 }
+
+// GetFileByName 获取目录下未删除的同名文件
+func (dao *fileDAOImpl) GetFileByName(userID, folderID int64, name string) (*File, error) {
+	var file File
+	err := dao.db.Where("user_id = ? AND folder_id = ? AND file_name = ? AND trashed_at IS NULL", userID, folderID, name).
+		First(&file).Error
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
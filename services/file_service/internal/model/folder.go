@@ -0,0 +1,169 @@
+package model
+
+import (
+	"cloud-storage-file-service/utils"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNameExists 目录下已有未删除的同名文件
+var ErrNameExists = errors.New("同名文件已存在")
+
+// isDuplicateKey 是否违反唯一索引，MySQL 的错误由 TranslateError 转换为 gorm.ErrDuplicatedKey
+func isDuplicateKey(err error) bool {
+	return errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// Folder 文件夹，按 ParentID 组织成每个用户一棵目录树
+type Folder struct {
+	ID        int64  `gorm:"primaryKey"`
+	UserID    int64  `gorm:"uniqueIndex:idx_folder_parent_name"`
+	ParentID  int64  `gorm:"uniqueIndex:idx_folder_parent_name"` // 0 表示根目录
+	Name      string `gorm:"size:255;uniqueIndex:idx_folder_parent_name"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ListOptions 目录列表的分页与排序参数
+type ListOptions struct {
+	Offset  int
+	Limit   int
+	OrderBy string // name | size | created_at
+	Desc    bool
+}
+
+// CreateFolder 创建文件夹
+func (dao *fileDAOImpl) CreateFolder(folder *Folder) error {
+	return dao.db.Create(folder).Error
+}
+
+// GetFolderByID 获取文件夹
+func (dao *fileDAOImpl) GetFolderByID(id int64) (*Folder, error) {
+	var folder Folder
+	err := dao.db.First(&folder, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// UpdateFolder 保存文件夹的名称和父目录
+func (dao *fileDAOImpl) UpdateFolder(folder *Folder) error {
+	return dao.db.Model(folder).Select("name", "parent_id", "updated_at").Updates(folder).Error
+}
+
+// DeleteFolder 删除文件夹记录
+func (dao *fileDAOImpl) DeleteFolder(id int64) error {
+	return dao.db.Delete(&Folder{}, id).Error
+}
+
+// ListSubFolders 列出直接子文件夹
+func (dao *fileDAOImpl) ListSubFolders(userID, parentID int64) ([]Folder, error) {
+	var folders []Folder
+	err := dao.db.Where("user_id = ? AND parent_id = ?", userID, parentID).Find(&folders).Error
+	return folders, err
+}
+
+// ListFolderFiles 列出文件夹下所有未删除的文件
+func (dao *fileDAOImpl) ListFolderFiles(userID, folderID int64) ([]File, error) {
+	var files []File
+	err := dao.db.Where("user_id = ? AND folder_id = ? AND trashed_at IS NULL", userID, folderID).Find(&files).Error
+	return files, err
+}
+
+// NameExists 检查文件夹下是否已有同名的文件或子文件夹
+func (dao *fileDAOImpl) NameExists(userID, folderID int64, name string) (bool, error) {
+	var count int64
+	err := dao.db.Model(&Folder{}).
+		Where("user_id = ? AND parent_id = ? AND name = ?", userID, folderID, name).
+		Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+	err = dao.db.Model(&File{}).
+		Where("user_id = ? AND folder_id = ? AND file_name = ? AND trashed_at IS NULL", userID, folderID, name).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// backfillLiveNames 为唯一索引加入前的未删除文件补上 live 标记
+// 已经重名的文件补标记会失败，只记录日志，由用户改名或删除后恢复约束
+func (dao *fileDAOImpl) backfillLiveNames() {
+	var ids []int64
+	if err := dao.db.Model(&File{}).Where("trashed_at IS NULL AND live IS NULL").Pluck("id", &ids).Error; err != nil {
+		utils.Error("[Folder] 查询待补齐 live 标记的文件失败: %v", err)
+		return
+	}
+	for _, id := range ids {
+		if err := dao.db.Model(&File{}).Where("id = ?", id).Update("live", true).Error; err != nil {
+			utils.Error("[Folder] 文件 %d 补齐 live 标记失败: %v", id, err)
+		}
+	}
+}
+
+// ListDirectory 分页列出目录内容，文件夹排在文件之前，返回总条数
+func (dao *fileDAOImpl) ListDirectory(userID, folderID int64, opts ListOptions) ([]Folder, []File, int64, error) {
+	var folderCount, fileCount int64
+	folderQuery := dao.db.Model(&Folder{}).Where("user_id = ? AND parent_id = ?", userID, folderID).Session(&gorm.Session{})
+	fileQuery := dao.db.Model(&File{}).Where("user_id = ? AND folder_id = ? AND trashed_at IS NULL", userID, folderID).Session(&gorm.Session{})
+	if err := folderQuery.Count(&folderCount).Error; err != nil {
+		return nil, nil, 0, err
+	}
+	if err := fileQuery.Count(&fileCount).Error; err != nil {
+		return nil, nil, 0, err
+	}
+
+	direction := " asc"
+	if opts.Desc {
+		direction = " desc"
+	}
+	// 文件夹没有大小，按大小排序时退化为按名称排序
+	folderOrder := opts.OrderBy
+	if folderOrder == "size" {
+		folderOrder = "name"
+	}
+	fileOrder := opts.OrderBy
+	if fileOrder == "name" {
+		fileOrder = "file_name"
+	}
+
+	var folders []Folder
+	if int64(opts.Offset) < folderCount {
+		err := folderQuery.Order(folderOrder + direction).Order("id asc").
+			Offset(opts.Offset).Limit(opts.Limit).Find(&folders).Error
+		if err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	var files []File
+	remaining := opts.Limit - len(folders)
+	if remaining > 0 {
+		fileOffset := int64(opts.Offset) - folderCount
+		if fileOffset < 0 {
+			fileOffset = 0
+		}
+		err := fileQuery.Order(fileOrder + direction).Order("id asc").
+			Offset(int(fileOffset)).Limit(remaining).Find(&files).Error
+		if err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	return folders, files, folderCount + fileCount, nil
+}
+
+// MoveFile 把文件移动到 folderID 下并改名，目标名称已被占用时返回 ErrNameExists
+func (dao *fileDAOImpl) MoveFile(id, folderID int64, name string) error {
+	err := dao.db.Model(&File{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"folder_id": folderID,
+		"file_name": name,
+	}).Error
+	if err != nil && isDuplicateKey(err) {
+		return ErrNameExists
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFolder_UniqueLiveFileName(t *testing.T) {
+	dao := newTestDAO(t)
+	first := &File{FileName: "a.txt", UserID: 1, CreatedAt: time.Now()}
+	if err := dao.CreateFile(first); err != nil {
+		t.Fatalf("CreateFile() error = %v", err)
+	}
+	if err := dao.CreateFile(&File{FileName: "a.txt", UserID: 1, CreatedAt: time.Now()}); !errors.Is(err, ErrNameExists) {
+		t.Fatalf("CreateFile() duplicate error = %v, want ErrNameExists", err)
+	}
+	// 其他用户和其他目录不受影响
+	if err := dao.CreateFile(&File{FileName: "a.txt", UserID: 2, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("CreateFile() other user error = %v", err)
+	}
+	other := &File{FileName: "a.txt", UserID: 1, FolderID: 7, CreatedAt: time.Now()}
+	if err := dao.CreateFile(other); err != nil {
+		t.Fatalf("CreateFile() other folder error = %v", err)
+	}
+	if err := dao.MoveFile(other.ID, 0, "a.txt"); !errors.Is(err, ErrNameExists) {
+		t.Fatalf("MoveFile() onto existing name error = %v, want ErrNameExists", err)
+	}
+
+	// 移入回收站后名称可以再次使用，恢复到被占用的名称失败
+	if err := dao.MoveFileToTrash(first.ID, time.Now()); err != nil {
+		t.Fatalf("MoveFileToTrash() error = %v", err)
+	}
+	second := &File{FileName: "a.txt", UserID: 1, CreatedAt: time.Now()}
+	if err := dao.CreateFile(second); err != nil {
+		t.Fatalf("CreateFile() after trash error = %v", err)
+	}
+	if err := dao.MoveFileToTrash(second.ID, time.Now()); err != nil {
+		t.Fatalf("MoveFileToTrash() error = %v", err)
+	}
+	if err := dao.RestoreFileFromTrash(first.ID, 0, "a.txt"); err != nil {
+		t.Fatalf("RestoreFileFromTrash() error = %v", err)
+	}
+	if err := dao.RestoreFileFromTrash(second.ID, 0, "a.txt"); !errors.Is(err, ErrNameExists) {
+		t.Fatalf("RestoreFileFromTrash() onto existing name error = %v, want ErrNameExists", err)
+	}
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserKey 用户密钥，用于包装该用户上传对象的数据密钥，本身由主密钥包装后保存
+// 每个用户只有一个启用的密钥，轮换后旧密钥在其包装的数据密钥全部重新包装后删除
+type UserKey struct {
+	ID         int64  `gorm:"primaryKey"`
+	UserID     int64  `gorm:"uniqueIndex:idx_user_key_version"`
+	Version    int    `gorm:"uniqueIndex:idx_user_key_version"` // 从1开始递增
+	WrappedKey string `gorm:"size:255"`
+	Active     bool
+	CreatedAt  time.Time
+}
+
+// Encryption 对象的加密信息，KeyID 为0表示对象未加密
+type Encryption struct {
+	KeyID    int64  `gorm:"index"`    // 包装数据密钥的用户密钥
+	DataKey  string `gorm:"size:255"` // 被用户密钥包装的数据密钥
+	PartSize int64  // 各分片独立加密时的分片大小，0 表示整个对象连续加密
+}
+
+// GetActiveUserKey 获取用户当前启用的密钥
+func (dao *fileDAOImpl) GetActiveUserKey(userID int64) (*UserKey, error) {
+	var key UserKey
+	err := dao.db.Where("user_id = ? AND active = ?", userID, true).Order("version desc").First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetUserKey 获取密钥
+func (dao *fileDAOImpl) GetUserKey(id int64) (*UserKey, error) {
+	var key UserKey
+	if err := dao.db.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// CreateUserKey 为用户创建下一个版本的密钥并设为启用，用户原有的密钥全部停用
+// 并发创建时版本号冲突的一方失败
+func (dao *fileDAOImpl) CreateUserKey(key *UserKey) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		var maxVersion int
+		if err := tx.Model(&UserKey{}).Where("user_id = ?", key.UserID).
+			Select("COALESCE(MAX(version), 0)").Scan(&maxVersion).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&UserKey{}).Where("user_id = ?", key.UserID).
+			Update("active", false).Error; err != nil {
+			return err
+		}
+		key.Version = maxVersion + 1
+		key.Active = true
+		return tx.Create(key).Error
+	})
+}
+
+// ListKeyOwners 列出拥有密钥的用户
+func (dao *fileDAOImpl) ListKeyOwners() ([]int64, error) {
+	var userIDs []int64
+	err := dao.db.Model(&UserKey{}).Distinct("user_id").Order("user_id asc").Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// ListRetiredUserKeys 列出用户已停用的密钥
+func (dao *fileDAOImpl) ListRetiredUserKeys(userID int64) ([]UserKey, error) {
+	var keys []UserKey
+	err := dao.db.Where("user_id = ? AND active = ?", userID, false).Order("version asc").Find(&keys).Error
+	return keys, err
+}
+
+// DeleteUserKey 删除不再被任何 Blob、版本、分块或衍生对象引用的停用密钥，仍有引用时不删除并返回 false
+func (dao *fileDAOImpl) DeleteUserKey(id int64) (bool, error) {
+	deleted := false
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		var refs int64
+		if err := tx.Model(&Blob{}).Where("key_id = ?", id).Count(&refs).Error; err != nil {
+			return err
+		}
+		if refs > 0 {
+			return nil
+		}
+		if err := tx.Model(&FileVersion{}).Where("key_id = ?", id).Count(&refs).Error; err != nil {
+			return err
+		}
+		if refs > 0 {
+			return nil
+		}
+		if err := tx.Model(&Chunk{}).Where("key_id = ?", id).Count(&refs).Error; err != nil {
+			return err
+		}
+		if refs > 0 {
+			return nil
+		}
+		if err := tx.Model(&Derivative{}).Where("key_id = ?", id).Count(&refs).Error; err != nil {
+			return err
+		}
+		if refs > 0 {
+			return nil
+		}
+		result := tx.Where("active = ?", false).Delete(&UserKey{}, id)
+		deleted = result.RowsAffected > 0
+		return result.Error
+	})
+	return deleted, err
+}
+
+// ListBlobsByKey 按 ID 升序列出数据密钥由 keyID 包装的 Blob
+func (dao *fileDAOImpl) ListBlobsByKey(keyID, afterID int64, limit int) ([]Blob, error) {
+	var blobs []Blob
+	err := dao.db.Where("key_id = ? AND id > ?", keyID, afterID).Order("id asc").Limit(limit).Find(&blobs).Error
+	return blobs, err
+}
+
+// ListVersionsByKey 按 ID 升序列出数据密钥由 keyID 包装的版本
+func (dao *fileDAOImpl) ListVersionsByKey(keyID, afterID int64, limit int) ([]FileVersion, error) {
+	var versions []FileVersion
+	err := dao.db.Where("key_id = ? AND id > ?", keyID, afterID).Order("id asc").Limit(limit).Find(&versions).Error
+	return versions, err
+}
+
+// RewrapBlobKey 把 Blob 的数据密钥从 oldKeyID 换成由 newKeyID 包装的 dataKey
+func (dao *fileDAOImpl) RewrapBlobKey(id, oldKeyID, newKeyID int64, dataKey string) error {
+	return dao.db.Model(&Blob{}).Where("id = ? AND key_id = ?", id, oldKeyID).
+		Updates(map[string]interface{}{"key_id": newKeyID, "data_key": dataKey}).Error
+}
+
+// RewrapVersionKey 把版本的数据密钥从 oldKeyID 换成由 newKeyID 包装的 dataKey
+func (dao *fileDAOImpl) RewrapVersionKey(id, oldKeyID, newKeyID int64, dataKey string) error {
+	return dao.db.Model(&FileVersion{}).Where("id = ? AND key_id = ?", id, oldKeyID).
+		Updates(map[string]interface{}{"key_id": newKeyID, "data_key": dataKey}).Error
+}
+
+// ListChunksByKey 按 ID 升序列出数据密钥由 keyID 包装的分块
+func (dao *fileDAOImpl) ListChunksByKey(keyID, afterID int64, limit int) ([]Chunk, error) {
+	var chunks []Chunk
+	err := dao.db.Where("key_id = ? AND id > ?", keyID, afterID).Order("id asc").Limit(limit).Find(&chunks).Error
+	return chunks, err
+}
+
+// RewrapChunkKey 把分块的数据密钥从 oldKeyID 换成由 newKeyID 包装的 dataKey
+func (dao *fileDAOImpl) RewrapChunkKey(id, oldKeyID, newKeyID int64, dataKey string) error {
+	return dao.db.Model(&Chunk{}).Where("id = ? AND key_id = ?", id, oldKeyID).
+		Updates(map[string]interface{}{"key_id": newKeyID, "data_key": dataKey}).Error
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"cloud-storage-file-service/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FileTag 用户为文件设置的键值标签，同一文件的键唯一
+type FileTag struct {
+	ID     int64  `gorm:"primaryKey"`
+	FileID int64  `gorm:"uniqueIndex:idx_file_tag"`
+	Name   string `gorm:"size:64;uniqueIndex:idx_file_tag;index:idx_tag_value"`
+	Value  string `gorm:"size:255;index:idx_tag_value"`
+}
+
+// SetVersionMimeType 记录从版本内容识别出的 MIME 类型
+func (dao *fileDAOImpl) SetVersionMimeType(id int64, mimeType string) error {
+	return dao.db.Model(&FileVersion{}).Where("id = ?", id).Update("mime_type", mimeType).Error
+}
+
+// UpdateFileTags 设置文件的标签并删除 remove 中的标签，同一个键同时出现时以设置为准
+func (dao *fileDAOImpl) UpdateFileTags(fileID int64, set map[string]string, remove []string) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		if len(remove) > 0 {
+			if err := tx.Where("file_id = ? AND name IN ?", fileID, remove).Delete(&FileTag{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(set) > 0 {
+			tags := make([]FileTag, 0, len(set))
+			for name, value := range set {
+				tags = append(tags, FileTag{FileID: fileID, Name: name, Value: value})
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "file_id"}, {Name: "name"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value"}),
+			}).Create(&tags).Error; err != nil {
+				return err
+			}
+		}
+		// 标签变化也算作文件更新
+		return tx.Model(&File{}).Where("id = ?", fileID).Update("updated_at", time.Now()).Error
+	})
+}
+
+// ListFileTags 批量获取文件的标签，没有标签的文件不在结果中
+func (dao *fileDAOImpl) ListFileTags(fileIDs []int64) (map[int64]map[string]string, error) {
+	result := make(map[int64]map[string]string)
+	if len(fileIDs) == 0 {
+		return result, nil
+	}
+	var tags []FileTag
+	if err := dao.db.Where("file_id IN ?", fileIDs).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		if result[t.FileID] == nil {
+			result[t.FileID] = make(map[string]string)
+		}
+		result[t.FileID][t.Name] = t.Value
+	}
+	return result, nil
+}
+
+// backfillUpdatedAt 为更新时间字段上线前的文件以创建时间作为更新时间
+func (dao *fileDAOImpl) backfillUpdatedAt() {
+	err := dao.db.Model(&File{}).Where("updated_at IS NULL").
+		Update("updated_at", gorm.Expr("created_at")).Error
+	if err != nil {
+		utils.Error("[Metadata] 补齐文件更新时间失败: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// 引用存储对象的记录类型
+const (
+	RefBlob       = "blob"       // 非分块存储的 Blob
+	RefChunk      = "chunk"      // 内容定义分块
+	RefDerivative = "derivative" // 缩略图和预览
+	RefPart       = "part"       // 上传中版本的独立分片对象
+)
+
+// ObjectRef 引用一个存储对象的记录
+type ObjectRef struct {
+	Kind       string
+	ID         int64
+	OwnerID    int64 // 衍生对象所属的 Blob、分片所属的版本，其他为0
+	ObjectName string
+}
+
+// ListObjectRefs 按 ID 升序列出某类记录引用的对象
+// 已完成版本的分片只描述版本内容的布局，对象已合并删除，不在其中；上传中版本的对象在完成时才写入，也不在其中
+func (dao *fileDAOImpl) ListObjectRefs(kind string, afterID int64, limit int) ([]ObjectRef, error) {
+	var query *gorm.DB
+	switch kind {
+	case RefBlob:
+		query = dao.db.Model(&Blob{}).Select("id, object_name").Where("chunked = ?", false)
+	case RefChunk:
+		query = dao.db.Model(&Chunk{}).Select("id, object_name")
+	case RefDerivative:
+		query = dao.db.Model(&Derivative{}).Select("id, blob_id AS owner_id, object_name")
+	case RefPart:
+		query = dao.db.Model(&FilePart{}).Select("id, version_id AS owner_id, object_name").
+			Where("object_name <> '' AND version_id IN (?)", dao.db.Model(&FileVersion{}).Select("id").Where("status = 0"))
+	default:
+		return nil, fmt.Errorf("unknown object ref kind: %s", kind)
+	}
+	var refs []ObjectRef
+	err := query.Where("id > ?", afterID).Order("id asc").Limit(limit).Scan(&refs).Error
+	for i := range refs {
+		refs[i].Kind = kind
+	}
+	return refs, err
+}
+
+// ListFileIDsByBlob 列出有已完成版本引用该 Blob 的文件
+func (dao *fileDAOImpl) ListFileIDsByBlob(blobID int64) ([]int64, error) {
+	var ids []int64
+	err := dao.db.Model(&FileVersion{}).Where("blob_id = ? AND status = 1", blobID).
+		Distinct("file_id").Pluck("file_id", &ids).Error
+	return ids, err
+}
+
+// ListBlobIDsByChunk 列出分块清单中包含该分块的 Blob
+func (dao *fileDAOImpl) ListBlobIDsByChunk(chunkID int64) ([]int64, error) {
+	var ids []int64
+	err := dao.db.Model(&ManifestEntry{}).Where("chunk_id = ? AND blob_id <> 0", chunkID).
+		Distinct("blob_id").Pluck("blob_id", &ids).Error
+	return ids, err
+}
+
+// ResetDerivations 删除 Blob 的衍生对象记录并标记为待生成，返回删除的记录，调用方负责删除对象
+func (dao *fileDAOImpl) ResetDerivations(blobID int64) ([]Derivative, error) {
+	var derivatives []Derivative
+	err := dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("blob_id = ?", blobID).Find(&derivatives).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("blob_id = ?", blobID).Delete(&Derivative{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Blob{}).Where("id = ?", blobID).Update("derive_status", DerivePending).Error
+	})
+	return derivatives, err
+}
+
+// DeletePart 删除一条分片记录
+func (dao *fileDAOImpl) DeletePart(id int64) error {
+	return dao.db.Delete(&FilePart{}, id).Error
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 完整性校验发现的问题类型
+const (
+	ScrubMissing          = "missing"           // 对象或分块不存在
+	ScrubSizeMismatch     = "size_mismatch"     // 对象或内容大小与记录不一致
+	ScrubChecksumMismatch = "checksum_mismatch" // 内容的 SHA-256 或 MD5 与记录不一致
+	ScrubUnreadable       = "unreadable"        // 无法读取或解密
+)
+
+// ScrubFinding 完整性校验发现的问题，同一问题再次校验时仍存在则只更新最后发现时间，不再出现时标记为已解决
+type ScrubFinding struct {
+	ID          int64  `gorm:"primaryKey"`
+	FileID      int64  `gorm:"index"`
+	VersionID   int64  // 问题所在的版本
+	ObjectName  string `gorm:"size:512"` // 出问题的对象或分块
+	Problem     string `gorm:"size:32"`
+	Detail      string `gorm:"size:1024"`
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+	ResolvedAt  *time.Time `gorm:"index"` // nil 表示问题仍存在
+}
+
+// scrubKey 版本、对象和问题类型都相同时视为同一问题
+type scrubKey struct {
+	versionID  int64
+	objectName string
+	problem    string
+}
+
+func (f *ScrubFinding) key() scrubKey {
+	return scrubKey{f.VersionID, f.ObjectName, f.Problem}
+}
+
+// ListFilesToScrub 列出已完成且从未校验或上次校验早于 before 的文件，最久未校验的在前
+func (dao *fileDAOImpl) ListFilesToScrub(before time.Time, limit int) ([]File, error) {
+	var files []File
+	err := dao.db.Where("status = 1 AND (scrubbed_at IS NULL OR scrubbed_at < ?)", before).
+		Order("scrubbed_at asc").Order("id asc").Limit(limit).Find(&files).Error
+	return files, err
+}
+
+// SaveScrubResult 记录一次文件校验的结果：仍存在的问题更新最后发现时间，不再出现的标记为已解决，新问题新增记录
+func (dao *fileDAOImpl) SaveScrubResult(fileID int64, findings []ScrubFinding, at time.Time) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		var open []ScrubFinding
+		if err := tx.Where("file_id = ? AND resolved_at IS NULL", fileID).Find(&open).Error; err != nil {
+			return err
+		}
+		existing := make(map[scrubKey]*ScrubFinding, len(open))
+		for i := range open {
+			existing[open[i].key()] = &open[i]
+		}
+
+		for i := range findings {
+			f := &findings[i]
+			if prev := existing[f.key()]; prev != nil {
+				delete(existing, f.key())
+				if err := tx.Model(prev).Updates(map[string]interface{}{"detail": f.Detail, "last_seen_at": at}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			f.FileID = fileID
+			f.FirstSeenAt = at
+			f.LastSeenAt = at
+			if err := tx.Create(f).Error; err != nil {
+				return err
+			}
+		}
+		for _, prev := range existing {
+			if err := tx.Model(prev).Update("resolved_at", at).Error; err != nil {
+				return err
+			}
+		}
+		// 不修改文件的更新时间
+		return tx.Model(&File{}).Where("id = ?", fileID).UpdateColumn("scrubbed_at", at).Error
+	})
+}
+
+// ListCorruptedFileIDs 按文件ID升序列出有未解决问题的文件
+func (dao *fileDAOImpl) ListCorruptedFileIDs(afterID int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := dao.db.Model(&ScrubFinding{}).Where("resolved_at IS NULL AND file_id > ?", afterID).
+		Distinct("file_id").Order("file_id asc").Limit(limit).Pluck("file_id", &ids).Error
+	return ids, err
+}
+
+// ListOpenFindings 列出文件未解决的问题
+func (dao *fileDAOImpl) ListOpenFindings(fileIDs []int64) ([]ScrubFinding, error) {
+	var findings []ScrubFinding
+	if len(fileIDs) == 0 {
+		return findings, nil
+	}
+	err := dao.db.Where("file_id IN ? AND resolved_at IS NULL", fileIDs).
+		Order("file_id asc").Order("id asc").Find(&findings).Error
+	return findings, err
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"cloud-storage-file-service/utils"
+)
+
+// FileFilter 文件搜索条件，零值表示不限制
+type FileFilter struct {
+	Name          string            // 文件名子串
+	MinSize       int64             // 最小大小（含）
+	MaxSize       int64             // 最大大小（含），0 表示不限
+	Status        *int              // 上传状态
+	CreatedAfter  *time.Time        // 创建时间下限（含）
+	CreatedBefore *time.Time        // 创建时间上限（不含）
+	MimeType      string            // 精确匹配，以 "/" 结尾时按前缀匹配，如 "image/"
+	UpdatedAfter  *time.Time        // 更新时间下限（含）
+	UpdatedBefore *time.Time        // 更新时间上限（不含）
+	MtimeAfter    *time.Time        // 客户端修改时间下限（含）
+	MtimeBefore   *time.Time        // 客户端修改时间上限（不含）
+	Tags          map[string]string // 必须全部匹配的标签，值为空时只要求有该标签
+}
+
+// escapeLike 转义 LIKE 中的通配符，查询需带上 ESCAPE '!'
+// SQLite 没有默认的转义字符，MySQL 字符串中的反斜杠本身又要转义，因此统一用 '!'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`).Replace(s)
+}
+
+// SearchFiles 按条件查询用户未删除的文件，按 ID 倒序，afterID 非0时只返回 ID 小于它的记录
+func (dao *fileDAOImpl) SearchFiles(userID int64, filter FileFilter, afterID int64, limit int) ([]File, error) {
+	query := dao.db.Model(&File{}).Where("user_id = ? AND trashed_at IS NULL", userID)
+	if afterID > 0 {
+		query = query.Where("id < ?", afterID)
+	}
+	if filter.Name != "" {
+		query = query.Where("file_name LIKE ? ESCAPE '!'", "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.MinSize > 0 {
+		query = query.Where("size >= ?", filter.MinSize)
+	}
+	if filter.MaxSize > 0 {
+		query = query.Where("size <= ?", filter.MaxSize)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.MimeType != "" {
+		if strings.HasSuffix(filter.MimeType, "/") {
+			query = query.Where("mime_type LIKE ? ESCAPE '!'", escapeLike(filter.MimeType)+"%")
+		} else {
+			query = query.Where("mime_type = ?", filter.MimeType)
+		}
+	}
+	if filter.UpdatedAfter != nil {
+		query = query.Where("updated_at >= ?", *filter.UpdatedAfter)
+	}
+	if filter.UpdatedBefore != nil {
+		query = query.Where("updated_at < ?", *filter.UpdatedBefore)
+	}
+	if filter.MtimeAfter != nil {
+		query = query.Where("mtime >= ?", *filter.MtimeAfter)
+	}
+	if filter.MtimeBefore != nil {
+		query = query.Where("mtime < ?", *filter.MtimeBefore)
+	}
+	for name, value := range filter.Tags {
+		tag := dao.db.Model(&FileTag{}).Select("1").Where("file_tags.file_id = files.id AND file_tags.name = ?", name)
+		if value != "" {
+			tag = tag.Where("file_tags.value = ?", value)
+		}
+		query = query.Where("EXISTS (?)", tag)
+	}
+
+	var files []File
+	err := query.Order("id desc").Limit(limit).Find(&files).Error
+	return files, err
+}
+
+// backfillMimeTypes 为 MIME 类型字段上线前的文件按扩展名补齐类型
+func (dao *fileDAOImpl) backfillMimeTypes() {
+	var files []File
+	if err := dao.db.Select("id", "file_name").Where("mime_type = '' OR mime_type IS NULL").Find(&files).Error; err != nil {
+		utils.Error("[Search] 查询待补齐 MIME 类型的文件失败: %v", err)
+		return
+	}
+	for _, f := range files {
+		dao.db.Model(&File{}).Where("id = ?", f.ID).Update("mime_type", utils.MimeTypeByName(f.FileName))
+	}
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearch_SearchFiles(t *testing.T) {
+	dao := newTestDAO(t)
+	now := time.Now()
+	ago := func(hours int) *time.Time {
+		at := now.Add(-time.Duration(hours) * time.Hour)
+		return &at
+	}
+	create := func(file *File, tags map[string]string) int64 {
+		t.Helper()
+		if file.UserID == 0 {
+			file.UserID = 1
+		}
+		if file.CreatedAt.IsZero() {
+			file.CreatedAt = now
+		}
+		if err := dao.CreateFile(file); err != nil {
+			t.Fatalf("CreateFile(%s) error = %v", file.FileName, err)
+		}
+		if tags != nil {
+			if err := dao.UpdateFileTags(file.ID, tags, nil); err != nil {
+				t.Fatalf("UpdateFileTags() error = %v", err)
+			}
+		}
+		return file.ID
+	}
+
+	underscore := create(&File{FileName: "report_2024.txt", Size: 100, MimeType: "text/plain", Status: 1, CreatedAt: *ago(48)}, nil)
+	dash := create(&File{FileName: "report-2024.txt", Size: 200, MimeType: "text/plain", Status: 1}, nil)
+	percent := create(&File{FileName: "100% done.png", Size: 300, MimeType: "image/png", Status: 1}, map[string]string{"project": "alpha"})
+	digits := create(&File{FileName: "1000 done.png", Size: 400, MimeType: "image/png", Status: 1, Mtime: ago(72)}, nil)
+	draft := create(&File{FileName: "draft.txt", Size: 50, MimeType: "text/plain", Status: 0}, map[string]string{"draft": ""})
+	// 回收站中的文件和其他用户的文件都不会被搜到
+	trashed := create(&File{FileName: "report_old.txt", Size: 100, Status: 1}, nil)
+	if err := dao.MoveFileToTrash(trashed, now); err != nil {
+		t.Fatalf("MoveFileToTrash() error = %v", err)
+	}
+	create(&File{FileName: "report_2024.txt", UserID: 2, Size: 100, Status: 1}, nil)
+
+	uploading := 0
+	tests := []struct {
+		name   string
+		filter FileFilter
+		want   []int64
+	}{
+		{name: "all", want: []int64{draft, digits, percent, dash, underscore}},
+		{name: "name with underscore", filter: FileFilter{Name: "report_"}, want: []int64{underscore}},
+		{name: "name with percent", filter: FileFilter{Name: "100%"}, want: []int64{percent}},
+		{name: "name substring", filter: FileFilter{Name: "done"}, want: []int64{digits, percent}},
+		{name: "size range", filter: FileFilter{MinSize: 200, MaxSize: 300}, want: []int64{percent, dash}},
+		{name: "status", filter: FileFilter{Status: &uploading}, want: []int64{draft}},
+		{name: "created before", filter: FileFilter{CreatedBefore: ago(24)}, want: []int64{underscore}},
+		{name: "created after", filter: FileFilter{CreatedAfter: ago(24), MinSize: 300}, want: []int64{digits, percent}},
+		{name: "mime prefix", filter: FileFilter{MimeType: "image/"}, want: []int64{digits, percent}},
+		{name: "mime exact", filter: FileFilter{MimeType: "text/plain", MaxSize: 100}, want: []int64{draft, underscore}},
+		{name: "mtime", filter: FileFilter{MtimeAfter: ago(96), MtimeBefore: ago(48)}, want: []int64{digits}},
+		{name: "updated", filter: FileFilter{UpdatedAfter: ago(1), UpdatedBefore: ago(-1), Name: "2024"}, want: []int64{dash, underscore}},
+		{name: "tag value", filter: FileFilter{Tags: map[string]string{"project": "alpha"}}, want: []int64{percent}},
+		{name: "tag presence", filter: FileFilter{Tags: map[string]string{"draft": ""}}, want: []int64{draft}},
+		{name: "tag value mismatch", filter: FileFilter{Tags: map[string]string{"project": "beta"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := dao.SearchFiles(1, tt.filter, 0, 100)
+			if err != nil {
+				t.Fatalf("SearchFiles() error = %v", err)
+			}
+			if got := fileIDs(files); !equalIDs(got, tt.want) {
+				t.Errorf("SearchFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 游标翻页：afterID 本身不再返回
+	var pages [][]int64
+	for afterID := int64(0); ; {
+		files, err := dao.SearchFiles(1, FileFilter{}, afterID, 2)
+		if err != nil {
+			t.Fatalf("SearchFiles(afterID=%d) error = %v", afterID, err)
+		}
+		if len(files) == 0 {
+			break
+		}
+		pages = append(pages, fileIDs(files))
+		afterID = files[len(files)-1].ID
+	}
+	want := [][]int64{{draft, digits}, {percent, dash}, {underscore}}
+	if len(pages) != len(want) {
+		t.Fatalf("pages = %v, want %v", pages, want)
+	}
+	for i := range want {
+		if !equalIDs(pages[i], want[i]) {
+			t.Errorf("page %d = %v, want %v", i, pages[i], want[i])
+		}
+	}
+}
+
+func fileIDs(files []File) []int64 {
+	ids := make([]int64, 0, len(files))
+	for _, f := range files {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// MoveFileToTrash 将文件标记为已移入回收站
+func (dao *fileDAOImpl) MoveFileToTrash(id int64, at time.Time) error {
+	return dao.db.Model(&File{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"trashed_at": at,
+		"live":       nil,
+	}).Error
+}
+
+// RestoreFileFromTrash 将文件从回收站恢复到指定目录，并使用给定名称，名称已被占用时返回 ErrNameExists
+func (dao *fileDAOImpl) RestoreFileFromTrash(id, folderID int64, name string) error {
+	err := dao.db.Model(&File{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"trashed_at": nil,
+		"live":       true,
+		"folder_id":  folderID,
+		"file_name":  name,
+	}).Error
+	if err != nil && isDuplicateKey(err) {
+		return ErrNameExists
+	}
+	return err
+}
+
+// ListTrash 分页列出用户回收站中的文件，按删除时间倒序
+func (dao *fileDAOImpl) ListTrash(userID int64, offset, limit int) ([]File, int64, error) {
+	var total int64
+	query := dao.db.Model(&File{}).Where("user_id = ? AND trashed_at IS NOT NULL", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var files []File
+	err := dao.db.Where("user_id = ? AND trashed_at IS NOT NULL", userID).
+		Order("trashed_at desc").Order("id desc").
+		Offset(offset).Limit(limit).Find(&files).Error
+	return files, total, err
+}
+
+// ListUserTrash 列出用户回收站中的全部文件
+func (dao *fileDAOImpl) ListUserTrash(userID int64) ([]File, error) {
+	var files []File
+	err := dao.db.Where("user_id = ? AND trashed_at IS NOT NULL", userID).Find(&files).Error
+	return files, err
+}
+
+// ListTrashedBefore 列出在 before 之前移入回收站的文件，最多 limit 条
+func (dao *fileDAOImpl) ListTrashedBefore(before time.Time, limit int) ([]File, error) {
+	var files []File
+	err := dao.db.Where("trashed_at IS NOT NULL AND trashed_at < ?", before).
+		Order("trashed_at asc").Limit(limit).Find(&files).Error
+	return files, err
+}
@@ -0,0 +1,204 @@
+package model
+
+import (
+	"time"
+
+	"cloud-storage-file-service/utils"
+
+	"gorm.io/gorm"
+)
+
+// FileVersion 文件版本，同一路径的每次上传都会生成一个新版本
+type FileVersion struct {
+	ID          int64  `gorm:"primaryKey"`
+	FileID      int64  `gorm:"index"`
+	VersionNo   int    // 从1开始递增
+	ObjectName  string `gorm:"size:512"` // 上传完成后为 Blob 的对象名
+	BlobID      int64  `gorm:"index"`    // 上传完成前为0
+	Size        int64
+	Md5         string
+	Sha256      string `gorm:"size:64"` // 上传中为客户端声明的值，完成后为服务端计算的值
+	Status      int    // 0 = uploading, 1 = completed, 2 = aborted
+	Reserved    int64  // 上传中时向用户服务预留的空间
+	CreatedAt   time.Time
+	ExpiresAt   *time.Time `gorm:"index"`    // 上传会话的过期时间，只有上传中的版本有值
+	UploadID    string     `gorm:"size:255"` // S3 分片上传ID，为空时使用独立的分片对象（压缩的上传和旧的上传会话）
+	Compression string     `gorm:"size:16"`  // 存储压缩方式，完成后与 Blob 一致
+	MimeType    string     `gorm:"size:127"` // 从第1个分片开头识别出的内容类型，为空时沿用文件按扩展名推断的类型
+	Mtime       *time.Time // 客户端提供的原始修改时间
+	Chunked     bool       // 内容定义分块上传，内容由分块清单描述，没有版本对象
+	Encryption  Encryption `gorm:"embedded"` // 完成后与 Blob 一致
+}
+
+// UserUsage 按文件表统计的用户已用空间
+type UserUsage struct {
+	UserID    int64
+	UsedSpace int64
+}
+
+// CreateVersion 创建版本记录
+func (dao *fileDAOImpl) CreateVersion(version *FileVersion) error {
+	return dao.db.Create(version).Error
+}
+
+// GetVersionByID 获取版本
+func (dao *fileDAOImpl) GetVersionByID(id int64) (*FileVersion, error) {
+	var version FileVersion
+	err := dao.db.First(&version, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// GetPendingVersion 获取文件最新的上传中版本
+func (dao *fileDAOImpl) GetPendingVersion(fileID int64) (*FileVersion, error) {
+	var version FileVersion
+	err := dao.db.Where("file_id = ? AND status = 0", fileID).Order("id desc").First(&version).Error
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// ListVersions 列出文件的所有版本，新版本在前
+func (dao *fileDAOImpl) ListVersions(fileID int64) ([]FileVersion, error) {
+	var versions []FileVersion
+	err := dao.db.Where("file_id = ?", fileID).Order("version_no desc").Find(&versions).Error
+	return versions, err
+}
+
+// MaxVersionNo 获取文件当前最大的版本号，没有版本时返回0
+func (dao *fileDAOImpl) MaxVersionNo(fileID int64) (int, error) {
+	var max int
+	err := dao.db.Model(&FileVersion{}).Where("file_id = ?", fileID).
+		Select("COALESCE(MAX(version_no), 0)").Scan(&max).Error
+	return max, err
+}
+
+// UpdateVersionStatus 更新版本状态
+func (dao *fileDAOImpl) UpdateVersionStatus(id int64, status int) error {
+	return dao.db.Model(&FileVersion{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// DeleteVersion 删除版本记录
+func (dao *fileDAOImpl) DeleteVersion(id int64) error {
+	return dao.db.Delete(&FileVersion{}, id).Error
+}
+
+// SetCurrentVersion 将版本设为文件的当前版本，并同步文件的对象名、大小、校验值、压缩方式、修改时间和内容类型
+func (dao *fileDAOImpl) SetCurrentVersion(fileID int64, version *FileVersion) error {
+	updates := map[string]interface{}{
+		"current_version_id": version.ID,
+		"object_name":        version.ObjectName,
+		"size":               version.Size,
+		"md5":                version.Md5,
+		"sha256":             version.Sha256,
+		"compression":        version.Compression,
+		"mtime":              version.Mtime,
+		"status":             1,
+	}
+	if version.MimeType != "" {
+		updates["mime_type"] = version.MimeType
+	}
+	return dao.db.Model(&File{}).Where("id = ?", fileID).Updates(updates).Error
+}
+
+// UpdateVersion 更新版本的对象、分片上传ID、Blob、加密和压缩方式、校验值和状态
+func (dao *fileDAOImpl) UpdateVersion(version *FileVersion) error {
+	return dao.db.Model(version).Select("object_name", "upload_id", "blob_id", "key_id", "data_key", "part_size",
+		"compression", "chunked", "size", "md5", "sha256", "status").Updates(version).Error
+}
+
+// AddVersionReserved 增加上传中版本预留的空间，版本已不在上传中时返回 false
+func (dao *fileDAOImpl) AddVersionReserved(id, delta int64) (bool, error) {
+	result := dao.db.Model(&FileVersion{}).Where("id = ? AND status = 0", id).
+		Update("reserved", gorm.Expr("reserved + ?", delta))
+	return result.RowsAffected == 1, result.Error
+}
+
+// TouchVersion 延长上传会话的过期时间
+func (dao *fileDAOImpl) TouchVersion(id int64, expiresAt time.Time) error {
+	return dao.db.Model(&FileVersion{}).Where("id = ? AND status = 0", id).
+		Update("expires_at", expiresAt).Error
+}
+
+// CountPendingVersions 统计上传中的版本数
+func (dao *fileDAOImpl) CountPendingVersions() (int64, error) {
+	var count int64
+	err := dao.db.Model(&FileVersion{}).Where("status = 0").Count(&count).Error
+	return count, err
+}
+
+// ListExpiredVersions 列出已过期的上传中版本
+// 没有过期时间的旧会话按创建时间早于 createdBefore 判断
+func (dao *fileDAOImpl) ListExpiredVersions(now, createdBefore time.Time, limit int) ([]FileVersion, error) {
+	var versions []FileVersion
+	err := dao.db.Where("status = 0 AND (expires_at < ? OR (expires_at IS NULL AND created_at < ?))", now, createdBefore).
+		Order("id asc").Limit(limit).Find(&versions).Error
+	return versions, err
+}
+
+// AbortVersion 把上传中的版本标记为已中止，预留空间清零
+func (dao *fileDAOImpl) AbortVersion(id int64) error {
+	return dao.db.Model(&FileVersion{}).Where("id = ? AND status = 0", id).
+		Updates(map[string]interface{}{"status": 2, "reserved": 0, "expires_at": nil}).Error
+}
+
+// SumUsageByUser 按用户统计已用空间：已完成版本按大小，上传中版本按预留空间
+// 回收站中的文件仍占用空间
+func (dao *fileDAOImpl) SumUsageByUser() ([]UserUsage, error) {
+	var usages []UserUsage
+	err := dao.db.Model(&FileVersion{}).
+		Select("files.user_id AS user_id, " +
+			"SUM(CASE WHEN file_versions.status = 1 THEN file_versions.size ELSE file_versions.reserved END) AS used_space").
+		Joins("JOIN files ON files.id = file_versions.file_id").
+		Group("files.user_id").
+		Scan(&usages).Error
+	return usages, err
+}
+
+// backfillVersions 为版本功能上线前创建的文件补建第1个版本，并把旧分片归到该版本下
+func (dao *fileDAOImpl) backfillVersions() {
+	var files []File
+	err := dao.db.Where("current_version_id = 0 AND NOT EXISTS (?)",
+		dao.db.Model(&FileVersion{}).Select("1").Where("file_versions.file_id = files.id")).
+		Find(&files).Error
+	if err != nil {
+		utils.Error("[Version] 查询待补建版本的文件失败: %v", err)
+		return
+	}
+
+	for _, f := range files {
+		version := &FileVersion{
+			FileID:     f.ID,
+			VersionNo:  1,
+			ObjectName: f.ObjectName,
+			Size:       f.Size,
+			Md5:        f.Md5,
+			Status:     f.Status,
+			CreatedAt:  f.CreatedAt,
+		}
+		if err := dao.db.Create(version).Error; err != nil {
+			utils.Error("[Version] 文件 %d 补建版本失败: %v", f.ID, err)
+			continue
+		}
+		dao.db.Model(&FilePart{}).Where("file_id = ? AND version_id = 0", f.ID).Update("version_id", version.ID)
+		if f.Status == 1 {
+			dao.db.Model(&File{}).Where("id = ?", f.ID).Update("current_version_id", version.ID)
+		}
+	}
+	if len(files) > 0 {
+		utils.Info("[Version] 已为 %d 个文件补建版本", len(files))
+	}
+}
+
+// backfillPartObjects 为分片记录补齐对象名，旧分片对象名由版本对象名推导
+func (dao *fileDAOImpl) backfillPartObjects() {
+	err := dao.db.Exec("UPDATE file_parts p JOIN file_versions v ON p.version_id = v.id " +
+		"SET p.object_name = CONCAT(v.object_name, '.part.', p.part_number) " +
+		"WHERE p.object_name = '' OR p.object_name IS NULL").Error
+	if err != nil {
+		utils.Error("[Version] 补齐分片对象名失败: %v", err)
+	}
+}
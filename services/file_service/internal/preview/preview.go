@@ -0,0 +1,116 @@
+// Package preview 从文件内容生成缩略图和文本预览，只使用纯 Go 的解码器
+package preview
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"unicode/utf8"
+
+	xdraw "golang.org/x/image/draw"
+
+	_ "image/gif"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels 可生成缩略图的图片最大像素数，防止解压炸弹耗尽内存
+const MaxPixels = 40 * 1000 * 1000
+
+var (
+	ErrTooLarge    = errors.New("图片像素过多")
+	ErrUnsupported = errors.New("不支持的图片格式")
+)
+
+// TextMimeType 文本预览的 MIME 类型
+const TextMimeType = "text/plain; charset=utf-8"
+
+// DecodeImage 解码 JPEG、PNG、GIF、WebP 或 BMP 图片，解码前先按头部检查像素数
+// GIF 只取第一帧
+func DecodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// Thumbnail 把图片等比缩放到最长边不超过 size，不放大
+func Thumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		size = max(w, h)
+	}
+	tw, th := size, size
+	if w >= h {
+		th = max(h*size/w, 1)
+	} else {
+		tw = max(w*size/h, 1)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	xdraw.BiLinear.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// Encode 编码缩略图，不透明的图片用 JPEG，有透明区域的用 PNG
+func Encode(img image.Image) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && !opaque.Opaque() {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
+
+// IsImage 按文件头的魔数判断是否为支持的图片格式
+func IsImage(head []byte) bool {
+	switch http.DetectContentType(head) {
+	case "image/jpeg", "image/png", "image/gif", "image/webp", "image/bmp":
+		return true
+	}
+	return false
+}
+
+// IsText 按开头的内容判断是否为 UTF-8 文本，末尾被截断的字符不影响判断
+func IsText(head []byte) bool {
+	if len(head) == 0 || bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	return utf8.Valid(trimPartialRune(head))
+}
+
+// Text 取文本开头不超过 limit 字节作为预览，不截断多字节字符
+func Text(data []byte, limit int) []byte {
+	if len(data) > limit {
+		data = data[:limit]
+	}
+	return trimPartialRune(data)
+}
+
+// trimPartialRune 去掉末尾不完整的 UTF-8 字符
+func trimPartialRune(data []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				return data[:len(data)-i]
+			}
+			break
+		}
+	}
+	return data
+}
@@ -0,0 +1,64 @@
+package preview
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestThumbnailKeepsAspectAndNeverUpscales(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	if b := Thumbnail(img, 128).Bounds(); b.Dx() != 128 || b.Dy() != 32 {
+		t.Fatalf("got %dx%d, want 128x32", b.Dx(), b.Dy())
+	}
+	if b := Thumbnail(img, 1024).Bounds(); b.Dx() != 400 || b.Dy() != 100 {
+		t.Fatalf("got %dx%d, want 400x100", b.Dx(), b.Dy())
+	}
+	tall := image.NewRGBA(image.Rect(0, 0, 10, 3000))
+	if b := Thumbnail(tall, 256).Bounds(); b.Dx() != 1 || b.Dy() != 256 {
+		t.Fatalf("got %dx%d, want 1x256", b.Dx(), b.Dy())
+	}
+}
+
+func TestDecodeAndEncode(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 64, 48))
+	src.Set(1, 1, color.NRGBA{R: 255, A: 128})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	if !IsImage(buf.Bytes()[:64]) {
+		t.Fatal("png header not recognized")
+	}
+	img, err := DecodeImage(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, mime, err := Encode(Thumbnail(img, 32)); err != nil || mime != "image/png" {
+		t.Fatalf("translucent thumbnail encoded as %q: %v", mime, err)
+	}
+	if _, mime, err := Encode(image.NewGray(image.Rect(0, 0, 8, 8))); err != nil || mime != "image/jpeg" {
+		t.Fatalf("opaque thumbnail encoded as %q: %v", mime, err)
+	}
+	if _, err := DecodeImage([]byte("not an image")); err != ErrUnsupported {
+		t.Fatalf("got %v, want ErrUnsupported", err)
+	}
+}
+
+func TestText(t *testing.T) {
+	data := []byte("第一行\nsecond line")
+	if !IsText(data[:4]) {
+		t.Fatal("text cut inside a rune should still be text")
+	}
+	if IsText([]byte{'a', 0, 'b'}) || IsText([]byte{0xff, 0xfe, 'a'}) {
+		t.Fatal("binary content detected as text")
+	}
+	if got := Text(data, 4); string(got) != "第" {
+		t.Fatalf("got %q, want %q", got, "第")
+	}
+	if got := Text(data, 100); !bytes.Equal(got, data) {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,153 @@
+package rpc
+
+import (
+	"cloud-storage-file-service/discovery"
+	"cloud-storage-file-service/internal/model"
+	userpb "cloud-storage-file-service/proto/user"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// UserClient 用户服务客户端，首次调用时通过 etcd 发现 user-service
+type UserClient struct {
+	etcdClient *discovery.EtcdClient
+	mu         sync.Mutex
+	conn       *grpc.ClientConn
+	client     userpb.UserServiceClient
+}
+
+// NewUserClient 创建用户服务客户端
+func NewUserClient(etcdClient *discovery.EtcdClient) *UserClient {
+	return &UserClient{etcdClient: etcdClient}
+}
+
+// getClient 获取 gRPC 客户端，尚未连接时先进行服务发现
+func (u *UserClient) getClient() (userpb.UserServiceClient, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.client != nil {
+		return u.client, nil
+	}
+
+	addrs, err := u.etcdClient.Discover("user-service")
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no available instance for user-service")
+	}
+
+	conn, err := grpc.NewClient(addrs[0], grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial user-service: %w", err)
+	}
+	u.conn = conn
+	u.client = userpb.NewUserServiceClient(conn)
+	return u.client, nil
+}
+
+// UpdateUsage 增减用户已用空间，delta 为负数表示返还
+func (u *UserClient) UpdateUsage(ctx context.Context, userID int64, delta int64) error {
+	client, err := u.getClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := client.UpdateUsage(ctx, &userpb.UpdateUsageRequest{
+		UserId: userID,
+		Delta:  delta,
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.GetSuccess() {
+		return fmt.Errorf("user-service rejected usage update for user %d", userID)
+	}
+	return nil
+}
+
+// ReserveSpace 预留空间，剩余容量不足时返回 false
+func (u *UserClient) ReserveSpace(ctx context.Context, userID int64, size int64) (bool, error) {
+	client, err := u.getClient()
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := client.ReserveSpace(ctx, &userpb.ReserveSpaceRequest{
+		UserId: userID,
+		Size:   size,
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetSuccess(), nil
+}
+
+// SetUsage 在已用空间仍为 expected 时设置为 usedSpace，用于对账
+// 期间已用空间被其他请求修改过时返回 false
+func (u *UserClient) SetUsage(ctx context.Context, userID int64, expected, usedSpace int64) (bool, error) {
+	client, err := u.getClient()
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := client.SetUsage(ctx, &userpb.SetUsageRequest{
+		UserId:            userID,
+		UsedSpace:         usedSpace,
+		ExpectedUsedSpace: expected,
+	})
+	if err != nil {
+		return false, err
+	}
+	if resp.GetConflict() {
+		return false, nil
+	}
+	if !resp.GetSuccess() {
+		return false, fmt.Errorf("user-service rejected usage reset for user %d", userID)
+	}
+	return true, nil
+}
+
+// ListUsage 按用户ID顺序列出已用空间不为0的用户，用于对账
+func (u *UserClient) ListUsage(ctx context.Context, afterID int64, limit int) ([]model.UserUsage, error) {
+	client, err := u.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := client.ListUsage(ctx, &userpb.ListUsageRequest{
+		AfterId: afterID,
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	usages := make([]model.UserUsage, 0, len(resp.GetUsages()))
+	for _, u := range resp.GetUsages() {
+		usages = append(usages, model.UserUsage{UserID: u.GetUserId(), UsedSpace: u.GetUsedSpace()})
+	}
+	return usages, nil
+}
+
+// Close 关闭连接
+func (u *UserClient) Close() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.conn != nil {
+		return u.conn.Close()
+	}
+	return nil
+}
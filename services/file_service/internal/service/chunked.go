@@ -0,0 +1,331 @@
+package service
+
+import (
+	"bytes"
+	"cloud-storage-file-service/internal/chunker"
+	"cloud-storage-file-service/internal/encryption"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// ChunkRef 客户端按 FastCDC 切分出的一个分块
+type ChunkRef struct {
+	Sha256 string
+	Size   int64
+}
+
+// validSha256 是否为小写十六进制的 SHA-256
+func validSha256(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// InitChunkedUpload 以内容定义分块的方式开始上传，chunks 为文件按顺序切分出的分块
+// 返回服务端还缺少的分块哈希，客户端只需通过 UploadChunk 上传这些分块，然后调用 UploadComplete
+// 目录下已有同名文件时为其创建新版本，未改动的分块与旧版本共享
+func (s *StorageService) InitChunkedUpload(ctx context.Context, fileName string, size int64, md5, sha256 string, userID, folderID int64, chunks []ChunkRef, meta FileMeta) (*model.File, []string, error) {
+	if err := validateName(fileName); err != nil {
+		return nil, nil, err
+	}
+	if err := validateTags(meta.Tags, nil); err != nil {
+		return nil, nil, err
+	}
+	if !validSha256(sha256) {
+		return nil, nil, fmt.Errorf("分块上传必须提供文件的 SHA-256")
+	}
+	if len(chunks) == 0 {
+		return nil, nil, fmt.Errorf("分块清单为空")
+	}
+	var total int64
+	for i, c := range chunks {
+		if !validSha256(c.Sha256) {
+			return nil, nil, fmt.Errorf("分块 %d 的 SHA-256 不合法", i)
+		}
+		if c.Size <= 0 || c.Size > chunker.MaxSize {
+			return nil, nil, fmt.Errorf("分块 %d 的大小不合法: %d", i, c.Size)
+		}
+		total += c.Size
+	}
+	if total != size {
+		return nil, nil, fmt.Errorf("分块大小之和 %d 与文件大小 %d 不一致", total, size)
+	}
+
+	view, err := s.initUpload(ctx, fileName, size, md5, sha256, userID, folderID, uploadOptions{chunks: chunks, meta: meta})
+	if err != nil {
+		return nil, nil, err
+	}
+	if view.Status == 1 {
+		return view, nil, nil
+	}
+	version, err := s.pendingVersion(view.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	missing, err := s.fileDAO.MissingChunks(version.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询缺少的分块失败: %v", err)
+	}
+	utils.Info("[Chunked] 文件ID=%d 共 %d 个分块, 需上传 %d 个", view.ID, len(chunks), len(missing))
+	return view, missing, nil
+}
+
+// createManifest 为上传中的版本创建分块清单，并关联 userID 已持有的分块
+func (s *StorageService) createManifest(userID int64, version *model.FileVersion, chunks []ChunkRef) error {
+	entries := make([]model.ManifestEntry, 0, len(chunks))
+	var offset int64
+	for i, c := range chunks {
+		entries = append(entries, model.ManifestEntry{
+			VersionID: version.ID,
+			Seq:       i,
+			Offset:    offset,
+			Size:      c.Size,
+			Sha256:    c.Sha256,
+		})
+		offset += c.Size
+	}
+	if err := s.fileDAO.CreateManifest(entries); err != nil {
+		return fmt.Errorf("创建分块清单失败: %v", err)
+	}
+	if err := s.fileDAO.LinkChunks(userID, version.ID); err != nil {
+		return fmt.Errorf("关联已有分块失败: %v", err)
+	}
+	return nil
+}
+
+// UploadChunk 上传分块上传中缺少的一个分块，内容的 SHA-256 必须与声明的一致
+// 分块已存在（包括其他上传刚刚上传过）时直接返回成功
+func (s *StorageService) UploadChunk(ctx context.Context, fileID int64, chunkSha256 string, data []byte) error {
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != chunkSha256 {
+		return fmt.Errorf("分块校验失败")
+	}
+	file, err := s.fileDAO.GetFileByID(fileID)
+	if err != nil {
+		return fmt.Errorf("找不到文件记录: %v", err)
+	}
+	version, err := s.pendingVersion(fileID)
+	if err != nil {
+		return err
+	}
+	if !version.Chunked {
+		return fmt.Errorf("该上传不是分块上传")
+	}
+	missing, err := s.fileDAO.MissingChunks(version.ID)
+	if err != nil {
+		return fmt.Errorf("查询缺少的分块失败: %v", err)
+	}
+	if i := sort.SearchStrings(missing, chunkSha256); i == len(missing) || missing[i] != chunkSha256 {
+		// 已上传过的分块重复上传视为成功，清单之外的分块拒绝
+		entries, err := s.fileDAO.ListVersionManifest(version.ID)
+		if err != nil {
+			return fmt.Errorf("查询分块清单失败: %v", err)
+		}
+		for _, e := range entries {
+			if e.Sha256 == chunkSha256 {
+				return nil
+			}
+		}
+		return fmt.Errorf("分块不在清单中: %s", chunkSha256)
+	}
+
+	enc, err := s.newEncryption(file.UserID)
+	if err != nil {
+		return err
+	}
+	reader, err := s.encryptStream(enc, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	chunk := &model.Chunk{
+		Sha256:     chunkSha256,
+		Size:       int64(len(data)),
+		Bucket:     s.bucket,
+		ObjectName: newObjectKey(),
+		CreatedAt:  time.Now(),
+		Encryption: enc,
+	}
+	if _, err := s.store.Put(ctx, chunk.ObjectName, reader, chunk.Size); err != nil {
+		return fmt.Errorf("上传分块失败: %v", err)
+	}
+	if _, created, err := s.fileDAO.SaveChunk(version.ID, chunk); err != nil || !created {
+		s.store.Delete(ctx, chunk.ObjectName)
+		if err != nil {
+			return fmt.Errorf("登记分块失败: %v", err)
+		}
+	}
+	s.touchUpload(version)
+	return nil
+}
+
+// completeChunked 完成分块上传：确认分块都已上传，按清单读出全文校验后登记 Blob
+func (s *StorageService) completeChunked(ctx context.Context, file *model.File, version *model.FileVersion) error {
+	entries, err := s.fileDAO.ListVersionManifest(version.ID)
+	if err != nil {
+		return fmt.Errorf("查询分块清单失败: %v", err)
+	}
+	missing := 0
+	for _, e := range entries {
+		if e.ChunkID == 0 {
+			missing++
+		}
+	}
+	if missing > 0 {
+		return fmt.Errorf("还有 %d 个分块未上传", missing)
+	}
+
+	reader, err := s.openManifest(ctx, entries, 0, 0)
+	if err != nil {
+		return err
+	}
+	head := newHeadReader(reader)
+	shaStr, md5Str, size, err := hashReader(head)
+	reader.Close()
+	if err != nil {
+		return err
+	}
+	if version.Sha256 != shaStr {
+		return fmt.Errorf("文件校验失败: client=%s, server=%s", version.Sha256, shaStr)
+	}
+	s.recordMimeType(version, head.head)
+	return s.finishVersion(ctx, file.UserID, version, size, md5Str, shaStr)
+}
+
+// chunkedUploadProgress 分块上传中已存在的分块总大小
+func (s *StorageService) chunkedUploadProgress(version *model.FileVersion) (int64, error) {
+	entries, err := s.fileDAO.ListVersionManifest(version.ID)
+	if err != nil {
+		return 0, err
+	}
+	var uploaded int64
+	for _, e := range entries {
+		if e.ChunkID != 0 {
+			uploaded += e.Size
+		}
+	}
+	return uploaded, nil
+}
+
+// releaseManifest 删除分块清单并释放分块，引用归零的分块对象随之删除，返回删除的分块总大小
+func (s *StorageService) releaseManifest(ctx context.Context, versionID, blobID int64) (int64, error) {
+	released, err := s.fileDAO.ReleaseManifest(versionID, blobID)
+	if err != nil {
+		return 0, fmt.Errorf("释放分块失败: %v", err)
+	}
+	var freed int64
+	for _, c := range released {
+		if err := s.store.Delete(ctx, c.ObjectName); err != nil {
+			utils.Error("[Chunked] 删除分块对象 %s 失败: %v", c.ObjectName, err)
+			continue
+		}
+		freed += c.Size
+	}
+	return freed, nil
+}
+
+// openChunkedVersion 读取分块存储的版本从 offset 开始的 length 字节，length 为0时读到末尾
+func (s *StorageService) openChunkedVersion(ctx context.Context, version *model.FileVersion, offset, length int64) (io.ReadCloser, error) {
+	entries, err := s.fileDAO.ListBlobManifest(version.BlobID)
+	if err != nil {
+		return nil, fmt.Errorf("查询分块清单失败: %v", err)
+	}
+	return s.openManifest(ctx, entries, offset, length)
+}
+
+// openManifest 按清单依次读取分块，从 offset 开始共 length 字节，length 为0时读到末尾
+func (s *StorageService) openManifest(ctx context.Context, entries []model.ManifestEntry, offset, length int64) (io.ReadCloser, error) {
+	first := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Offset+entries[i].Size > offset
+	})
+	entries = entries[first:]
+	if length > 0 {
+		last := sort.Search(len(entries), func(i int) bool {
+			return entries[i].Offset >= offset+length
+		})
+		entries = entries[:last]
+	}
+
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.ChunkID)
+	}
+	chunks, err := s.fileDAO.GetChunks(ids)
+	if err != nil {
+		return nil, fmt.Errorf("查询分块失败: %v", err)
+	}
+	byID := make(map[int64]*model.Chunk, len(chunks))
+	for i := range chunks {
+		byID[chunks[i].ID] = &chunks[i]
+	}
+	for _, e := range entries {
+		if byID[e.ChunkID] == nil {
+			return nil, fmt.Errorf("分块不存在: %s", e.Sha256)
+		}
+	}
+
+	reader := &manifestReader{ctx: ctx, s: s, entries: entries, chunks: byID, offset: offset}
+	if length > 0 {
+		return decryptingReader{Reader: io.LimitReader(reader, length), Closer: reader}, nil
+	}
+	return reader, nil
+}
+
+// manifestReader 依次打开分块对象读取，第一个分块从 offset 处开始
+type manifestReader struct {
+	ctx     context.Context
+	s       *StorageService
+	entries []model.ManifestEntry
+	chunks  map[int64]*model.Chunk
+	offset  int64
+	current io.ReadCloser
+}
+
+func (r *manifestReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.entries) == 0 {
+				return 0, io.EOF
+			}
+			entry := r.entries[0]
+			r.entries = r.entries[1:]
+			chunk := r.chunks[entry.ChunkID]
+			skip := max(r.offset-entry.Offset, 0)
+			layout := encryption.Layout{PartSize: chunk.Encryption.PartSize}
+			object, err := r.s.openObject(r.ctx, chunk.ObjectName, chunk.Encryption, layout, skip, 0)
+			if err != nil {
+				return 0, fmt.Errorf("读取分块 %s 失败: %v", chunk.Sha256, err)
+			}
+			r.current = object
+		}
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *manifestReader) Close() error {
+	if r.current != nil {
+		return r.current.Close()
+	}
+	return nil
+}
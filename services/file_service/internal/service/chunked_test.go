@@ -0,0 +1,75 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+// chunkRefs 把 chunks 拼成文件内容，并返回对应的分块清单
+func chunkRefs(chunks ...[]byte) ([]byte, []ChunkRef) {
+	var data []byte
+	refs := make([]ChunkRef, 0, len(chunks))
+	for _, c := range chunks {
+		data = append(data, c...)
+		refs = append(refs, ChunkRef{Sha256: sha256Of(c), Size: int64(len(c))})
+	}
+	return data, refs
+}
+
+func TestStorageService_ChunkedUpload(t *testing.T) {
+	s, _, _ := newTestService(t)
+	ctx := context.Background()
+	c1, c2, c3 := randomData(10, 3000), randomData(11, 2000), randomData(12, 1000)
+
+	data, refs := chunkRefs(c1, c2)
+	view, missing, err := s.InitChunkedUpload(ctx, "a.bin", int64(len(data)), md5Of(data), sha256Of(data), 1, 0, refs, FileMeta{})
+	if err != nil {
+		t.Fatalf("InitChunkedUpload() error = %v", err)
+	}
+	if len(missing) != 2 {
+		t.Fatalf("missing = %d, want 2", len(missing))
+	}
+	if err := s.UploadChunk(ctx, view.ID, sha256Of(c1), c2); err == nil {
+		t.Error("UploadChunk() with mismatched content should fail")
+	}
+	for _, c := range [][]byte{c1, c2} {
+		if err := s.UploadChunk(ctx, view.ID, sha256Of(c), c); err != nil {
+			t.Fatalf("UploadChunk() error = %v", err)
+		}
+	}
+	if err := s.UploadComplete(ctx, view.ID); err != nil {
+		t.Fatalf("UploadComplete() error = %v", err)
+	}
+	if got := download(t, s, view.ID, 0); !bytes.Equal(got, data) {
+		t.Fatalf("downloaded %d bytes, want %d", len(got), len(data))
+	}
+
+	// 同一用户的新文件只需要上传没有的分块
+	data2, refs2 := chunkRefs(c1, c3)
+	view2, missing, err := s.InitChunkedUpload(ctx, "b.bin", int64(len(data2)), md5Of(data2), sha256Of(data2), 1, 0, refs2, FileMeta{})
+	if err != nil {
+		t.Fatalf("InitChunkedUpload() error = %v", err)
+	}
+	if len(missing) != 1 || missing[0] != sha256Of(c3) {
+		t.Fatalf("missing = %v, want only c3", missing)
+	}
+	if err := s.UploadChunk(ctx, view2.ID, sha256Of(c3), c3); err != nil {
+		t.Fatalf("UploadChunk() error = %v", err)
+	}
+	if err := s.UploadComplete(ctx, view2.ID); err != nil {
+		t.Fatalf("UploadComplete() error = %v", err)
+	}
+	if got := readRange(t, s, view2.ID, 2990, 20); !bytes.Equal(got, data2[2990:3010]) {
+		t.Error("range across chunks mismatch")
+	}
+
+	// 其他用户只声明哈希不能引用这些分块
+	_, missing, err = s.InitChunkedUpload(ctx, "c.bin", int64(len(data)), md5Of(data), sha256Of(data), 2, 0, refs, FileMeta{})
+	if err != nil {
+		t.Fatalf("InitChunkedUpload() error = %v", err)
+	}
+	if len(missing) != 2 {
+		t.Errorf("other user missing = %d, want 2", len(missing))
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"bytes"
+	"cloud-storage-file-service/internal/compression"
+	"cloud-storage-file-service/internal/encryption"
+	"cloud-storage-file-service/internal/model"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// compressPart 把分片按块压缩，返回压缩后的内容、编码后的块列表和明文大小
+// 分片大小受分片上限约束，整块读入内存压缩
+func compressPart(codec string, reader io.Reader, size int64) ([]byte, []byte, int64, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("读取分片失败: %v", err)
+	}
+	if size > 0 && int64(len(data)) != size {
+		return nil, nil, 0, fmt.Errorf("分片大小不一致: 声明 %d, 实际 %d", size, len(data))
+	}
+	packed, blocks, err := compression.Compress(codec, data)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("压缩分片失败: %v", err)
+	}
+	return packed, compression.EncodeBlocks(blocks), int64(len(data)), nil
+}
+
+// partObjectReader 依次读出分片对象解密后的内容，读到时才打开对象
+type partObjectReader struct {
+	ctx     context.Context
+	s       *StorageService
+	version *model.FileVersion
+	part    model.FilePart
+	object  io.ReadCloser
+}
+
+func (r *partObjectReader) Read(p []byte) (int, error) {
+	if r.object == nil {
+		layout := encryption.Layout{Parts: []encryption.Part{{Number: r.part.PartNumber}}}
+		object, err := r.s.openObject(r.ctx, r.part.ObjectName, r.version.Encryption, layout, 0, 0)
+		if err != nil {
+			return 0, fmt.Errorf("读取分片 %d 失败: %v", r.part.PartNumber, err)
+		}
+		r.object = object
+	}
+	n, err := r.object.Read(p)
+	if err == io.EOF {
+		r.object.Close()
+	}
+	return n, err
+}
+
+// concatCompressedParts 把压缩的分片对象依次拼接为版本对象，末尾写入块索引，完成后删除分片对象
+// 压缩后的分片可能小于对象存储合并要求的最小分片大小，因此由文件服务读出后整体写入，加密的内容改为整体连续加密
+func (s *StorageService) concatCompressedParts(ctx context.Context, version *model.FileVersion, parts []model.FilePart) error {
+	var blocks []compression.Block
+	readers := make([]io.Reader, 0, len(parts)+1)
+	for _, p := range parts {
+		partBlocks, err := compression.DecodeBlocks(p.Blocks)
+		if err != nil {
+			return fmt.Errorf("分片 %d 的块信息损坏: %v", p.PartNumber, err)
+		}
+		blocks = append(blocks, partBlocks...)
+		readers = append(readers, &partObjectReader{ctx: ctx, s: s, version: version, part: p})
+	}
+	footer := compression.Footer(blocks)
+	readers = append(readers, bytes.NewReader(footer))
+	total := compression.NewIndex(blocks).StoredSize() + int64(len(footer))
+
+	version.Encryption.PartSize = 0
+	sealed, err := s.encryptStream(version.Encryption, io.MultiReader(readers...))
+	if err != nil {
+		return err
+	}
+	if _, err := s.store.Put(ctx, version.ObjectName, sealed, total); err != nil {
+		return fmt.Errorf("合并分片失败: %v", err)
+	}
+	s.removePartObjects(ctx, parts)
+	return nil
+}
+
+// blockIndex 读取压缩对象末尾的块索引
+func (s *StorageService) blockIndex(ctx context.Context, objectName string, enc model.Encryption) (*compression.Index, error) {
+	if idx, ok := s.indexes.get(objectName); ok {
+		return idx, nil
+	}
+	info, err := s.store.Stat(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size < compression.TrailerSize {
+		return nil, compression.ErrCorruptIndex
+	}
+
+	layout := encryption.Layout{PartSize: enc.PartSize}
+	read := func(offset, length int64) ([]byte, error) {
+		object, err := s.openObject(ctx, objectName, enc, layout, offset, length)
+		if err != nil {
+			return nil, err
+		}
+		defer object.Close()
+		buf := make([]byte, length)
+		if _, err := io.ReadFull(object, buf); err != nil {
+			return nil, err
+		}
+		return buf, nil
+	}
+
+	trailer, err := read(info.Size-compression.TrailerSize, compression.TrailerSize)
+	if err != nil {
+		return nil, err
+	}
+	indexLen, err := compression.ParseTrailer(trailer)
+	if err != nil {
+		return nil, err
+	}
+	if indexLen <= 0 || indexLen > info.Size-compression.TrailerSize {
+		return nil, compression.ErrCorruptIndex
+	}
+	buf, err := read(info.Size-compression.TrailerSize-indexLen, indexLen)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := compression.DecodeBlocks(buf)
+	if err != nil {
+		return nil, err
+	}
+	idx := compression.NewIndex(blocks)
+	if idx.StoredSize()+indexLen+compression.TrailerSize != info.Size {
+		return nil, compression.ErrCorruptIndex
+	}
+	s.indexes.put(objectName, idx)
+	return idx, nil
+}
+
+// openCompressed 读取压缩对象明文从 offset 开始的 length 字节，length 为0时读到末尾
+// 只读取并解压覆盖该范围的块
+func (s *StorageService) openCompressed(ctx context.Context, objectName string, enc model.Encryption, codec string, offset, length int64) (io.ReadCloser, error) {
+	idx, err := s.blockIndex(ctx, objectName, enc)
+	if err != nil {
+		return nil, fmt.Errorf("读取块索引失败: %v", err)
+	}
+	first, last, start, end := idx.Range(offset, length)
+	if start == end {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	object, err := s.openObject(ctx, objectName, enc, encryption.Layout{PartSize: enc.PartSize}, start, end-start)
+	if err != nil {
+		return nil, err
+	}
+	var reader io.Reader = idx.NewReader(object, codec, first, last, offset)
+	if length > 0 {
+		reader = io.LimitReader(reader, length)
+	}
+	return decryptingReader{Reader: reader, Closer: object}, nil
+}
+
+// indexCache 压缩对象块索引的 LRU 缓存，对象写入后不再修改，按对象名缓存
+type indexCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // 最近使用的在前
+	entries map[string]*list.Element
+}
+
+type indexEntry struct {
+	objectName string
+	index      *compression.Index
+}
+
+func newIndexCache(max int) *indexCache {
+	return &indexCache{max: max, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *indexCache) get(objectName string) (*compression.Index, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[objectName]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*indexEntry).index, true
+}
+
+func (c *indexCache) put(objectName string, idx *compression.Index) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[objectName]; ok {
+		elem.Value.(*indexEntry).index = idx
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[objectName] = c.order.PushFront(&indexEntry{objectName: objectName, index: idx})
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*indexEntry).objectName)
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CopyFile 把文件的当前版本复制为 userID 在 folderID 下的新文件，内容不重新上传
+// 调用方负责校验 userID 能否读取源文件；name 为空时沿用源文件名，重名时追加 " (n)" 后缀
+// 新文件引用源版本的 Blob，只增加引用计数，没有 Blob 的旧版本在存储端复制对象
+// 复制的大小计入 userID 的已用空间，标签一并复制
+func (s *StorageService) CopyFile(ctx context.Context, userID, fileID, folderID int64, name string) (*model.File, error) {
+	src, err := s.fileDAO.GetFileByID(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("找不到文件记录: %v", err)
+	}
+	if src.Status != 1 {
+		return nil, fmt.Errorf("文件尚未完成上传")
+	}
+	if src.TrashedAt != nil {
+		return nil, fmt.Errorf("文件已在回收站中")
+	}
+	if name == "" {
+		name = src.FileName
+	}
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	if _, err := s.checkFolder(userID, folderID); err != nil {
+		return nil, err
+	}
+	name, err = s.availableName(userID, folderID, name)
+	if err != nil {
+		return nil, err
+	}
+	srcVersion, err := s.resolveVersion(src.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadTags(src); err != nil {
+		return nil, err
+	}
+
+	reserved, err := s.reserveSpace(ctx, userID, srcVersion.Size)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := s.retainVersionBlob(ctx, srcVersion)
+	if err != nil {
+		s.reportUsage(ctx, userID, -reserved)
+		return nil, err
+	}
+
+	file := &model.File{
+		FileName:  name,
+		Bucket:    s.bucket,
+		MimeType:  src.MimeType,
+		Status:    0,
+		CreatedAt: time.Now(),
+		UserID:    userID,
+		FolderID:  folderID,
+	}
+	if err := s.fileDAO.CreateFile(file); err != nil {
+		s.releaseBlob(ctx, blob.ID)
+		s.reportUsage(ctx, userID, -reserved)
+		if errors.Is(err, model.ErrNameExists) {
+			return nil, errNameTaken(name)
+		}
+		return nil, fmt.Errorf("创建文件记录失败: %v", err)
+	}
+	version := &model.FileVersion{
+		ObjectName:  blob.ObjectName,
+		BlobID:      blob.ID,
+		Size:        blob.Size,
+		Md5:         blob.Md5,
+		Sha256:      blob.Sha256,
+		Status:      1,
+		Encryption:  blob.Encryption,
+		Compression: blob.Compression,
+		Chunked:     blob.Chunked,
+		MimeType:    srcVersion.MimeType,
+		Mtime:       srcVersion.Mtime,
+	}
+	if err := s.createVersion(file, version); err != nil {
+		s.fileDAO.DeleteFile(file.ID)
+		s.releaseBlob(ctx, blob.ID)
+		s.reportUsage(ctx, userID, -reserved)
+		return nil, err
+	}
+	if err := s.applyTags(file, src.Tags, nil); err != nil {
+		s.rollbackCopy(ctx, file)
+		return nil, err
+	}
+	return file, nil
+}
+
+// rollbackCopy 撤销复制出的文件，删除记录和版本时释放 Blob 引用并返还占用的空间
+func (s *StorageService) rollbackCopy(ctx context.Context, file *model.File) {
+	if err := s.DeleteFile(ctx, file.ID); err != nil {
+		utils.Error("[Copy] 回滚文件 %d 失败: %v", file.ID, err)
+	}
+}
+
+// retainVersionBlob 为已完成版本的 Blob 增加一次引用
+// 没有 Blob 的旧版本在存储端把对象复制为新对象，并登记为新的 Blob
+func (s *StorageService) retainVersionBlob(ctx context.Context, version *model.FileVersion) (*model.Blob, error) {
+	if version.BlobID != 0 {
+		blob, err := s.fileDAO.RetainBlob(version.BlobID)
+		if err != nil {
+			return nil, fmt.Errorf("引用 Blob 失败: %v", err)
+		}
+		return blob, nil
+	}
+
+	objectName := newObjectKey()
+	if err := s.store.Compose(ctx, objectName, []string{version.ObjectName}); err != nil {
+		return nil, fmt.Errorf("复制对象失败: %v", err)
+	}
+	blob, _, err := s.fileDAO.AcquireBlob(&model.Blob{
+		Sha256:      version.Sha256,
+		Md5:         version.Md5,
+		Size:        version.Size,
+		Bucket:      s.bucket,
+		ObjectName:  objectName,
+		CreatedAt:   time.Now(),
+		Encryption:  version.Encryption,
+		Compression: version.Compression,
+	})
+	if err != nil {
+		s.store.Delete(ctx, objectName)
+		return nil, fmt.Errorf("登记 Blob 失败: %v", err)
+	}
+	if blob.ObjectName != objectName {
+		// 已有内容相同的 Blob，复制的对象不再需要
+		s.store.Delete(ctx, objectName)
+	}
+	return blob, nil
+}
+
+// MoveFile 在 userID 自己的目录间移动文件，name 为空时沿用原文件名
+func (s *StorageService) MoveFile(ctx context.Context, userID, fileID, folderID int64, name string) (*model.File, error) {
+	file, err := s.ownedFile(userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file.TrashedAt != nil {
+		return nil, fmt.Errorf("文件已在回收站中")
+	}
+	if name == "" {
+		name = file.FileName
+	}
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	if folderID == file.FolderID && name == file.FileName {
+		return file, s.loadTags(file)
+	}
+	if _, err := s.checkFolder(file.UserID, folderID); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(file.UserID, folderID, name); err != nil {
+		return nil, err
+	}
+	if err := s.fileDAO.MoveFile(file.ID, folderID, name); err != nil {
+		if errors.Is(err, model.ErrNameExists) {
+			return nil, errNameTaken(name)
+		}
+		return nil, fmt.Errorf("移动文件失败: %v", err)
+	}
+
+	moved, err := s.fileDAO.GetFileByID(file.ID)
+	if err != nil {
+		return nil, err
+	}
+	return moved, s.loadTags(moved)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestStorageService_CopyFile(t *testing.T) {
+	s, usage, _ := newTestService(t)
+	ctx := context.Background()
+	data := []byte("copy me")
+	src := upload(t, s, 1, 0, "src.txt", data, "")
+	folder, err := s.CreateFolder(ctx, 1, 0, "backup")
+	if err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+
+	dst, err := s.CopyFile(ctx, 1, src.ID, folder.ID, "")
+	if err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	if dst.ID == src.ID || dst.FolderID != folder.ID || dst.FileName != "src.txt" {
+		t.Errorf("copy = %+v", dst)
+	}
+	if got := download(t, s, dst.ID, 0); !bytes.Equal(got, data) {
+		t.Errorf("copied content = %q", got)
+	}
+	again, err := s.CopyFile(ctx, 1, src.ID, folder.ID, "")
+	if err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	if again.FileName != "src (1).txt" {
+		t.Errorf("name = %q, want %q", again.FileName, "src (1).txt")
+	}
+	if got := usage.get(1); got != 3*int64(len(data)) {
+		t.Errorf("used = %d, want %d", got, 3*len(data))
+	}
+
+	// 删除源文件不影响共享同一 Blob 的副本
+	if err := s.DeleteFile(ctx, src.ID); err != nil {
+		t.Fatalf("DeleteFile() error = %v", err)
+	}
+	if got := download(t, s, dst.ID, 0); !bytes.Equal(got, data) {
+		t.Errorf("copied content after source deleted = %q", got)
+	}
+}
+
+func TestStorageService_MoveFile(t *testing.T) {
+	s, _, _ := newTestService(t)
+	ctx := context.Background()
+	file := upload(t, s, 1, 0, "move.txt", []byte("move me"), "")
+	folder, err := s.CreateFolder(ctx, 1, 0, "docs")
+	if err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	other, err := s.CreateFolder(ctx, 2, 0, "theirs")
+	if err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+
+	moved, err := s.MoveFile(ctx, 1, file.ID, folder.ID, "renamed.txt")
+	if err != nil {
+		t.Fatalf("MoveFile() error = %v", err)
+	}
+	if moved.FolderID != folder.ID || moved.FileName != "renamed.txt" {
+		t.Errorf("moved = %+v", moved)
+	}
+	if _, err := s.MoveFile(ctx, 1, file.ID, other.ID, ""); err == nil {
+		t.Error("MoveFile() into another user's folder should fail")
+	}
+	if _, err := s.MoveFile(ctx, 2, file.ID, other.ID, ""); err == nil {
+		t.Error("MoveFile() by another user should fail")
+	}
+
+	upload(t, s, 1, 0, "taken.txt", []byte("x"), "")
+	if _, err := s.MoveFile(ctx, 1, file.ID, 0, "taken.txt"); err == nil {
+		t.Error("MoveFile() onto an existing name should fail")
+	}
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"bytes"
+	"cloud-storage-file-service/internal/encryption"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/internal/preview"
+	"cloud-storage-file-service/utils"
+	"context"
+	"expvar"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// deriveMetrics 缩略图和预览生成的累计指标，通过 /debug/vars 暴露
+var deriveMetrics = expvar.NewMap("derivations")
+
+// previewOptions 缩略图和文本预览的生成参数
+type previewOptions struct {
+	sizes     []int // 缩略图最长边像素，升序
+	textBytes int   // 文本预览取文件开头的字节数
+	maxImage  int64 // 超过该大小的图片不生成缩略图
+}
+
+// defaultPreviewOptions 默认生成 128、256、512 像素的缩略图和 64KB 的文本预览
+func defaultPreviewOptions() previewOptions {
+	return previewOptions{
+		sizes:     []int{128, 256, 512},
+		textBytes: 64 * 1024,
+		maxImage:  50 * 1024 * 1024,
+	}
+}
+
+// SetPreviewOptions 设置缩略图尺寸、文本预览大小和生成缩略图的图片大小上限，不大于0的参数保持默认值
+func (s *StorageService) SetPreviewOptions(sizes []int, textBytes int, maxImage int64) {
+	var valid []int
+	for _, size := range sizes {
+		if size > 0 {
+			valid = append(valid, size)
+		}
+	}
+	if len(valid) > 0 {
+		sort.Ints(valid)
+		s.previews.sizes = valid
+	}
+	if textBytes > 0 {
+		s.previews.textBytes = textBytes
+	}
+	if maxImage > 0 {
+		s.previews.maxImage = maxImage
+	}
+}
+
+// Thumbnail 读取到的缩略图或文本预览，Data 为空表示没有可用的衍生对象
+type Thumbnail struct {
+	Data     []byte
+	MimeType string
+	Size     int  // 缩略图最长边像素，文本预览为0
+	Pending  bool // 还未生成
+}
+
+// GetThumbnail 读取文件当前版本的缩略图，取最长边不小于 size 的最小一张，都小于 size 时取最大的一张
+// 没有缩略图的文本文件返回文本预览
+func (s *StorageService) GetThumbnail(ctx context.Context, fileID int64, size int) (*Thumbnail, error) {
+	if _, err := s.GetFileInfo(ctx, fileID); err != nil {
+		return nil, err
+	}
+	version, err := s.resolveVersion(fileID, 0)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := s.fileDAO.GetBlobByID(version.BlobID)
+	if err != nil {
+		return nil, fmt.Errorf("获取 Blob 失败: %v", err)
+	}
+	if blob.DeriveStatus == model.DerivePending {
+		return &Thumbnail{Pending: true}, nil
+	}
+	derivatives, err := s.fileDAO.ListDerivatives(blob.ID)
+	if err != nil {
+		return nil, fmt.Errorf("查询缩略图失败: %v", err)
+	}
+
+	var chosen *model.Derivative
+	for i := range derivatives {
+		d := &derivatives[i]
+		switch d.Kind {
+		case model.DerivativeThumbnail:
+			if chosen == nil || chosen.Kind != model.DerivativeThumbnail || chosen.Size < size {
+				chosen = d
+			}
+		case model.DerivativePreview:
+			if chosen == nil {
+				chosen = d
+			}
+		}
+	}
+	if chosen == nil {
+		return &Thumbnail{}, nil
+	}
+
+	object, err := s.openObject(ctx, chosen.ObjectName, chosen.Encryption, encryption.Layout{}, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("读取缩略图失败: %v", err)
+	}
+	defer object.Close()
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, fmt.Errorf("读取缩略图失败: %v", err)
+	}
+	return &Thumbnail{Data: data, MimeType: chosen.MimeType, Size: chosen.Size}, nil
+}
+
+// enqueueDerivation 把新的 Blob 排入生成队列，队列已满时留给定期扫描处理
+func (s *StorageService) enqueueDerivation(blobID int64) {
+	select {
+	case s.derivations <- blobID:
+	default:
+	}
+}
+
+// openBlob 读取 Blob 从 offset 开始的 length 字节，length 为0时读到末尾
+func (s *StorageService) openBlob(ctx context.Context, blob *model.Blob, offset, length int64) (io.ReadCloser, error) {
+	return s.openVersion(ctx, &model.FileVersion{
+		ObjectName:  blob.ObjectName,
+		BlobID:      blob.ID,
+		Size:        blob.Size,
+		Encryption:  blob.Encryption,
+		Compression: blob.Compression,
+		Chunked:     blob.Chunked,
+	}, offset, length)
+}
+
+// readBlob 读取 Blob 开头最多 limit 字节
+func (s *StorageService) readBlob(ctx context.Context, blob *model.Blob, limit int64) ([]byte, error) {
+	object, err := s.openBlob(ctx, blob, 0, min(limit, blob.Size))
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+	return io.ReadAll(object)
+}
+
+// deriveBlob 为 Blob 生成缩略图或文本预览并登记，内容不支持时只标记为无需生成
+// 写对象失败时返回错误，Blob 保持待生成状态，等下次扫描重试
+func (s *StorageService) deriveBlob(ctx context.Context, blob *model.Blob) error {
+	if blob.Size == 0 {
+		_, err := s.fileDAO.SaveDerivatives(blob.ID, nil, model.DeriveSkipped)
+		return err
+	}
+	head, err := s.readBlob(ctx, blob, int64(max(s.previews.textBytes, 512)))
+	if err != nil {
+		return fmt.Errorf("读取内容失败: %v", err)
+	}
+
+	var derivatives []model.Derivative
+	put := func(kind string, size int, mimeType string, data []byte) error {
+		d, err := s.putDerivative(ctx, blob, kind, size, mimeType, data)
+		if err != nil {
+			return err
+		}
+		derivatives = append(derivatives, *d)
+		return nil
+	}
+	switch {
+	case preview.IsImage(head):
+		if blob.Size > s.previews.maxImage {
+			break
+		}
+		data, err := s.readBlob(ctx, blob, blob.Size)
+		if err != nil {
+			return fmt.Errorf("读取内容失败: %v", err)
+		}
+		img, err := preview.DecodeImage(data)
+		if err != nil {
+			utils.Warn("[Derive] Blob=%d 图片无法解码: %v", blob.ID, err)
+			break
+		}
+		longest := max(img.Bounds().Dx(), img.Bounds().Dy())
+		for _, size := range s.previews.sizes {
+			encoded, mimeType, err := preview.Encode(preview.Thumbnail(img, size))
+			if err == nil {
+				err = put(model.DerivativeThumbnail, min(size, longest), mimeType, encoded)
+			}
+			if err != nil {
+				s.deleteDerivativeObjects(ctx, derivatives)
+				return err
+			}
+			// 原图不大于该尺寸时更大的尺寸都与它相同
+			if size >= longest {
+				break
+			}
+		}
+	case preview.IsText(head):
+		if err := put(model.DerivativePreview, 0, preview.TextMimeType, preview.Text(head, s.previews.textBytes)); err != nil {
+			return err
+		}
+	}
+
+	status := model.DeriveSkipped
+	if len(derivatives) > 0 {
+		status = model.DeriveDone
+	}
+	saved, err := s.fileDAO.SaveDerivatives(blob.ID, derivatives, status)
+	if err != nil || !saved {
+		// Blob 在生成期间被删除或已由其他协程处理
+		s.deleteDerivativeObjects(ctx, derivatives)
+		if err != nil {
+			return fmt.Errorf("登记衍生对象失败: %v", err)
+		}
+		return nil
+	}
+	deriveMetrics.Add("generated", int64(len(derivatives)))
+	if status == model.DeriveSkipped {
+		deriveMetrics.Add("skipped", 1)
+	}
+	return nil
+}
+
+// putDerivative 加密并写入一个衍生对象，返回待登记的记录
+func (s *StorageService) putDerivative(ctx context.Context, blob *model.Blob, kind string, size int, mimeType string, data []byte) (*model.Derivative, error) {
+	enc, err := s.derivedEncryption(blob.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := s.encryptStream(enc, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	d := &model.Derivative{
+		BlobID:     blob.ID,
+		Kind:       kind,
+		Size:       size,
+		MimeType:   mimeType,
+		Bucket:     s.bucket,
+		ObjectName: newObjectKey(),
+		Bytes:      int64(len(data)),
+		CreatedAt:  time.Now(),
+		Encryption: enc,
+	}
+	if _, err := s.store.Put(ctx, d.ObjectName, reader, d.Bytes); err != nil {
+		return nil, fmt.Errorf("写入%s失败: %v", kind, err)
+	}
+	return d, nil
+}
+
+// deleteDerivatives 删除 Blob 的衍生对象，失败只记录日志
+func (s *StorageService) deleteDerivatives(ctx context.Context, blobID int64) {
+	derivatives, err := s.fileDAO.DeleteDerivatives(blobID)
+	if err != nil {
+		utils.Error("[Derive] 删除 Blob=%d 的衍生对象记录失败: %v", blobID, err)
+		return
+	}
+	s.deleteDerivativeObjects(ctx, derivatives)
+}
+
+// deleteDerivativeObjects 删除衍生对象的存储对象，失败只记录日志
+func (s *StorageService) deleteDerivativeObjects(ctx context.Context, derivatives []model.Derivative) {
+	for _, d := range derivatives {
+		if err := s.store.Delete(ctx, d.ObjectName); err != nil {
+			utils.Error("[Derive] 删除衍生对象 %s 失败: %v", d.ObjectName, err)
+		}
+	}
+}
+
+// DerivePending 为所有待生成的 Blob 生成缩略图和预览，返回处理的 Blob 数
+func (s *StorageService) DerivePending(ctx context.Context) (int, error) {
+	processed := 0
+	for afterID := int64(0); ; {
+		blobs, err := s.fileDAO.ListPendingDerivations(afterID, 100)
+		if err != nil {
+			return processed, fmt.Errorf("查询待生成的 Blob 失败: %v", err)
+		}
+		if len(blobs) == 0 {
+			return processed, nil
+		}
+		for i := range blobs {
+			afterID = blobs[i].ID
+			if ctx.Err() != nil {
+				return processed, ctx.Err()
+			}
+			if err := s.deriveBlob(ctx, &blobs[i]); err != nil {
+				deriveMetrics.Add("errors", 1)
+				utils.Error("[Derive] Blob=%d 生成缩略图失败: %v", blobs[i].ID, err)
+				continue
+			}
+			processed++
+		}
+	}
+}
+
+// StartDerivationWorker 启动后台协程，为上传完成的新内容生成缩略图和文本预览
+// 另外每隔 interval 扫描一次遗漏的 Blob，包括服务重启前未处理完的和功能上线前已有的
+func (s *StorageService) StartDerivationWorker(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case blobID := <-s.derivations:
+				blob, err := s.fileDAO.GetBlobByID(blobID)
+				if err != nil || blob.DeriveStatus != model.DerivePending {
+					continue
+				}
+				if err := s.deriveBlob(ctx, blob); err != nil {
+					deriveMetrics.Add("errors", 1)
+					utils.Error("[Derive] Blob=%d 生成缩略图失败: %v", blobID, err)
+				}
+			case <-ticker.C:
+				processed, err := s.DerivePending(ctx)
+				if err != nil {
+					utils.Error("[Derive] 扫描待生成的 Blob 失败: %v", err)
+				}
+				if processed > 0 {
+					utils.Info("[Derive] 已处理 %d 个 Blob", processed)
+				}
+			}
+		}
+	}()
+	utils.Info("[Derive] 已启动, 缩略图尺寸=%v, 扫描间隔=%s", s.previews.sizes, interval)
+}
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/encryption"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// keyring 主密钥以及解开后的用户密钥缓存
+type keyring struct {
+	master   []byte
+	mu       sync.Mutex
+	userKeys map[int64][]byte // 用户密钥ID -> 明文密钥
+}
+
+// SetMasterKey 设置主密钥并开启加密，之后上传的对象都会加密，已有的明文对象仍可正常读取
+func (s *StorageService) SetMasterKey(master []byte) error {
+	if len(master) != encryption.KeySize {
+		return encryption.ErrInvalidKey
+	}
+	s.keys = &keyring{master: master, userKeys: make(map[int64][]byte)}
+	return nil
+}
+
+// EncryptionEnabled 是否配置了主密钥
+func (s *StorageService) EncryptionEnabled() bool {
+	return s.keys != nil
+}
+
+// unwrapUserKey 解开用户密钥，结果按密钥ID缓存
+func (s *StorageService) unwrapUserKey(key *model.UserKey) ([]byte, error) {
+	s.keys.mu.Lock()
+	defer s.keys.mu.Unlock()
+	if plain, ok := s.keys.userKeys[key.ID]; ok {
+		return plain, nil
+	}
+	plain, err := encryption.Unwrap(s.keys.master, key.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("解开用户密钥 %d 失败: %v", key.ID, err)
+	}
+	s.keys.userKeys[key.ID] = plain
+	return plain, nil
+}
+
+// activeUserKey 获取用户启用的密钥，没有时为其生成一个
+func (s *StorageService) activeUserKey(userID int64) (*model.UserKey, []byte, error) {
+	key, err := s.fileDAO.GetActiveUserKey(userID)
+	if err != nil {
+		if key, err = s.createUserKey(userID); err != nil {
+			// 并发为同一用户生成密钥时版本号冲突，改用对方生成的密钥
+			if key, err = s.fileDAO.GetActiveUserKey(userID); err != nil {
+				return nil, nil, fmt.Errorf("获取用户密钥失败: %v", err)
+			}
+		}
+	}
+	plain, err := s.unwrapUserKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, plain, nil
+}
+
+// createUserKey 为用户生成新的启用密钥，原有密钥停用
+func (s *StorageService) createUserKey(userID int64) (*model.UserKey, error) {
+	plain, err := encryption.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := encryption.Wrap(s.keys.master, plain)
+	if err != nil {
+		return nil, err
+	}
+	key := &model.UserKey{UserID: userID, WrappedKey: wrapped}
+	if err := s.fileDAO.CreateUserKey(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// newEncryption 为新对象生成数据密钥并用用户密钥包装，未开启加密时返回零值
+func (s *StorageService) newEncryption(userID int64) (model.Encryption, error) {
+	if s.keys == nil {
+		return model.Encryption{}, nil
+	}
+	key, kek, err := s.activeUserKey(userID)
+	if err != nil {
+		return model.Encryption{}, err
+	}
+	return newDataKey(key, kek)
+}
+
+// newDataKey 生成数据密钥并用用户密钥包装
+func newDataKey(key *model.UserKey, kek []byte) (model.Encryption, error) {
+	dataKey, err := encryption.GenerateKey()
+	if err != nil {
+		return model.Encryption{}, err
+	}
+	wrapped, err := encryption.Wrap(kek, dataKey)
+	if err != nil {
+		return model.Encryption{}, err
+	}
+	return model.Encryption{KeyID: key.ID, DataKey: wrapped}, nil
+}
+
+// derivedEncryption 为衍生对象生成数据密钥，用源对象的用户密钥包装，源对象未加密时返回零值
+func (s *StorageService) derivedEncryption(src model.Encryption) (model.Encryption, error) {
+	if src.KeyID == 0 || s.keys == nil {
+		return model.Encryption{}, nil
+	}
+	key, err := s.fileDAO.GetUserKey(src.KeyID)
+	if err != nil {
+		return model.Encryption{}, fmt.Errorf("找不到用户密钥 %d: %v", src.KeyID, err)
+	}
+	kek, err := s.unwrapUserKey(key)
+	if err != nil {
+		return model.Encryption{}, err
+	}
+	return newDataKey(key, kek)
+}
+
+// dataKey 解开对象的数据密钥，对象未加密时返回 nil
+func (s *StorageService) dataKey(enc model.Encryption) ([]byte, error) {
+	if enc.KeyID == 0 {
+		return nil, nil
+	}
+	if s.keys == nil {
+		return nil, fmt.Errorf("对象已加密但未配置主密钥")
+	}
+	key, err := s.fileDAO.GetUserKey(enc.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("找不到用户密钥 %d: %v", enc.KeyID, err)
+	}
+	kek, err := s.unwrapUserKey(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := encryption.Unwrap(kek, enc.DataKey)
+	if err != nil {
+		return nil, fmt.Errorf("解开数据密钥失败: %v", err)
+	}
+	return plain, nil
+}
+
+// encryptPart 返回加密分片 partNumber 内容的 Reader，对象未加密时原样返回
+func (s *StorageService) encryptPart(enc model.Encryption, partNumber int, r io.Reader) (io.Reader, error) {
+	key, err := s.dataKey(enc)
+	if err != nil || key == nil {
+		return r, err
+	}
+	return encryption.NewPartReader(r, key, partNumber)
+}
+
+// encryptStream 返回把 r 作为整体连续加密的 Reader，对象未加密时原样返回
+func (s *StorageService) encryptStream(enc model.Encryption, r io.Reader) (io.Reader, error) {
+	key, err := s.dataKey(enc)
+	if err != nil || key == nil {
+		return r, err
+	}
+	return encryption.NewReader(r, key, encryption.Layout{}, 0)
+}
+
+// decryptingReader 在读取时解密对象的 ReadCloser
+type decryptingReader struct {
+	io.Reader
+	io.Closer
+}
+
+// openObject 读取对象从 offset 开始的 length 字节（length 为0时读到末尾）并按 layout 解密
+func (s *StorageService) openObject(ctx context.Context, objectName string, enc model.Encryption, layout encryption.Layout, offset, length int64) (io.ReadCloser, error) {
+	key, err := s.dataKey(enc)
+	if err != nil {
+		return nil, err
+	}
+	object, err := s.store.Get(ctx, objectName, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return object, nil
+	}
+	reader, err := encryption.NewReader(object, key, layout, offset)
+	if err != nil {
+		object.Close()
+		return nil, err
+	}
+	return decryptingReader{Reader: reader, Closer: object}, nil
+}
+
+// openVersion 读取已完成版本从 offset 开始的 length 字节，length 为0时读到末尾
+// 加密、压缩和分块存储的内容透明还原
+func (s *StorageService) openVersion(ctx context.Context, version *model.FileVersion, offset, length int64) (io.ReadCloser, error) {
+	if version.Chunked {
+		return s.openChunkedVersion(ctx, version, offset, length)
+	}
+	if version.Compression != "" {
+		return s.openCompressed(ctx, version.ObjectName, version.Encryption, version.Compression, offset, length)
+	}
+	layout := encryption.Layout{PartSize: version.Encryption.PartSize}
+	return s.openObject(ctx, version.ObjectName, version.Encryption, layout, offset, length)
+}
+
+// sealUploadedObject 计算合并后的版本对象明文的 SHA-256、MD5 和大小，加密的对象同时确定其分片布局
+// 加密分片的编号不连续或大小不一致时无法按固定分片大小定位，改为整体连续加密写入新对象
+func (s *StorageService) sealUploadedObject(ctx context.Context, version *model.FileVersion, parts []model.FilePart) (string, string, int64, error) {
+	switch {
+	case version.Compression != "":
+		// 合并时已整体连续加密
+	case version.Encryption.KeyID == 0:
+	case uniformParts(parts):
+		version.Encryption.PartSize = parts[0].Size
+	default:
+		return s.reencryptObject(ctx, version, parts)
+	}
+	object, err := s.openVersion(ctx, version, 0, 0)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("读取对象失败: %v", err)
+	}
+	defer object.Close()
+	return hashReader(object)
+}
+
+// reencryptObject 按分片解密合并后的对象，整体连续加密写入新对象，同时计算明文的校验值
+func (s *StorageService) reencryptObject(ctx context.Context, version *model.FileVersion, parts []model.FilePart) (string, string, int64, error) {
+
+	layout := encryption.Layout{Parts: make([]encryption.Part, 0, len(parts))}
+	var total int64
+	for _, p := range parts {
+		layout.Parts = append(layout.Parts, encryption.Part{Number: p.PartNumber, Size: p.Size})
+		total += p.Size
+	}
+	object, err := s.openObject(ctx, version.ObjectName, version.Encryption, layout, 0, 0)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("读取对象失败: %v", err)
+	}
+	defer object.Close()
+
+	shaHash := sha256.New()
+	md5Hash := md5.New()
+	enc := version.Encryption
+	enc.PartSize = 0
+	sealed, err := s.encryptStream(enc, io.TeeReader(object, io.MultiWriter(shaHash, md5Hash)))
+	if err != nil {
+		return "", "", 0, err
+	}
+	objectName := newObjectKey()
+	if _, err := s.store.Put(ctx, objectName, sealed, total); err != nil {
+		return "", "", 0, fmt.Errorf("重新加密对象失败: %v", err)
+	}
+	if err := s.store.Delete(ctx, version.ObjectName); err != nil {
+		utils.Error("[Encryption] 删除对象 %s 失败: %v", version.ObjectName, err)
+	}
+	version.ObjectName = objectName
+	version.Encryption = enc
+	return hex.EncodeToString(shaHash.Sum(nil)), hex.EncodeToString(md5Hash.Sum(nil)), total, nil
+}
+
+// uniformParts 分片编号从1开始连续，且除最后一个外大小相同
+func uniformParts(parts []model.FilePart) bool {
+	for i, p := range parts {
+		if p.PartNumber != i+1 {
+			return false
+		}
+		if i < len(parts)-1 && p.Size != parts[0].Size {
+			return false
+		}
+	}
+	return len(parts) == 1 || parts[0].Size > 0
+}
+
+// KeyRotationResult 一次密钥轮换的结果
+type KeyRotationResult struct {
+	Rewrapped   int // 重新包装的数据密钥数
+	RetiredKeys int // 删除的旧用户密钥数
+}
+
+// RotateUserKey 为用户生成新密钥，并把旧密钥包装的数据密钥全部改由新密钥包装，对象内容不需要重写
+// 旧密钥在不再被引用后删除；中断后重新执行会继续处理剩余的旧密钥
+func (s *StorageService) RotateUserKey(ctx context.Context, userID int64) (KeyRotationResult, error) {
+	var result KeyRotationResult
+	if s.keys == nil {
+		return result, fmt.Errorf("未配置主密钥")
+	}
+	if _, err := s.fileDAO.GetActiveUserKey(userID); err != nil {
+		return result, nil
+	}
+	newKey, err := s.createUserKey(userID)
+	if err != nil {
+		return result, fmt.Errorf("生成用户密钥失败: %v", err)
+	}
+	newKEK, err := s.unwrapUserKey(newKey)
+	if err != nil {
+		return result, err
+	}
+
+	retired, err := s.fileDAO.ListRetiredUserKeys(userID)
+	if err != nil {
+		return result, fmt.Errorf("查询旧密钥失败: %v", err)
+	}
+	for i := range retired {
+		oldKey := &retired[i]
+		oldKEK, err := s.unwrapUserKey(oldKey)
+		if err != nil {
+			return result, err
+		}
+		rewrap := func(dataKey string) (string, error) {
+			plain, err := encryption.Unwrap(oldKEK, dataKey)
+			if err != nil {
+				return "", err
+			}
+			return encryption.Wrap(newKEK, plain)
+		}
+
+		for afterID := int64(0); ; {
+			blobs, err := s.fileDAO.ListBlobsByKey(oldKey.ID, afterID, 100)
+			if err != nil {
+				return result, fmt.Errorf("查询 Blob 失败: %v", err)
+			}
+			if len(blobs) == 0 {
+				break
+			}
+			for _, blob := range blobs {
+				afterID = blob.ID
+				wrapped, err := rewrap(blob.Encryption.DataKey)
+				if err != nil {
+					return result, fmt.Errorf("重新包装 Blob %d 的数据密钥失败: %v", blob.ID, err)
+				}
+				if err := s.fileDAO.RewrapBlobKey(blob.ID, oldKey.ID, newKey.ID, wrapped); err != nil {
+					return result, fmt.Errorf("更新 Blob %d 失败: %v", blob.ID, err)
+				}
+				result.Rewrapped++
+			}
+		}
+		for afterID := int64(0); ; {
+			versions, err := s.fileDAO.ListVersionsByKey(oldKey.ID, afterID, 100)
+			if err != nil {
+				return result, fmt.Errorf("查询版本失败: %v", err)
+			}
+			if len(versions) == 0 {
+				break
+			}
+			for _, v := range versions {
+				afterID = v.ID
+				wrapped, err := rewrap(v.Encryption.DataKey)
+				if err != nil {
+					return result, fmt.Errorf("重新包装版本 %d 的数据密钥失败: %v", v.ID, err)
+				}
+				if err := s.fileDAO.RewrapVersionKey(v.ID, oldKey.ID, newKey.ID, wrapped); err != nil {
+					return result, fmt.Errorf("更新版本 %d 失败: %v", v.ID, err)
+				}
+				result.Rewrapped++
+			}
+		}
+		for afterID := int64(0); ; {
+			chunks, err := s.fileDAO.ListChunksByKey(oldKey.ID, afterID, 100)
+			if err != nil {
+				return result, fmt.Errorf("查询分块失败: %v", err)
+			}
+			if len(chunks) == 0 {
+				break
+			}
+			for _, c := range chunks {
+				afterID = c.ID
+				wrapped, err := rewrap(c.Encryption.DataKey)
+				if err != nil {
+					return result, fmt.Errorf("重新包装分块 %d 的数据密钥失败: %v", c.ID, err)
+				}
+				if err := s.fileDAO.RewrapChunkKey(c.ID, oldKey.ID, newKey.ID, wrapped); err != nil {
+					return result, fmt.Errorf("更新分块 %d 失败: %v", c.ID, err)
+				}
+				result.Rewrapped++
+			}
+		}
+		for afterID := int64(0); ; {
+			derivatives, err := s.fileDAO.ListDerivativesByKey(oldKey.ID, afterID, 100)
+			if err != nil {
+				return result, fmt.Errorf("查询衍生对象失败: %v", err)
+			}
+			if len(derivatives) == 0 {
+				break
+			}
+			for _, d := range derivatives {
+				afterID = d.ID
+				wrapped, err := rewrap(d.Encryption.DataKey)
+				if err != nil {
+					return result, fmt.Errorf("重新包装衍生对象 %d 的数据密钥失败: %v", d.ID, err)
+				}
+				if err := s.fileDAO.RewrapDerivativeKey(d.ID, oldKey.ID, newKey.ID, wrapped); err != nil {
+					return result, fmt.Errorf("更新衍生对象 %d 失败: %v", d.ID, err)
+				}
+				result.Rewrapped++
+			}
+		}
+
+		deleted, err := s.fileDAO.DeleteUserKey(oldKey.ID)
+		if err != nil {
+			return result, fmt.Errorf("删除旧密钥 %d 失败: %v", oldKey.ID, err)
+		}
+		if !deleted {
+			// 轮换期间仍有上传在使用旧密钥，下次轮换时再处理
+			utils.Info("[KeyRotation] 用户=%d 旧密钥 %d 仍被引用，暂不删除", userID, oldKey.ID)
+			continue
+		}
+		s.keys.mu.Lock()
+		delete(s.keys.userKeys, oldKey.ID)
+		s.keys.mu.Unlock()
+		result.RetiredKeys++
+	}
+	return result, nil
+}
+
+// RotateAllUserKeys 依次轮换所有已有密钥的用户
+func (s *StorageService) RotateAllUserKeys(ctx context.Context) (KeyRotationResult, error) {
+	var total KeyRotationResult
+	userIDs, err := s.fileDAO.ListKeyOwners()
+	if err != nil {
+		return total, fmt.Errorf("查询用户失败: %v", err)
+	}
+	for _, userID := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		result, err := s.RotateUserKey(ctx, userID)
+		total.Rewrapped += result.Rewrapped
+		total.RetiredKeys += result.RetiredKeys
+		if err != nil {
+			return total, fmt.Errorf("轮换用户 %d 的密钥失败: %v", userID, err)
+		}
+		utils.Info("[KeyRotation] 用户=%d 重新包装 %d 个数据密钥, 删除旧密钥 %d 个", userID, result.Rewrapped, result.RetiredKeys)
+	}
+	return total, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bytes"
+	"cloud-storage-file-service/internal/blobstore"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+// storedObject 读出文件当前版本在存储后端中的原始对象
+func storedObject(t *testing.T, s *StorageService, store *blobstore.MemoryStore, fileID int64) []byte {
+	t.Helper()
+	file, err := s.GetFileInfo(context.Background(), fileID)
+	if err != nil {
+		t.Fatalf("GetFileInfo() error = %v", err)
+	}
+	version, err := s.fileDAO.GetVersionByID(file.CurrentVersionID)
+	if err != nil {
+		t.Fatalf("GetVersionByID() error = %v", err)
+	}
+	r, err := store.Get(context.Background(), version.ObjectName, 0, 0)
+	if err != nil {
+		t.Fatalf("Get(%s) error = %v", version.ObjectName, err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read object error = %v", err)
+	}
+	return data
+}
+
+// readRange 读出文件当前版本从 offset 开始的 length 字节
+func readRange(t *testing.T, s *StorageService, fileID, offset, length int64) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := s.ReadFile(context.Background(), fileID, 0, offset, length, &buf); err != nil {
+		t.Fatalf("ReadFile(%d, %d) error = %v", offset, length, err)
+	}
+	return buf.Bytes()
+}
+
+func TestStorageService_EncryptionRoundTrip(t *testing.T) {
+	s, _, store := newTestService(t)
+	if err := s.SetMasterKey(bytes.Repeat([]byte{7}, 32)); err != nil {
+		t.Fatalf("SetMasterKey() error = %v", err)
+	}
+	data := randomData(3, testPartSize+4321)
+
+	file := upload(t, s, 1, 0, "secret.bin", data, "")
+	if bytes.Contains(storedObject(t, s, store, file.ID), data[:64]) {
+		t.Error("stored object contains plaintext")
+	}
+	if got := download(t, s, file.ID, 0); !bytes.Equal(got, data) {
+		t.Fatalf("downloaded %d bytes, want %d", len(got), len(data))
+	}
+	offset, length := int64(testPartSize-100), int64(300)
+	if got := readRange(t, s, file.ID, offset, length); !bytes.Equal(got, data[offset:offset+length]) {
+		t.Error("range across parts mismatch")
+	}
+	if _, _, err := s.GeneratePresignedURL(context.Background(), file.ID, 0, 60); err == nil {
+		t.Error("GeneratePresignedURL() of encrypted file should fail")
+	}
+}
+
+func TestStorageService_CompressionRoundTrip(t *testing.T) {
+	for _, codec := range []string{"zstd", "gzip"} {
+		t.Run(codec, func(t *testing.T) {
+			s, usage, store := newTestService(t)
+			data := []byte(strings.Repeat("compressible line of text\n", testPartSize/13))
+
+			file := upload(t, s, 1, 0, "log.txt", data, codec)
+			if file.Compression != codec {
+				t.Errorf("compression = %q, want %q", file.Compression, codec)
+			}
+			if stored := storedObject(t, s, store, file.ID); len(stored) >= len(data)/4 {
+				t.Errorf("stored %d bytes for %d bytes of text", len(stored), len(data))
+			}
+			if got := download(t, s, file.ID, 0); !bytes.Equal(got, data) {
+				t.Fatalf("downloaded %d bytes, want %d", len(got), len(data))
+			}
+			offset, length := int64(testPartSize-7), int64(1000)
+			if got := readRange(t, s, file.ID, offset, length); !bytes.Equal(got, data[offset:offset+length]) {
+				t.Error("range across parts mismatch")
+			}
+			// 已用空间按原始大小计算
+			if got := usage.get(1); got != int64(len(data)) {
+				t.Errorf("used = %d, want %d", got, len(data))
+			}
+		})
+	}
+}
+
+func TestStorageService_EncryptedCompressedRoundTrip(t *testing.T) {
+	s, _, _ := newTestService(t)
+	if err := s.SetMasterKey(bytes.Repeat([]byte{9}, 32)); err != nil {
+		t.Fatalf("SetMasterKey() error = %v", err)
+	}
+	data := []byte(strings.Repeat("both encrypted and compressed\n", 1000))
+
+	file := upload(t, s, 1, 0, "both.txt", data, "zstd")
+	if got := download(t, s, file.ID, 0); !bytes.Equal(got, data) {
+		t.Fatalf("downloaded %d bytes, want %d", len(got), len(data))
+	}
+	if got := readRange(t, s, file.ID, 1234, 567); !bytes.Equal(got, data[1234:1234+567]) {
+		t.Error("range mismatch")
+	}
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/archive"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+)
+
+// extractLimits 服务端解压的限制
+type extractLimits struct {
+	maxEntries  int   // 条目数，包括目录
+	maxExpanded int64 // 解压后的总大小
+	maxDepth    int   // 条目路径的层数
+}
+
+// defaultExtractLimits 默认最多10000个条目、解压后10GB、16层目录
+func defaultExtractLimits() extractLimits {
+	return extractLimits{
+		maxEntries:  10000,
+		maxExpanded: 10 << 30,
+		maxDepth:    16,
+	}
+}
+
+// SetExtractLimits 设置服务端解压的条目数、解压后总大小和目录层数上限，不大于0的参数保持默认值
+func (s *StorageService) SetExtractLimits(maxEntries int, maxExpanded int64, maxDepth int) {
+	if maxEntries > 0 {
+		s.extract.maxEntries = maxEntries
+	}
+	if maxExpanded > 0 {
+		s.extract.maxExpanded = maxExpanded
+	}
+	if maxDepth > 0 {
+		s.extract.maxDepth = maxDepth
+	}
+}
+
+// ExtractResult 解压结果
+type ExtractResult struct {
+	Files   []model.File // 新建的文件
+	Folders int          // 新建的文件夹数
+	Skipped []string     // 跳过的符号链接等特殊条目和名称不合法的条目
+}
+
+// ExtractArchive 把用户的 ZIP、tar 或 tar.gz 文件解压到 folderID 下，每个普通文件新建一个文件，目录结构保留为文件夹
+// 先完整检查一遍条目：路径越界、条目数、解压后总大小或目录层数超过限制时不解压任何内容；
+// 然后按解压后的总大小一次性预留用户空间，再逐个写入。内容与已有 Blob 相同的条目直接引用已有 Blob
+// 与已有文件或文件夹重名的条目追加 " (n)" 后缀，同名文件夹直接合并。中途失败时已解压的文件保留，未用完的空间返还
+func (s *StorageService) ExtractArchive(ctx context.Context, userID, fileID, folderID int64) (*ExtractResult, error) {
+	file, err := s.ownedFile(userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file.TrashedAt != nil {
+		return nil, fmt.Errorf("文件已在回收站中")
+	}
+	version, err := s.resolveVersion(file.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.checkFolder(userID, folderID); err != nil {
+		return nil, err
+	}
+
+	src := &versionSource{ctx: ctx, s: s, version: version}
+	defer src.Close()
+	head := make([]byte, min(version.Size, archive.HeadLen))
+	if _, err := src.ReadAt(head, 0); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	format := archive.Detect(head)
+	if format == "" {
+		return nil, fmt.Errorf("不支持的压缩包格式，仅支持 zip、tar 和 tar.gz")
+	}
+	limits := archive.Limits{
+		MaxEntries:  s.extract.maxEntries,
+		MaxExpanded: s.extract.maxExpanded,
+		MaxDepth:    s.extract.maxDepth,
+	}
+
+	var total int64
+	err = archive.Walk(format, src, limits, func(e archive.Entry, _ io.Reader) error {
+		total += e.Size
+		return nil
+	})
+	if err != nil {
+		return nil, extractError(err, limits)
+	}
+
+	reserved, err := s.reserveSpace(ctx, userID, total)
+	if err != nil {
+		return nil, err
+	}
+	x := &extraction{s: s, userID: userID, folders: map[string]int64{"": folderID}, result: &ExtractResult{}}
+	err = archive.Walk(format, src, limits, func(e archive.Entry, r io.Reader) error {
+		return x.entry(ctx, e, r)
+	})
+	if x.used < reserved {
+		s.reportUsage(ctx, userID, x.used-reserved)
+	}
+	if err != nil {
+		return x.result, extractError(err, limits)
+	}
+	utils.Info("[Extract] 用户=%d 文件=%d 解压完成: %d 个文件, %d 个文件夹, 跳过 %d 个条目",
+		userID, fileID, len(x.result.Files), x.result.Folders, len(x.result.Skipped))
+	return x.result, nil
+}
+
+// extractError 把压缩包读取错误转换为面向用户的说明
+func extractError(err error, limits archive.Limits) error {
+	switch {
+	case errors.Is(err, archive.ErrUnsafePath):
+		return fmt.Errorf("压缩包包含不安全的路径: %v", err)
+	case errors.Is(err, archive.ErrTooMany):
+		return fmt.Errorf("压缩包条目数超过上限 %d", limits.MaxEntries)
+	case errors.Is(err, archive.ErrTooLarge):
+		return fmt.Errorf("压缩包解压后大小超过上限 %d 字节", limits.MaxExpanded)
+	case errors.Is(err, archive.ErrTooDeep):
+		return fmt.Errorf("压缩包目录层数超过上限 %d", limits.MaxDepth)
+	case errors.Is(err, archive.ErrUnsupported):
+		return fmt.Errorf("无法解析压缩包: %v", err)
+	}
+	return fmt.Errorf("解压失败: %v", err)
+}
+
+// extraction 一次解压的状态
+type extraction struct {
+	s       *StorageService
+	userID  int64
+	folders map[string]int64 // 压缩包中的目录路径 -> 文件夹ID，根目录为目标文件夹
+	used    int64            // 已写入文件的大小
+	result  *ExtractResult
+}
+
+// entry 处理一个条目
+func (x *extraction) entry(ctx context.Context, e archive.Entry, r io.Reader) error {
+	name := path.Base(e.Path)
+	if e.Type == archive.TypeOther || validateName(name) != nil {
+		x.result.Skipped = append(x.result.Skipped, e.Path)
+		return nil
+	}
+	if e.Type == archive.TypeDir {
+		_, err := x.folder(e.Path)
+		return err
+	}
+	parentID, err := x.folder(archive.Dir(e.Path))
+	if err != nil {
+		return err
+	}
+	name, err = x.s.availableName(x.userID, parentID, name)
+	if err != nil {
+		return err
+	}
+	file, err := x.s.extractFile(ctx, x.userID, parentID, name, e, r)
+	if err != nil {
+		return fmt.Errorf("%s: %v", e.Path, err)
+	}
+	x.used += file.Size
+	x.result.Files = append(x.result.Files, *file)
+	return nil
+}
+
+// folder 返回压缩包中目录对应的文件夹ID，不存在时逐级创建，已有同名文件夹时直接使用
+func (x *extraction) folder(dir string) (int64, error) {
+	if id, ok := x.folders[dir]; ok {
+		return id, nil
+	}
+	parentID, err := x.folder(archive.Dir(dir))
+	if err != nil {
+		return 0, err
+	}
+	name := path.Base(dir)
+	if err := validateName(name); err != nil {
+		return 0, err
+	}
+	subFolders, err := x.s.fileDAO.ListSubFolders(x.userID, parentID)
+	if err != nil {
+		return 0, fmt.Errorf("查询文件夹失败: %v", err)
+	}
+	for _, folder := range subFolders {
+		if folder.Name == name {
+			x.folders[dir] = folder.ID
+			return folder.ID, nil
+		}
+	}
+	// 与文件重名时改名
+	if name, err = x.s.availableName(x.userID, parentID, name); err != nil {
+		return 0, err
+	}
+	folder := &model.Folder{
+		UserID:    x.userID,
+		ParentID:  parentID,
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := x.s.fileDAO.CreateFolder(folder); err != nil {
+		return 0, fmt.Errorf("创建文件夹失败: %v", err)
+	}
+	x.folders[dir] = folder.ID
+	x.result.Folders++
+	return folder.ID, nil
+}
+
+// extractFile 把一个条目的内容写入新对象并新建文件，内容与已有 Blob 相同时引用已有 Blob 并删除新对象
+// 空间已由调用方预留
+func (s *StorageService) extractFile(ctx context.Context, userID, folderID int64, name string, e archive.Entry, r io.Reader) (*model.File, error) {
+	enc, err := s.newEncryption(userID)
+	if err != nil {
+		return nil, err
+	}
+	shaHash := sha256.New()
+	md5Hash := md5.New()
+	var read byteCounter
+	head := newHeadReader(io.TeeReader(io.LimitReader(r, e.Size), io.MultiWriter(shaHash, md5Hash, &read)))
+	reader, err := s.encryptStream(enc, head)
+	if err != nil {
+		return nil, err
+	}
+	objectName := newObjectKey()
+	if _, err := s.store.Put(ctx, objectName, reader, e.Size); err != nil {
+		s.store.Delete(ctx, objectName)
+		return nil, fmt.Errorf("写入对象失败: %v", err)
+	}
+	if int64(read) != e.Size {
+		s.store.Delete(ctx, objectName)
+		return nil, fmt.Errorf("条目内容不完整: 应为 %d 字节，实际 %d 字节", e.Size, read)
+	}
+	// 读到条目末尾，ZIP 条目在此时校验 CRC-32
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		s.store.Delete(ctx, objectName)
+		return nil, fmt.Errorf("读取条目失败: %v", err)
+	}
+
+	blob, reused, err := s.fileDAO.AcquireBlob(&model.Blob{
+		Sha256:     hex.EncodeToString(shaHash.Sum(nil)),
+		Md5:        hex.EncodeToString(md5Hash.Sum(nil)),
+		Size:       e.Size,
+		Bucket:     s.bucket,
+		ObjectName: objectName,
+		CreatedAt:  time.Now(),
+		Encryption: enc,
+	})
+	if err != nil {
+		s.store.Delete(ctx, objectName)
+		return nil, fmt.Errorf("登记 Blob 失败: %v", err)
+	}
+	if reused && blob.ObjectName != objectName {
+		if err := s.store.Delete(ctx, objectName); err != nil {
+			utils.Error("[Blob] 删除重复对象 %s 失败: %v", objectName, err)
+		}
+	}
+
+	mimeType := utils.DetectMimeType(name, head.head)
+	file := &model.File{
+		FileName:  name,
+		Bucket:    s.bucket,
+		MimeType:  mimeType,
+		Status:    0,
+		CreatedAt: time.Now(),
+		UserID:    userID,
+		FolderID:  folderID,
+	}
+	if err := s.fileDAO.CreateFile(file); err != nil {
+		s.releaseBlob(ctx, blob.ID)
+		if errors.Is(err, model.ErrNameExists) {
+			return nil, errNameTaken(name)
+		}
+		return nil, fmt.Errorf("创建文件记录失败: %v", err)
+	}
+	var mtime *time.Time
+	if !e.ModTime.IsZero() {
+		mtime = &e.ModTime
+	}
+	version := &model.FileVersion{
+		ObjectName:  blob.ObjectName,
+		BlobID:      blob.ID,
+		Size:        blob.Size,
+		Md5:         blob.Md5,
+		Sha256:      blob.Sha256,
+		Status:      1,
+		Encryption:  blob.Encryption,
+		Compression: blob.Compression,
+		Chunked:     blob.Chunked,
+		MimeType:    mimeType,
+		Mtime:       mtime,
+	}
+	if err := s.createVersion(file, version); err != nil {
+		s.fileDAO.DeleteFile(file.ID)
+		s.releaseBlob(ctx, blob.ID)
+		return nil, err
+	}
+	file.Mtime = mtime
+	if !reused {
+		s.enqueueDerivation(blob.ID)
+	}
+	return file, nil
+}
+
+// byteCounter 统计写入的字节数
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// versionSource 按 archive.Source 读取已完成的版本
+// 连续的 ReadAt 复用同一个读取流，ZIP 条目按顺序解压时不会为每次读取重新请求对象
+type versionSource struct {
+	ctx     context.Context
+	s       *StorageService
+	version *model.FileVersion
+
+	mu  sync.Mutex
+	rc  io.ReadCloser
+	pos int64
+}
+
+func (v *versionSource) Size() int64 {
+	return v.version.Size
+}
+
+func (v *versionSource) Open() (io.ReadCloser, error) {
+	return v.s.openVersion(v.ctx, v.version, 0, 0)
+}
+
+func (v *versionSource) ReadAt(p []byte, off int64) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if off >= v.version.Size {
+		return 0, io.EOF
+	}
+	want := p[:min(int64(len(p)), v.version.Size-off)]
+	if v.rc == nil || v.pos != off {
+		v.close()
+		rc, err := v.s.openVersion(v.ctx, v.version, off, 0)
+		if err != nil {
+			return 0, err
+		}
+		v.rc, v.pos = rc, off
+	}
+	n, err := io.ReadFull(v.rc, want)
+	v.pos += int64(n)
+	if err != nil {
+		v.close()
+		return n, err
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close 关闭读取流
+func (v *versionSource) Close() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.close()
+}
+
+func (v *versionSource) close() {
+	if v.rc != nil {
+		v.rc.Close()
+		v.rc = nil
+	}
+}
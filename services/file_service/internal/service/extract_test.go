@@ -0,0 +1,76 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+)
+
+func zipArchive(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range entries {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("zip Create(%q) error = %v", name, err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("zip Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestStorageService_ExtractArchive(t *testing.T) {
+	s, usage, _ := newTestService(t)
+	ctx := context.Background()
+	archive := zipArchive(t, map[string]string{
+		"a.txt":     "alpha",
+		"dir/b.txt": "bravo",
+	})
+	file := upload(t, s, 1, 0, "bundle.zip", archive, "")
+
+	if _, err := s.ExtractArchive(ctx, 2, file.ID, 0); err == nil {
+		t.Error("ExtractArchive() by another user should fail")
+	}
+	result, err := s.ExtractArchive(ctx, 1, file.ID, 0)
+	if err != nil {
+		t.Fatalf("ExtractArchive() error = %v", err)
+	}
+	if len(result.Files) != 2 || result.Folders != 1 {
+		t.Fatalf("result = %d files, %d folders", len(result.Files), result.Folders)
+	}
+	for _, f := range result.Files {
+		want := map[string]string{"a.txt": "alpha", "b.txt": "bravo"}[f.FileName]
+		if got := download(t, s, f.ID, 0); string(got) != want {
+			t.Errorf("%s = %q, want %q", f.FileName, got, want)
+		}
+	}
+	if got, want := usage.get(1), int64(len(archive)+len("alpha")+len("bravo")); got != want {
+		t.Errorf("used = %d, want %d", got, want)
+	}
+}
+
+func TestStorageService_ExtractArchiveUnsafePath(t *testing.T) {
+	s, usage, _ := newTestService(t)
+	ctx := context.Background()
+	archive := zipArchive(t, map[string]string{"../evil.txt": "nope"})
+	file := upload(t, s, 1, 0, "evil.zip", archive, "")
+
+	if _, err := s.ExtractArchive(ctx, 1, file.ID, 0); err == nil {
+		t.Fatal("ExtractArchive() with path traversal should fail")
+	}
+	_, files, total, err := s.ListDirectory(ctx, 1, 0, 1, 10, "", "")
+	if err != nil {
+		t.Fatalf("ListDirectory() error = %v", err)
+	}
+	if total != 1 || len(files) != 1 {
+		t.Errorf("files after failed extract = %d", total)
+	}
+	if got := usage.get(1); got != int64(len(archive)) {
+		t.Errorf("used = %d, want %d", got, len(archive))
+	}
+}
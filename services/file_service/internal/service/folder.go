@@ -0,0 +1,305 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// validateName 校验文件或文件夹名称
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("名称不合法: %q", name)
+	}
+	if len(name) > 255 {
+		return fmt.Errorf("名称过长")
+	}
+	if strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("名称不能包含路径分隔符")
+	}
+	return nil
+}
+
+// checkFolder 校验文件夹存在且属于该用户，folderID 为 0 表示根目录
+func (s *StorageService) checkFolder(userID, folderID int64) (*model.Folder, error) {
+	if folderID == 0 {
+		return nil, nil
+	}
+	folder, err := s.fileDAO.GetFolderByID(folderID)
+	if err != nil {
+		return nil, fmt.Errorf("找不到文件夹: %v", err)
+	}
+	if folder.UserID != userID {
+		return nil, fmt.Errorf("无权访问该文件夹")
+	}
+	return folder, nil
+}
+
+// checkNameAvailable 同一目录下文件和文件夹不能重名
+func (s *StorageService) checkNameAvailable(userID, folderID int64, name string) error {
+	exists, err := s.fileDAO.NameExists(userID, folderID, name)
+	if err != nil {
+		return fmt.Errorf("检查重名失败: %v", err)
+	}
+	if exists {
+		return errNameTaken(name)
+	}
+	return nil
+}
+
+// errNameTaken 目录下已有同名文件或文件夹，唯一索引拒绝并发写入同名文件时也返回该错误
+func errNameTaken(name string) error {
+	return fmt.Errorf("该目录下已存在同名文件或文件夹: %s", name)
+}
+
+// CreateFolder 在 parentID 下创建文件夹
+func (s *StorageService) CreateFolder(ctx context.Context, userID, parentID int64, name string) (*model.Folder, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	if _, err := s.checkFolder(userID, parentID); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(userID, parentID, name); err != nil {
+		return nil, err
+	}
+
+	folder := &model.Folder{
+		UserID:    userID,
+		ParentID:  parentID,
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.fileDAO.CreateFolder(folder); err != nil {
+		return nil, fmt.Errorf("创建文件夹失败: %v", err)
+	}
+	return folder, nil
+}
+
+// RenameFolder 重命名文件夹
+func (s *StorageService) RenameFolder(ctx context.Context, userID, folderID int64, name string) (*model.Folder, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	folder, err := s.checkFolder(userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if folder == nil {
+		return nil, fmt.Errorf("不能重命名根目录")
+	}
+	if folder.Name == name {
+		return folder, nil
+	}
+	if err := s.checkNameAvailable(userID, folder.ParentID, name); err != nil {
+		return nil, err
+	}
+
+	folder.Name = name
+	folder.UpdatedAt = time.Now()
+	if err := s.fileDAO.UpdateFolder(folder); err != nil {
+		return nil, fmt.Errorf("重命名文件夹失败: %v", err)
+	}
+	return folder, nil
+}
+
+// MoveFolder 把文件夹移动到 targetParentID 下
+func (s *StorageService) MoveFolder(ctx context.Context, userID, folderID, targetParentID int64) (*model.Folder, error) {
+	folder, err := s.checkFolder(userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if folder == nil {
+		return nil, fmt.Errorf("不能移动根目录")
+	}
+	if folder.ParentID == targetParentID {
+		return folder, nil
+	}
+	if _, err := s.checkFolder(userID, targetParentID); err != nil {
+		return nil, err
+	}
+
+	// 不能移动到自身或自身的子孙目录下
+	for id := targetParentID; id != 0; {
+		if id == folderID {
+			return nil, fmt.Errorf("不能将文件夹移动到其自身或子目录下")
+		}
+		parent, err := s.fileDAO.GetFolderByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("找不到文件夹: %v", err)
+		}
+		id = parent.ParentID
+	}
+
+	if err := s.checkNameAvailable(userID, targetParentID, folder.Name); err != nil {
+		return nil, err
+	}
+
+	folder.ParentID = targetParentID
+	folder.UpdatedAt = time.Now()
+	if err := s.fileDAO.UpdateFolder(folder); err != nil {
+		return nil, fmt.Errorf("移动文件夹失败: %v", err)
+	}
+	return folder, nil
+}
+
+// DeleteFolder 删除文件夹，recursive 为 true 时删除子文件夹并把其中的文件移入回收站
+func (s *StorageService) DeleteFolder(ctx context.Context, userID, folderID int64, recursive bool) error {
+	folder, err := s.checkFolder(userID, folderID)
+	if err != nil {
+		return err
+	}
+	if folder == nil {
+		return fmt.Errorf("不能删除根目录")
+	}
+
+	if !recursive {
+		exists, err := s.hasChildren(userID, folderID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("文件夹不为空")
+		}
+		return s.fileDAO.DeleteFolder(folderID)
+	}
+	return s.deleteFolderTree(ctx, userID, folderID)
+}
+
+// hasChildren 判断文件夹下是否还有内容
+func (s *StorageService) hasChildren(userID, folderID int64) (bool, error) {
+	_, _, total, err := s.fileDAO.ListDirectory(userID, folderID, model.ListOptions{Limit: 1, OrderBy: "name"})
+	if err != nil {
+		return false, fmt.Errorf("查询目录内容失败: %v", err)
+	}
+	return total > 0, nil
+}
+
+// deleteFolderTree 深度优先删除整棵子树，文件移入回收站
+func (s *StorageService) deleteFolderTree(ctx context.Context, userID, folderID int64) error {
+	children, err := s.fileDAO.ListSubFolders(userID, folderID)
+	if err != nil {
+		return fmt.Errorf("查询子文件夹失败: %v", err)
+	}
+	for _, child := range children {
+		if err := s.deleteFolderTree(ctx, userID, child.ID); err != nil {
+			return err
+		}
+	}
+
+	files, err := s.fileDAO.ListFolderFiles(userID, folderID)
+	if err != nil {
+		return fmt.Errorf("查询文件夹内文件失败: %v", err)
+	}
+	for _, f := range files {
+		if err := s.TrashFile(ctx, userID, f.ID); err != nil {
+			return err
+		}
+	}
+
+	utils.Info("[DeleteFolder] 用户=%d 删除文件夹=%d, 文件数=%d", userID, folderID, len(files))
+	return s.fileDAO.DeleteFolder(folderID)
+}
+
+// ListDirectory 分页列出目录内容
+func (s *StorageService) ListDirectory(ctx context.Context, userID, folderID int64, page, pageSize int, sortBy, order string) ([]model.Folder, []model.File, int64, error) {
+	if _, err := s.checkFolder(userID, folderID); err != nil {
+		return nil, nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	switch sortBy {
+	case "":
+		sortBy = "name"
+	case "name", "size", "created_at":
+	default:
+		return nil, nil, 0, fmt.Errorf("不支持的排序字段: %s", sortBy)
+	}
+
+	opts := model.ListOptions{
+		Offset:  (page - 1) * pageSize,
+		Limit:   pageSize,
+		OrderBy: sortBy,
+		Desc:    strings.EqualFold(order, "desc"),
+	}
+	folders, files, total, err := s.fileDAO.ListDirectory(userID, folderID, opts)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if err := s.attachTags(files); err != nil {
+		return nil, nil, 0, err
+	}
+	return folders, files, total, nil
+}
+
+// LookupPath 从 folderID 起依次进入 folders 中的子文件夹，再在最后一级文件夹中查找名为 name 的文件
+// 返回最后一级文件夹的ID和路径是否存在；create 为 true 时创建不存在的文件夹，路径总是存在
+// name 为空或文件不存在时返回的文件为空
+func (s *StorageService) LookupPath(ctx context.Context, userID, folderID int64, folders []string, name string, create bool) (int64, bool, *model.File, error) {
+	if _, err := s.checkFolder(userID, folderID); err != nil {
+		return 0, false, nil, err
+	}
+	for _, folderName := range folders {
+		if err := validateName(folderName); err != nil {
+			return 0, false, nil, err
+		}
+		id, err := s.subFolderID(userID, folderID, folderName)
+		if err != nil {
+			return 0, false, nil, err
+		}
+		if id == 0 && !create {
+			return 0, false, nil, nil
+		}
+		if id == 0 {
+			folder, err := s.CreateFolder(ctx, userID, folderID, folderName)
+			if err != nil {
+				// 并发创建时改用已创建的文件夹
+				if id, _ = s.subFolderID(userID, folderID, folderName); id == 0 {
+					return 0, false, nil, err
+				}
+			} else {
+				id = folder.ID
+			}
+		}
+		folderID = id
+	}
+
+	if name == "" {
+		return folderID, true, nil, nil
+	}
+	if file, err := s.fileDAO.GetFileByName(userID, folderID, name); err == nil {
+		return folderID, true, file, nil
+	}
+	return folderID, true, nil, nil
+}
+
+// subFolderID 返回 parentID 下名为 name 的子文件夹ID，不存在时返回0
+func (s *StorageService) subFolderID(userID, parentID int64, name string) (int64, error) {
+	subFolders, err := s.fileDAO.ListSubFolders(userID, parentID)
+	if err != nil {
+		return 0, fmt.Errorf("获取子文件夹失败: %v", err)
+	}
+	for _, f := range subFolders {
+		if f.Name == name {
+			return f.ID, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"bytes"
+	"cloud-storage-file-service/internal/fetch"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"expvar"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// importMetrics 从URL导入的累计指标，通过 /debug/vars 暴露
+var importMetrics = expvar.NewMap("imports")
+
+// 导入任务状态
+const (
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+	ImportCancelled = "cancelled"
+)
+
+// importRetention 结束的导入任务状态保留的时间，供客户端查询结果
+const importRetention = time.Hour
+
+// importJob 一个从URL导入的任务
+type importJob struct {
+	cancel     context.CancelFunc
+	done       chan struct{}
+	status     string
+	err        error
+	finishedAt time.Time
+}
+
+// importRegistry 进行中和最近结束的导入任务，按文件ID索引
+type importRegistry struct {
+	mu   sync.Mutex
+	jobs map[int64]*importJob
+}
+
+// SetImportOptions 设置从URL导入的远程文件大小上限（不大于0表示不限制）以及是否允许访问内网地址
+func (s *StorageService) SetImportOptions(maxSize int64, allowPrivate bool) {
+	s.fetcher = fetch.NewClient(allowPrivate, maxSize)
+}
+
+// ImportFromURL 从 HTTP(S) 地址导入文件到 userID 的 folderID 下，返回上传中的文件
+// 远程文件在后台按分片大小读取，经 UploadPartStream 写入后完成上传；进度通过 GetUploadProgress 查询，
+// 远程未声明大小时总大小为0，写入超出预留的部分前逐个分片补充预留，空间不足时导入失败并取消上传。
+// CancelUpload 会中止下载并取消上传
+// name 为空时取 Content-Disposition 或 URL 中的文件名；与已有文件重名时为其创建新版本，与上传相同
+func (s *StorageService) ImportFromURL(ctx context.Context, userID, folderID int64, rawURL, name string, meta FileMeta) (*model.File, error) {
+	if err := validateTags(meta.Tags, nil); err != nil {
+		return nil, err
+	}
+	// 下载在请求返回后继续进行，不使用请求的 ctx
+	jobCtx, cancel := context.WithCancel(context.Background())
+	resp, err := s.fetcher.Open(jobCtx, rawURL)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("请求远程文件失败: %v", err)
+	}
+	if name == "" {
+		name = resp.Name
+	}
+	if err := validateName(name); err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("无法确定文件名，请指定名称: %v", err)
+	}
+	if meta.Mtime == nil && !resp.LastModified.IsZero() {
+		meta.Mtime = &resp.LastModified
+	}
+
+	// 远程未声明大小时先不预留空间，写入时按分片补充预留
+	size := max(resp.Size, 0)
+	file, err := s.initUpload(ctx, name, size, "", "", userID, folderID, uploadOptions{meta: meta})
+	if err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, err
+	}
+	if file.Status == 1 {
+		// 不声明校验值不会秒传，这里只是防御
+		resp.Body.Close()
+		cancel()
+		return file, nil
+	}
+
+	version, err := s.pendingVersion(file.ID)
+	if err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, err
+	}
+
+	// 同一文件之前的导入已随新上传被放弃
+	s.imports.stop(file.ID)
+	job := s.imports.start(file.ID, cancel)
+	importMetrics.Add("started", 1)
+	utils.Info("[Import] 用户=%d 文件=%d 开始从 %s 导入，声明大小=%d", userID, file.ID, rawURL, resp.Size)
+	go s.runImport(jobCtx, job, userID, version, resp.Body)
+	return file, nil
+}
+
+// runImport 把远程内容按分片写入并完成上传，失败时取消上传
+func (s *StorageService) runImport(ctx context.Context, job *importJob, userID int64, version *model.FileVersion, body io.ReadCloser) {
+	defer body.Close()
+	fileID := version.FileID
+	written, err := s.importParts(ctx, userID, version, body)
+	if err == nil {
+		err = s.UploadComplete(ctx, fileID)
+	}
+	importMetrics.Add("bytes", written)
+
+	status := ImportCompleted
+	switch {
+	case err == nil:
+		utils.Info("[Import] 文件=%d 导入完成，大小=%d", fileID, written)
+	case ctx.Err() != nil:
+		// 由 CancelUpload 取消，上传会话由调用方清理
+		status = ImportCancelled
+		utils.Info("[Import] 文件=%d 导入已取消", fileID)
+	default:
+		status = ImportFailed
+		utils.Error("[Import] 文件=%d 导入失败: %v", fileID, err)
+		// 已被新的上传取代时不能取消新的上传
+		if !s.isPendingVersion(version) {
+			break
+		}
+		if cerr := s.cancelUpload(context.Background(), fileID); cerr != nil {
+			utils.Error("[Import] 文件=%d 取消上传失败: %v", fileID, cerr)
+		}
+	}
+	importMetrics.Add(status, 1)
+	s.imports.finish(job, status, err)
+}
+
+// importParts 按分片大小读取远程内容并逐个写入分片，返回写入的字节数
+// 内容为空时也写入一个空分片，使上传可以完成；上传被新的上传取代或补充预留空间失败时停止
+func (s *StorageService) importParts(ctx context.Context, userID int64, version *model.FileVersion, body io.Reader) (int64, error) {
+	buf := make([]byte, s.partSize)
+	var written int64
+	for partNumber := 1; ; partNumber++ {
+		n, err := io.ReadFull(body, buf)
+		switch {
+		case err == io.EOF && partNumber > 1:
+			return written, nil
+		case err != nil && err != io.EOF && err != io.ErrUnexpectedEOF:
+			return written, fmt.Errorf("读取远程文件失败: %v", err)
+		}
+		if !s.isPendingVersion(version) {
+			return written, fmt.Errorf("上传已被取消或被新的上传取代")
+		}
+		if err := s.reserveImportPart(ctx, userID, version, written+int64(n)); err != nil {
+			return written, err
+		}
+		sum := md5.Sum(buf[:n])
+		if err := s.UploadPartStream(ctx, version.FileID, partNumber, int64(n), bytes.NewReader(buf[:n]), hex.EncodeToString(sum[:])); err != nil {
+			return written, err
+		}
+		written += int64(n)
+		if n < len(buf) {
+			return written, nil
+		}
+	}
+}
+
+// reserveImportPart 写入后的总大小 total 超出版本已预留的空间时补充预留差额，并记录到版本上
+func (s *StorageService) reserveImportPart(ctx context.Context, userID int64, version *model.FileVersion, total int64) error {
+	if total <= version.Reserved {
+		return nil
+	}
+	extra, err := s.reserveSpace(ctx, userID, total-version.Reserved)
+	if err != nil || extra == 0 {
+		return err
+	}
+	ok, err := s.fileDAO.AddVersionReserved(version.ID, extra)
+	if err != nil || !ok {
+		s.reportUsage(ctx, userID, -extra)
+		if err != nil {
+			return fmt.Errorf("更新预留空间失败: %v", err)
+		}
+		return fmt.Errorf("上传已被取消或被新的上传取代")
+	}
+	version.Reserved += extra
+	return nil
+}
+
+// isPendingVersion 版本仍是文件正在上传的版本
+func (s *StorageService) isPendingVersion(version *model.FileVersion) bool {
+	current, err := s.fileDAO.GetPendingVersion(version.FileID)
+	return err == nil && current.ID == version.ID
+}
+
+// ImportStatus 返回文件最近一次从URL导入的状态和失败原因，没有导入任务时状态为空
+func (s *StorageService) ImportStatus(fileID int64) (string, string) {
+	s.imports.mu.Lock()
+	defer s.imports.mu.Unlock()
+	job, ok := s.imports.jobs[fileID]
+	if !ok {
+		return "", ""
+	}
+	if job.err != nil && job.status == ImportFailed {
+		return job.status, job.err.Error()
+	}
+	return job.status, ""
+}
+
+// start 登记新的导入任务，同时清理结束已久的任务
+func (r *importRegistry) start(fileID int64, cancel context.CancelFunc) *importJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.jobs == nil {
+		r.jobs = make(map[int64]*importJob)
+	}
+	for id, job := range r.jobs {
+		if job.status != ImportRunning && time.Since(job.finishedAt) > importRetention {
+			delete(r.jobs, id)
+		}
+	}
+	job := &importJob{cancel: cancel, done: make(chan struct{}), status: ImportRunning}
+	r.jobs[fileID] = job
+	return job
+}
+
+func (r *importRegistry) finish(job *importJob, status string, err error) {
+	r.mu.Lock()
+	job.status = status
+	job.err = err
+	job.finishedAt = time.Now()
+	r.mu.Unlock()
+	job.cancel()
+	close(job.done)
+}
+
+// stop 中止文件进行中的导入任务并等待其退出
+func (r *importRegistry) stop(fileID int64) {
+	r.mu.Lock()
+	job, ok := r.jobs[fileID]
+	r.mu.Unlock()
+	if !ok {
+		return
+	}
+	job.cancel()
+	<-job.done
+}
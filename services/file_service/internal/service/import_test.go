@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitImport 等待导入任务结束，返回最终状态
+func waitImport(t *testing.T, s *StorageService, fileID int64) (string, string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if status, msg := s.ImportStatus(fileID); status != ImportRunning {
+			return status, msg
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("import of file %d did not finish", fileID)
+	return "", ""
+}
+
+// chunkedServer 以 chunked 编码返回 data，不声明 Content-Length
+func chunkedServer(data []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < len(data); i += 64 * 1024 {
+			w.Write(data[i:min(i+64*1024, len(data))])
+			w.(http.Flusher).Flush()
+		}
+	}))
+}
+
+func TestStorageService_ImportUnknownSize(t *testing.T) {
+	s, usage, _ := newTestService(t)
+	s.SetImportOptions(0, true)
+	data := randomData(20, testPartSize+100)
+	srv := chunkedServer(data)
+	defer srv.Close()
+
+	file, err := s.ImportFromURL(context.Background(), 1, 0, srv.URL+"/remote.bin", "", FileMeta{})
+	if err != nil {
+		t.Fatalf("ImportFromURL() error = %v", err)
+	}
+	if status, msg := waitImport(t, s, file.ID); status != ImportCompleted {
+		t.Fatalf("import status = %s: %s", status, msg)
+	}
+	if got := download(t, s, file.ID, 0); !bytes.Equal(got, data) {
+		t.Fatalf("imported %d bytes, want %d", len(got), len(data))
+	}
+	if got := usage.get(1); got != int64(len(data)) {
+		t.Errorf("used = %d, want %d", got, len(data))
+	}
+}
+
+func TestStorageService_ImportUnknownSizeOverQuota(t *testing.T) {
+	s, usage, _ := newTestService(t)
+	s.SetImportOptions(0, true)
+	usage.total = testPartSize + 10
+	// 远程内容远大于容量，超出预留时应立即停止下载，而不是读完后才发现空间不足
+	var served atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := randomData(21, 64*1024)
+		for i := 0; i < 6*testPartSize/len(chunk); i++ {
+			n, err := w.Write(chunk)
+			served.Add(int64(n))
+			if err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+
+	file, err := s.ImportFromURL(context.Background(), 1, 0, srv.URL+"/remote.bin", "", FileMeta{})
+	if err != nil {
+		t.Fatalf("ImportFromURL() error = %v", err)
+	}
+	if status, msg := waitImport(t, s, file.ID); status != ImportFailed || !strings.Contains(msg, "空间不足") {
+		t.Fatalf("import status = %s: %s, want failure for quota", status, msg)
+	}
+	if got := served.Load(); got >= 6*testPartSize {
+		t.Errorf("served %d bytes, import should stop once the quota is exceeded", got)
+	}
+	if got := usage.get(1); got != 0 {
+		t.Errorf("used after failed import = %d, want 0", got)
+	}
+	if _, err := s.GetFileInfo(context.Background(), file.ID); err == nil {
+		t.Error("file of failed import should be removed")
+	}
+}
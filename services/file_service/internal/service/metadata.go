@@ -0,0 +1,152 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+)
+
+// 标签限制
+const (
+	maxTagsPerFile = 32
+	maxTagNameLen  = 64
+	maxTagValueLen = 255
+)
+
+// FileMeta 上传时客户端提供的文件元数据
+type FileMeta struct {
+	Mtime *time.Time        // 原始修改时间，可为空
+	Tags  map[string]string // 设置到文件上的标签，同名文件上传新版本时与已有标签合并
+}
+
+// validateTags 校验要设置和删除的标签
+func validateTags(set map[string]string, remove []string) error {
+	if len(set) > maxTagsPerFile {
+		return fmt.Errorf("每个文件最多 %d 个标签", maxTagsPerFile)
+	}
+	for name, value := range set {
+		if name == "" || utf8.RuneCountInString(name) > maxTagNameLen {
+			return fmt.Errorf("标签名长度必须在1到%d之间", maxTagNameLen)
+		}
+		if utf8.RuneCountInString(value) > maxTagValueLen {
+			return fmt.Errorf("标签 %s 的值不能超过%d个字符", name, maxTagValueLen)
+		}
+	}
+	for _, name := range remove {
+		if name == "" {
+			return fmt.Errorf("标签名不能为空")
+		}
+	}
+	return nil
+}
+
+// applyTags 更新文件的标签，更新后标签数不能超过上限
+func (s *StorageService) applyTags(file *model.File, set map[string]string, remove []string) error {
+	if len(set) == 0 && len(remove) == 0 {
+		return nil
+	}
+	if err := validateTags(set, remove); err != nil {
+		return err
+	}
+	if err := s.loadTags(file); err != nil {
+		return err
+	}
+	merged := make(map[string]string, len(file.Tags)+len(set))
+	for name, value := range file.Tags {
+		merged[name] = value
+	}
+	for _, name := range remove {
+		delete(merged, name)
+	}
+	for name, value := range set {
+		merged[name] = value
+	}
+	if len(merged) > maxTagsPerFile {
+		return fmt.Errorf("每个文件最多 %d 个标签", maxTagsPerFile)
+	}
+	if err := s.fileDAO.UpdateFileTags(file.ID, set, remove); err != nil {
+		return fmt.Errorf("更新标签失败: %v", err)
+	}
+	file.Tags = merged
+	return nil
+}
+
+// UpdateFileTags 设置文件的标签并删除 remove 中的标签
+func (s *StorageService) UpdateFileTags(ctx context.Context, userID, fileID int64, set map[string]string, remove []string) (*model.File, error) {
+	file, err := s.ownedFile(userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyTags(file, set, remove); err != nil {
+		return nil, err
+	}
+	updated, err := s.fileDAO.GetFileByID(fileID)
+	if err != nil {
+		return nil, err
+	}
+	updated.Tags = file.Tags
+	return updated, nil
+}
+
+// loadTags 加载单个文件的标签
+func (s *StorageService) loadTags(file *model.File) error {
+	tags, err := s.fileDAO.ListFileTags([]int64{file.ID})
+	if err != nil {
+		return fmt.Errorf("查询标签失败: %v", err)
+	}
+	file.Tags = tags[file.ID]
+	return nil
+}
+
+// attachTags 批量加载文件的标签
+func (s *StorageService) attachTags(files []model.File) error {
+	ids := make([]int64, 0, len(files))
+	for _, f := range files {
+		ids = append(ids, f.ID)
+	}
+	tags, err := s.fileDAO.ListFileTags(ids)
+	if err != nil {
+		return fmt.Errorf("查询标签失败: %v", err)
+	}
+	for i := range files {
+		files[i].Tags = tags[files[i].ID]
+	}
+	return nil
+}
+
+// headReader 在读取的同时保留内容开头用于识别内容类型
+type headReader struct {
+	io.Reader
+	head []byte
+}
+
+func newHeadReader(r io.Reader) *headReader {
+	return &headReader{Reader: r, head: make([]byte, 0, utils.SniffLen)}
+}
+
+func (h *headReader) Read(p []byte) (int, error) {
+	n, err := h.Reader.Read(p)
+	if room := utils.SniffLen - len(h.head); room > 0 {
+		h.head = append(h.head, p[:min(n, room)]...)
+	}
+	return n, err
+}
+
+// recordMimeType 按版本内容开头识别内容类型并记录到版本上，完成上传时同步到文件，失败只记录日志
+func (s *StorageService) recordMimeType(version *model.FileVersion, head []byte) {
+	if len(head) == 0 {
+		return
+	}
+	file, err := s.fileDAO.GetFileByID(version.FileID)
+	if err != nil {
+		return
+	}
+	version.MimeType = utils.DetectMimeType(file.FileName, head)
+	if err := s.fileDAO.SetVersionMimeType(version.ID, version.MimeType); err != nil {
+		utils.Error("[Metadata] 版本=%d 记录内容类型失败: %v", version.ID, err)
+	}
+}
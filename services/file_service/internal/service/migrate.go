@@ -0,0 +1,57 @@
+package service
+
+import (
+	"cloud-storage-file-service/utils"
+	"context"
+	"fmt"
+)
+
+// MigrateObjectKeys 把旧格式（以文件名为对象名）的 Blob 对象迁移到随机对象名下
+// 先复制对象再更新 Blob、版本和文件记录，最后删除旧对象；中断后重新执行会从未迁移的 Blob 继续
+// 只迁移已完成的 Blob，还有未完成的上传时拒绝执行；迁移期间不应有上传或删除，dryRun 为 true 时只统计不修改
+func (s *StorageService) MigrateObjectKeys(ctx context.Context, dryRun bool) (int, error) {
+	pending, err := s.fileDAO.CountPendingVersions()
+	if err != nil {
+		return 0, fmt.Errorf("查询未完成的上传失败: %v", err)
+	}
+	if pending > 0 {
+		if !dryRun {
+			return 0, fmt.Errorf("还有 %d 个未完成的上传，请等待上传完成或过期清理后再迁移", pending)
+		}
+		utils.Info("[MigrateKeys] 还有 %d 个未完成的上传，正式迁移前需等待其完成或过期清理", pending)
+	}
+
+	migrated := 0
+	var afterID int64
+	for {
+		blobs, err := s.fileDAO.ListBlobsOutsidePrefix(objectKeyPrefix, afterID, 100)
+		if err != nil {
+			return migrated, fmt.Errorf("查询待迁移的 Blob 失败: %v", err)
+		}
+		if len(blobs) == 0 {
+			return migrated, nil
+		}
+		for _, blob := range blobs {
+			afterID = blob.ID
+			newName := newObjectKey()
+			if dryRun {
+				utils.Info("[MigrateKeys] Blob=%d %s -> %s (dry-run)", blob.ID, blob.ObjectName, newName)
+				migrated++
+				continue
+			}
+
+			if err := s.store.Compose(ctx, newName, []string{blob.ObjectName}); err != nil {
+				return migrated, fmt.Errorf("复制对象 %s 失败: %v", blob.ObjectName, err)
+			}
+			if err := s.fileDAO.MoveBlobObject(blob.ID, blob.ObjectName, newName); err != nil {
+				s.store.Delete(ctx, newName)
+				return migrated, fmt.Errorf("更新 Blob %d 的对象名失败: %v", blob.ID, err)
+			}
+			if err := s.store.Delete(ctx, blob.ObjectName); err != nil {
+				utils.Error("[MigrateKeys] 删除旧对象 %s 失败: %v", blob.ObjectName, err)
+			}
+			utils.Info("[MigrateKeys] Blob=%d %s -> %s", blob.ID, blob.ObjectName, newName)
+			migrated++
+		}
+	}
+}
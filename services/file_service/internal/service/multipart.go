@@ -0,0 +1,163 @@
+package service
+
+import (
+	"bytes"
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"fmt"
+	"io"
+)
+
+// newMultipartUpload 为上传中的版本创建分片上传
+func (s *StorageService) newMultipartUpload(ctx context.Context, version *model.FileVersion) error {
+	uploadID, err := s.store.NewMultipartUpload(ctx, version.ObjectName)
+	if err != nil {
+		return fmt.Errorf("创建分片上传失败: %v", err)
+	}
+	version.UploadID = uploadID
+	return nil
+}
+
+// putPart 写入一个分片并返回分片记录，第1个分片写入成功后按其开头识别内容类型
+// md5Hex 非空时由存储端校验分片内容；压缩或加密的分片由调用方校验明文
+func (s *StorageService) putPart(ctx context.Context, version *model.FileVersion, partNumber int, reader io.Reader, size int64, md5Hex string) (*model.FilePart, error) {
+	if partNumber != 1 {
+		return s.writePart(ctx, version, partNumber, reader, size, md5Hex)
+	}
+	head := newHeadReader(reader)
+	part, err := s.writePart(ctx, version, partNumber, head, size, md5Hex)
+	if err == nil {
+		s.recordMimeType(version, head.head)
+	}
+	return part, err
+}
+
+// writePart 写入一个分片
+// 有分片上传ID时写入该分片上传，压缩的分片和旧的上传会话写成独立的分片对象
+func (s *StorageService) writePart(ctx context.Context, version *model.FileVersion, partNumber int, reader io.Reader, size int64, md5Hex string) (*model.FilePart, error) {
+	part := &model.FilePart{
+		FileID:     version.FileID,
+		VersionID:  version.ID,
+		PartNumber: partNumber,
+	}
+	if version.Compression != "" {
+		packed, blocks, plainSize, err := compressPart(version.Compression, reader, size)
+		if err != nil {
+			return nil, err
+		}
+		part.Size = plainSize
+		part.Blocks = blocks
+		reader, size, md5Hex = bytes.NewReader(packed), int64(len(packed)), ""
+	}
+
+	reader, err := s.encryptPart(version.Encryption, partNumber, reader)
+	if err != nil {
+		return nil, err
+	}
+	if version.Encryption.KeyID != 0 {
+		md5Hex = ""
+	}
+
+	if version.UploadID != "" {
+		info, err := s.store.PutPart(ctx, version.ObjectName, version.UploadID, partNumber, reader, size, md5Hex)
+		if err != nil {
+			return nil, fmt.Errorf("上传分片失败: %v", err)
+		}
+		part.ETag = info.ETag
+		part.Size = info.Size
+		return part, nil
+	}
+
+	part.ObjectName = partObjectName(version.ObjectName, partNumber)
+	info, err := s.store.Put(ctx, part.ObjectName, reader, size)
+	if err != nil {
+		return nil, fmt.Errorf("上传分片失败: %v", err)
+	}
+	part.ETag = info.ETag
+	if part.Blocks == nil {
+		part.Size = info.Size
+	}
+	return part, nil
+}
+
+// completeParts 把已上传的分片合并为版本对象
+// 原生分片上传直接完成，压缩的分片和旧的上传会话合并独立的分片对象后将其删除
+func (s *StorageService) completeParts(ctx context.Context, version *model.FileVersion, parts []model.FilePart) error {
+	if version.Compression != "" {
+		return s.concatCompressedParts(ctx, version, parts)
+	}
+	if version.UploadID != "" {
+		completed := make([]blobstore.PartInfo, 0, len(parts))
+		for _, p := range parts {
+			completed = append(completed, blobstore.PartInfo{PartNumber: p.PartNumber, ETag: p.ETag, Size: p.Size})
+		}
+		if _, err := s.store.CompleteMultipartUpload(ctx, version.ObjectName, version.UploadID, completed); err != nil {
+			return fmt.Errorf("合并分片失败: %v", err)
+		}
+		return nil
+	}
+
+	srcs := make([]string, 0, len(parts))
+	for _, p := range parts {
+		srcs = append(srcs, p.ObjectName)
+	}
+	if err := s.store.Compose(ctx, version.ObjectName, srcs); err != nil {
+		return fmt.Errorf("合并分片失败: %v", err)
+	}
+	s.removePartObjects(ctx, parts)
+	return nil
+}
+
+// removePartObjects 删除旧上传会话留下的独立分片对象，失败只记录日志
+func (s *StorageService) removePartObjects(ctx context.Context, parts []model.FilePart) {
+	for _, p := range parts {
+		if p.ObjectName == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, p.ObjectName); err != nil {
+			utils.Error("[Multipart] 删除分片对象 %s 失败: %v", p.ObjectName, err)
+		}
+	}
+}
+
+// abortMultipartUpload 中止上传中版本的分片上传，释放已上传的分片
+func (s *StorageService) abortMultipartUpload(ctx context.Context, version *model.FileVersion) error {
+	if version.UploadID == "" || version.Status == 1 {
+		return nil
+	}
+	if err := s.store.AbortMultipartUpload(ctx, version.ObjectName, version.UploadID); err != nil {
+		return fmt.Errorf("中止分片上传失败: %v", err)
+	}
+	return nil
+}
+
+// versionChunk 返回已完成版本的第 partNumber 个分片及其在版本对象中的起始位置
+// 没有分片记录的版本（直接上传或秒传）按分片大小切分
+func (s *StorageService) versionChunk(version *model.FileVersion, partNumber int) (*model.FilePart, int64, error) {
+	parts, err := s.fileDAO.ListParts(version.ID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取分片列表失败: %v", err)
+	}
+	if len(parts) == 0 {
+		offset := int64(partNumber-1) * s.partSize
+		if partNumber < 1 || offset >= version.Size {
+			return nil, 0, fmt.Errorf("分片不存在: %d", partNumber)
+		}
+		return &model.FilePart{
+			VersionID:  version.ID,
+			PartNumber: partNumber,
+			Size:       min(s.partSize, version.Size-offset),
+		}, offset, nil
+	}
+
+	var offset int64
+	for i := range parts {
+		if parts[i].PartNumber == partNumber {
+			return &parts[i], offset, nil
+		}
+		offset += parts[i].Size
+	}
+	return nil, 0, fmt.Errorf("分片不存在: %d", partNumber)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReconcileOptions 数据库与对象存储对账的参数
+type ReconcileOptions struct {
+	// Apply 为 false（默认）时只报告；为 true 时删除孤立对象，并处理对象缺失的记录
+	Apply bool
+	// Grace 最近这段时间内写入的对象可能属于进行中的上传，不视为孤立对象，小于等于0时使用 defaultReconcileGrace
+	Grace time.Duration
+}
+
+// defaultReconcileGrace 默认不处理最近24小时内写入的对象
+const defaultReconcileGrace = 24 * time.Hour
+
+// ReconcileReport 对账结果
+type ReconcileReport struct {
+	Objects     int                    // 存储中的对象数
+	Orphans     []blobstore.ObjectInfo // 没有记录引用的对象
+	OrphanBytes int64
+	Missing     []model.ObjectRef // 对象不存在的记录
+
+	// 以下只在 Apply 时有值
+	DeletedObjects int // 删除的孤立对象数
+	MarkedFiles    int // 内容缺失、已重新校验并记录问题的文件数
+	ResetBlobs     int // 衍生对象缺失、已重新排队生成的 Blob 数
+	DeletedParts   int // 对象缺失、已删除记录等待重新上传的分片数
+}
+
+// ReconcileObjects 对比对象存储中的对象和数据库中引用对象的记录
+// 列出没有记录引用的对象和对象不存在的记录，Apply 时：
+//   - 删除孤立对象
+//   - Blob 或分块的对象缺失时重新校验引用它的文件，问题记录到完整性校验表
+//   - 衍生对象缺失时删除该 Blob 的衍生对象并重新生成
+//   - 上传中版本的分片对象缺失时删除分片记录，客户端查询未上传的分片后重新上传
+//
+// 存储中的全部对象名会加载到内存
+func (s *StorageService) ReconcileObjects(ctx context.Context, opts ReconcileOptions) (*ReconcileReport, error) {
+	report := &ReconcileReport{}
+	listed := make(map[string]blobstore.ObjectInfo)
+	err := s.store.List(ctx, "", func(info blobstore.ObjectInfo) error {
+		listed[info.Key] = info
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("列举对象失败: %v", err)
+	}
+	report.Objects = len(listed)
+
+	referenced := make(map[string]bool, len(listed))
+	for _, kind := range []string{model.RefBlob, model.RefChunk, model.RefDerivative, model.RefPart} {
+		for afterID := int64(0); ; {
+			refs, err := s.fileDAO.ListObjectRefs(kind, afterID, 500)
+			if err != nil {
+				return nil, fmt.Errorf("查询 %s 记录失败: %v", kind, err)
+			}
+			if len(refs) == 0 {
+				break
+			}
+			afterID = refs[len(refs)-1].ID
+			for _, ref := range refs {
+				if _, ok := listed[ref.ObjectName]; ok || referenced[ref.ObjectName] {
+					referenced[ref.ObjectName] = true
+					continue
+				}
+				// 列举之后才写入的对象不算缺失
+				_, err := s.store.Stat(ctx, ref.ObjectName)
+				switch {
+				case err == nil:
+					referenced[ref.ObjectName] = true
+				case errors.Is(err, blobstore.ErrNotFound):
+					report.Missing = append(report.Missing, ref)
+				default:
+					return nil, fmt.Errorf("查询对象 %s 失败: %v", ref.ObjectName, err)
+				}
+			}
+		}
+	}
+
+	if opts.Grace <= 0 {
+		opts.Grace = defaultReconcileGrace
+	}
+	cutoff := time.Now().Add(-opts.Grace)
+	for key, info := range listed {
+		if referenced[key] || info.ModTime.After(cutoff) {
+			continue
+		}
+		report.Orphans = append(report.Orphans, info)
+		report.OrphanBytes += info.Size
+	}
+	sort.Slice(report.Orphans, func(i, j int) bool { return report.Orphans[i].Key < report.Orphans[j].Key })
+
+	if opts.Apply {
+		if err := s.applyReconcile(ctx, report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// applyReconcile 删除孤立对象并处理对象缺失的记录
+func (s *StorageService) applyReconcile(ctx context.Context, report *ReconcileReport) error {
+	for _, info := range report.Orphans {
+		if err := s.store.Delete(ctx, info.Key); err != nil {
+			return fmt.Errorf("删除孤立对象 %s 失败: %v", info.Key, err)
+		}
+		report.DeletedObjects++
+		utils.Info("[Reconcile] 已删除孤立对象 %s (%d 字节)", info.Key, info.Size)
+	}
+
+	var blobIDs []int64
+	resetBlobs := make(map[int64]bool)
+	for _, ref := range report.Missing {
+		switch ref.Kind {
+		case model.RefBlob:
+			blobIDs = append(blobIDs, ref.ID)
+		case model.RefChunk:
+			ids, err := s.fileDAO.ListBlobIDsByChunk(ref.ID)
+			if err != nil {
+				return fmt.Errorf("查询引用分块 %d 的 Blob 失败: %v", ref.ID, err)
+			}
+			blobIDs = append(blobIDs, ids...)
+		case model.RefDerivative:
+			if resetBlobs[ref.OwnerID] {
+				continue
+			}
+			derivatives, err := s.fileDAO.ResetDerivations(ref.OwnerID)
+			if err != nil {
+				return fmt.Errorf("重置 Blob %d 的衍生对象失败: %v", ref.OwnerID, err)
+			}
+			s.deleteDerivativeObjects(ctx, derivatives)
+			s.enqueueDerivation(ref.OwnerID)
+			resetBlobs[ref.OwnerID] = true
+		case model.RefPart:
+			if err := s.fileDAO.DeletePart(ref.ID); err != nil {
+				return fmt.Errorf("删除分片记录 %d 失败: %v", ref.ID, err)
+			}
+			report.DeletedParts++
+		}
+	}
+	report.ResetBlobs = len(resetBlobs)
+
+	marked := make(map[int64]bool)
+	for _, blobID := range blobIDs {
+		fileIDs, err := s.fileDAO.ListFileIDsByBlob(blobID)
+		if err != nil {
+			return fmt.Errorf("查询引用 Blob %d 的文件失败: %v", blobID, err)
+		}
+		for _, fileID := range fileIDs {
+			if marked[fileID] {
+				continue
+			}
+			marked[fileID] = true
+			if _, err := s.ScrubFile(ctx, fileID); err != nil {
+				return fmt.Errorf("校验文件 %d 失败: %v", fileID, err)
+			}
+			report.MarkedFiles++
+		}
+	}
+	return nil
+}
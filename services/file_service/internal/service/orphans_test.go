@@ -0,0 +1,113 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// agedStore 列举时给对象加上修改时间，recent 中的对象是刚写入的，其余的写入已超过两天
+type agedStore struct {
+	*blobstore.MemoryStore
+	recent map[string]bool
+}
+
+func (a *agedStore) List(ctx context.Context, prefix string, fn func(blobstore.ObjectInfo) error) error {
+	return a.MemoryStore.List(ctx, prefix, func(info blobstore.ObjectInfo) error {
+		info.ModTime = time.Now().Add(-48 * time.Hour)
+		if a.recent[info.Key] {
+			info.ModTime = time.Now()
+		}
+		return fn(info)
+	})
+}
+
+func TestStorageService_ReconcileObjects(t *testing.T) {
+	s, _, memStore := newTestService(t)
+	store := &agedStore{MemoryStore: memStore, recent: map[string]bool{"orphans/new": true}}
+	s.store = store
+	ctx := context.Background()
+
+	kept := upload(t, s, 1, 0, "kept.txt", randomData(1, 1000), "")
+	lost := upload(t, s, 1, 0, "lost.txt", randomData(2, 1000), "")
+	if err := store.Delete(ctx, currentVersion(t, s, lost).ObjectName); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	// 压缩的分片写成独立的对象，上传中时由分片记录引用
+	data := randomData(3, 100)
+	pending, err := s.InitUpload(ctx, "pending.bin", int64(len(data)), "", "", 1, 0, "zstd", FileMeta{})
+	if err != nil {
+		t.Fatalf("InitUpload() error = %v", err)
+	}
+	if err := s.UploadPart(ctx, pending.ID, 1, data, md5Of(data)); err != nil {
+		t.Fatalf("UploadPart() error = %v", err)
+	}
+	pendingVersion, err := s.fileDAO.GetPendingVersion(pending.ID)
+	if err != nil {
+		t.Fatalf("GetPendingVersion() error = %v", err)
+	}
+	parts, err := s.fileDAO.ListParts(pendingVersion.ID)
+	if err != nil || len(parts) != 1 || parts[0].ObjectName == "" {
+		t.Fatalf("ListParts() = %+v, %v, want one part object", parts, err)
+	}
+	if err := store.Delete(ctx, parts[0].ObjectName); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	putObject(t, memStore, "orphans/old", []byte("old"))
+	putObject(t, memStore, "orphans/new", []byte("new"))
+
+	report, err := s.ReconcileObjects(ctx, ReconcileOptions{})
+	if err != nil {
+		t.Fatalf("ReconcileObjects() error = %v", err)
+	}
+	if len(report.Orphans) != 1 || report.Orphans[0].Key != "orphans/old" || report.OrphanBytes != 3 {
+		t.Fatalf("Orphans = %+v, want only orphans/old", report.Orphans)
+	}
+	missing := make(map[string]bool)
+	for _, ref := range report.Missing {
+		missing[ref.Kind] = true
+	}
+	if len(report.Missing) != 2 || !missing[model.RefBlob] || !missing[model.RefPart] {
+		t.Fatalf("Missing = %+v, want the lost blob and the lost part", report.Missing)
+	}
+	if report.DeletedObjects != 0 || report.DeletedParts != 0 || report.MarkedFiles != 0 {
+		t.Errorf("report-only run changed something: %+v", report)
+	}
+	if _, err := store.Stat(ctx, "orphans/old"); err != nil {
+		t.Errorf("report-only run deleted the orphan: %v", err)
+	}
+
+	report, err = s.ReconcileObjects(ctx, ReconcileOptions{Apply: true})
+	if err != nil {
+		t.Fatalf("ReconcileObjects(Apply) error = %v", err)
+	}
+	if report.DeletedObjects != 1 || report.DeletedParts != 1 || report.MarkedFiles != 1 {
+		t.Errorf("ReconcileObjects(Apply) = %+v, want 1 object, 1 part and 1 file", report)
+	}
+	if _, err := store.Stat(ctx, "orphans/old"); !errors.Is(err, blobstore.ErrNotFound) {
+		t.Errorf("orphan older than the grace period still exists: %v", err)
+	}
+	if _, err := store.Stat(ctx, "orphans/new"); err != nil {
+		t.Errorf("object within the grace period was deleted: %v", err)
+	}
+	if _, err := store.Stat(ctx, currentVersion(t, s, kept).ObjectName); err != nil {
+		t.Errorf("referenced object was deleted: %v", err)
+	}
+
+	// 分片记录删除后客户端会重新上传该分片
+	if parts, err := s.fileDAO.ListParts(pendingVersion.ID); err != nil || len(parts) != 0 {
+		t.Errorf("ListParts() after apply = %+v, %v, want none", parts, err)
+	}
+	findings, err := s.fileDAO.ListOpenFindings([]int64{lost.ID})
+	if err != nil {
+		t.Fatalf("ListOpenFindings() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Problem != model.ScrubMissing {
+		t.Errorf("findings of the lost file = %+v, want one %s", findings, model.ScrubMissing)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"fmt"
+	"time"
+)
+
+// UsageReporter 维护用户已用空间，由用户服务实现
+// 已用空间 = 已完成版本的大小之和 + 上传中版本预留的空间之和
+type UsageReporter interface {
+	UpdateUsage(ctx context.Context, userID int64, delta int64) error
+	ReserveSpace(ctx context.Context, userID int64, size int64) (bool, error)
+	SetUsage(ctx context.Context, userID int64, expected, usedSpace int64) (bool, error)
+	ListUsage(ctx context.Context, afterID int64, limit int) ([]model.UserUsage, error)
+}
+
+// usagePageSize 对账时每次从用户服务读取的用户数
+const usagePageSize = 500
+
+// reportUsage 回写用户已用空间，失败只记录日志，偏差由对账任务修正
+func (s *StorageService) reportUsage(ctx context.Context, userID, delta int64) {
+	if s.usage == nil || userID == 0 || delta == 0 {
+		return
+	}
+	if err := s.usage.UpdateUsage(ctx, userID, delta); err != nil {
+		utils.Error("[Usage] 用户=%d 空间回写失败, delta=%d: %v", userID, delta, err)
+	}
+}
+
+// reserveSpace 向用户服务预留空间，剩余容量不足时返回错误
+// 返回实际预留的大小，未配置用户服务或没有归属用户时不预留
+func (s *StorageService) reserveSpace(ctx context.Context, userID, size int64) (int64, error) {
+	if s.usage == nil || userID == 0 || size <= 0 {
+		return 0, nil
+	}
+	ok, err := s.usage.ReserveSpace(ctx, userID, size)
+	if err != nil {
+		return 0, fmt.Errorf("预留空间失败: %v", err)
+	}
+	if !ok {
+		return 0, fmt.Errorf("用户空间不足: 需要 %d 字节", size)
+	}
+	return size, nil
+}
+
+// versionUsage 版本占用的用户空间：已完成的按实际大小，上传中的按预留大小
+func versionUsage(version *model.FileVersion) int64 {
+	if version.Status == 1 {
+		return version.Size
+	}
+	return version.Reserved
+}
+
+// ReconcileUsage 根据文件表重新计算每个用户的已用空间，与用户服务记录的不一致时写回
+// 用户服务记录的已用空间在统计之前读取，写回时只在其未变化时生效，
+// 统计期间有预留或回写的用户留到下次对账；文件表中没有版本的用户已用空间清零
+// 返回写回成功的用户数
+func (s *StorageService) ReconcileUsage(ctx context.Context) (int, error) {
+	if s.usage == nil {
+		return 0, nil
+	}
+	recorded := make(map[int64]int64)
+	var afterID int64
+	for {
+		page, err := s.usage.ListUsage(ctx, afterID, usagePageSize)
+		if err != nil {
+			return 0, fmt.Errorf("读取用户已用空间失败: %v", err)
+		}
+		for _, u := range page {
+			recorded[u.UserID] = u.UsedSpace
+			afterID = u.UserID
+		}
+		if len(page) < usagePageSize {
+			break
+		}
+	}
+	usages, err := s.fileDAO.SumUsageByUser()
+	if err != nil {
+		return 0, fmt.Errorf("统计用户已用空间失败: %v", err)
+	}
+	actual := make(map[int64]int64, len(usages))
+	for _, u := range usages {
+		actual[u.UserID] = u.UsedSpace
+	}
+	for userID := range recorded {
+		if _, ok := actual[userID]; !ok {
+			actual[userID] = 0
+		}
+	}
+
+	fixed := 0
+	for userID, used := range actual {
+		if userID == 0 || recorded[userID] == used {
+			continue
+		}
+		ok, err := s.usage.SetUsage(ctx, userID, recorded[userID], used)
+		if err != nil {
+			utils.Error("[UsageReconciler] 用户=%d 写回已用空间失败: %v", userID, err)
+			continue
+		}
+		if !ok {
+			utils.Info("[UsageReconciler] 用户=%d 已用空间在对账期间发生变化, 下次再对账", userID)
+			continue
+		}
+		fixed++
+	}
+	return fixed, nil
+}
+
+// StartUsageReconciler 启动后台协程，定期对账用户已用空间
+func (s *StorageService) StartUsageReconciler(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				n, err := s.ReconcileUsage(ctx)
+				if err != nil {
+					utils.Error("[UsageReconciler] 对账失败: %v", err)
+				}
+				if n > 0 {
+					utils.Info("[UsageReconciler] 已对账 %d 个用户", n)
+				}
+			}
+		}
+	}()
+	utils.Info("[UsageReconciler] 已启动, 间隔=%s", interval)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// UploadOffset 按偏移续传的上传状态
+type UploadOffset struct {
+	Offset    int64      // 从第1个分片起连续写入的字节数，下一个分片从这里开始
+	Size      int64      // 上传声明的总大小
+	PartSize  int64      // 续传时每个分片的大小，最后一个分片可以更小
+	ExpiresAt *time.Time // 上传会话的过期时间，为空表示不过期
+	Completed bool       // 没有进行中的上传，文件当前版本已完成
+}
+
+// GetUploadOffset 根据已保存的分片记录计算上传的续传偏移并校验文件归属
+// 只计入从第1个分片起编号连续、且除最后一个分片外大小都等于分片大小的分片，
+// 这样偏移总能换算成下一个分片的编号；没有进行中的上传时返回当前版本并标记为已完成
+func (s *StorageService) GetUploadOffset(userID, fileID int64) (*UploadOffset, error) {
+	file, err := s.ownedFile(userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.fileDAO.GetPendingVersion(fileID)
+	if err != nil {
+		if file.Status != 1 || file.CurrentVersionID == 0 {
+			return nil, fmt.Errorf("文件没有进行中的上传")
+		}
+		if version, err = s.fileDAO.GetVersionByID(file.CurrentVersionID); err != nil {
+			return nil, fmt.Errorf("找不到文件版本: %v", err)
+		}
+		return &UploadOffset{Offset: version.Size, Size: version.Size, PartSize: s.partSize, Completed: true}, nil
+	}
+	if version.Chunked {
+		return nil, fmt.Errorf("内容分块上传不支持按偏移续传")
+	}
+
+	parts, err := s.fileDAO.ListParts(version.ID)
+	if err != nil {
+		return nil, fmt.Errorf("获取分片列表失败: %v", err)
+	}
+	state := &UploadOffset{Size: version.Size, PartSize: s.partSize, ExpiresAt: version.ExpiresAt}
+	for i, p := range parts {
+		if p.PartNumber != i+1 || state.Offset+p.Size > version.Size {
+			break
+		}
+		if p.Size != s.partSize && state.Offset+p.Size != version.Size {
+			break
+		}
+		state.Offset += p.Size
+	}
+	return state, nil
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"expvar"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"time"
+)
+
+// 完整性校验的运行指标，通过 /debug/vars 暴露
+var scrubMetrics = expvar.NewMap("scrub")
+
+// ScrubResult 一轮完整性校验的结果
+type ScrubResult struct {
+	Files    int // 校验的文件数
+	Corrupt  int // 发现问题的文件数
+	Findings int // 发现的问题数
+}
+
+// CorruptedFile 有未解决问题的文件
+type CorruptedFile struct {
+	File     *model.File
+	Findings []model.ScrubFinding
+}
+
+// ScrubFile 立即校验一个文件的所有已完成版本，返回其未解决的问题
+func (s *StorageService) ScrubFile(ctx context.Context, fileID int64) ([]model.ScrubFinding, error) {
+	file, err := s.internalFile(fileID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.scrubFile(ctx, file); err != nil {
+		return nil, err
+	}
+	return s.fileDAO.ListOpenFindings([]int64{fileID})
+}
+
+// ListCorruptedFiles 按文件ID分页列出有未解决问题的文件
+func (s *StorageService) ListCorruptedFiles(ctx context.Context, cursor string, limit int) ([]CorruptedFile, string, error) {
+	afterID, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	ids, err := s.fileDAO.ListCorruptedFileIDs(afterID, limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("查询问题文件失败: %v", err)
+	}
+	next := ""
+	if len(ids) > limit {
+		ids = ids[:limit]
+		next = encodeCursor(ids[limit-1])
+	}
+	findings, err := s.fileDAO.ListOpenFindings(ids)
+	if err != nil {
+		return nil, "", fmt.Errorf("查询校验记录失败: %v", err)
+	}
+	byFile := make(map[int64][]model.ScrubFinding, len(ids))
+	for _, f := range findings {
+		byFile[f.FileID] = append(byFile[f.FileID], f)
+	}
+
+	result := make([]CorruptedFile, 0, len(ids))
+	for _, id := range ids {
+		file, err := s.fileDAO.GetFileByID(id)
+		if err != nil {
+			continue
+		}
+		result = append(result, CorruptedFile{File: file, Findings: byFile[id]})
+	}
+	return result, next, nil
+}
+
+// ScrubPending 校验最多 limit 个从未校验或上次校验早于 rescan 之前的文件
+func (s *StorageService) ScrubPending(ctx context.Context, rescan time.Duration, limit int) (ScrubResult, error) {
+	var result ScrubResult
+	files, err := s.fileDAO.ListFilesToScrub(time.Now().Add(-rescan), limit)
+	if err != nil {
+		return result, fmt.Errorf("查询待校验文件失败: %v", err)
+	}
+	for i := range files {
+		findings, err := s.scrubFile(ctx, &files[i])
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			// 存储暂时不可用等原因无法完成校验时不记录结果，下一轮重试
+			scrubMetrics.Add("errors", 1)
+			utils.Error("[Scrub] 文件=%d 校验失败: %v", files[i].ID, err)
+			continue
+		}
+		result.Files++
+		result.Findings += findings
+		if findings > 0 {
+			result.Corrupt++
+		}
+	}
+	return result, nil
+}
+
+// scrubFile 校验文件的所有已完成版本并记录结果，返回发现的问题数
+func (s *StorageService) scrubFile(ctx context.Context, file *model.File) (int, error) {
+	versions, err := s.fileDAO.ListVersions(file.ID)
+	if err != nil {
+		return 0, fmt.Errorf("查询版本失败: %v", err)
+	}
+	var findings []model.ScrubFinding
+	for i := range versions {
+		if versions[i].Status != 1 {
+			continue
+		}
+		found, err := s.scrubVersion(ctx, &versions[i])
+		if err != nil {
+			return 0, err
+		}
+		findings = append(findings, found...)
+	}
+
+	if err := s.fileDAO.SaveScrubResult(file.ID, findings, time.Now()); err != nil {
+		return 0, fmt.Errorf("记录校验结果失败: %v", err)
+	}
+	scrubMetrics.Add("files", 1)
+	scrubMetrics.Add("findings", int64(len(findings)))
+	for _, f := range findings {
+		utils.Warn("[Scrub] 文件=%d 版本=%d %s %s: %s", file.ID, f.VersionID, f.Problem, f.ObjectName, f.Detail)
+	}
+	return len(findings), nil
+}
+
+// scrubVersion 先确认版本的对象都存在且大小正确，再读取全部内容核对大小和校验值
+// 存储返回对象不存在以外的错误时返回 error，不作为问题记录
+func (s *StorageService) scrubVersion(ctx context.Context, version *model.FileVersion) ([]model.ScrubFinding, error) {
+	var findings []model.ScrubFinding
+	found := func(problem, objectName, format string, args ...interface{}) {
+		findings = append(findings, model.ScrubFinding{
+			VersionID:  version.ID,
+			ObjectName: objectName,
+			Problem:    problem,
+			Detail:     fmt.Sprintf(format, args...),
+		})
+	}
+
+	if version.Chunked {
+		if err := s.statChunks(ctx, version, found); err != nil {
+			return nil, err
+		}
+	} else {
+		info, err := s.store.Stat(ctx, version.ObjectName)
+		switch {
+		case errors.Is(err, blobstore.ErrNotFound):
+			found(model.ScrubMissing, version.ObjectName, "对象不存在")
+		case err != nil:
+			return nil, fmt.Errorf("查询对象 %s 失败: %v", version.ObjectName, err)
+		case version.Encryption.KeyID == 0 && version.Compression == "" && info.Size != version.Size:
+			// 加密和压缩的对象大小与明文不同，由读取时核对
+			found(model.ScrubSizeMismatch, version.ObjectName, "对象大小 %d，记录为 %d", info.Size, version.Size)
+		}
+	}
+	if len(findings) > 0 {
+		return findings, nil
+	}
+
+	parts, err := s.fileDAO.ListParts(version.ID)
+	if err != nil {
+		return nil, fmt.Errorf("查询分片失败: %v", err)
+	}
+	var partTotal int64
+	for _, p := range parts {
+		partTotal += p.Size
+	}
+	if len(parts) > 0 && partTotal != version.Size {
+		found(model.ScrubSizeMismatch, version.ObjectName, "分片大小之和 %d，记录为 %d", partTotal, version.Size)
+	}
+
+	object, err := s.openVersion(ctx, version, 0, 0)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		found(model.ScrubUnreadable, version.ObjectName, "%v", err)
+		return findings, nil
+	}
+	digests := newPartDigests(parts)
+	shaStr, md5Str, size, err := hashReader(io.TeeReader(object, digests))
+	object.Close()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		found(model.ScrubUnreadable, version.ObjectName, "%v", err)
+		return findings, nil
+	}
+
+	switch {
+	case size != version.Size:
+		found(model.ScrubSizeMismatch, version.ObjectName, "内容大小 %d，记录为 %d", size, version.Size)
+	case version.Sha256 != "" && shaStr != version.Sha256:
+		found(model.ScrubChecksumMismatch, version.ObjectName, "SHA-256 为 %s，记录为 %s%s", shaStr, version.Sha256, digests.mismatches(version))
+	case version.Md5 != "" && md5Str != version.Md5:
+		found(model.ScrubChecksumMismatch, version.ObjectName, "MD5 为 %s，记录为 %s%s", md5Str, version.Md5, digests.mismatches(version))
+	}
+	return findings, nil
+}
+
+// statChunks 确认分块存储的版本引用的分块都有记录、对象存在且未加密的分块大小正确
+func (s *StorageService) statChunks(ctx context.Context, version *model.FileVersion, found func(problem, objectName, format string, args ...interface{})) error {
+	entries, err := s.fileDAO.ListBlobManifest(version.BlobID)
+	if err != nil {
+		return fmt.Errorf("查询分块清单失败: %v", err)
+	}
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.ChunkID)
+	}
+	chunks, err := s.fileDAO.GetChunks(ids)
+	if err != nil {
+		return fmt.Errorf("查询分块失败: %v", err)
+	}
+	byID := make(map[int64]*model.Chunk, len(chunks))
+	for i := range chunks {
+		byID[chunks[i].ID] = &chunks[i]
+	}
+
+	checked := make(map[int64]bool, len(chunks))
+	for _, e := range entries {
+		chunk := byID[e.ChunkID]
+		if chunk == nil {
+			found(model.ScrubMissing, "", "分块 %s 没有记录", e.Sha256)
+			continue
+		}
+		if checked[chunk.ID] {
+			continue
+		}
+		checked[chunk.ID] = true
+		info, err := s.store.Stat(ctx, chunk.ObjectName)
+		switch {
+		case errors.Is(err, blobstore.ErrNotFound):
+			found(model.ScrubMissing, chunk.ObjectName, "分块 %s 的对象不存在", chunk.Sha256)
+		case err != nil:
+			return fmt.Errorf("查询分块 %s 失败: %v", chunk.ObjectName, err)
+		case chunk.Encryption.KeyID == 0 && info.Size != chunk.Size:
+			found(model.ScrubSizeMismatch, chunk.ObjectName, "分块 %s 的对象大小 %d，记录为 %d", chunk.Sha256, info.Size, chunk.Size)
+		}
+	}
+	return nil
+}
+
+// partDigests 按分片边界分段计算内容的 MD5，用于在整体校验失败时定位出错的分片
+type partDigests struct {
+	parts  []model.FilePart
+	sums   []string
+	index  int
+	remain int64
+	hash   hash.Hash
+}
+
+func newPartDigests(parts []model.FilePart) *partDigests {
+	d := &partDigests{parts: parts, hash: md5.New()}
+	if len(parts) > 0 {
+		d.remain = parts[0].Size
+	}
+	return d
+}
+
+func (d *partDigests) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 && d.index < len(d.parts) {
+		k := int(min(int64(len(p)), d.remain))
+		d.hash.Write(p[:k])
+		p = p[k:]
+		d.remain -= int64(k)
+		if d.remain == 0 {
+			d.sums = append(d.sums, hex.EncodeToString(d.hash.Sum(nil)))
+			d.hash.Reset()
+			d.index++
+			if d.index < len(d.parts) {
+				d.remain = d.parts[d.index].Size
+			}
+		}
+	}
+	return n, nil
+}
+
+// mismatches 列出内容与上传时 ETag 不一致的分片
+// 只有未加密、未压缩的分片的 ETag 是明文的 MD5，其他情况无法比较
+func (d *partDigests) mismatches(version *model.FileVersion) string {
+	if version.Encryption.KeyID != 0 || version.Compression != "" {
+		return ""
+	}
+	var bad []string
+	for i, sum := range d.sums {
+		etag := strings.Trim(d.parts[i].ETag, `"`)
+		if len(etag) == 32 && etag != sum {
+			bad = append(bad, fmt.Sprint(d.parts[i].PartNumber))
+		}
+	}
+	if len(bad) == 0 {
+		return ""
+	}
+	return "，与 ETag 不一致的分片: " + strings.Join(bad, ", ")
+}
+
+// StartScrubber 启动后台协程，每隔 interval 校验一批从未校验或上次校验早于 rescan 之前的文件
+func (s *StorageService) StartScrubber(ctx context.Context, rescan, interval time.Duration, batch int) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result, err := s.ScrubPending(ctx, rescan, batch)
+				scrubMetrics.Add("runs", 1)
+				if err != nil {
+					scrubMetrics.Add("errors", 1)
+					utils.Error("[Scrub] 完整性校验失败: %v", err)
+				}
+				if result.Corrupt > 0 {
+					utils.Warn("[Scrub] 校验 %d 个文件，%d 个文件发现 %d 个问题", result.Files, result.Corrupt, result.Findings)
+				} else if result.Files > 0 {
+					utils.Info("[Scrub] 校验 %d 个文件，未发现问题", result.Files)
+				}
+			}
+		}
+	}()
+	utils.Info("[Scrub] 已启动, 间隔=%s, 每批=%d, 重新校验周期=%s", interval, batch, rescan)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"bytes"
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/model"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// currentVersion 返回文件的当前版本
+func currentVersion(t *testing.T, s *StorageService, file *model.File) *model.FileVersion {
+	t.Helper()
+	version, err := s.fileDAO.GetVersionByID(file.CurrentVersionID)
+	if err != nil {
+		t.Fatalf("GetVersionByID() error = %v", err)
+	}
+	return version
+}
+
+// putObject 用 data 覆盖存储中的对象
+func putObject(t *testing.T, store *blobstore.MemoryStore, key string, data []byte) {
+	t.Helper()
+	if _, err := store.Put(context.Background(), key, bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("Put(%s) error = %v", key, err)
+	}
+}
+
+func TestStorageService_Scrub(t *testing.T) {
+	s, _, store := newTestService(t)
+	ctx := context.Background()
+
+	upload(t, s, 1, 0, "ok.txt", randomData(1, 1000), "")
+
+	missingData := randomData(2, 1000)
+	missing := upload(t, s, 1, 0, "missing.txt", missingData, "")
+	missingVersion := currentVersion(t, s, missing)
+	if err := store.Delete(ctx, missingVersion.ObjectName); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	truncated := upload(t, s, 1, 0, "truncated.txt", randomData(3, 1000), "")
+	putObject(t, store, currentVersion(t, s, truncated).ObjectName, randomData(3, 999))
+
+	shaCorrupt := upload(t, s, 1, 0, "sha.txt", randomData(4, 1000), "")
+	putObject(t, store, currentVersion(t, s, shaCorrupt).ObjectName, randomData(40, 1000))
+
+	// 早期的版本没有记录 SHA-256，只能用 MD5 发现内容被改动
+	md5Corrupt := upload(t, s, 1, 0, "md5.txt", randomData(5, 1000), "")
+	md5Version := currentVersion(t, s, md5Corrupt)
+	md5Version.Sha256 = ""
+	if err := s.fileDAO.UpdateVersion(md5Version); err != nil {
+		t.Fatalf("UpdateVersion() error = %v", err)
+	}
+	putObject(t, store, md5Version.ObjectName, randomData(50, 1000))
+
+	result, err := s.ScrubPending(ctx, 0, 100)
+	if err != nil {
+		t.Fatalf("ScrubPending() error = %v", err)
+	}
+	if result.Files != 5 || result.Corrupt != 4 || result.Findings != 4 {
+		t.Fatalf("ScrubPending() = %+v, want 5 files, 4 corrupt, 4 findings", result)
+	}
+
+	want := map[int64]struct{ problem, detail string }{
+		missing.ID:    {model.ScrubMissing, ""},
+		truncated.ID:  {model.ScrubSizeMismatch, "999"},
+		shaCorrupt.ID: {model.ScrubChecksumMismatch, "SHA-256"},
+		md5Corrupt.ID: {model.ScrubChecksumMismatch, "MD5"},
+	}
+	var corrupted []CorruptedFile
+	cursor := ""
+	for {
+		page, next, err := s.ListCorruptedFiles(ctx, cursor, 3)
+		if err != nil {
+			t.Fatalf("ListCorruptedFiles() error = %v", err)
+		}
+		corrupted = append(corrupted, page...)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if len(corrupted) != len(want) {
+		t.Fatalf("ListCorruptedFiles() returned %d files, want %d", len(corrupted), len(want))
+	}
+	for _, c := range corrupted {
+		w, ok := want[c.File.ID]
+		if !ok {
+			t.Errorf("file %s listed as corrupted", c.File.FileName)
+			continue
+		}
+		if len(c.Findings) != 1 {
+			t.Errorf("%s has %d findings, want 1", c.File.FileName, len(c.Findings))
+			continue
+		}
+		f := c.Findings[0]
+		if f.Problem != w.problem || !strings.Contains(f.Detail, w.detail) || f.VersionID != c.File.CurrentVersionID {
+			t.Errorf("%s finding = %+v, want %s mentioning %q", c.File.FileName, f, w.problem, w.detail)
+		}
+	}
+
+	// 已校验过的文件在重新校验周期内不再校验
+	if result, err := s.ScrubPending(ctx, time.Hour, 100); err != nil || result.Files != 0 {
+		t.Errorf("ScrubPending() again = %+v, %v, want no files", result, err)
+	}
+
+	// 对象恢复后立即校验，问题标记为已解决
+	putObject(t, store, missingVersion.ObjectName, missingData)
+	findings, err := s.ScrubFile(ctx, missing.ID)
+	if err != nil || len(findings) != 0 {
+		t.Fatalf("ScrubFile() after repair = %v, %v, want no findings", findings, err)
+	}
+	corrupted, _, err = s.ListCorruptedFiles(ctx, "", 10)
+	if err != nil {
+		t.Fatalf("ListCorruptedFiles() error = %v", err)
+	}
+	for _, c := range corrupted {
+		if c.File.ID == missing.ID {
+			t.Error("repaired file still listed as corrupted")
+		}
+	}
+	if len(corrupted) != 3 {
+		t.Errorf("ListCorruptedFiles() after repair returned %d files, want 3", len(corrupted))
+	}
+}
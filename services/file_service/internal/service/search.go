@@ -0,0 +1,79 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/model"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+)
+
+// encodeCursor 把最后一条记录的ID编码为不透明的游标
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// decodeCursor 解析游标，空游标表示从头开始
+func decodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("游标不合法")
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("游标不合法")
+	}
+	return id, nil
+}
+
+// ListFiles 按上传时间倒序列出用户的所有文件
+func (s *StorageService) ListFiles(ctx context.Context, userID int64, cursor string, limit int) ([]model.File, string, error) {
+	return s.SearchFiles(ctx, userID, model.FileFilter{}, cursor, limit)
+}
+
+// SearchFiles 按条件搜索用户的文件，返回下一页的游标，没有更多数据时游标为空
+func (s *StorageService) SearchFiles(ctx context.Context, userID int64, filter model.FileFilter, cursor string, limit int) ([]model.File, string, error) {
+	afterID, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if filter.MaxSize > 0 && filter.MinSize > filter.MaxSize {
+		return nil, "", fmt.Errorf("最小大小不能大于最大大小")
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return nil, "", fmt.Errorf("创建时间范围不合法")
+	}
+	if filter.UpdatedAfter != nil && filter.UpdatedBefore != nil && !filter.UpdatedAfter.Before(*filter.UpdatedBefore) {
+		return nil, "", fmt.Errorf("更新时间范围不合法")
+	}
+	if filter.MtimeAfter != nil && filter.MtimeBefore != nil && !filter.MtimeAfter.Before(*filter.MtimeBefore) {
+		return nil, "", fmt.Errorf("修改时间范围不合法")
+	}
+	if len(filter.Tags) > maxTagsPerFile {
+		return nil, "", fmt.Errorf("最多按 %d 个标签搜索", maxTagsPerFile)
+	}
+
+	// 多查一条用于判断是否还有下一页
+	files, err := s.fileDAO.SearchFiles(userID, filter, afterID, limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("查询文件失败: %v", err)
+	}
+	next := ""
+	if len(files) > limit {
+		files = files[:limit]
+		next = encodeCursor(files[limit-1].ID)
+	}
+	if err := s.attachTags(files); err != nil {
+		return nil, "", err
+	}
+	return files, next, nil
+}
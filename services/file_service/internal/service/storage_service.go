@@ -2,31 +2,48 @@ package service
 
 import (
 	"bytes"
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/compression"
+	"cloud-storage-file-service/internal/fetch"
 	"cloud-storage-file-service/internal/model"
 	"cloud-storage-file-service/utils"
 
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
 	"time"
-
-	"github.com/minio/minio-go/v7"
 )
 
+// ErrPresignUnsupported 文件的存储方式或存储后端不支持预签名下载，只能经由服务还原后下载
+var ErrPresignUnsupported = errors.New("不支持预签名下载")
+
 type StorageService struct {
-	client   *minio.Client
-	bucket   string
-	fileDAO  model.FileDAO
-	partSize int64 // 分片大小（以字节为单位）
+	store     blobstore.BlobStore // 对象存储后端
+	bucket    string
+	fileDAO   model.FileDAO
+	partSize  int64         // 分片大小（以字节为单位）
+	usage     UsageReporter // 用户已用空间回写，可为空
+	uploadTTL time.Duration // 上传会话的有效期，不大于0表示不过期
+	keys      *keyring      // 加密密钥，为空时不加密
+	indexes   *indexCache   // 压缩对象的块索引缓存
+
+	previews    previewOptions // 缩略图和文本预览的生成参数
+	derivations chan int64     // 待生成缩略图和预览的 Blob
+	extract     extractLimits  // 服务端解压的限制
+
+	fetcher *fetch.Client  // 从URL导入时下载远程文件
+	imports importRegistry // 从URL导入的任务
 }
 
 // NewStorageService 创建一个新的 StorageService 实例
 //
 // 参数：
-//   - client: *minio.Client 类型，指向一个 MinIO 客户端实例
+//   - store: blobstore.BlobStore 类型，对象存储后端（MinIO、本地磁盘或内存）
 //   - bucket: string 类型，存储桶的名称
 //   - dao: model.FileDAO 类型，文件数据访问对象
 //
@@ -35,13 +52,19 @@ type StorageService struct {
 //
 // 备注：
 //   - 默认分片大小为5MB，符合MinIO要求
-func NewStorageService(client *minio.Client, bucket string, dao model.FileDAO) *StorageService {
+func NewStorageService(store blobstore.BlobStore, bucket string, dao model.FileDAO) *StorageService {
 	return &StorageService{
-		client:  client,
+		store:   store,
 		bucket:  bucket,
 		fileDAO: dao,
 		// 默认分片大小为5MB，符合MinIO要求
 		partSize: 5 * 1024 * 1024,
+		indexes:  newIndexCache(1024),
+
+		previews:    defaultPreviewOptions(),
+		derivations: make(chan int64, 256),
+		extract:     defaultExtractLimits(),
+		fetcher:     fetch.NewClient(false, 0),
 	}
 }
 
@@ -53,6 +76,11 @@ func (s *StorageService) SetPartSize(size int64) {
 	s.partSize = size
 }
 
+// SetUsageReporter 设置用户已用空间的回写方式
+func (s *StorageService) SetUsageReporter(r UsageReporter) {
+	s.usage = r
+}
+
 // GetPartSize 获取分片大小
 func (s *StorageService) GetPartSize() int64 {
 	return s.partSize
@@ -65,66 +93,121 @@ func (s *StorageService) UploadFileDirectly(ctx context.Context, fileName string
 	if md5Str != clientMD5 {
 		return nil, fmt.Errorf("数据校验失败")
 	}
-	objectName := fmt.Sprintf("uploads/%s", fileName)
-
-	info, err := s.client.PutObject(ctx, s.bucket, objectName, bytes.NewReader(data), -1, minio.PutObjectOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("上传文件失败: %v", err)
-	}
+	shaSum := sha256.Sum256(data)
+	shaStr := hex.EncodeToString(shaSum[:])
 
 	file := &model.File{
-		FileName:   fileName,
-		Bucket:     s.bucket,
-		ObjectName: objectName,
-		Size:       info.Size,
-		Md5:        info.ETag,
-		Status:     1, // 已完成
-		CreatedAt:  time.Now(),
+		FileName:  fileName,
+		Bucket:    s.bucket,
+		Size:      int64(len(data)),
+		Md5:       md5Str,
+		Sha256:    shaStr,
+		MimeType:  utils.MimeTypeByName(fileName),
+		Status:    0, // 上传中
+		CreatedAt: time.Now(),
 	}
-
 	if err := s.fileDAO.CreateFile(file); err != nil {
 		return nil, fmt.Errorf("创建文件记录失败: %v", err)
 	}
-	return file, nil
+
+	view, err := s.startVersion(ctx, file, file.Size, md5Str, shaStr, uploadOptions{direct: true})
+	if err != nil {
+		return nil, err
+	}
+	if view.Status == 1 {
+		return view, nil
+	}
+	version, err := s.pendingVersion(file.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := s.encryptStream(version.Encryption, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.store.Put(ctx, version.ObjectName, reader, file.Size); err != nil {
+		return nil, fmt.Errorf("上传文件失败: %v", err)
+	}
+	s.recordMimeType(version, data[:min(len(data), utils.SniffLen)])
+	if err := s.finishVersion(ctx, file.UserID, version, file.Size, md5Str, shaStr); err != nil {
+		return nil, err
+	}
+	return s.fileDAO.GetFileByID(file.ID)
 }
 
 // InitUpload 初始化上传
-func (s *StorageService) InitUpload(ctx context.Context, fileName string, size int64, md5 string, userID int64) (*model.File, error) {
-	existedFile, err := s.fileDAO.GetFileByMD5(md5)
-	if err == nil && existedFile != nil {
-		file := &model.File{
-			FileName:   fileName,
-			Bucket:     s.bucket,
-			ObjectName: existedFile.ObjectName,
-			Size:       size,
-			Md5:        md5,
-			Status:     1, // 已完成
-			CreatedAt:  time.Now(),
-			UserID:     userID,
+// 目录下已有同名文件时不新建文件，而是为其创建一个新版本，返回的文件信息反映本次上传
+// 提供 sha256 时，内容已存在的文件会直接秒传完成
+// codec 为存储时的压缩方式（zstd、gzip），为空或文件类型本身已压缩时不压缩
+// meta 中的修改时间记录在本次上传的版本上，标签立即设置到文件上
+func (s *StorageService) InitUpload(ctx context.Context, fileName string, size int64, md5, sha256 string, userID int64, folderID int64, codec string, meta FileMeta) (*model.File, error) {
+	if err := validateName(fileName); err != nil {
+		return nil, err
+	}
+	if !compression.Valid(codec) {
+		return nil, fmt.Errorf("不支持的压缩方式: %s", codec)
+	}
+	if err := validateTags(meta.Tags, nil); err != nil {
+		return nil, err
+	}
+	if !compression.Compressible(utils.MimeTypeByName(fileName)) {
+		codec = ""
+	}
+	return s.initUpload(ctx, fileName, size, md5, sha256, userID, folderID, uploadOptions{codec: codec, meta: meta})
+}
+
+// initUpload 在目录下新建文件或为同名文件创建新版本，并开始上传
+func (s *StorageService) initUpload(ctx context.Context, fileName string, size int64, md5, sha256 string, userID, folderID int64, opts uploadOptions) (*model.File, error) {
+	if _, err := s.checkFolder(userID, folderID); err != nil {
+		return nil, err
+	}
+
+	if existing, err := s.fileDAO.GetFileByName(userID, folderID, fileName); err == nil {
+		if err := s.applyTags(existing, opts.meta.Tags, nil); err != nil {
+			return nil, err
 		}
-		if err := s.fileDAO.CreateFile(file); err != nil {
-			return nil, fmt.Errorf("创建文件记录失败: %v", err)
+		view, err := s.initVersionUpload(ctx, existing, size, md5, sha256, opts)
+		if err != nil {
+			return nil, err
 		}
-		return file, nil
-
+		view.Tags = existing.Tags
+		return view, nil
+	}
+	if err := s.checkNameAvailable(userID, folderID, fileName); err != nil {
+		return nil, err
 	}
-	objectName := fmt.Sprintf("uploads/%s", fileName)
 
 	file := &model.File{
-		FileName:   fileName,
-		Bucket:     s.bucket,
-		ObjectName: objectName,
-		Size:       size,
-		Md5:        md5,
-		Status:     0, // 上传中
-		CreatedAt:  time.Now(),
-		UserID:     userID,
+		FileName:  fileName,
+		Bucket:    s.bucket,
+		Size:      size,
+		Md5:       md5,
+		Sha256:    sha256,
+		MimeType:  utils.MimeTypeByName(fileName),
+		Status:    0, // 上传中
+		CreatedAt: time.Now(),
+		UserID:    userID,
+		FolderID:  folderID,
 	}
 
 	if err := s.fileDAO.CreateFile(file); err != nil {
+		if errors.Is(err, model.ErrNameExists) {
+			return nil, errNameTaken(fileName)
+		}
 		return nil, fmt.Errorf("创建文件记录失败: %v", err)
 	}
-	return file, nil
+	view, err := s.startVersion(ctx, file, size, md5, sha256, opts)
+	if err != nil {
+		// 空间不足等原因无法开始上传时不保留空的文件记录
+		s.fileDAO.DeleteFile(file.ID)
+		return nil, err
+	}
+	if err := s.applyTags(file, opts.meta.Tags, nil); err != nil {
+		utils.Error("[Metadata] 文件=%d 设置标签失败: %v", file.ID, err)
+	}
+	view.Tags = file.Tags
+	return view, nil
 }
 
 // UploadPart 上传分片
@@ -136,28 +219,24 @@ func (s *StorageService) UploadPart(ctx context.Context, fileID int64, partNumbe
 		return fmt.Errorf("数据校验失败")
 	}
 
-	file, err := s.fileDAO.GetFileByID(fileID)
+	version, err := s.pendingVersion(fileID)
 	if err != nil {
-		return fmt.Errorf("找不到文件记录: %v", err)
+		return err
 	}
 
-	partObject := fmt.Sprintf("%s.part.%d", file.ObjectName, partNumber)
-
 	// 直接使用传入的数据进行上传，避免额外的内存分配
-	info, err := s.client.PutObject(ctx, s.bucket, partObject, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{})
+	part, err := s.putPart(ctx, version, partNumber, bytes.NewReader(data), int64(len(data)), md5Str)
 	if err != nil {
-		return fmt.Errorf("上传分片失败: %v", err)
+		return err
 	}
+	part.Md5 = md5Str
+	part.UploadedAt = time.Now()
 
-	part := &model.FilePart{
-		FileID:     file.ID,
-		PartNumber: partNumber,
-		ETag:       info.ETag,
-		Size:       int64(len(data)),
-		UploadedAt: time.Now(),
+	if err := s.fileDAO.SavePart(part); err != nil {
+		return err
 	}
-
-	return s.fileDAO.SavePart(part)
+	s.touchUpload(version)
+	return nil
 }
 
 // UploadPartStream
@@ -169,22 +248,20 @@ func (s *StorageService) UploadPartStream(
 	reader io.Reader,
 	clientMD5 string,
 ) error {
-	// 获取文件记录
-	file, err := s.fileDAO.GetFileByID(fileID)
+	// 获取正在上传的版本
+	version, err := s.pendingVersion(fileID)
 	if err != nil {
-		return fmt.Errorf("找不到文件记录: %v", err)
+		return err
 	}
 
-	partObject := fmt.Sprintf("%s.part.%d", file.ObjectName, partNumber)
-
 	// === 1️⃣ 边读边计算MD5 + 统计总大小 ===
 	hash := md5.New()
 	teeReader := io.TeeReader(reader, hash)
 
-	// 为 PutObject 估算大小（MinIO 要求传入 size，可以用 -1 表示未知大小）
-	info, err := s.client.PutObject(ctx, s.bucket, partObject, teeReader, partSize, minio.PutObjectOptions{})
+	// 客户端提供了 MD5 时存储端也会校验，不一致的分片不会被写入
+	part, err := s.putPart(ctx, version, partNumber, teeReader, partSize, clientMD5)
 	if err != nil {
-		return fmt.Errorf("上传分片失败: %v", err)
+		return err
 	}
 
 	// === 2️⃣ 上传完成后计算 MD5 校验 ===
@@ -194,19 +271,15 @@ func (s *StorageService) UploadPartStream(
 	}
 
 	// === 3️⃣ 保存数据库分片信息 ===
-	part := &model.FilePart{
-		FileID:     file.ID,
-		PartNumber: partNumber,
-		ETag:       info.ETag,
-		UploadedAt: time.Now(),
-	}
-
+	part.Md5 = serverMD5
+	part.UploadedAt = time.Now()
 	if err := s.fileDAO.SavePart(part); err != nil {
 		return fmt.Errorf("保存分片信息失败: %v", err)
 	}
+	s.touchUpload(version)
 
 	utils.Info("[UploadPart] 文件ID=%d 分片=%d 上传完成, MD5校验通过, 大小=%.2fMB",
-		fileID, partNumber, float64(info.Size)/1024/1024)
+		fileID, partNumber, float64(part.Size)/1024/1024)
 
 	return nil
 }
@@ -267,9 +340,26 @@ func (s *StorageService) UploadPartsConcurrentlyWithMD5(ctx context.Context, fil
 	return nil
 }
 
+// ListUploadedParts 列出上传中版本已保存的分片，按编号升序
+func (s *StorageService) ListUploadedParts(fileID int64) ([]model.FilePart, error) {
+	version, err := s.pendingVersion(fileID)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := s.fileDAO.ListParts(version.ID)
+	if err != nil {
+		return nil, fmt.Errorf("查询分片失败: %v", err)
+	}
+	return parts, nil
+}
+
 // 获取未上传的分片
 func (s *StorageService) GetIncompleteParts(ctx context.Context, fileID int64, totalParts int) ([]int, error) {
-	uploadedParts, err := s.fileDAO.ListParts(fileID)
+	version, err := s.pendingVersion(fileID)
+	if err != nil {
+		return nil, err
+	}
+	uploadedParts, err := s.fileDAO.ListParts(version.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -291,139 +381,182 @@ func (s *StorageService) GetIncompleteParts(ctx context.Context, fileID int64, t
 // 完成分片上传
 func (s *StorageService) UploadComplete(ctx context.Context, fileID int64) error {
 	file, err := s.fileDAO.GetFileByID(fileID)
+	if err != nil {
+		return fmt.Errorf("找不到文件记录: %v", err)
+	}
+	version, err := s.pendingVersion(fileID)
 	if err != nil {
 		return err
 	}
+	if version.Chunked {
+		return s.completeChunked(ctx, file, version)
+	}
 
-	parts, err := s.fileDAO.ListParts(fileID)
+	parts, err := s.fileDAO.ListParts(version.ID)
 	if err != nil {
 		return err
 	}
 
-	var srcs []minio.CopySrcOptions
-	for _, p := range parts {
-		src := minio.CopySrcOptions{
-			Bucket: s.bucket,
-			Object: fmt.Sprintf("%s.part.%d", file.ObjectName, p.PartNumber),
-		}
-		srcs = append(srcs, src)
+	if len(parts) == 0 {
+		return fmt.Errorf("没有已上传的分片")
 	}
 
-	dst := minio.CopyDestOptions{
-		Bucket: s.bucket,
-		Object: file.ObjectName,
+	if err := s.completeParts(ctx, version, parts); err != nil {
+		return err
 	}
 
-	// 使用正确的 ComposeObject 调用格式：上下文、目标、多个源对象
-	if _, err := s.client.ComposeObject(ctx, dst, srcs...); err != nil {
-		return fmt.Errorf("合并分片失败: %v", err)
+	// 服务端重新计算校验值，客户端声明了 SHA-256 时必须一致
+	shaStr, md5Str, size, err := s.sealUploadedObject(ctx, version, parts)
+	if err != nil {
+		return err
+	}
+	if version.Sha256 != "" && version.Sha256 != shaStr {
+		s.store.Delete(ctx, version.ObjectName)
+		return fmt.Errorf("文件校验失败: client=%s, server=%s", version.Sha256, shaStr)
 	}
 
-	return s.fileDAO.UpdateFileStatus(fileID, 1)
+	return s.finishVersion(ctx, file.UserID, version, size, md5Str, shaStr)
 }
 
 // 下载文件到Writer
-func (s *StorageService) DownloadFileToWriter(ctx context.Context, fileID int64, w io.Writer) error {
-	file, err := s.fileDAO.GetFileByID(fileID)
+// versionID 为0时下载当前版本
+func (s *StorageService) DownloadFileToWriter(ctx context.Context, fileID, versionID int64, w io.Writer) error {
+	version, err := s.resolveVersion(fileID, versionID)
 	if err != nil {
 		return err
 	}
-	parts, err := s.fileDAO.ListParts(fileID)
+	object, err := s.openVersion(ctx, version, 0, 0)
 	if err != nil {
-		return err
+		return fmt.Errorf("下载文件失败: %v", err)
 	}
-	for _, p := range parts {
-		partObjectName := fmt.Sprintf("%s.part.%d", file.ObjectName, p.PartNumber)
-		object, err := s.client.GetObject(ctx, s.bucket, partObjectName, minio.GetObjectOptions{})
-		if err != nil {
-			return fmt.Errorf("下载分片失败: %v", err)
-		}
-		_, err = io.Copy(w, object)
-		object.Close()
-		if err != nil {
-			return fmt.Errorf("写入分片失败: %v", err)
-		}
+	defer object.Close()
+	if _, err := io.Copy(w, object); err != nil {
+		return fmt.Errorf("写入文件失败: %v", err)
 	}
 	return nil
 }
 
 // 下载文件的指定分片
-func (s *StorageService) DownloadFileWithOffset(ctx context.Context, fileID int64, startPart int, startOffset int64, w io.Writer) error {
-	file, err := s.fileDAO.GetFileByID(fileID)
+// versionID 为0时下载当前版本
+func (s *StorageService) DownloadFileWithOffset(ctx context.Context, fileID, versionID int64, startPart int, startOffset int64, w io.Writer) error {
+	version, err := s.resolveVersion(fileID, versionID)
 	if err != nil {
-		return fmt.Errorf("获取文件失败: %v", err)
+		return err
 	}
-
-	parts, err := s.fileDAO.ListParts(fileID)
+	chunk, offset, err := s.versionChunk(version, startPart)
 	if err != nil {
-		return fmt.Errorf("获取分片列表失败: %v", err)
+		return err
+	}
+	if startOffset < 0 || startOffset > chunk.Size {
+		return fmt.Errorf("读取范围不合法: offset=%d", startOffset)
 	}
 
-	for _, p := range parts {
-		if p.PartNumber < startPart {
-			continue
-		}
+	object, err := s.openVersion(ctx, version, offset+startOffset, 0)
+	if err != nil {
+		return fmt.Errorf("下载分片失败: %v", err)
+	}
+	defer object.Close()
+	if _, err := io.Copy(w, object); err != nil {
+		return fmt.Errorf("写入分片失败: %v", err)
+	}
+	return nil
+}
 
-		objName := fmt.Sprintf("%s.part.%d", file.ObjectName, p.PartNumber)
-		opts := minio.GetObjectOptions{}
-		if p.PartNumber == startPart && startOffset > 0 {
-			opts.SetRange(startOffset, 0)
-		}
+// 下载分片数据，把分片从 startOffset 开始的 length 字节写入 w，length 为0时读到分片末尾
+// versionID 为0时下载当前版本，返回写入的字节数和分片上传时记录的 ETag
+// 分片数据从版本对象中按字节范围读取
+func (s *StorageService) DownloadChunk(ctx context.Context, fileID, versionID int64, chunkIndex int, startOffset, length int64, w io.Writer) (int64, string, error) {
+	if startOffset < 0 || length < 0 {
+		return 0, "", fmt.Errorf("读取范围不合法: offset=%d, length=%d", startOffset, length)
+	}
+	version, err := s.resolveVersion(fileID, versionID)
+	if err != nil {
+		return 0, "", err
+	}
+	chunk, offset, err := s.versionChunk(version, chunkIndex)
+	if err != nil {
+		return 0, "", err
+	}
+	if startOffset > chunk.Size {
+		return 0, "", fmt.Errorf("读取范围不合法: offset=%d, size=%d", startOffset, chunk.Size)
+	}
+	if length == 0 || startOffset+length > chunk.Size {
+		length = chunk.Size - startOffset
+	}
+	if length == 0 {
+		return 0, chunk.ETag, nil
+	}
 
-		object, err := s.client.GetObject(ctx, s.bucket, objName, opts)
-		if err != nil {
-			return fmt.Errorf("下载分片失败: %v", err)
-		}
+	object, err := s.openVersion(ctx, version, offset+startOffset, length)
+	if err != nil {
+		return 0, "", fmt.Errorf("下载分片失败: %v", err)
+	}
+	defer object.Close()
 
-		_, err = io.Copy(w, object)
-		object.Close()
-		if err != nil {
-			return fmt.Errorf("写入分片失败: %v", err)
-		}
+	n, err := io.Copy(w, io.LimitReader(object, length))
+	if err != nil {
+		return n, "", fmt.Errorf("读取分片失败: %v", err)
 	}
 
-	return nil
+	return n, chunk.ETag, nil
 }
 
-// 下载分片数据
-func (s *StorageService) DownloadChunk(ctx context.Context, fileID int64, chunkIndex int, startOffset int64) ([]byte, string, error) {
+// ReadFile 把文件指定版本从 offset 开始的 length 字节写入 w，length 为0时读到末尾
+// versionID 为0时读取当前版本
+func (s *StorageService) ReadFile(ctx context.Context, fileID, versionID, offset, length int64, w io.Writer) error {
 	file, err := s.fileDAO.GetFileByID(fileID)
 	if err != nil {
-		return nil, "", fmt.Errorf("获取文件信息失败: %v", err)
+		return fmt.Errorf("获取文件信息失败: %v", err)
+	}
+	if file.TrashedAt != nil {
+		return fmt.Errorf("文件已在回收站中")
 	}
-	part, err := s.fileDAO.GetPart(fileID, chunkIndex)
+	version, err := s.resolveVersion(fileID, versionID)
 	if err != nil {
-		return nil, "", fmt.Errorf("获取分片信息失败: %v", err)
+		return err
 	}
-
-	objName := fmt.Sprintf("%s.part.%d", file.ObjectName, chunkIndex)
-	opts := minio.GetObjectOptions{}
-	if startOffset > 0 {
-		opts.SetRange(startOffset, 0)
+	if offset < 0 || length < 0 || offset > version.Size {
+		return fmt.Errorf("读取范围不合法: offset=%d, length=%d, size=%d", offset, length, version.Size)
+	}
+	if length == 0 || offset+length > version.Size {
+		length = version.Size - offset
+	}
+	if length == 0 {
+		return nil
 	}
 
-	object, err := s.client.GetObject(ctx, s.bucket, objName, opts)
+	object, err := s.openVersion(ctx, version, offset, length)
 	if err != nil {
-		return nil, "", fmt.Errorf("下载分片失败: %v", err)
+		return fmt.Errorf("读取文件失败: %v", err)
 	}
 	defer object.Close()
 
-	data, err := io.ReadAll(object)
-	if err != nil {
-		return nil, "", fmt.Errorf("读取分片失败: %v", err)
+	if _, err := io.Copy(w, io.LimitReader(object, length)); err != nil {
+		return fmt.Errorf("读取文件失败: %v", err)
 	}
-
-	return data, part.ETag, nil
+	return nil
 }
 
 // 获取上传进度
+// 没有进行中的上传时返回当前版本的进度
 func (s *StorageService) GetUploadProgress(fileID int64) (uploadedSize int64, totalSize int64, err error) {
-	file, err := s.fileDAO.GetFileByID(fileID)
+	version, err := s.fileDAO.GetPendingVersion(fileID)
 	if err != nil {
-		return 0, 0, err
+		file, err := s.fileDAO.GetFileByID(fileID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if version, err = s.fileDAO.GetVersionByID(file.CurrentVersionID); err != nil {
+			return 0, 0, fmt.Errorf("找不到文件版本: %v", err)
+		}
 	}
 
-	parts, err := s.fileDAO.ListParts(fileID)
+	if version.Chunked && version.Status == 0 {
+		uploaded, err := s.chunkedUploadProgress(version)
+		return uploaded, version.Size, err
+	}
+
+	parts, err := s.fileDAO.ListParts(version.ID)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -433,59 +566,54 @@ func (s *StorageService) GetUploadProgress(fileID int64) (uploadedSize int64, to
 		uploaded += p.Size
 	}
 
-	return uploaded, file.Size, nil
+	return uploaded, version.Size, nil
 }
 
-// 删除分片
+// DeleteFile 永久删除文件所有版本的对象、分片和记录，已完成的版本会返还用户空间
 func (s *StorageService) DeleteFile(ctx context.Context, fileID int64) error {
-	parts, err := s.fileDAO.ListParts(fileID)
+	file, err := s.fileDAO.GetFileByID(fileID)
 	if err != nil {
 		return err
 	}
-	file, err := s.fileDAO.GetFileByID(fileID)
+	versions, err := s.fileDAO.ListVersions(fileID)
 	if err != nil {
 		return err
 	}
-	// 删除分片
-	for _, p := range parts {
-		objName := fmt.Sprintf("%s.part.%d", file.ObjectName, p.PartNumber)
-		err := s.client.RemoveObject(ctx, s.bucket, objName, minio.RemoveObjectOptions{})
-		if err != nil {
-			return fmt.Errorf("删除分片 %s 失败: %v", objName, err)
+
+	var freed int64
+	for i := range versions {
+		if err := s.purgeVersion(ctx, &versions[i]); err != nil {
+			return err
 		}
+		freed += versionUsage(&versions[i])
 	}
-	objName := file.ObjectName
-	err = s.client.RemoveObject(ctx, s.bucket, objName, minio.RemoveObjectOptions{})
-	if err != nil {
-		return fmt.Errorf("删除文件 %s 失败: %v", objName, err)
+	if err := s.fileDAO.DeleteFile(fileID); err != nil {
+		return err
 	}
-	// 删除数据库记录
-	return s.fileDAO.DeleteParts(fileID)
+	s.reportUsage(ctx, file.UserID, -freed)
+	return nil
 }
 
 // 上传分片
 func (s *StorageService) uploadPart(ctx context.Context, fileID int64, partNumber int, data []byte) error {
-	file, err := s.fileDAO.GetFileByID(fileID)
+	version, err := s.pendingVersion(fileID)
 	if err != nil {
-		return fmt.Errorf("找不到文件记录: %v", err)
+		return err
 	}
 
-	partObject := fmt.Sprintf("%s.part.%d", file.ObjectName, partNumber)
-
-	info, err := s.client.PutObject(ctx, s.bucket, partObject, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{})
+	part, err := s.putPart(ctx, version, partNumber, bytes.NewReader(data), int64(len(data)), "")
 	if err != nil {
-		return fmt.Errorf("上传分片失败: %v", err)
+		return err
 	}
+	sum := md5.Sum(data)
+	part.Md5 = hex.EncodeToString(sum[:])
+	part.UploadedAt = time.Now()
 
-	part := &model.FilePart{
-		FileID:     file.ID,
-		PartNumber: partNumber,
-		ETag:       info.ETag,
-		Size:       int64(len(data)),
-		UploadedAt: time.Now(),
+	if err := s.fileDAO.SavePart(part); err != nil {
+		return err
 	}
-
-	return s.fileDAO.SavePart(part)
+	s.touchUpload(version)
+	return nil
 }
 
 // GetFileInfo 获取文件信息
@@ -499,6 +627,12 @@ func (s *StorageService) GetFileInfo(ctx context.Context, fileID int64) (*model.
 	if file.Status != 1 {
 		return nil, fmt.Errorf("文件未完成上传或不可用")
 	}
+	if file.TrashedAt != nil {
+		return nil, fmt.Errorf("文件已在回收站中")
+	}
+	if err := s.loadTags(file); err != nil {
+		return nil, err
+	}
 
 	return file, nil
 }
@@ -506,7 +640,10 @@ func (s *StorageService) GetFileInfo(ctx context.Context, fileID int64) (*model.
 func (s *StorageService) FileDAO() model.FileDAO {
 	return s.fileDAO
 }
-func (s *StorageService) GeneratePresignedURL(ctx context.Context, fileID int64, expireSeconds int32) (string, int64, error) {
+
+// GeneratePresignedURL 生成下载链接，versionID 为0时指向当前版本
+// 内容无法直接从存储后端下载时返回包装了 ErrPresignUnsupported 的错误
+func (s *StorageService) GeneratePresignedURL(ctx context.Context, fileID, versionID int64, expireSeconds int32) (string, int64, error) {
 	file, err := s.fileDAO.GetFileByID(fileID)
 	if err != nil {
 		return "", 0, fmt.Errorf("获取文件信息失败: %v", err)
@@ -516,6 +653,23 @@ func (s *StorageService) GeneratePresignedURL(ctx context.Context, fileID int64,
 	if file.Status != 1 {
 		return "", 0, fmt.Errorf("文件尚未完成上传")
 	}
+	if file.TrashedAt != nil {
+		return "", 0, fmt.Errorf("文件已在回收站中")
+	}
+	version, err := s.resolveVersion(fileID, versionID)
+	if err != nil {
+		return "", 0, err
+	}
+	// 加密、压缩或分块存储的内容只能经由文件服务还原后下载
+	if version.Encryption.KeyID != 0 {
+		return "", 0, fmt.Errorf("文件已加密，%w", ErrPresignUnsupported)
+	}
+	if version.Compression != "" {
+		return "", 0, fmt.Errorf("文件为压缩存储，%w", ErrPresignUnsupported)
+	}
+	if version.Chunked {
+		return "", 0, fmt.Errorf("文件为分块存储，%w", ErrPresignUnsupported)
+	}
 
 	// 设置默认过期时间为1小时
 	if expireSeconds <= 0 {
@@ -526,10 +680,17 @@ func (s *StorageService) GeneratePresignedURL(ctx context.Context, fileID int64,
 	expireTime := time.Now().Add(time.Duration(expireSeconds) * time.Second)
 
 	// 生成预签名URL
-	presignedURL, err := s.client.PresignedGetObject(ctx, s.bucket, file.ObjectName, time.Duration(expireSeconds)*time.Second, nil)
+	contentType := version.MimeType
+	if contentType == "" {
+		contentType = file.MimeType
+	}
+	presignedURL, err := s.store.PresignGet(ctx, version.ObjectName, time.Duration(expireSeconds)*time.Second, contentType)
+	if errors.Is(err, blobstore.ErrPresignUnsupported) {
+		return "", 0, fmt.Errorf("存储后端%w", ErrPresignUnsupported)
+	}
 	if err != nil {
 		return "", 0, fmt.Errorf("生成预签名URL失败: %v", err)
 	}
 
-	return presignedURL.String(), expireTime.Unix(), nil
+	return presignedURL, expireTime.Unix(), nil
 }
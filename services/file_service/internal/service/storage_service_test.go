@@ -0,0 +1,399 @@
+package service
+
+import (
+	"bytes"
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/model"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testPartSize 测试使用的分片大小，即允许的最小分片
+const testPartSize = 5 * 1024 * 1024
+
+// fakeUsage 内存中的用户已用空间，代替用户服务
+type fakeUsage struct {
+	mu    sync.Mutex
+	used  map[int64]int64
+	total int64 // 每个用户的容量，0 表示不限制
+}
+
+func newFakeUsage(total int64) *fakeUsage {
+	return &fakeUsage{used: make(map[int64]int64), total: total}
+}
+
+func (f *fakeUsage) UpdateUsage(ctx context.Context, userID int64, delta int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.used[userID] = max(f.used[userID]+delta, 0)
+	return nil
+}
+
+func (f *fakeUsage) ReserveSpace(ctx context.Context, userID int64, size int64) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.total > 0 && f.used[userID]+size > f.total {
+		return false, nil
+	}
+	f.used[userID] += size
+	return true, nil
+}
+
+func (f *fakeUsage) SetUsage(ctx context.Context, userID int64, expected, usedSpace int64) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.used[userID] != expected {
+		return false, nil
+	}
+	f.used[userID] = usedSpace
+	return true, nil
+}
+
+func (f *fakeUsage) ListUsage(ctx context.Context, afterID int64, limit int) ([]model.UserUsage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var usages []model.UserUsage
+	for id, used := range f.used {
+		if id > afterID && used != 0 {
+			usages = append(usages, model.UserUsage{UserID: id, UsedSpace: used})
+		}
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].UserID < usages[j].UserID })
+	if len(usages) > limit {
+		usages = usages[:limit]
+	}
+	return usages, nil
+}
+
+func (f *fakeUsage) get(userID int64) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.used[userID]
+}
+
+// newTestService 使用内存存储后端和内存 SQLite 数据库创建 StorageService
+func newTestService(t *testing.T) (*StorageService, *fakeUsage, *blobstore.MemoryStore) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared&_pragma=busy_timeout(5000)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite error = %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db.DB() error = %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	store := blobstore.NewMemoryStore()
+	s := NewStorageService(store, "test", model.NewFileDAO(db))
+	usage := newFakeUsage(0)
+	s.SetUsageReporter(usage)
+	return s, usage, store
+}
+
+func randomData(seed int64, n int) []byte {
+	data := make([]byte, n)
+	rand.New(rand.NewSource(seed)).Read(data)
+	return data
+}
+
+func md5Of(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func sha256Of(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// upload 按分片上传 data 并完成，codec 为存储时的压缩方式
+func upload(t *testing.T, s *StorageService, userID, folderID int64, name string, data []byte, codec string) *model.File {
+	t.Helper()
+	ctx := context.Background()
+	view, err := s.InitUpload(ctx, name, int64(len(data)), md5Of(data), sha256Of(data), userID, folderID, codec, FileMeta{})
+	if err != nil {
+		t.Fatalf("InitUpload(%q) error = %v", name, err)
+	}
+	if view.Status != 1 {
+		for i := 0; i*testPartSize < len(data) || i == 0; i++ {
+			part := data[i*testPartSize : min((i+1)*testPartSize, len(data))]
+			if err := s.UploadPart(ctx, view.ID, i+1, part, md5Of(part)); err != nil {
+				t.Fatalf("UploadPart(%d) error = %v", i+1, err)
+			}
+		}
+		if err := s.UploadComplete(ctx, view.ID); err != nil {
+			t.Fatalf("UploadComplete(%q) error = %v", name, err)
+		}
+	}
+	file, err := s.GetFileInfo(ctx, view.ID)
+	if err != nil {
+		t.Fatalf("GetFileInfo(%d) error = %v", view.ID, err)
+	}
+	return file
+}
+
+// download 读出文件的某个版本，versionID 为0时读当前版本
+func download(t *testing.T, s *StorageService, fileID, versionID int64) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := s.DownloadFileToWriter(context.Background(), fileID, versionID, &buf); err != nil {
+		t.Fatalf("DownloadFileToWriter(%d, %d) error = %v", fileID, versionID, err)
+	}
+	return buf.Bytes()
+}
+
+func TestStorageService_MultipartRoundTrip(t *testing.T) {
+	s, usage, _ := newTestService(t)
+	data := randomData(1, 2*testPartSize+1234)
+
+	file := upload(t, s, 1, 0, "data.bin", data, "")
+	if file.Status != 1 || file.Size != int64(len(data)) || file.Sha256 != sha256Of(data) {
+		t.Fatalf("file = %+v", file)
+	}
+	if got := download(t, s, file.ID, 0); !bytes.Equal(got, data) {
+		t.Fatalf("downloaded %d bytes, want %d", len(got), len(data))
+	}
+	if got := usage.get(1); got != int64(len(data)) {
+		t.Errorf("used = %d, want %d", got, len(data))
+	}
+}
+
+func TestStorageService_Versions(t *testing.T) {
+	s, usage, _ := newTestService(t)
+	ctx := context.Background()
+	v1 := []byte("first version")
+	v2 := []byte("second version, a bit longer")
+
+	first := upload(t, s, 1, 0, "notes.txt", v1, "")
+	second := upload(t, s, 1, 0, "notes.txt", v2, "")
+	if second.ID != first.ID {
+		t.Fatalf("同名上传应创建新版本, got file %d, want %d", second.ID, first.ID)
+	}
+	_, versions, err := s.ListVersions(ctx, 1, first.ID)
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("len(versions) = %d, want 2", len(versions))
+	}
+	var old model.FileVersion
+	for _, v := range versions {
+		if v.ID != second.CurrentVersionID {
+			old = v
+		}
+	}
+	if got := download(t, s, first.ID, old.ID); !bytes.Equal(got, v1) {
+		t.Errorf("old version = %q, want %q", got, v1)
+	}
+	if got := download(t, s, first.ID, 0); !bytes.Equal(got, v2) {
+		t.Errorf("current version = %q, want %q", got, v2)
+	}
+	if got := usage.get(1); got != int64(len(v1)+len(v2)) {
+		t.Errorf("used = %d, want %d", got, len(v1)+len(v2))
+	}
+
+	if _, err := s.RestoreVersion(ctx, 1, first.ID, old.ID); err != nil {
+		t.Fatalf("RestoreVersion() error = %v", err)
+	}
+	if got := download(t, s, first.ID, 0); !bytes.Equal(got, v1) {
+		t.Errorf("restored version = %q, want %q", got, v1)
+	}
+	if err := s.DeleteVersion(ctx, 1, first.ID, old.ID); err == nil {
+		t.Error("DeleteVersion(current) should fail")
+	}
+	if err := s.DeleteVersion(ctx, 1, first.ID, second.CurrentVersionID); err != nil {
+		t.Fatalf("DeleteVersion() error = %v", err)
+	}
+	if got := usage.get(1); got != int64(len(v1)) {
+		t.Errorf("used after delete = %d, want %d", got, len(v1))
+	}
+	if _, _, err := s.ListVersions(ctx, 2, first.ID); err == nil {
+		t.Error("ListVersions() by another user should fail")
+	}
+	if _, _, err := s.ListVersions(ctx, 0, first.ID); err == nil {
+		t.Error("ListVersions() without a user should fail")
+	}
+}
+
+func TestStorageService_Dedup(t *testing.T) {
+	s, usage, store := newTestService(t)
+	ctx := context.Background()
+	data := randomData(2, 4096)
+	upload(t, s, 1, 0, "a.bin", data, "")
+
+	// 同一用户上传相同内容直接秒传
+	view, err := s.InitUpload(ctx, "b.bin", int64(len(data)), md5Of(data), sha256Of(data), 1, 0, "", FileMeta{})
+	if err != nil {
+		t.Fatalf("InitUpload() error = %v", err)
+	}
+	if view.Status != 1 {
+		t.Errorf("same user upload status = %d, want 1", view.Status)
+	}
+	if got := usage.get(1); got != 2*int64(len(data)) {
+		t.Errorf("used = %d, want %d", got, 2*len(data))
+	}
+
+	// 其他用户只声明哈希不能引用这份内容
+	view, err = s.InitUpload(ctx, "c.bin", int64(len(data)), md5Of(data), sha256Of(data), 2, 0, "", FileMeta{})
+	if err != nil {
+		t.Fatalf("InitUpload() error = %v", err)
+	}
+	if view.Status != 0 {
+		t.Errorf("other user upload status = %d, want 0", view.Status)
+	}
+	if err := s.CancelUpload(ctx, view.ID); err != nil {
+		t.Fatalf("CancelUpload() error = %v", err)
+	}
+
+	objects := 0
+	store.List(ctx, "", func(blobstore.ObjectInfo) error { objects++; return nil })
+	if objects != 1 {
+		t.Errorf("objects = %d, want 1", objects)
+	}
+}
+
+func TestStorageService_QuotaReservation(t *testing.T) {
+	s, usage, _ := newTestService(t)
+	usage.total = 1000
+	ctx := context.Background()
+
+	view, err := s.InitUpload(ctx, "big.bin", 800, "", "", 1, 0, "", FileMeta{})
+	if err != nil {
+		t.Fatalf("InitUpload() error = %v", err)
+	}
+	if got := usage.get(1); got != 800 {
+		t.Errorf("reserved = %d, want 800", got)
+	}
+	if _, err := s.InitUpload(ctx, "more.bin", 300, "", "", 1, 0, "", FileMeta{}); err == nil || !strings.Contains(err.Error(), "空间不足") {
+		t.Errorf("InitUpload() over quota error = %v", err)
+	}
+	if err := s.CancelUpload(ctx, view.ID); err != nil {
+		t.Fatalf("CancelUpload() error = %v", err)
+	}
+	if got := usage.get(1); got != 0 {
+		t.Errorf("used after cancel = %d, want 0", got)
+	}
+
+	// 对账修正偏差，没有文件的用户清零
+	data := []byte("reconcile me")
+	upload(t, s, 1, 0, "small.txt", data, "")
+	usage.used[1] = 999
+	usage.used[3] = 42
+	n, err := s.ReconcileUsage(ctx)
+	if err != nil {
+		t.Fatalf("ReconcileUsage() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("reconciled = %d, want 2", n)
+	}
+	if got := usage.get(1); got != int64(len(data)) {
+		t.Errorf("used = %d, want %d", got, len(data))
+	}
+	if got := usage.get(3); got != 0 {
+		t.Errorf("used of user without files = %d, want 0", got)
+	}
+}
+
+func TestStorageService_Trash(t *testing.T) {
+	s, usage, store := newTestService(t)
+	ctx := context.Background()
+	data := []byte("to be trashed")
+	file := upload(t, s, 1, 0, "trash.txt", data, "")
+
+	if err := s.TrashFile(ctx, 2, file.ID); err == nil {
+		t.Error("TrashFile() by another user should fail")
+	}
+	if err := s.TrashFile(ctx, 0, file.ID); err == nil {
+		t.Error("TrashFile() without a user should fail")
+	}
+	if err := s.TrashFile(ctx, 1, file.ID); err != nil {
+		t.Fatalf("TrashFile() error = %v", err)
+	}
+	trashed, total, err := s.ListTrash(ctx, 1, 1, 10)
+	if err != nil || total != 1 || len(trashed) != 1 {
+		t.Fatalf("ListTrash() = %d files, total %d, error %v", len(trashed), total, err)
+	}
+	if _, _, err := s.GeneratePresignedURL(ctx, file.ID, 0, 60); err == nil {
+		t.Error("GeneratePresignedURL() of trashed file should fail")
+	}
+	if _, err := s.RestoreFile(ctx, 1, file.ID); err != nil {
+		t.Fatalf("RestoreFile() error = %v", err)
+	}
+	if got := download(t, s, file.ID, 0); !bytes.Equal(got, data) {
+		t.Errorf("restored content = %q", got)
+	}
+
+	if err := s.TrashFile(ctx, 1, file.ID); err != nil {
+		t.Fatalf("TrashFile() error = %v", err)
+	}
+	count, freed, err := s.EmptyTrash(ctx, 1)
+	if err != nil {
+		t.Fatalf("EmptyTrash() error = %v", err)
+	}
+	if count != 1 || freed != int64(len(data)) {
+		t.Errorf("EmptyTrash() = %d, %d", count, freed)
+	}
+	if got := usage.get(1); got != 0 {
+		t.Errorf("used = %d, want 0", got)
+	}
+	objects := 0
+	store.List(ctx, "", func(blobstore.ObjectInfo) error { objects++; return nil })
+	if objects != 0 {
+		t.Errorf("objects = %d, want 0", objects)
+	}
+}
+
+func TestStorageService_MigrateKeysRefusesPendingUploads(t *testing.T) {
+	s, _, _ := newTestService(t)
+	ctx := context.Background()
+	if _, err := s.InitUpload(ctx, "pending.bin", 100, "", "", 1, 0, "", FileMeta{}); err != nil {
+		t.Fatalf("InitUpload() error = %v", err)
+	}
+	if _, err := s.MigrateObjectKeys(ctx, true); err != nil {
+		t.Errorf("MigrateObjectKeys(dry-run) error = %v", err)
+	}
+	if _, err := s.MigrateObjectKeys(ctx, false); err == nil || !strings.Contains(err.Error(), "未完成的上传") {
+		t.Errorf("MigrateObjectKeys() with pending upload error = %v", err)
+	}
+}
+
+func TestStorageService_ListUploadedPartsRecordsMD5(t *testing.T) {
+	s, _, _ := newTestService(t)
+	ctx := context.Background()
+	data := randomData(7, testPartSize+100)
+	view, err := s.InitUpload(ctx, "parts.bin", int64(len(data)), "", "", 1, 0, "zstd", FileMeta{})
+	if err != nil {
+		t.Fatalf("InitUpload() error = %v", err)
+	}
+	first, second := data[:testPartSize], data[testPartSize:]
+	if err := s.UploadPart(ctx, view.ID, 1, first, md5Of(first)); err != nil {
+		t.Fatalf("UploadPart(1) error = %v", err)
+	}
+	if err := s.UploadPartStream(ctx, view.ID, 2, int64(len(second)), bytes.NewReader(second), ""); err != nil {
+		t.Fatalf("UploadPartStream(2) error = %v", err)
+	}
+
+	// 压缩存储时 MD5 仍按明文计算
+	parts, err := s.ListUploadedParts(view.ID)
+	if err != nil {
+		t.Fatalf("ListUploadedParts() error = %v", err)
+	}
+	if len(parts) != 2 || parts[0].PartNumber != 1 || parts[0].Md5 != md5Of(first) || parts[1].Md5 != md5Of(second) {
+		t.Fatalf("ListUploadedParts() = %+v", parts)
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// TrashFile 把文件移入回收站，上传中的文件直接删除
+func (s *StorageService) TrashFile(ctx context.Context, userID, fileID int64) error {
+	file, err := s.ownedFile(userID, fileID)
+	if err != nil {
+		return err
+	}
+	if file.TrashedAt != nil {
+		return nil
+	}
+	if file.Status != 1 {
+		return s.DeleteFile(ctx, fileID)
+	}
+	return s.fileDAO.MoveFileToTrash(fileID, time.Now())
+}
+
+// ListTrash 分页列出回收站
+func (s *StorageService) ListTrash(ctx context.Context, userID int64, page, pageSize int) ([]model.File, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return s.fileDAO.ListTrash(userID, (page-1)*pageSize, pageSize)
+}
+
+// RestoreFile 从回收站恢复文件
+// 原文件夹已被删除时恢复到根目录，目标目录有同名项时自动改名
+func (s *StorageService) RestoreFile(ctx context.Context, userID, fileID int64) (*model.File, error) {
+	file, err := s.fileDAO.GetFileByID(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("找不到文件记录: %v", err)
+	}
+	if file.UserID != userID {
+		return nil, fmt.Errorf("无权恢复该文件")
+	}
+	if file.TrashedAt == nil {
+		return nil, fmt.Errorf("文件不在回收站中")
+	}
+
+	folderID := file.FolderID
+	if folderID != 0 {
+		if _, err := s.fileDAO.GetFolderByID(folderID); err != nil {
+			folderID = 0
+		}
+	}
+	name, err := s.availableName(userID, folderID, file.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fileDAO.RestoreFileFromTrash(fileID, folderID, name); err != nil {
+		if errors.Is(err, model.ErrNameExists) {
+			return nil, errNameTaken(name)
+		}
+		return nil, fmt.Errorf("恢复文件失败: %v", err)
+	}
+	file.TrashedAt = nil
+	file.FolderID = folderID
+	file.FileName = name
+	return file, nil
+}
+
+// availableName 返回目录下可用的名称，重名时追加 " (n)" 后缀
+func (s *StorageService) availableName(userID, folderID int64, name string) (string, error) {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 1; i <= 100; i++ {
+		exists, err := s.fileDAO.NameExists(userID, folderID, candidate)
+		if err != nil {
+			return "", fmt.Errorf("检查重名失败: %v", err)
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+	return "", errNameTaken(name)
+}
+
+// EmptyTrash 清空用户回收站，返回清除的文件数和释放的空间
+func (s *StorageService) EmptyTrash(ctx context.Context, userID int64) (int64, int64, error) {
+	files, err := s.fileDAO.ListUserTrash(userID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("查询回收站失败: %v", err)
+	}
+
+	var count, freed int64
+	for _, f := range files {
+		if err := s.DeleteFile(ctx, f.ID); err != nil {
+			return count, freed, fmt.Errorf("清除文件 %d 失败: %v", f.ID, err)
+		}
+		count++
+		freed += f.Size
+	}
+	return count, freed, nil
+}
+
+// PurgeExpiredTrash 永久删除在回收站中超过保留期的文件
+func (s *StorageService) PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int, error) {
+	before := time.Now().Add(-retention)
+	purged := 0
+	for {
+		files, err := s.fileDAO.ListTrashedBefore(before, 100)
+		if err != nil {
+			return purged, fmt.Errorf("查询过期回收站文件失败: %v", err)
+		}
+		if len(files) == 0 {
+			return purged, nil
+		}
+		for _, f := range files {
+			if err := s.DeleteFile(ctx, f.ID); err != nil {
+				// 结束本轮清理，下一轮会重试
+				return purged, fmt.Errorf("清除文件 %d 失败: %v", f.ID, err)
+			}
+			purged++
+		}
+	}
+}
+
+// StartTrashPurger 启动后台协程，定期清理过期的回收站文件
+func (s *StorageService) StartTrashPurger(ctx context.Context, retention, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				n, err := s.PurgeExpiredTrash(ctx, retention)
+				if err != nil {
+					utils.Error("[TrashPurger] 清理回收站失败: %v", err)
+				}
+				if n > 0 {
+					utils.Info("[TrashPurger] 已永久删除 %d 个过期文件", n)
+				}
+			}
+		}
+	}()
+	utils.Info("[TrashPurger] 已启动, 保留期=%s, 间隔=%s", retention, interval)
+}
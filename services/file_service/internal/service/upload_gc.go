@@ -0,0 +1,165 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"expvar"
+	"fmt"
+	"time"
+)
+
+// uploadGCMetrics 过期上传清理的累计指标，通过 /debug/vars 暴露
+var uploadGCMetrics = expvar.NewMap("upload_gc")
+
+// UploadGCResult 一次清理的结果
+type UploadGCResult struct {
+	Sessions       int   // 中止的上传会话数
+	Parts          int   // 删除的分片数
+	ReclaimedBytes int64 // 回收的对象存储空间
+}
+
+// SetUploadTTL 设置上传会话的有效期，每次上传分片都会顺延
+func (s *StorageService) SetUploadTTL(ttl time.Duration) {
+	s.uploadTTL = ttl
+}
+
+// uploadExpiry 新上传会话的过期时间，未设置有效期时为 nil
+func (s *StorageService) uploadExpiry() *time.Time {
+	if s.uploadTTL <= 0 {
+		return nil
+	}
+	expiresAt := time.Now().Add(s.uploadTTL)
+	return &expiresAt
+}
+
+// touchUpload 顺延上传会话的过期时间，失败只记录日志
+func (s *StorageService) touchUpload(version *model.FileVersion) {
+	expiresAt := s.uploadExpiry()
+	if expiresAt == nil {
+		return
+	}
+	if err := s.fileDAO.TouchVersion(version.ID, *expiresAt); err != nil {
+		utils.Error("[UploadGC] 版本=%d 顺延过期时间失败: %v", version.ID, err)
+	}
+}
+
+// abortUpload 中止过期的上传会话：中止分片上传、删除分片对象和分片记录，版本标记为已中止并返还预留空间
+// 文件还没有任何已完成的版本时文件也标记为已中止
+func (s *StorageService) abortUpload(ctx context.Context, version *model.FileVersion) (int, int64, error) {
+	parts, err := s.fileDAO.ListParts(version.ID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("查询分片失败: %v", err)
+	}
+
+	var reclaimed int64
+	for _, p := range parts {
+		if p.ObjectName != "" {
+			if err := s.store.Delete(ctx, p.ObjectName); err != nil {
+				return 0, 0, fmt.Errorf("删除分片 %s 失败: %v", p.ObjectName, err)
+			}
+		}
+		reclaimed += p.Size
+	}
+	if err := s.abortMultipartUpload(ctx, version); err != nil {
+		return 0, 0, err
+	}
+	// 合并后登记失败的上传还会留下合并对象
+	if version.ObjectName != "" {
+		if info, err := s.store.Stat(ctx, version.ObjectName); err == nil {
+			if err := s.store.Delete(ctx, version.ObjectName); err != nil {
+				return 0, 0, fmt.Errorf("删除对象 %s 失败: %v", version.ObjectName, err)
+			}
+			reclaimed += info.Size
+		}
+	}
+	// 分块上传释放清单，只被这次上传引用的分块随之删除
+	if version.Chunked {
+		freed, err := s.releaseManifest(ctx, version.ID, 0)
+		if err != nil {
+			return 0, 0, err
+		}
+		reclaimed += freed
+	}
+
+	if err := s.fileDAO.DeleteParts(version.ID); err != nil {
+		return 0, 0, fmt.Errorf("删除分片记录失败: %v", err)
+	}
+	if err := s.fileDAO.AbortVersion(version.ID); err != nil {
+		return 0, 0, fmt.Errorf("中止版本失败: %v", err)
+	}
+
+	file, err := s.internalFile(version.FileID)
+	if err != nil {
+		return len(parts), reclaimed, nil
+	}
+	s.reportUsage(ctx, file.UserID, -version.Reserved)
+	if file.CurrentVersionID == 0 {
+		if err := s.fileDAO.UpdateFileStatus(file.ID, 2); err != nil {
+			utils.Error("[UploadGC] 文件=%d 标记中止失败: %v", file.ID, err)
+		}
+	}
+	return len(parts), reclaimed, nil
+}
+
+// SweepExpiredUploads 中止所有已过期的上传会话
+func (s *StorageService) SweepExpiredUploads(ctx context.Context) (UploadGCResult, error) {
+	var result UploadGCResult
+	if s.uploadTTL <= 0 {
+		return result, nil
+	}
+
+	now := time.Now()
+	for {
+		versions, err := s.fileDAO.ListExpiredVersions(now, now.Add(-s.uploadTTL), 100)
+		if err != nil {
+			return result, fmt.Errorf("查询过期上传失败: %v", err)
+		}
+		if len(versions) == 0 {
+			return result, nil
+		}
+		for i := range versions {
+			parts, reclaimed, err := s.abortUpload(ctx, &versions[i])
+			if err != nil {
+				// 结束本轮清理，下一轮会重试
+				return result, fmt.Errorf("中止上传 %d 失败: %v", versions[i].ID, err)
+			}
+			result.Sessions++
+			result.Parts += parts
+			result.ReclaimedBytes += reclaimed
+
+			uploadGCMetrics.Add("aborted_sessions", 1)
+			uploadGCMetrics.Add("removed_parts", int64(parts))
+			uploadGCMetrics.Add("reclaimed_bytes", reclaimed)
+		}
+	}
+}
+
+// StartUploadSweeper 启动后台协程，定期清理过期的上传会话
+func (s *StorageService) StartUploadSweeper(ctx context.Context, interval time.Duration) {
+	if s.uploadTTL <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result, err := s.SweepExpiredUploads(ctx)
+				uploadGCMetrics.Add("sweeps", 1)
+				if err != nil {
+					uploadGCMetrics.Add("errors", 1)
+					utils.Error("[UploadGC] 清理过期上传失败: %v", err)
+				}
+				if result.Sessions > 0 {
+					utils.Info("[UploadGC] 已中止 %d 个过期上传, 删除分片 %d 个, 回收 %d 字节",
+						result.Sessions, result.Parts, result.ReclaimedBytes)
+				}
+			}
+		}
+	}()
+	utils.Info("[UploadGC] 已启动, 有效期=%s, 间隔=%s", s.uploadTTL, interval)
+}
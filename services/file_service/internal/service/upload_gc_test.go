@@ -0,0 +1,129 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/model"
+	"context"
+	"testing"
+	"time"
+)
+
+// startUpload 开始上传 data 并上传第一个分片，返回上传中的版本
+func startUpload(t *testing.T, s *StorageService, name string, data []byte) (*model.File, *model.FileVersion) {
+	t.Helper()
+	ctx := context.Background()
+	view, err := s.InitUpload(ctx, name, int64(len(data)), "", "", 1, 0, "zstd", FileMeta{})
+	if err != nil {
+		t.Fatalf("InitUpload(%q) error = %v", name, err)
+	}
+	part := data[:min(testPartSize, len(data))]
+	if err := s.UploadPart(ctx, view.ID, 1, part, md5Of(part)); err != nil {
+		t.Fatalf("UploadPart(%q) error = %v", name, err)
+	}
+	version, err := s.fileDAO.GetPendingVersion(view.ID)
+	if err != nil {
+		t.Fatalf("GetPendingVersion(%q) error = %v", name, err)
+	}
+	return view, version
+}
+
+// assertAborted 检查版本已中止且分片记录和分片对象都已删除
+func assertAborted(t *testing.T, s *StorageService, version *model.FileVersion, objects []string) {
+	t.Helper()
+	got, err := s.fileDAO.GetVersionByID(version.ID)
+	if err != nil || got.Status != 2 || got.Reserved != 0 {
+		t.Errorf("version %d = %+v, %v, want aborted without reservation", version.ID, got, err)
+	}
+	if parts, err := s.fileDAO.ListParts(version.ID); err != nil || len(parts) != 0 {
+		t.Errorf("parts of version %d = %d, %v, want none", version.ID, len(parts), err)
+	}
+	for _, name := range objects {
+		if _, err := s.store.Stat(context.Background(), name); err == nil {
+			t.Errorf("part object %s still exists", name)
+		}
+	}
+}
+
+func partObjects(t *testing.T, s *StorageService, version *model.FileVersion) []string {
+	t.Helper()
+	parts, err := s.fileDAO.ListParts(version.ID)
+	if err != nil || len(parts) == 0 {
+		t.Fatalf("ListParts(%d) = %d, %v", version.ID, len(parts), err)
+	}
+	var names []string
+	for _, p := range parts {
+		names = append(names, p.ObjectName)
+	}
+	return names
+}
+
+func TestStorageService_SweepExpiredUploads(t *testing.T) {
+	s, usage, _ := newTestService(t)
+	ctx := context.Background()
+
+	// 设置有效期之前开始的上传没有过期时间，按创建时间判断
+	legacyFile, legacy := startUpload(t, s, "legacy.bin", randomData(1, 100))
+
+	s.SetUploadTTL(time.Hour)
+	expiredFile, expired := startUpload(t, s, "expired.bin", randomData(2, 200))
+	if err := s.fileDAO.TouchVersion(expired.ID, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("TouchVersion() error = %v", err)
+	}
+	liveFile, live := startUpload(t, s, "live.bin", randomData(3, 300))
+
+	// 已有完成版本的文件上传新版本过期后，文件保持可用
+	current := randomData(4, 400)
+	versioned := upload(t, s, 1, 0, "versioned.bin", current, "")
+	_, versionUpload := startUpload(t, s, "versioned.bin", randomData(5, 500))
+	if err := s.fileDAO.TouchVersion(versionUpload.ID, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("TouchVersion() error = %v", err)
+	}
+
+	if got, want := usage.get(1), int64(100+200+300+400+500); got != want {
+		t.Fatalf("used before sweep = %d, want %d", got, want)
+	}
+	expiredObjects := partObjects(t, s, expired)
+	versionObjects := partObjects(t, s, versionUpload)
+
+	result, err := s.SweepExpiredUploads(ctx)
+	if err != nil {
+		t.Fatalf("SweepExpiredUploads() error = %v", err)
+	}
+	if result.Sessions != 2 || result.Parts != 2 || result.ReclaimedBytes != 200+500 {
+		t.Errorf("SweepExpiredUploads() = %+v, want 2 sessions, 2 parts, 700 bytes", result)
+	}
+	assertAborted(t, s, expired, expiredObjects)
+	assertAborted(t, s, versionUpload, versionObjects)
+	if got, want := usage.get(1), int64(100+300+400); got != want {
+		t.Errorf("used after sweep = %d, want %d", got, want)
+	}
+	if file, err := s.fileDAO.GetFileByID(expiredFile.ID); err != nil || file.Status != 2 {
+		t.Errorf("file without a completed version = %+v, %v, want aborted", file, err)
+	}
+	if file, err := s.fileDAO.GetFileByID(versioned.ID); err != nil || file.Status != 1 || file.CurrentVersionID != versioned.CurrentVersionID {
+		t.Errorf("file with a completed version = %+v, %v, want unchanged", file, err)
+	}
+	for _, f := range []*model.File{legacyFile, liveFile} {
+		if _, err := s.GetUploadOffset(1, f.ID); err != nil {
+			t.Errorf("upload %s was aborted: %v", f.FileName, err)
+		}
+	}
+
+	// 有效期缩短后，没有过期时间的上传按创建时间过期，顺延过的上传不受影响
+	s.SetUploadTTL(time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	legacyObjects := partObjects(t, s, legacy)
+	result, err = s.SweepExpiredUploads(ctx)
+	if err != nil {
+		t.Fatalf("SweepExpiredUploads() error = %v", err)
+	}
+	if result.Sessions != 1 {
+		t.Errorf("second sweep aborted %d sessions, want 1", result.Sessions)
+	}
+	assertAborted(t, s, legacy, legacyObjects)
+	if got, want := usage.get(1), int64(300+400); got != want {
+		t.Errorf("used after second sweep = %d, want %d", got, want)
+	}
+	if parts, err := s.fileDAO.ListParts(live.ID); err != nil || len(parts) != 1 {
+		t.Errorf("parts of live upload = %d, %v, want 1", len(parts), err)
+	}
+}
@@ -0,0 +1,466 @@
+package service
+
+import (
+	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/utils"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+)
+
+// partObjectName 分片对象名
+func partObjectName(objectName string, partNumber int) string {
+	return fmt.Sprintf("%s.part.%d", objectName, partNumber)
+}
+
+// objectKeyPrefix 新对象名的前缀，迁移命令据此识别旧格式的对象
+const objectKeyPrefix = "objects/"
+
+// newObjectKey 生成与文件名和用户无关的随机对象名
+// 格式为 objects/<id前2位>/<id第3-4位>/<id>，按前缀分散到不同目录
+func newObjectKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Errorf("生成对象名失败: %v", err))
+	}
+	id := hex.EncodeToString(b[:])
+	return fmt.Sprintf("%s%s/%s/%s", objectKeyPrefix, id[:2], id[2:4], id)
+}
+
+// createVersion 为文件创建下一个版本，已完成的版本会直接设为当前版本
+func (s *StorageService) createVersion(file *model.File, version *model.FileVersion) error {
+	maxNo, err := s.fileDAO.MaxVersionNo(file.ID)
+	if err != nil {
+		return fmt.Errorf("查询版本号失败: %v", err)
+	}
+	version.FileID = file.ID
+	version.VersionNo = maxNo + 1
+	version.CreatedAt = time.Now()
+	if err := s.fileDAO.CreateVersion(version); err != nil {
+		return fmt.Errorf("创建版本记录失败: %v", err)
+	}
+	if version.Status == 1 {
+		if err := s.fileDAO.SetCurrentVersion(file.ID, version); err != nil {
+			return fmt.Errorf("更新当前版本失败: %v", err)
+		}
+		file.CurrentVersionID = version.ID
+		file.ObjectName = version.ObjectName
+		file.Size = version.Size
+		file.Md5 = version.Md5
+		file.Sha256 = version.Sha256
+		file.Compression = version.Compression
+		file.Status = 1
+	}
+	return nil
+}
+
+// uploadOptions 上传的存储方式
+type uploadOptions struct {
+	codec  string     // 分片的压缩方式，为空不压缩
+	chunks []ChunkRef // 不为空时为内容定义分块上传
+	meta   FileMeta   // 客户端提供的元数据
+	direct bool       // 一次性写入版本对象，不创建分片上传
+}
+
+// startVersion 为文件开始上传一个新版本
+// 客户端提供的 SHA-256、MD5 和大小与用户自己已有的 Blob 完全一致时直接引用该 Blob，不再上传
+// 开始前先向用户服务预留 size 大小的空间，秒传时预留的空间直接计为已用
+// 压缩的分片写成独立的分片对象，不使用 S3 分片上传；分块上传只记录分块清单，不创建版本对象
+// 直接上传一次写入版本对象，同样不使用 S3 分片上传
+// 返回的文件信息反映本次上传：秒传时为已完成，否则为上传中
+func (s *StorageService) startVersion(ctx context.Context, file *model.File, size int64, md5, sha256 string, opts uploadOptions) (*model.File, error) {
+	reserved, err := s.reserveSpace(ctx, file.UserID, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if sha256 != "" {
+		if blob, err := s.fileDAO.ReuseBlob(file.UserID, sha256, md5, size); err == nil {
+			version := &model.FileVersion{
+				ObjectName:  blob.ObjectName,
+				BlobID:      blob.ID,
+				Size:        blob.Size,
+				Md5:         blob.Md5,
+				Sha256:      blob.Sha256,
+				Status:      1,
+				Encryption:  blob.Encryption,
+				Compression: blob.Compression,
+				Chunked:     blob.Chunked,
+				Mtime:       opts.meta.Mtime,
+			}
+			if err := s.createVersion(file, version); err != nil {
+				s.releaseBlob(ctx, blob.ID)
+				s.reportUsage(ctx, file.UserID, -reserved)
+				return nil, err
+			}
+			return file, nil
+		}
+	}
+
+	// 分块上传的内容按分块各自加密，版本本身没有数据密钥
+	var enc model.Encryption
+	if len(opts.chunks) == 0 {
+		enc, err = s.newEncryption(file.UserID)
+		if err != nil {
+			s.reportUsage(ctx, file.UserID, -reserved)
+			return nil, err
+		}
+	}
+	version := &model.FileVersion{
+		Size:        size,
+		Md5:         md5,
+		Sha256:      sha256,
+		Status:      0,
+		Reserved:    reserved,
+		ExpiresAt:   s.uploadExpiry(),
+		Encryption:  enc,
+		Compression: opts.codec,
+		Chunked:     len(opts.chunks) > 0,
+		Mtime:       opts.meta.Mtime,
+	}
+	if err := s.createVersion(file, version); err != nil {
+		s.reportUsage(ctx, file.UserID, -reserved)
+		return nil, err
+	}
+	if version.Chunked {
+		if err := s.createManifest(file.UserID, version, opts.chunks); err != nil {
+			s.purgeVersion(ctx, version)
+			s.reportUsage(ctx, file.UserID, -reserved)
+			return nil, err
+		}
+		return s.pendingView(file, version), nil
+	}
+	version.ObjectName = newObjectKey()
+	if opts.codec == "" && !opts.direct {
+		if err := s.newMultipartUpload(ctx, version); err != nil {
+			s.purgeVersion(ctx, version)
+			s.reportUsage(ctx, file.UserID, -reserved)
+			return nil, err
+		}
+	}
+	if err := s.fileDAO.UpdateVersion(version); err != nil {
+		return nil, fmt.Errorf("更新版本记录失败: %v", err)
+	}
+
+	return s.pendingView(file, version), nil
+}
+
+// pendingView 反映上传中版本的文件信息
+func (s *StorageService) pendingView(file *model.File, version *model.FileVersion) *model.File {
+	view := *file
+	view.Size = version.Size
+	view.Md5 = version.Md5
+	view.Sha256 = version.Sha256
+	view.Compression = version.Compression
+	view.Status = 0
+	return &view
+}
+
+// finishVersion 版本对象写入完成后登记 Blob 并设为当前版本
+// 内容与已有 Blob 相同时改为引用已有 Blob，并删除刚写入的对象；新的 Blob 排队生成缩略图和预览
+// 实际大小超出预留空间时补充预留，不足时返还多预留的部分
+func (s *StorageService) finishVersion(ctx context.Context, userID int64, version *model.FileVersion, size int64, md5, sha256 string) error {
+	extra, err := s.reserveSpace(ctx, userID, size-version.Reserved)
+	if err != nil {
+		if !version.Chunked {
+			s.store.Delete(ctx, version.ObjectName)
+		}
+		return err
+	}
+
+	blob, reused, err := s.fileDAO.AcquireBlob(&model.Blob{
+		Sha256:      sha256,
+		Md5:         md5,
+		Size:        size,
+		Bucket:      s.bucket,
+		ObjectName:  version.ObjectName,
+		CreatedAt:   time.Now(),
+		Encryption:  version.Encryption,
+		Compression: version.Compression,
+		Chunked:     version.Chunked,
+	})
+	if err != nil {
+		s.reportUsage(ctx, userID, -extra)
+		return fmt.Errorf("登记 Blob 失败: %v", err)
+	}
+	switch {
+	case reused && version.Chunked:
+		if _, err := s.releaseManifest(ctx, version.ID, 0); err != nil {
+			utils.Error("[Blob] 释放版本 %d 的分块清单失败: %v", version.ID, err)
+		}
+		utils.Info("[Blob] 版本=%d 与 Blob=%d 内容相同，已去重", version.ID, blob.ID)
+	case reused && blob.ObjectName != version.ObjectName:
+		if err := s.store.Delete(ctx, version.ObjectName); err != nil {
+			utils.Error("[Blob] 删除重复对象 %s 失败: %v", version.ObjectName, err)
+		}
+		utils.Info("[Blob] 版本=%d 与 Blob=%d 内容相同，已去重", version.ID, blob.ID)
+	case version.Chunked:
+		if err := s.fileDAO.MoveManifestToBlob(version.ID, blob.ID); err != nil {
+			s.releaseBlob(ctx, blob.ID)
+			s.reportUsage(ctx, userID, -extra)
+			return fmt.Errorf("登记分块清单失败: %v", err)
+		}
+	}
+
+	version.ObjectName = blob.ObjectName
+	version.BlobID = blob.ID
+	version.Encryption = blob.Encryption
+	version.Compression = blob.Compression
+	version.Chunked = blob.Chunked
+	version.Size = size
+	version.Md5 = md5
+	version.Sha256 = sha256
+	version.Status = 1
+	if err := s.fileDAO.UpdateVersion(version); err != nil {
+		s.releaseBlob(ctx, blob.ID)
+		s.reportUsage(ctx, userID, -extra)
+		return fmt.Errorf("更新版本记录失败: %v", err)
+	}
+	if size < version.Reserved {
+		s.reportUsage(ctx, userID, size-version.Reserved)
+	}
+	if err := s.fileDAO.SetCurrentVersion(version.FileID, version); err != nil {
+		return err
+	}
+	if !reused {
+		s.enqueueDerivation(blob.ID)
+	}
+	return nil
+}
+
+// releaseBlob 释放一次 Blob 引用，最后一个引用释放时删除对象和衍生对象，分块存储的 Blob 释放其分块
+func (s *StorageService) releaseBlob(ctx context.Context, blobID int64) error {
+	blob, err := s.fileDAO.ReleaseBlob(blobID)
+	if err != nil {
+		return fmt.Errorf("释放 Blob 失败: %v", err)
+	}
+	if blob == nil {
+		return nil
+	}
+	s.deleteDerivatives(ctx, blob.ID)
+	if blob.Chunked {
+		_, err := s.releaseManifest(ctx, 0, blob.ID)
+		return err
+	}
+	if err := s.store.Delete(ctx, blob.ObjectName); err != nil {
+		return fmt.Errorf("删除文件 %s 失败: %v", blob.ObjectName, err)
+	}
+	return nil
+}
+
+// hashReader 读取全部内容计算 SHA-256、MD5 和大小
+func hashReader(r io.Reader) (string, string, int64, error) {
+	shaHash := sha256.New()
+	md5Hash := md5.New()
+	size, err := io.Copy(io.MultiWriter(shaHash, md5Hash), r)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("计算校验值失败: %v", err)
+	}
+	return hex.EncodeToString(shaHash.Sum(nil)), hex.EncodeToString(md5Hash.Sum(nil)), size, nil
+}
+
+// pendingVersion 获取文件正在上传的版本
+func (s *StorageService) pendingVersion(fileID int64) (*model.FileVersion, error) {
+	version, err := s.fileDAO.GetPendingVersion(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("文件没有进行中的上传: %v", err)
+	}
+	return version, nil
+}
+
+// resolveVersion 获取文件的指定版本，versionID 为0时返回当前版本
+func (s *StorageService) resolveVersion(fileID, versionID int64) (*model.FileVersion, error) {
+	if versionID == 0 {
+		file, err := s.fileDAO.GetFileByID(fileID)
+		if err != nil {
+			return nil, fmt.Errorf("获取文件信息失败: %v", err)
+		}
+		if file.CurrentVersionID == 0 {
+			return nil, fmt.Errorf("文件尚未完成上传")
+		}
+		versionID = file.CurrentVersionID
+	}
+	version, err := s.fileDAO.GetVersionByID(versionID)
+	if err != nil || version.FileID != fileID {
+		return nil, fmt.Errorf("找不到文件版本: %d", versionID)
+	}
+	if version.Status != 1 {
+		return nil, fmt.Errorf("该版本尚未完成上传")
+	}
+	return version, nil
+}
+
+// initVersionUpload 为已存在的文件开始上传新版本，之前未完成的上传会被放弃
+// 当前版本在上传完成前保持不变
+func (s *StorageService) initVersionUpload(ctx context.Context, file *model.File, size int64, md5, sha256 string, opts uploadOptions) (*model.File, error) {
+	for {
+		pending, err := s.fileDAO.GetPendingVersion(file.ID)
+		if err != nil {
+			break
+		}
+		if err := s.purgeVersion(ctx, pending); err != nil {
+			return nil, err
+		}
+		s.reportUsage(ctx, file.UserID, -pending.Reserved)
+	}
+	return s.startVersion(ctx, file, size, md5, sha256, opts)
+}
+
+// purgeVersion 删除版本的分片、分块清单和记录，中止未完成的分片上传，并释放其引用的 Blob
+func (s *StorageService) purgeVersion(ctx context.Context, version *model.FileVersion) error {
+	parts, err := s.fileDAO.ListParts(version.ID)
+	if err != nil {
+		return err
+	}
+	for _, p := range parts {
+		if p.ObjectName == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, p.ObjectName); err != nil {
+			return fmt.Errorf("删除分片 %s 失败: %v", p.ObjectName, err)
+		}
+	}
+	if err := s.abortMultipartUpload(ctx, version); err != nil {
+		return err
+	}
+	if version.Chunked && version.BlobID == 0 {
+		if _, err := s.releaseManifest(ctx, version.ID, 0); err != nil {
+			return err
+		}
+	}
+	if err := s.fileDAO.DeleteParts(version.ID); err != nil {
+		return err
+	}
+	if err := s.fileDAO.DeleteVersion(version.ID); err != nil {
+		return err
+	}
+	if version.BlobID != 0 {
+		return s.releaseBlob(ctx, version.BlobID)
+	}
+	return nil
+}
+
+// CancelUpload 取消进行中的上传并返还预留的空间，正在从URL导入时先中止下载
+// 文件还没有任何已完成的版本时连同文件记录一起删除
+func (s *StorageService) CancelUpload(ctx context.Context, fileID int64) error {
+	s.imports.stop(fileID)
+	return s.cancelUpload(ctx, fileID)
+}
+
+// cancelUpload 取消进行中的上传并返还预留的空间
+func (s *StorageService) cancelUpload(ctx context.Context, fileID int64) error {
+	file, err := s.fileDAO.GetFileByID(fileID)
+	if err != nil {
+		return fmt.Errorf("找不到文件记录: %v", err)
+	}
+	if file.CurrentVersionID == 0 {
+		return s.DeleteFile(ctx, fileID)
+	}
+	version, err := s.pendingVersion(fileID)
+	if err != nil {
+		return err
+	}
+	if err := s.purgeVersion(ctx, version); err != nil {
+		return err
+	}
+	s.reportUsage(ctx, file.UserID, -version.Reserved)
+	return nil
+}
+
+// ownedFile 获取文件并校验归属，面向用户的接口都经过这里，userID 不能为0
+func (s *StorageService) ownedFile(userID, fileID int64) (*model.File, error) {
+	if userID == 0 {
+		return nil, fmt.Errorf("缺少用户ID")
+	}
+	file, err := s.internalFile(fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file.UserID != userID {
+		return nil, fmt.Errorf("无权访问该文件")
+	}
+	return file, nil
+}
+
+// internalFile 获取文件但不校验归属，只供过期上传清理、对象对账等内部任务使用
+func (s *StorageService) internalFile(fileID int64) (*model.File, error) {
+	file, err := s.fileDAO.GetFileByID(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("找不到文件记录: %v", err)
+	}
+	return file, nil
+}
+
+// ListVersions 列出文件的所有版本
+func (s *StorageService) ListVersions(ctx context.Context, userID, fileID int64) (*model.File, []model.FileVersion, error) {
+	file, err := s.ownedFile(userID, fileID)
+	if err != nil {
+		return nil, nil, err
+	}
+	versions, err := s.fileDAO.ListVersions(fileID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询版本列表失败: %v", err)
+	}
+	return file, versions, nil
+}
+
+// GetVersion 获取文件的某个版本
+func (s *StorageService) GetVersion(ctx context.Context, userID, fileID, versionID int64) (*model.File, *model.FileVersion, error) {
+	file, err := s.ownedFile(userID, fileID)
+	if err != nil {
+		return nil, nil, err
+	}
+	version, err := s.fileDAO.GetVersionByID(versionID)
+	if err != nil || version.FileID != fileID {
+		return nil, nil, fmt.Errorf("找不到文件版本: %d", versionID)
+	}
+	return file, version, nil
+}
+
+// RestoreVersion 把历史版本设为当前版本，不复制数据
+func (s *StorageService) RestoreVersion(ctx context.Context, userID, fileID, versionID int64) (*model.File, error) {
+	file, err := s.ownedFile(userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file.TrashedAt != nil {
+		return nil, fmt.Errorf("文件已在回收站中")
+	}
+	version, err := s.resolveVersion(fileID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if file.CurrentVersionID == version.ID {
+		return file, nil
+	}
+	if err := s.fileDAO.SetCurrentVersion(fileID, version); err != nil {
+		return nil, fmt.Errorf("恢复版本失败: %v", err)
+	}
+	utils.Info("[Version] 文件=%d 恢复到版本 %d", fileID, version.VersionNo)
+	return s.fileDAO.GetFileByID(fileID)
+}
+
+// DeleteVersion 删除文件的历史版本，当前版本不能删除
+func (s *StorageService) DeleteVersion(ctx context.Context, userID, fileID, versionID int64) error {
+	file, err := s.ownedFile(userID, fileID)
+	if err != nil {
+		return err
+	}
+	if file.CurrentVersionID == versionID {
+		return fmt.Errorf("不能删除当前版本")
+	}
+	version, err := s.fileDAO.GetVersionByID(versionID)
+	if err != nil || version.FileID != fileID {
+		return fmt.Errorf("找不到文件版本: %d", versionID)
+	}
+	if err := s.purgeVersion(ctx, version); err != nil {
+		return err
+	}
+	s.reportUsage(ctx, file.UserID, -versionUsage(version))
+	return nil
+}
@@ -12,23 +12,26 @@ import (
 	"cloud-storage-file-service/discovery"
 	"cloud-storage-file-service/global"
 	"cloud-storage-file-service/internal/api"
+	"cloud-storage-file-service/internal/blobstore"
+	"cloud-storage-file-service/internal/encryption"
 	"cloud-storage-file-service/internal/model"
+	"cloud-storage-file-service/internal/rpc"
 	"cloud-storage-file-service/internal/service"
 	pb "cloud-storage-file-service/proto"
 	"cloud-storage-file-service/utils"
 
-	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
 	"google.golang.org/grpc"
 )
 
 var (
 	cfg            *config.Config
-	minioClient    *minio.Client
+	blobStore      blobstore.BlobStore
 	fileDAO        model.FileDAO
 	storageService *service.StorageService
 	grpcServer     *grpc.Server
 	db             *database.DB
+	etcdClient     *discovery.EtcdClient
+	userClient     *rpc.UserClient
 )
 
 func main() {
@@ -41,18 +44,24 @@ func main() {
 	// 初始化数据库
 	initDatabase()
 
-	// 初始化MinIO
-	initMinIO()
+	// 初始化对象存储
+	initBlobStore()
 
 	// 初始化DAO
 	initDAO()
 
+	// 初始化etcd
+	initETCD()
+
 	// 初始化Service
 	initService()
 
 	// 初始化gRPC服务
 	initGRPC()
 
+	// 启动后台任务
+	startBackgroundJobs()
+
 	// 启动所有服务
 	startServices()
 
@@ -90,40 +99,15 @@ func initDatabase() {
 	utils.Info("Database initialized successfully")
 }
 
-// initMinIO 初始化MinIO客户端
-func initMinIO() {
-	// 使用服务配置中的MinIO配置
-	minioConfig := cfg.Minio
-
-	// 初始化MinIO客户端
+// initBlobStore 按配置初始化对象存储后端
+func initBlobStore() {
 	var err error
-	minioClient, err = minio.New(minioConfig.Endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(minioConfig.AccessKey, minioConfig.SecretKey, ""),
-		Secure: minioConfig.UseSSL,
-	})
-
-	if err != nil {
-		panic(fmt.Errorf("failed to create minio client: %w", err))
-	}
-
-	// 检查存储桶是否存在，不存在则创建
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	exists, err := minioClient.BucketExists(ctx, "cloud-storage")
+	blobStore, err = blobstore.New(context.Background(), cfg, "cloud-storage")
 	if err != nil {
-		panic(fmt.Errorf("failed to check bucket: %w", err))
-	}
-
-	if !exists {
-		utils.Info("Creating bucket: cloud-storage")
-		err = minioClient.MakeBucket(ctx, "cloud-storage", minio.MakeBucketOptions{})
-		if err != nil {
-			panic(fmt.Errorf("failed to create bucket: %w", err))
-		}
+		panic(fmt.Errorf("failed to initialize storage backend: %w", err))
 	}
 
-	utils.Info("MinIO initialized successfully")
+	utils.Info("Storage backend initialized successfully: %s", cfg.Storage.Backend)
 }
 
 // initDAO 初始化数据访问对象
@@ -132,14 +116,71 @@ func initDAO() {
 	utils.Info("DAO initialized successfully")
 }
 
+// initETCD 初始化etcd客户端
+func initETCD() {
+	globalCfg, err := global.LoadConfig("global/global.yaml")
+	if err != nil {
+		panic(fmt.Errorf("failed to load global config: %w", err))
+	}
+	etcdClient, err = discovery.NewEtcdClient(globalCfg.Etcd.Endpoints)
+	if err != nil {
+		panic(fmt.Errorf("failed to create etcd client: %w", err))
+	}
+	utils.Info("Etcd initialized successfully")
+}
+
 // initService 初始化业务服务
 func initService() {
-	storageService = service.NewStorageService(minioClient, "cloud-storage", fileDAO)
+	storageService = service.NewStorageService(blobStore, "cloud-storage", fileDAO)
 	// 设置分片大小
 	storageService.SetPartSize(cfg.Storage.PartSize)
+	// 设置上传会话有效期
+	storageService.SetUploadTTL(time.Duration(cfg.Storage.UploadTTLMinutes) * time.Minute)
+	// 配置了主密钥时开启静态加密
+	if cfg.Encryption.MasterKey != "" {
+		masterKey, err := encryption.ParseKey(cfg.Encryption.MasterKey)
+		if err == nil {
+			err = storageService.SetMasterKey(masterKey)
+		}
+		if err != nil {
+			panic(fmt.Errorf("invalid encryption master key: %w", err))
+		}
+		utils.Info("Encryption at rest enabled")
+	}
+	// 缩略图和文本预览
+	storageService.SetPreviewOptions(cfg.Preview.ThumbnailSizes,
+		cfg.Preview.TextPreviewKB*1024, int64(cfg.Preview.MaxImageMB)*1024*1024)
+	// 服务端解压的限制
+	storageService.SetExtractLimits(cfg.Extract.MaxEntries,
+		int64(cfg.Extract.MaxExpandedMB)*1024*1024, cfg.Extract.MaxDepth)
+	// 从URL导入
+	storageService.SetImportOptions(int64(cfg.Import.MaxSizeMB)*1024*1024, cfg.Import.AllowPrivateNetworks)
+	// 通过用户服务预留和回写已用空间
+	userClient = rpc.NewUserClient(etcdClient)
+	storageService.SetUsageReporter(userClient)
 	utils.Info("Service initialized successfully")
 }
 
+// startBackgroundJobs 启动后台任务
+func startBackgroundJobs() {
+	ctx := context.Background()
+	storageService.StartTrashPurger(ctx,
+		time.Duration(cfg.Storage.TrashRetentionDays)*24*time.Hour,
+		time.Duration(cfg.Storage.TrashPurgeIntervalMinutes)*time.Minute)
+	storageService.StartUsageReconciler(ctx,
+		time.Duration(cfg.Storage.UsageReconcileIntervalMinutes)*time.Minute)
+	storageService.StartUploadSweeper(ctx,
+		time.Duration(cfg.Storage.UploadSweepIntervalMinutes)*time.Minute)
+	storageService.StartDerivationWorker(ctx,
+		time.Duration(cfg.Preview.ScanIntervalMinutes)*time.Minute)
+	if cfg.Scrub.IntervalMinutes > 0 {
+		storageService.StartScrubber(ctx,
+			time.Duration(cfg.Scrub.RescanDays)*24*time.Hour,
+			time.Duration(cfg.Scrub.IntervalMinutes)*time.Minute,
+			cfg.Scrub.BatchSize)
+	}
+}
+
 // initGRPC 初始化gRPC服务
 func initGRPC() {
 	// 增加gRPC消息大小限制
@@ -171,17 +212,9 @@ func initGRPC() {
 // startServices 启动所有服务
 func startServices() {
 	//注册etcd
-	globalCfg, err := global.LoadConfig("global/global.yaml")
-	if err != nil {
-		utils.Warn("Warning: Failed to load global config: %v", err)
-	}
-	etcdClient, err := discovery.NewEtcdClient(globalCfg.Etcd.Endpoints)
-	if err != nil {
-		utils.Warn("Warning: Failed to create etcd client: %v", err)
-	}
 	etcdClient.Register("file-service", fmt.Sprintf("localhost:%d", cfg.GRPC.Port), 5)
 
-	// 启动HTTP健康检查服务
+	// 启动HTTP健康检查服务，运行指标通过 /debug/vars 暴露
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
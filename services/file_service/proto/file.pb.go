@@ -31,6 +31,16 @@ type FileInfo struct {
 	UserID        int64                  `protobuf:"varint,4,opt,name=userID,proto3" json:"userID,omitempty"`
 	Md5           string                 `protobuf:"bytes,5,opt,name=md5,proto3" json:"md5,omitempty"`
 	Status        int32                  `protobuf:"varint,6,opt,name=status,proto3" json:"status,omitempty"`
+	FolderId      int64                  `protobuf:"varint,7,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`    // 所在文件夹ID，0 表示根目录
+	TrashedAt     int64                  `protobuf:"varint,8,opt,name=trashed_at,json=trashedAt,proto3" json:"trashed_at,omitempty"` // 移入回收站的时间戳，0 表示未删除
+	VersionId     int64                  `protobuf:"varint,9,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"` // 当前版本ID
+	Sha256        string                 `protobuf:"bytes,10,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	MimeType      string                 `protobuf:"bytes,11,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Compression   string                 `protobuf:"bytes,13,opt,name=compression,proto3" json:"compression,omitempty"` // 当前版本的存储压缩方式，为空表示未压缩
+	UpdatedAt     int64                  `protobuf:"varint,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Mtime         int64                  `protobuf:"varint,15,opt,name=mtime,proto3" json:"mtime,omitempty"`                                                                        // 客户端提供的当前版本修改时间戳，0 表示未提供
+	Tags          map[string]string      `protobuf:"bytes,16,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 用户自定义标签
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -107,6 +117,286 @@ func (x *FileInfo) GetStatus() int32 {
 	return 0
 }
 
+func (x *FileInfo) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *FileInfo) GetTrashedAt() int64 {
+	if x != nil {
+		return x.TrashedAt
+	}
+	return 0
+}
+
+func (x *FileInfo) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+func (x *FileInfo) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *FileInfo) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *FileInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *FileInfo) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+func (x *FileInfo) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *FileInfo) GetMtime() int64 {
+	if x != nil {
+		return x.Mtime
+	}
+	return 0
+}
+
+func (x *FileInfo) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// 文件版本信息
+type VersionInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Version       int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"` // 版本号，从1开始
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Md5           string                 `protobuf:"bytes,5,opt,name=md5,proto3" json:"md5,omitempty"`
+	Status        int32                  `protobuf:"varint,6,opt,name=status,proto3" json:"status,omitempty"` // 0 上传中，1 已完成
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	IsCurrent     bool                   `protobuf:"varint,8,opt,name=is_current,json=isCurrent,proto3" json:"is_current,omitempty"`
+	Sha256        string                 `protobuf:"bytes,9,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	MimeType      string                 `protobuf:"bytes,10,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"` // 根据内容识别的类型，为空表示上传未完成
+	Mtime         int64                  `protobuf:"varint,11,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionInfo) Reset() {
+	*x = VersionInfo{}
+	mi := &file_file_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionInfo) ProtoMessage() {}
+
+func (x *VersionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionInfo.ProtoReflect.Descriptor instead.
+func (*VersionInfo) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VersionInfo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *VersionInfo) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *VersionInfo) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *VersionInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *VersionInfo) GetMd5() string {
+	if x != nil {
+		return x.Md5
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *VersionInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *VersionInfo) GetIsCurrent() bool {
+	if x != nil {
+		return x.IsCurrent
+	}
+	return false
+}
+
+func (x *VersionInfo) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetMtime() int64 {
+	if x != nil {
+		return x.Mtime
+	}
+	return 0
+}
+
+// 文件夹信息
+type FolderInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ParentId      int64                  `protobuf:"varint,3,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // 父文件夹ID，0 表示根目录
+	UserId        int64                  `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FolderInfo) Reset() {
+	*x = FolderInfo{}
+	mi := &file_file_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FolderInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FolderInfo) ProtoMessage() {}
+
+func (x *FolderInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FolderInfo.ProtoReflect.Descriptor instead.
+func (*FolderInfo) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FolderInfo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *FolderInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FolderInfo) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *FolderInfo) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *FolderInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *FolderInfo) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
 // 上传初始化请求
 type InitUploadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -114,13 +404,18 @@ type InitUploadRequest struct {
 	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
 	Md5           string                 `protobuf:"bytes,3,opt,name=md5,proto3" json:"md5,omitempty"`
 	UserID        int64                  `protobuf:"varint,4,opt,name=userID,proto3" json:"userID,omitempty"`
+	FolderId      int64                  `protobuf:"varint,5,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`                                                  // 目标文件夹ID，0 表示根目录
+	Sha256        string                 `protobuf:"bytes,6,opt,name=sha256,proto3" json:"sha256,omitempty"`                                                                       // 可选，提供时可秒传自己已有的相同内容，并在上传完成时校验
+	Compression   string                 `protobuf:"bytes,7,opt,name=compression,proto3" json:"compression,omitempty"`                                                             // 可选，存储时的压缩方式: zstd 或 gzip，已压缩的文件类型会自动跳过
+	Mtime         int64                  `protobuf:"varint,8,opt,name=mtime,proto3" json:"mtime,omitempty"`                                                                        // 可选，客户端文件的修改时间戳（秒）
+	Tags          map[string]string      `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 可选，设置到文件上的标签
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *InitUploadRequest) Reset() {
 	*x = InitUploadRequest{}
-	mi := &file_file_proto_msgTypes[1]
+	mi := &file_file_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -132,7 +427,7 @@ func (x *InitUploadRequest) String() string {
 func (*InitUploadRequest) ProtoMessage() {}
 
 func (x *InitUploadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[1]
+	mi := &file_file_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -145,7 +440,7 @@ func (x *InitUploadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitUploadRequest.ProtoReflect.Descriptor instead.
 func (*InitUploadRequest) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{1}
+	return file_file_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *InitUploadRequest) GetFileName() string {
@@ -176,6 +471,41 @@ func (x *InitUploadRequest) GetUserID() int64 {
 	return 0
 }
 
+func (x *InitUploadRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *InitUploadRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *InitUploadRequest) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+func (x *InitUploadRequest) GetMtime() int64 {
+	if x != nil {
+		return x.Mtime
+	}
+	return 0
+}
+
+func (x *InitUploadRequest) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
 // 上传初始化响应
 type InitUploadResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -186,7 +516,7 @@ type InitUploadResponse struct {
 
 func (x *InitUploadResponse) Reset() {
 	*x = InitUploadResponse{}
-	mi := &file_file_proto_msgTypes[2]
+	mi := &file_file_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -198,7 +528,7 @@ func (x *InitUploadResponse) String() string {
 func (*InitUploadResponse) ProtoMessage() {}
 
 func (x *InitUploadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[2]
+	mi := &file_file_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -211,7 +541,7 @@ func (x *InitUploadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitUploadResponse.ProtoReflect.Descriptor instead.
 func (*InitUploadResponse) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{2}
+	return file_file_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *InitUploadResponse) GetFile() *FileInfo {
@@ -233,7 +563,7 @@ type PartMetadata struct {
 
 func (x *PartMetadata) Reset() {
 	*x = PartMetadata{}
-	mi := &file_file_proto_msgTypes[3]
+	mi := &file_file_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -245,7 +575,7 @@ func (x *PartMetadata) String() string {
 func (*PartMetadata) ProtoMessage() {}
 
 func (x *PartMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[3]
+	mi := &file_file_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -258,7 +588,7 @@ func (x *PartMetadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PartMetadata.ProtoReflect.Descriptor instead.
 func (*PartMetadata) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{3}
+	return file_file_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *PartMetadata) GetFileId() int64 {
@@ -298,7 +628,7 @@ type PartContent struct {
 
 func (x *PartContent) Reset() {
 	*x = PartContent{}
-	mi := &file_file_proto_msgTypes[4]
+	mi := &file_file_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -310,7 +640,7 @@ func (x *PartContent) String() string {
 func (*PartContent) ProtoMessage() {}
 
 func (x *PartContent) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[4]
+	mi := &file_file_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -323,7 +653,7 @@ func (x *PartContent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PartContent.ProtoReflect.Descriptor instead.
 func (*PartContent) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{4}
+	return file_file_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PartContent) GetData() []byte {
@@ -347,7 +677,7 @@ type UploadPartRequest struct {
 
 func (x *UploadPartRequest) Reset() {
 	*x = UploadPartRequest{}
-	mi := &file_file_proto_msgTypes[5]
+	mi := &file_file_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -359,7 +689,7 @@ func (x *UploadPartRequest) String() string {
 func (*UploadPartRequest) ProtoMessage() {}
 
 func (x *UploadPartRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[5]
+	mi := &file_file_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,7 +702,7 @@ func (x *UploadPartRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadPartRequest.ProtoReflect.Descriptor instead.
 func (*UploadPartRequest) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{5}
+	return file_file_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UploadPartRequest) GetPartData() isUploadPartRequest_PartData {
@@ -426,7 +756,7 @@ type CompleteUploadRequest struct {
 
 func (x *CompleteUploadRequest) Reset() {
 	*x = CompleteUploadRequest{}
-	mi := &file_file_proto_msgTypes[6]
+	mi := &file_file_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -438,7 +768,7 @@ func (x *CompleteUploadRequest) String() string {
 func (*CompleteUploadRequest) ProtoMessage() {}
 
 func (x *CompleteUploadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[6]
+	mi := &file_file_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -451,7 +781,7 @@ func (x *CompleteUploadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CompleteUploadRequest.ProtoReflect.Descriptor instead.
 func (*CompleteUploadRequest) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{6}
+	return file_file_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CompleteUploadRequest) GetFileId() int64 {
@@ -470,7 +800,7 @@ type CompleteUploadResponse struct {
 
 func (x *CompleteUploadResponse) Reset() {
 	*x = CompleteUploadResponse{}
-	mi := &file_file_proto_msgTypes[7]
+	mi := &file_file_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -482,7 +812,7 @@ func (x *CompleteUploadResponse) String() string {
 func (*CompleteUploadResponse) ProtoMessage() {}
 
 func (x *CompleteUploadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[7]
+	mi := &file_file_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -495,7 +825,7 @@ func (x *CompleteUploadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CompleteUploadResponse.ProtoReflect.Descriptor instead.
 func (*CompleteUploadResponse) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{7}
+	return file_file_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *CompleteUploadResponse) GetFile() *FileInfo {
@@ -510,13 +840,16 @@ type DownloadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
 	PartNumber    int32                  `protobuf:"varint,2,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
+	VersionId     int64                  `protobuf:"varint,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"` // 可选，0 表示当前版本
+	Offset        int64                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`                        // 分片内的起始字节
+	Length        int64                  `protobuf:"varint,5,opt,name=length,proto3" json:"length,omitempty"`                        // 读取长度，0 表示读到分片末尾
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DownloadRequest) Reset() {
 	*x = DownloadRequest{}
-	mi := &file_file_proto_msgTypes[8]
+	mi := &file_file_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -528,7 +861,7 @@ func (x *DownloadRequest) String() string {
 func (*DownloadRequest) ProtoMessage() {}
 
 func (x *DownloadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[8]
+	mi := &file_file_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -541,7 +874,7 @@ func (x *DownloadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DownloadRequest.ProtoReflect.Descriptor instead.
 func (*DownloadRequest) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{8}
+	return file_file_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DownloadRequest) GetFileId() int64 {
@@ -558,17 +891,42 @@ func (x *DownloadRequest) GetPartNumber() int32 {
 	return 0
 }
 
+func (x *DownloadRequest) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+func (x *DownloadRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *DownloadRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+// 数据消息只携带 data，最后一条消息为 last=true 的校验信息，不携带数据
 type DownloadResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	Md5           string                 `protobuf:"bytes,3,opt,name=md5,proto3" json:"md5,omitempty"`
+	Md5           string                 `protobuf:"bytes,3,opt,name=md5,proto3" json:"md5,omitempty"` // 本次返回数据的 MD5
+	Last          bool                   `protobuf:"varint,4,opt,name=last,proto3" json:"last,omitempty"`
+	Size          int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`                        // 本次返回数据的总字节数
+	PartEtag      string                 `protobuf:"bytes,6,opt,name=part_etag,json=partEtag,proto3" json:"part_etag,omitempty"` // 分片上传时记录的 ETag
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DownloadResponse) Reset() {
 	*x = DownloadResponse{}
-	mi := &file_file_proto_msgTypes[9]
+	mi := &file_file_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -580,7 +938,7 @@ func (x *DownloadResponse) String() string {
 func (*DownloadResponse) ProtoMessage() {}
 
 func (x *DownloadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[9]
+	mi := &file_file_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -593,7 +951,7 @@ func (x *DownloadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DownloadResponse.ProtoReflect.Descriptor instead.
 func (*DownloadResponse) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{9}
+	return file_file_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DownloadResponse) GetData() []byte {
@@ -610,17 +968,39 @@ func (x *DownloadResponse) GetMd5() string {
 	return ""
 }
 
-// 删除请求
+func (x *DownloadResponse) GetLast() bool {
+	if x != nil {
+		return x.Last
+	}
+	return false
+}
+
+func (x *DownloadResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DownloadResponse) GetPartEtag() string {
+	if x != nil {
+		return x.PartEtag
+	}
+	return ""
+}
+
+// 删除请求（移入回收站）
 type DeleteRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 非0时校验文件归属
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteRequest) Reset() {
 	*x = DeleteRequest{}
-	mi := &file_file_proto_msgTypes[10]
+	mi := &file_file_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -632,7 +1012,3657 @@ func (x *DeleteRequest) String() string {
 func (*DeleteRequest) ProtoMessage() {}
 
 func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[10]
+	mi := &file_file_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *DeleteRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// 生成预签名url
+type GeneratePresignedURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	ExpireSeconds int32                  `protobuf:"varint,2,opt,name=expire_seconds,json=expireSeconds,proto3" json:"expire_seconds,omitempty"` // 过期时间（秒），默认3600秒
+	VersionId     int64                  `protobuf:"varint,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`             // 可选，0 表示当前版本
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratePresignedURLRequest) Reset() {
+	*x = GeneratePresignedURLRequest{}
+	mi := &file_file_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratePresignedURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratePresignedURLRequest) ProtoMessage() {}
+
+func (x *GeneratePresignedURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratePresignedURLRequest.ProtoReflect.Descriptor instead.
+func (*GeneratePresignedURLRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GeneratePresignedURLRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *GeneratePresignedURLRequest) GetExpireSeconds() int32 {
+	if x != nil {
+		return x.ExpireSeconds
+	}
+	return 0
+}
+
+func (x *GeneratePresignedURLRequest) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+type GeneratePresignedURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	ExpireAt      int64                  `protobuf:"varint,2,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"` // 过期时间戳
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratePresignedURLResponse) Reset() {
+	*x = GeneratePresignedURLResponse{}
+	mi := &file_file_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratePresignedURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratePresignedURLResponse) ProtoMessage() {}
+
+func (x *GeneratePresignedURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratePresignedURLResponse.ProtoReflect.Descriptor instead.
+func (*GeneratePresignedURLResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GeneratePresignedURLResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *GeneratePresignedURLResponse) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+// 获取文件信息
+type GetFileInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFileInfoRequest) Reset() {
+	*x = GetFileInfoRequest{}
+	mi := &file_file_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFileInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileInfoRequest) ProtoMessage() {}
+
+func (x *GetFileInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetFileInfoRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetFileInfoRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+type GetFileInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFileInfoResponse) Reset() {
+	*x = GetFileInfoResponse{}
+	mi := &file_file_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFileInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileInfoResponse) ProtoMessage() {}
+
+func (x *GetFileInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetFileInfoResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetFileInfoResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+// 获取上传进度
+type GetUploadProgressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUploadProgressRequest) Reset() {
+	*x = GetUploadProgressRequest{}
+	mi := &file_file_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadProgressRequest) ProtoMessage() {}
+
+func (x *GetUploadProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadProgressRequest.ProtoReflect.Descriptor instead.
+func (*GetUploadProgressRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetUploadProgressRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+type GetUploadProgressResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadedSize  int64                  `protobuf:"varint,1,opt,name=uploaded_size,json=uploadedSize,proto3" json:"uploaded_size,omitempty"`
+	TotalSize     int64                  `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	Progress      float64                `protobuf:"fixed64,3,opt,name=progress,proto3" json:"progress,omitempty"`                           // 上传进度百分比 (0-100)
+	ImportStatus  string                 `protobuf:"bytes,4,opt,name=import_status,json=importStatus,proto3" json:"import_status,omitempty"` // 从URL导入的状态：running、completed、failed、cancelled，不是导入时为空
+	ImportError   string                 `protobuf:"bytes,5,opt,name=import_error,json=importError,proto3" json:"import_error,omitempty"`    // 导入失败的原因
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUploadProgressResponse) Reset() {
+	*x = GetUploadProgressResponse{}
+	mi := &file_file_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadProgressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadProgressResponse) ProtoMessage() {}
+
+func (x *GetUploadProgressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadProgressResponse.ProtoReflect.Descriptor instead.
+func (*GetUploadProgressResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetUploadProgressResponse) GetUploadedSize() int64 {
+	if x != nil {
+		return x.UploadedSize
+	}
+	return 0
+}
+
+func (x *GetUploadProgressResponse) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *GetUploadProgressResponse) GetProgress() float64 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *GetUploadProgressResponse) GetImportStatus() string {
+	if x != nil {
+		return x.ImportStatus
+	}
+	return ""
+}
+
+func (x *GetUploadProgressResponse) GetImportError() string {
+	if x != nil {
+		return x.ImportError
+	}
+	return ""
+}
+
+// 获取未完成分片
+type GetIncompletePartsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	TotalParts    int32                  `protobuf:"varint,2,opt,name=total_parts,json=totalParts,proto3" json:"total_parts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIncompletePartsRequest) Reset() {
+	*x = GetIncompletePartsRequest{}
+	mi := &file_file_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIncompletePartsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIncompletePartsRequest) ProtoMessage() {}
+
+func (x *GetIncompletePartsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIncompletePartsRequest.ProtoReflect.Descriptor instead.
+func (*GetIncompletePartsRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetIncompletePartsRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *GetIncompletePartsRequest) GetTotalParts() int32 {
+	if x != nil {
+		return x.TotalParts
+	}
+	return 0
+}
+
+type GetIncompletePartsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MissingParts  []int32                `protobuf:"varint,1,rep,packed,name=missing_parts,json=missingParts,proto3" json:"missing_parts,omitempty"` // 缺失的分片编号列表
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIncompletePartsResponse) Reset() {
+	*x = GetIncompletePartsResponse{}
+	mi := &file_file_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIncompletePartsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIncompletePartsResponse) ProtoMessage() {}
+
+func (x *GetIncompletePartsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIncompletePartsResponse.ProtoReflect.Descriptor instead.
+func (*GetIncompletePartsResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetIncompletePartsResponse) GetMissingParts() []int32 {
+	if x != nil {
+		return x.MissingParts
+	}
+	return nil
+}
+
+// 上传中版本已保存的分片，按编号升序
+type ListUploadedPartsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUploadedPartsRequest) Reset() {
+	*x = ListUploadedPartsRequest{}
+	mi := &file_file_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUploadedPartsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUploadedPartsRequest) ProtoMessage() {}
+
+func (x *ListUploadedPartsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUploadedPartsRequest.ProtoReflect.Descriptor instead.
+func (*ListUploadedPartsRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListUploadedPartsRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+type UploadedPart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PartNumber    int32                  `protobuf:"varint,1,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Md5           string                 `protobuf:"bytes,3,opt,name=md5,proto3" json:"md5,omitempty"` // 分片明文内容的 MD5，之前上传的分片没有记录时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadedPart) Reset() {
+	*x = UploadedPart{}
+	mi := &file_file_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadedPart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadedPart) ProtoMessage() {}
+
+func (x *UploadedPart) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadedPart.ProtoReflect.Descriptor instead.
+func (*UploadedPart) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UploadedPart) GetPartNumber() int32 {
+	if x != nil {
+		return x.PartNumber
+	}
+	return 0
+}
+
+func (x *UploadedPart) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *UploadedPart) GetMd5() string {
+	if x != nil {
+		return x.Md5
+	}
+	return ""
+}
+
+type ListUploadedPartsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Parts         []*UploadedPart        `protobuf:"bytes,1,rep,name=parts,proto3" json:"parts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUploadedPartsResponse) Reset() {
+	*x = ListUploadedPartsResponse{}
+	mi := &file_file_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUploadedPartsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUploadedPartsResponse) ProtoMessage() {}
+
+func (x *ListUploadedPartsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUploadedPartsResponse.ProtoReflect.Descriptor instead.
+func (*ListUploadedPartsResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListUploadedPartsResponse) GetParts() []*UploadedPart {
+	if x != nil {
+		return x.Parts
+	}
+	return nil
+}
+
+// 按偏移续传的上传状态，偏移根据已保存的分片计算
+type GetUploadOffsetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 非0时校验文件归属
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUploadOffsetRequest) Reset() {
+	*x = GetUploadOffsetRequest{}
+	mi := &file_file_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadOffsetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadOffsetRequest) ProtoMessage() {}
+
+func (x *GetUploadOffsetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadOffsetRequest.ProtoReflect.Descriptor instead.
+func (*GetUploadOffsetRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetUploadOffsetRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *GetUploadOffsetRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type GetUploadOffsetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Offset        int64                  `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"` // 从第1个分片起连续写入的字节数
+	TotalSize     int64                  `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	PartSize      int64                  `protobuf:"varint,3,opt,name=part_size,json=partSize,proto3" json:"part_size,omitempty"`    // 续传时每个分片的大小，最后一个分片可以更小
+	ExpiresAt     int64                  `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // 上传会话的过期时间戳，0 表示不过期
+	Completed     bool                   `protobuf:"varint,5,opt,name=completed,proto3" json:"completed,omitempty"`                  // 没有进行中的上传，文件当前版本已完成
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUploadOffsetResponse) Reset() {
+	*x = GetUploadOffsetResponse{}
+	mi := &file_file_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadOffsetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadOffsetResponse) ProtoMessage() {}
+
+func (x *GetUploadOffsetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadOffsetResponse.ProtoReflect.Descriptor instead.
+func (*GetUploadOffsetResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetUploadOffsetResponse) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetUploadOffsetResponse) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *GetUploadOffsetResponse) GetPartSize() int64 {
+	if x != nil {
+		return x.PartSize
+	}
+	return 0
+}
+
+func (x *GetUploadOffsetResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *GetUploadOffsetResponse) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+// 取消上传
+type CancelUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelUploadRequest) Reset() {
+	*x = CancelUploadRequest{}
+	mi := &file_file_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelUploadRequest) ProtoMessage() {}
+
+func (x *CancelUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelUploadRequest.ProtoReflect.Descriptor instead.
+func (*CancelUploadRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CancelUploadRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+// 创建文件夹
+type CreateFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ParentId      int64                  `protobuf:"varint,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFolderRequest) Reset() {
+	*x = CreateFolderRequest{}
+	mi := &file_file_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFolderRequest) ProtoMessage() {}
+
+func (x *CreateFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFolderRequest.ProtoReflect.Descriptor instead.
+func (*CreateFolderRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateFolderRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateFolderRequest) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *CreateFolderRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folder        *FolderInfo            `protobuf:"bytes,1,opt,name=folder,proto3" json:"folder,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFolderResponse) Reset() {
+	*x = CreateFolderResponse{}
+	mi := &file_file_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFolderResponse) ProtoMessage() {}
+
+func (x *CreateFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFolderResponse.ProtoReflect.Descriptor instead.
+func (*CreateFolderResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CreateFolderResponse) GetFolder() *FolderInfo {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+// 重命名文件夹
+type RenameFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderId      int64                  `protobuf:"varint,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFolderRequest) Reset() {
+	*x = RenameFolderRequest{}
+	mi := &file_file_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFolderRequest) ProtoMessage() {}
+
+func (x *RenameFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFolderRequest.ProtoReflect.Descriptor instead.
+func (*RenameFolderRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RenameFolderRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RenameFolderRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *RenameFolderRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RenameFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folder        *FolderInfo            `protobuf:"bytes,1,opt,name=folder,proto3" json:"folder,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFolderResponse) Reset() {
+	*x = RenameFolderResponse{}
+	mi := &file_file_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFolderResponse) ProtoMessage() {}
+
+func (x *RenameFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFolderResponse.ProtoReflect.Descriptor instead.
+func (*RenameFolderResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RenameFolderResponse) GetFolder() *FolderInfo {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+// 移动文件夹
+type MoveFolderRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderId       int64                  `protobuf:"varint,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	TargetParentId int64                  `protobuf:"varint,3,opt,name=target_parent_id,json=targetParentId,proto3" json:"target_parent_id,omitempty"` // 目标父文件夹ID，0 表示根目录
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MoveFolderRequest) Reset() {
+	*x = MoveFolderRequest{}
+	mi := &file_file_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFolderRequest) ProtoMessage() {}
+
+func (x *MoveFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFolderRequest.ProtoReflect.Descriptor instead.
+func (*MoveFolderRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MoveFolderRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *MoveFolderRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *MoveFolderRequest) GetTargetParentId() int64 {
+	if x != nil {
+		return x.TargetParentId
+	}
+	return 0
+}
+
+type MoveFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folder        *FolderInfo            `protobuf:"bytes,1,opt,name=folder,proto3" json:"folder,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFolderResponse) Reset() {
+	*x = MoveFolderResponse{}
+	mi := &file_file_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFolderResponse) ProtoMessage() {}
+
+func (x *MoveFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFolderResponse.ProtoReflect.Descriptor instead.
+func (*MoveFolderResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *MoveFolderResponse) GetFolder() *FolderInfo {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+// 删除文件夹
+type DeleteFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderId      int64                  `protobuf:"varint,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Recursive     bool                   `protobuf:"varint,3,opt,name=recursive,proto3" json:"recursive,omitempty"` // 为 false 时只允许删除空文件夹
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFolderRequest) Reset() {
+	*x = DeleteFolderRequest{}
+	mi := &file_file_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFolderRequest) ProtoMessage() {}
+
+func (x *DeleteFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFolderRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFolderRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DeleteFolderRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *DeleteFolderRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *DeleteFolderRequest) GetRecursive() bool {
+	if x != nil {
+		return x.Recursive
+	}
+	return false
+}
+
+// 列出目录内容
+type ListDirectoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderId      int64                  `protobuf:"varint,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"` // 0 表示根目录
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`                         // 从1开始
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // 默认50，最大200
+	SortBy        string                 `protobuf:"bytes,5,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`        // name | size | created_at，默认 name
+	Order         string                 `protobuf:"bytes,6,opt,name=order,proto3" json:"order,omitempty"`                        // asc | desc，默认 asc
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDirectoryRequest) Reset() {
+	*x = ListDirectoryRequest{}
+	mi := &file_file_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDirectoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDirectoryRequest) ProtoMessage() {}
+
+func (x *ListDirectoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDirectoryRequest.ProtoReflect.Descriptor instead.
+func (*ListDirectoryRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListDirectoryRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListDirectoryRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *ListDirectoryRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListDirectoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDirectoryRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListDirectoryRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+type ListDirectoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folders       []*FolderInfo          `protobuf:"bytes,1,rep,name=folders,proto3" json:"folders,omitempty"` // 文件夹总是排在文件之前
+	Files         []*FileInfo            `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty"`
+	Total         int64                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"` // 文件夹与文件的总数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDirectoryResponse) Reset() {
+	*x = ListDirectoryResponse{}
+	mi := &file_file_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDirectoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDirectoryResponse) ProtoMessage() {}
+
+func (x *ListDirectoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDirectoryResponse.ProtoReflect.Descriptor instead.
+func (*ListDirectoryResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListDirectoryResponse) GetFolders() []*FolderInfo {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+func (x *ListDirectoryResponse) GetFiles() []*FileInfo {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ListDirectoryResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// 按名称路径查找文件夹和文件，用于 S3 等按路径访问的接口
+type LookupPathRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderId      int64                  `protobuf:"varint,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"` // 起始文件夹，0 表示根目录
+	Folders       []string               `protobuf:"bytes,3,rep,name=folders,proto3" json:"folders,omitempty"`                    // 依次进入的子文件夹名称
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`                          // 可选，在最后一级文件夹中查找的文件名
+	Create        bool                   `protobuf:"varint,5,opt,name=create,proto3" json:"create,omitempty"`                     // 为 true 时创建不存在的文件夹
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupPathRequest) Reset() {
+	*x = LookupPathRequest{}
+	mi := &file_file_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupPathRequest) ProtoMessage() {}
+
+func (x *LookupPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupPathRequest.ProtoReflect.Descriptor instead.
+func (*LookupPathRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *LookupPathRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *LookupPathRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *LookupPathRequest) GetFolders() []string {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+func (x *LookupPathRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LookupPathRequest) GetCreate() bool {
+	if x != nil {
+		return x.Create
+	}
+	return false
+}
+
+type LookupPathResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FolderId      int64                  `protobuf:"varint,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"` // 最后一级文件夹ID，0 表示根目录
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`                       // 路径中的文件夹都存在
+	File          *FileInfo              `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`                          // 找到的文件，没有时为空
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupPathResponse) Reset() {
+	*x = LookupPathResponse{}
+	mi := &file_file_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupPathResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupPathResponse) ProtoMessage() {}
+
+func (x *LookupPathResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupPathResponse.ProtoReflect.Descriptor instead.
+func (*LookupPathResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *LookupPathResponse) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *LookupPathResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *LookupPathResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+// 列出回收站
+type ListTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`                         // 从1开始
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // 默认50，最大200
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashRequest) Reset() {
+	*x = ListTrashRequest{}
+	mi := &file_file_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashRequest) ProtoMessage() {}
+
+func (x *ListTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashRequest.ProtoReflect.Descriptor instead.
+func (*ListTrashRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListTrashRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListTrashRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListTrashRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Files         []*FileInfo            `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"` // 按删除时间倒序
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashResponse) Reset() {
+	*x = ListTrashResponse{}
+	mi := &file_file_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashResponse) ProtoMessage() {}
+
+func (x *ListTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashResponse.ProtoReflect.Descriptor instead.
+func (*ListTrashResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListTrashResponse) GetFiles() []*FileInfo {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ListTrashResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// 从回收站恢复文件
+type RestoreFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFileRequest) Reset() {
+	*x = RestoreFileRequest{}
+	mi := &file_file_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFileRequest) ProtoMessage() {}
+
+func (x *RestoreFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFileRequest.ProtoReflect.Descriptor instead.
+func (*RestoreFileRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RestoreFileRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RestoreFileRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+type RestoreFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"` // 原文件夹已不存在时恢复到根目录，重名时自动改名
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFileResponse) Reset() {
+	*x = RestoreFileResponse{}
+	mi := &file_file_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFileResponse) ProtoMessage() {}
+
+func (x *RestoreFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFileResponse.ProtoReflect.Descriptor instead.
+func (*RestoreFileResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RestoreFileResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+// 清空回收站
+type EmptyTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmptyTrashRequest) Reset() {
+	*x = EmptyTrashRequest{}
+	mi := &file_file_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmptyTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmptyTrashRequest) ProtoMessage() {}
+
+func (x *EmptyTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmptyTrashRequest.ProtoReflect.Descriptor instead.
+func (*EmptyTrashRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *EmptyTrashRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type EmptyTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PurgedCount   int64                  `protobuf:"varint,1,opt,name=purged_count,json=purgedCount,proto3" json:"purged_count,omitempty"`
+	FreedSize     int64                  `protobuf:"varint,2,opt,name=freed_size,json=freedSize,proto3" json:"freed_size,omitempty"` // 释放的空间（字节）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmptyTrashResponse) Reset() {
+	*x = EmptyTrashResponse{}
+	mi := &file_file_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmptyTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmptyTrashResponse) ProtoMessage() {}
+
+func (x *EmptyTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmptyTrashResponse.ProtoReflect.Descriptor instead.
+func (*EmptyTrashResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *EmptyTrashResponse) GetPurgedCount() int64 {
+	if x != nil {
+		return x.PurgedCount
+	}
+	return 0
+}
+
+func (x *EmptyTrashResponse) GetFreedSize() int64 {
+	if x != nil {
+		return x.FreedSize
+	}
+	return 0
+}
+
+// 列出文件版本
+type ListVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVersionsRequest) Reset() {
+	*x = ListVersionsRequest{}
+	mi := &file_file_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsRequest) ProtoMessage() {}
+
+func (x *ListVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListVersionsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListVersionsRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+type ListVersionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*VersionInfo         `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"` // 按版本号倒序
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVersionsResponse) Reset() {
+	*x = ListVersionsResponse{}
+	mi := &file_file_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsResponse) ProtoMessage() {}
+
+func (x *ListVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ListVersionsResponse) GetVersions() []*VersionInfo {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// 获取文件版本
+type GetVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	VersionId     int64                  `protobuf:"varint,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVersionRequest) Reset() {
+	*x = GetVersionRequest{}
+	mi := &file_file_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionRequest) ProtoMessage() {}
+
+func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetVersionRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetVersionRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *GetVersionRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *GetVersionRequest) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+type GetVersionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       *VersionInfo           `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVersionResponse) Reset() {
+	*x = GetVersionResponse{}
+	mi := &file_file_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionResponse) ProtoMessage() {}
+
+func (x *GetVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersionResponse.ProtoReflect.Descriptor instead.
+func (*GetVersionResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetVersionResponse) GetVersion() *VersionInfo {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+// 恢复历史版本
+type RestoreVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	VersionId     int64                  `protobuf:"varint,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreVersionRequest) Reset() {
+	*x = RestoreVersionRequest{}
+	mi := &file_file_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreVersionRequest) ProtoMessage() {}
+
+func (x *RestoreVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreVersionRequest.ProtoReflect.Descriptor instead.
+func (*RestoreVersionRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *RestoreVersionRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RestoreVersionRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *RestoreVersionRequest) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+type RestoreVersionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreVersionResponse) Reset() {
+	*x = RestoreVersionResponse{}
+	mi := &file_file_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreVersionResponse) ProtoMessage() {}
+
+func (x *RestoreVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreVersionResponse.ProtoReflect.Descriptor instead.
+func (*RestoreVersionResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *RestoreVersionResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+// 删除历史版本
+type DeleteVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	VersionId     int64                  `protobuf:"varint,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"` // 不能是当前版本
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVersionRequest) Reset() {
+	*x = DeleteVersionRequest{}
+	mi := &file_file_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVersionRequest) ProtoMessage() {}
+
+func (x *DeleteVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVersionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVersionRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *DeleteVersionRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *DeleteVersionRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *DeleteVersionRequest) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+// 列出用户的所有文件
+type ListFilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"` // 上一页返回的 next_cursor，为空表示第一页
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`  // 默认50，最大200
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFilesRequest) Reset() {
+	*x = ListFilesRequest{}
+	mi := &file_file_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFilesRequest) ProtoMessage() {}
+
+func (x *ListFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFilesRequest.ProtoReflect.Descriptor instead.
+func (*ListFilesRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ListFilesRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListFilesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListFilesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListFilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Files         []*FileInfo            `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`                             // 按上传时间倒序
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // 为空表示没有更多数据
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFilesResponse) Reset() {
+	*x = ListFilesResponse{}
+	mi := &file_file_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFilesResponse) ProtoMessage() {}
+
+func (x *ListFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFilesResponse.ProtoReflect.Descriptor instead.
+func (*ListFilesResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListFilesResponse) GetFiles() []*FileInfo {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ListFilesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// 搜索文件，未设置的条件不参与过滤
+type SearchFilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // 文件名子串
+	MinSize       int64                  `protobuf:"varint,3,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`
+	MaxSize       int64                  `protobuf:"varint,4,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`                   // 0 表示不限
+	Status        *int32                 `protobuf:"varint,5,opt,name=status,proto3,oneof" json:"status,omitempty"`                              // 0 上传中，1 已完成
+	CreatedAfter  int64                  `protobuf:"varint,6,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`    // 时间戳（秒），含
+	CreatedBefore int64                  `protobuf:"varint,7,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"` // 时间戳（秒），不含
+	MimeType      string                 `protobuf:"bytes,8,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`                 // 精确匹配，以 "/" 结尾时按前缀匹配，如 "image/"
+	Cursor        string                 `protobuf:"bytes,9,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,10,opt,name=limit,proto3" json:"limit,omitempty"`
+	UpdatedAfter  int64                  `protobuf:"varint,11,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`
+	UpdatedBefore int64                  `protobuf:"varint,12,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`
+	MtimeAfter    int64                  `protobuf:"varint,13,opt,name=mtime_after,json=mtimeAfter,proto3" json:"mtime_after,omitempty"`
+	MtimeBefore   int64                  `protobuf:"varint,14,opt,name=mtime_before,json=mtimeBefore,proto3" json:"mtime_before,omitempty"`
+	Tags          map[string]string      `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 需全部匹配，值为空时只要求有该标签
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchFilesRequest) Reset() {
+	*x = SearchFilesRequest{}
+	mi := &file_file_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchFilesRequest) ProtoMessage() {}
+
+func (x *SearchFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchFilesRequest.ProtoReflect.Descriptor instead.
+func (*SearchFilesRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *SearchFilesRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SearchFilesRequest) GetMinSize() int64 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetMaxSize() int64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetStatus() int32 {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetCreatedAfter() int64 {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetCreatedBefore() int64 {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *SearchFilesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *SearchFilesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetUpdatedAfter() int64 {
+	if x != nil {
+		return x.UpdatedAfter
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetUpdatedBefore() int64 {
+	if x != nil {
+		return x.UpdatedBefore
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetMtimeAfter() int64 {
+	if x != nil {
+		return x.MtimeAfter
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetMtimeBefore() int64 {
+	if x != nil {
+		return x.MtimeBefore
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type SearchFilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Files         []*FileInfo            `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchFilesResponse) Reset() {
+	*x = SearchFilesResponse{}
+	mi := &file_file_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchFilesResponse) ProtoMessage() {}
+
+func (x *SearchFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchFilesResponse.ProtoReflect.Descriptor instead.
+func (*SearchFilesResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *SearchFilesResponse) GetFiles() []*FileInfo {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *SearchFilesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// 按字节范围读取文件内容
+type ReadFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	VersionId     int64                  `protobuf:"varint,2,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"` // 可选，0 表示当前版本
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`                        // 起始字节
+	Length        int64                  `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`                        // 读取长度，0 表示读到文件末尾
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadFileRequest) Reset() {
+	*x = ReadFileRequest{}
+	mi := &file_file_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadFileRequest) ProtoMessage() {}
+
+func (x *ReadFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadFileRequest.ProtoReflect.Descriptor instead.
+func (*ReadFileRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ReadFileRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *ReadFileRequest) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+func (x *ReadFileRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ReadFileRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+type ReadFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadFileResponse) Reset() {
+	*x = ReadFileResponse{}
+	mi := &file_file_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadFileResponse) ProtoMessage() {}
+
+func (x *ReadFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadFileResponse.ProtoReflect.Descriptor instead.
+func (*ReadFileResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ReadFileResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// 内容定义分块上传中的一个分块
+type ChunkRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sha256        string                 `protobuf:"bytes,1,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChunkRef) Reset() {
+	*x = ChunkRef{}
+	mi := &file_file_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkRef) ProtoMessage() {}
+
+func (x *ChunkRef) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkRef.ProtoReflect.Descriptor instead.
+func (*ChunkRef) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ChunkRef) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *ChunkRef) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// 分块上传初始化请求，chunks 为文件按 FastCDC 切分出的分块，按顺序排列
+type InitChunkedUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileName      string                 `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Md5           string                 `protobuf:"bytes,3,opt,name=md5,proto3" json:"md5,omitempty"`
+	UserID        int64                  `protobuf:"varint,4,opt,name=userID,proto3" json:"userID,omitempty"`
+	FolderId      int64                  `protobuf:"varint,5,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Sha256        string                 `protobuf:"bytes,6,opt,name=sha256,proto3" json:"sha256,omitempty"` // 必填，上传完成时校验
+	Chunks        []*ChunkRef            `protobuf:"bytes,7,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	Mtime         int64                  `protobuf:"varint,8,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	Tags          map[string]string      `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InitChunkedUploadRequest) Reset() {
+	*x = InitChunkedUploadRequest{}
+	mi := &file_file_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitChunkedUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitChunkedUploadRequest) ProtoMessage() {}
+
+func (x *InitChunkedUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitChunkedUploadRequest.ProtoReflect.Descriptor instead.
+func (*InitChunkedUploadRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *InitChunkedUploadRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *InitChunkedUploadRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *InitChunkedUploadRequest) GetMd5() string {
+	if x != nil {
+		return x.Md5
+	}
+	return ""
+}
+
+func (x *InitChunkedUploadRequest) GetUserID() int64 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *InitChunkedUploadRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *InitChunkedUploadRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *InitChunkedUploadRequest) GetChunks() []*ChunkRef {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+func (x *InitChunkedUploadRequest) GetMtime() int64 {
+	if x != nil {
+		return x.Mtime
+	}
+	return 0
+}
+
+func (x *InitChunkedUploadRequest) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type InitChunkedUploadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	MissingChunks []string               `protobuf:"bytes,2,rep,name=missing_chunks,json=missingChunks,proto3" json:"missing_chunks,omitempty"` // 服务端缺少、需要上传的分块哈希
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InitChunkedUploadResponse) Reset() {
+	*x = InitChunkedUploadResponse{}
+	mi := &file_file_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitChunkedUploadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitChunkedUploadResponse) ProtoMessage() {}
+
+func (x *InitChunkedUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitChunkedUploadResponse.ProtoReflect.Descriptor instead.
+func (*InitChunkedUploadResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *InitChunkedUploadResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+func (x *InitChunkedUploadResponse) GetMissingChunks() []string {
+	if x != nil {
+		return x.MissingChunks
+	}
+	return nil
+}
+
+// 上传一个分块
+type UploadChunkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Sha256        string                 `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadChunkRequest) Reset() {
+	*x = UploadChunkRequest{}
+	mi := &file_file_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadChunkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadChunkRequest) ProtoMessage() {}
+
+func (x *UploadChunkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadChunkRequest.ProtoReflect.Descriptor instead.
+func (*UploadChunkRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *UploadChunkRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *UploadChunkRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *UploadChunkRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// 获取文件当前版本的缩略图，没有缩略图的文本文件返回文本预览
+type GetThumbnailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Size          int32                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"` // 期望的最长边像素，取不小于它的最小一张，0 表示最小的一张
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetThumbnailRequest) Reset() {
+	*x = GetThumbnailRequest{}
+	mi := &file_file_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetThumbnailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetThumbnailRequest) ProtoMessage() {}
+
+func (x *GetThumbnailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetThumbnailRequest.ProtoReflect.Descriptor instead.
+func (*GetThumbnailRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetThumbnailRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *GetThumbnailRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type GetThumbnailResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"` // 为空表示没有缩略图
+	MimeType      string                 `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	Size          int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`       // 缩略图实际的最长边像素，文本预览为0
+	Pending       bool                   `protobuf:"varint,4,opt,name=pending,proto3" json:"pending,omitempty"` // 还在排队生成
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetThumbnailResponse) Reset() {
+	*x = GetThumbnailResponse{}
+	mi := &file_file_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetThumbnailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetThumbnailResponse) ProtoMessage() {}
+
+func (x *GetThumbnailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetThumbnailResponse.ProtoReflect.Descriptor instead.
+func (*GetThumbnailResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetThumbnailResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *GetThumbnailResponse) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *GetThumbnailResponse) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GetThumbnailResponse) GetPending() bool {
+	if x != nil {
+		return x.Pending
+	}
+	return false
+}
+
+// 设置或删除文件标签
+type UpdateFileTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Set           map[string]string      `protobuf:"bytes,3,rep,name=set,proto3" json:"set,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Remove        []string               `protobuf:"bytes,4,rep,name=remove,proto3" json:"remove,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFileTagsRequest) Reset() {
+	*x = UpdateFileTagsRequest{}
+	mi := &file_file_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFileTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFileTagsRequest) ProtoMessage() {}
+
+func (x *UpdateFileTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFileTagsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFileTagsRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *UpdateFileTagsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UpdateFileTagsRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *UpdateFileTagsRequest) GetSet() map[string]string {
+	if x != nil {
+		return x.Set
+	}
+	return nil
+}
+
+func (x *UpdateFileTagsRequest) GetRemove() []string {
+	if x != nil {
+		return x.Remove
+	}
+	return nil
+}
+
+type UpdateFileTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFileTagsResponse) Reset() {
+	*x = UpdateFileTagsResponse{}
+	mi := &file_file_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFileTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFileTagsResponse) ProtoMessage() {}
+
+func (x *UpdateFileTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFileTagsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFileTagsResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *UpdateFileTagsResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+// 复制文件的当前版本，内容不重新上传，复制的大小计入 user_id 的已用空间
+type CopyFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 新文件的所有者，调用方负责校验其能否读取源文件
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	FolderId      int64                  `protobuf:"varint,3,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"` // 目标文件夹ID，0 表示根目录
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`                          // 为空时沿用源文件名，重名时追加序号
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyFileRequest) Reset() {
+	*x = CopyFileRequest{}
+	mi := &file_file_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyFileRequest) ProtoMessage() {}
+
+func (x *CopyFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyFileRequest.ProtoReflect.Descriptor instead.
+func (*CopyFileRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *CopyFileRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CopyFileRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *CopyFileRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *CopyFileRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CopyFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyFileResponse) Reset() {
+	*x = CopyFileResponse{}
+	mi := &file_file_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyFileResponse) ProtoMessage() {}
+
+func (x *CopyFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyFileResponse.ProtoReflect.Descriptor instead.
+func (*CopyFileResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *CopyFileResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+// 移动文件，只能在用户自己的目录间移动
+type MoveFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	FolderId      int64                  `protobuf:"varint,4,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"` // 目标文件夹ID，0 表示根目录
+	Name          string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`                          // 为空时沿用原文件名
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFileRequest) Reset() {
+	*x = MoveFileRequest{}
+	mi := &file_file_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFileRequest) ProtoMessage() {}
+
+func (x *MoveFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFileRequest.ProtoReflect.Descriptor instead.
+func (*MoveFileRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *MoveFileRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *MoveFileRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *MoveFileRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *MoveFileRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type MoveFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFileResponse) Reset() {
+	*x = MoveFileResponse{}
+	mi := &file_file_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFileResponse) ProtoMessage() {}
+
+func (x *MoveFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFileResponse.ProtoReflect.Descriptor instead.
+func (*MoveFileResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *MoveFileResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+// 从 http(s) 地址导入文件，远程内容在后台写入，进度通过 GetUploadProgress 查询，CancelUpload 可中止
+type ImportFromURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	FolderId      int64                  `protobuf:"varint,3,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"` // 目标文件夹ID，0 表示根目录
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`                          // 为空时取远程提供的文件名或 URL 中的文件名
+	Mtime         int64                  `protobuf:"varint,5,opt,name=mtime,proto3" json:"mtime,omitempty"`                       // 为0时使用远程的 Last-Modified
+	Tags          map[string]string      `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportFromURLRequest) Reset() {
+	*x = ImportFromURLRequest{}
+	mi := &file_file_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportFromURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportFromURLRequest) ProtoMessage() {}
+
+func (x *ImportFromURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportFromURLRequest.ProtoReflect.Descriptor instead.
+func (*ImportFromURLRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ImportFromURLRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ImportFromURLRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ImportFromURLRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+func (x *ImportFromURLRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImportFromURLRequest) GetMtime() int64 {
+	if x != nil {
+		return x.Mtime
+	}
+	return 0
+}
+
+func (x *ImportFromURLRequest) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type ImportFromURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"` // 上传中的文件，远程未声明大小时 size 为0
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportFromURLResponse) Reset() {
+	*x = ImportFromURLResponse{}
+	mi := &file_file_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportFromURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportFromURLResponse) ProtoMessage() {}
+
+func (x *ImportFromURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportFromURLResponse.ProtoReflect.Descriptor instead.
+func (*ImportFromURLResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *ImportFromURLResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+// 把 zip、tar 或 tar.gz 文件解压到文件夹下，解压后的总大小计入用户已用空间
+type ExtractArchiveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`       // 压缩包文件
+	FolderId      int64                  `protobuf:"varint,3,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"` // 目标文件夹ID，0 表示根目录
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtractArchiveRequest) Reset() {
+	*x = ExtractArchiveRequest{}
+	mi := &file_file_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractArchiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractArchiveRequest) ProtoMessage() {}
+
+func (x *ExtractArchiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractArchiveRequest.ProtoReflect.Descriptor instead.
+func (*ExtractArchiveRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ExtractArchiveRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ExtractArchiveRequest) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *ExtractArchiveRequest) GetFolderId() int64 {
+	if x != nil {
+		return x.FolderId
+	}
+	return 0
+}
+
+type ExtractArchiveResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Files          []*FileInfo            `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`                                          // 新建的文件
+	FoldersCreated int32                  `protobuf:"varint,2,opt,name=folders_created,json=foldersCreated,proto3" json:"folders_created,omitempty"` // 新建的文件夹数
+	Skipped        []string               `protobuf:"bytes,3,rep,name=skipped,proto3" json:"skipped,omitempty"`                                      // 跳过的符号链接等特殊条目
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExtractArchiveResponse) Reset() {
+	*x = ExtractArchiveResponse{}
+	mi := &file_file_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtractArchiveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractArchiveResponse) ProtoMessage() {}
+
+func (x *ExtractArchiveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractArchiveResponse.ProtoReflect.Descriptor instead.
+func (*ExtractArchiveResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *ExtractArchiveResponse) GetFiles() []*FileInfo {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ExtractArchiveResponse) GetFoldersCreated() int32 {
+	if x != nil {
+		return x.FoldersCreated
+	}
+	return 0
+}
+
+func (x *ExtractArchiveResponse) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// 完整性校验发现的问题
+type ScrubFinding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FileId        int64                  `protobuf:"varint,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	VersionId     int64                  `protobuf:"varint,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	ObjectName    string                 `protobuf:"bytes,4,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	Problem       string                 `protobuf:"bytes,5,opt,name=problem,proto3" json:"problem,omitempty"` // missing、size_mismatch、checksum_mismatch、unreadable
+	Detail        string                 `protobuf:"bytes,6,opt,name=detail,proto3" json:"detail,omitempty"`
+	FirstSeenAt   int64                  `protobuf:"varint,7,opt,name=first_seen_at,json=firstSeenAt,proto3" json:"first_seen_at,omitempty"`
+	LastSeenAt    int64                  `protobuf:"varint,8,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScrubFinding) Reset() {
+	*x = ScrubFinding{}
+	mi := &file_file_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScrubFinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrubFinding) ProtoMessage() {}
+
+func (x *ScrubFinding) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrubFinding.ProtoReflect.Descriptor instead.
+func (*ScrubFinding) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *ScrubFinding) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ScrubFinding) GetFileId() int64 {
+	if x != nil {
+		return x.FileId
+	}
+	return 0
+}
+
+func (x *ScrubFinding) GetVersionId() int64 {
+	if x != nil {
+		return x.VersionId
+	}
+	return 0
+}
+
+func (x *ScrubFinding) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *ScrubFinding) GetProblem() string {
+	if x != nil {
+		return x.Problem
+	}
+	return ""
+}
+
+func (x *ScrubFinding) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *ScrubFinding) GetFirstSeenAt() int64 {
+	if x != nil {
+		return x.FirstSeenAt
+	}
+	return 0
+}
+
+func (x *ScrubFinding) GetLastSeenAt() int64 {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return 0
+}
+
+// 管理接口：列出有未解决问题的文件
+type ListCorruptedFilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cursor        string                 `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCorruptedFilesRequest) Reset() {
+	*x = ListCorruptedFilesRequest{}
+	mi := &file_file_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCorruptedFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCorruptedFilesRequest) ProtoMessage() {}
+
+func (x *ListCorruptedFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -643,42 +4673,48 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ListCorruptedFilesRequest.ProtoReflect.Descriptor instead.
+func (*ListCorruptedFilesRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *DeleteRequest) GetFileId() int64 {
+func (x *ListCorruptedFilesRequest) GetCursor() string {
 	if x != nil {
-		return x.FileId
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListCorruptedFilesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
 	}
 	return 0
 }
 
-// 生成预签名url
-type GeneratePresignedURLRequest struct {
+type CorruptedFile struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
-	ExpireSeconds int32                  `protobuf:"varint,2,opt,name=expire_seconds,json=expireSeconds,proto3" json:"expire_seconds,omitempty"` // 过期时间（秒），默认3600秒
+	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Findings      []*ScrubFinding        `protobuf:"bytes,2,rep,name=findings,proto3" json:"findings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GeneratePresignedURLRequest) Reset() {
-	*x = GeneratePresignedURLRequest{}
-	mi := &file_file_proto_msgTypes[11]
+func (x *CorruptedFile) Reset() {
+	*x = CorruptedFile{}
+	mi := &file_file_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GeneratePresignedURLRequest) String() string {
+func (x *CorruptedFile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GeneratePresignedURLRequest) ProtoMessage() {}
+func (*CorruptedFile) ProtoMessage() {}
 
-func (x *GeneratePresignedURLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[11]
+func (x *CorruptedFile) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -689,48 +4725,48 @@ func (x *GeneratePresignedURLRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GeneratePresignedURLRequest.ProtoReflect.Descriptor instead.
-func (*GeneratePresignedURLRequest) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use CorruptedFile.ProtoReflect.Descriptor instead.
+func (*CorruptedFile) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *GeneratePresignedURLRequest) GetFileId() int64 {
+func (x *CorruptedFile) GetFile() *FileInfo {
 	if x != nil {
-		return x.FileId
+		return x.File
 	}
-	return 0
+	return nil
 }
 
-func (x *GeneratePresignedURLRequest) GetExpireSeconds() int32 {
+func (x *CorruptedFile) GetFindings() []*ScrubFinding {
 	if x != nil {
-		return x.ExpireSeconds
+		return x.Findings
 	}
-	return 0
+	return nil
 }
 
-type GeneratePresignedURLResponse struct {
+type ListCorruptedFilesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	ExpireAt      int64                  `protobuf:"varint,2,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"` // 过期时间戳
+	Files         []*CorruptedFile       `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GeneratePresignedURLResponse) Reset() {
-	*x = GeneratePresignedURLResponse{}
-	mi := &file_file_proto_msgTypes[12]
+func (x *ListCorruptedFilesResponse) Reset() {
+	*x = ListCorruptedFilesResponse{}
+	mi := &file_file_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GeneratePresignedURLResponse) String() string {
+func (x *ListCorruptedFilesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GeneratePresignedURLResponse) ProtoMessage() {}
+func (*ListCorruptedFilesResponse) ProtoMessage() {}
 
-func (x *GeneratePresignedURLResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[12]
+func (x *ListCorruptedFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -741,48 +4777,48 @@ func (x *GeneratePresignedURLResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GeneratePresignedURLResponse.ProtoReflect.Descriptor instead.
-func (*GeneratePresignedURLResponse) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ListCorruptedFilesResponse.ProtoReflect.Descriptor instead.
+func (*ListCorruptedFilesResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *GeneratePresignedURLResponse) GetUrl() string {
+func (x *ListCorruptedFilesResponse) GetFiles() []*CorruptedFile {
 	if x != nil {
-		return x.Url
+		return x.Files
 	}
-	return ""
+	return nil
 }
 
-func (x *GeneratePresignedURLResponse) GetExpireAt() int64 {
+func (x *ListCorruptedFilesResponse) GetNextCursor() string {
 	if x != nil {
-		return x.ExpireAt
+		return x.NextCursor
 	}
-	return 0
+	return ""
 }
 
-// 获取文件信息
-type GetFileInfoRequest struct {
+// 管理接口：立即校验一个文件
+type ScrubFileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetFileInfoRequest) Reset() {
-	*x = GetFileInfoRequest{}
-	mi := &file_file_proto_msgTypes[13]
+func (x *ScrubFileRequest) Reset() {
+	*x = ScrubFileRequest{}
+	mi := &file_file_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetFileInfoRequest) String() string {
+func (x *ScrubFileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetFileInfoRequest) ProtoMessage() {}
+func (*ScrubFileRequest) ProtoMessage() {}
 
-func (x *GetFileInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[13]
+func (x *ScrubFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -793,40 +4829,40 @@ func (x *GetFileInfoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetFileInfoRequest.ProtoReflect.Descriptor instead.
-func (*GetFileInfoRequest) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ScrubFileRequest.ProtoReflect.Descriptor instead.
+func (*ScrubFileRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{77}
 }
 
-func (x *GetFileInfoRequest) GetFileId() int64 {
+func (x *ScrubFileRequest) GetFileId() int64 {
 	if x != nil {
 		return x.FileId
 	}
 	return 0
 }
 
-type GetFileInfoResponse struct {
+type ScrubFileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Findings      []*ScrubFinding        `protobuf:"bytes,1,rep,name=findings,proto3" json:"findings,omitempty"` // 为空表示未发现问题
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetFileInfoResponse) Reset() {
-	*x = GetFileInfoResponse{}
-	mi := &file_file_proto_msgTypes[14]
+func (x *ScrubFileResponse) Reset() {
+	*x = ScrubFileResponse{}
+	mi := &file_file_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetFileInfoResponse) String() string {
+func (x *ScrubFileResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetFileInfoResponse) ProtoMessage() {}
+func (*ScrubFileResponse) ProtoMessage() {}
 
-func (x *GetFileInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[14]
+func (x *ScrubFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -837,41 +4873,43 @@ func (x *GetFileInfoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetFileInfoResponse.ProtoReflect.Descriptor instead.
-func (*GetFileInfoResponse) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ScrubFileResponse.ProtoReflect.Descriptor instead.
+func (*ScrubFileResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{78}
 }
 
-func (x *GetFileInfoResponse) GetFile() *FileInfo {
+func (x *ScrubFileResponse) GetFindings() []*ScrubFinding {
 	if x != nil {
-		return x.File
+		return x.Findings
 	}
 	return nil
 }
 
-// 获取上传进度
-type GetUploadProgressRequest struct {
+// 管理接口：对账对象存储和数据库，默认只报告
+type ReconcileObjectsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Apply         bool                   `protobuf:"varint,1,opt,name=apply,proto3" json:"apply,omitempty"`                                   // 为 true 时删除孤立对象并处理对象缺失的记录
+	GraceSeconds  int64                  `protobuf:"varint,2,opt,name=grace_seconds,json=graceSeconds,proto3" json:"grace_seconds,omitempty"` // 不处理最近这段时间内写入的对象，0 表示24小时
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                                   // 每类最多返回的条目数，0 表示1000，计数不受限制
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUploadProgressRequest) Reset() {
-	*x = GetUploadProgressRequest{}
-	mi := &file_file_proto_msgTypes[15]
+func (x *ReconcileObjectsRequest) Reset() {
+	*x = ReconcileObjectsRequest{}
+	mi := &file_file_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUploadProgressRequest) String() string {
+func (x *ReconcileObjectsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUploadProgressRequest) ProtoMessage() {}
+func (*ReconcileObjectsRequest) ProtoMessage() {}
 
-func (x *GetUploadProgressRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[15]
+func (x *ReconcileObjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -882,42 +4920,56 @@ func (x *GetUploadProgressRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUploadProgressRequest.ProtoReflect.Descriptor instead.
-func (*GetUploadProgressRequest) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ReconcileObjectsRequest.ProtoReflect.Descriptor instead.
+func (*ReconcileObjectsRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{79}
 }
 
-func (x *GetUploadProgressRequest) GetFileId() int64 {
+func (x *ReconcileObjectsRequest) GetApply() bool {
 	if x != nil {
-		return x.FileId
+		return x.Apply
+	}
+	return false
+}
+
+func (x *ReconcileObjectsRequest) GetGraceSeconds() int64 {
+	if x != nil {
+		return x.GraceSeconds
 	}
 	return 0
 }
 
-type GetUploadProgressResponse struct {
+func (x *ReconcileObjectsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type OrphanObject struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UploadedSize  int64                  `protobuf:"varint,1,opt,name=uploaded_size,json=uploadedSize,proto3" json:"uploaded_size,omitempty"`
-	TotalSize     int64                  `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
-	Progress      float64                `protobuf:"fixed64,3,opt,name=progress,proto3" json:"progress,omitempty"` // 上传进度百分比 (0-100)
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	ModifiedAt    int64                  `protobuf:"varint,3,opt,name=modified_at,json=modifiedAt,proto3" json:"modified_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUploadProgressResponse) Reset() {
-	*x = GetUploadProgressResponse{}
-	mi := &file_file_proto_msgTypes[16]
+func (x *OrphanObject) Reset() {
+	*x = OrphanObject{}
+	mi := &file_file_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUploadProgressResponse) String() string {
+func (x *OrphanObject) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUploadProgressResponse) ProtoMessage() {}
+func (*OrphanObject) ProtoMessage() {}
 
-func (x *GetUploadProgressResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[16]
+func (x *OrphanObject) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -928,56 +4980,56 @@ func (x *GetUploadProgressResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUploadProgressResponse.ProtoReflect.Descriptor instead.
-func (*GetUploadProgressResponse) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use OrphanObject.ProtoReflect.Descriptor instead.
+func (*OrphanObject) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{80}
 }
 
-func (x *GetUploadProgressResponse) GetUploadedSize() int64 {
+func (x *OrphanObject) GetKey() string {
 	if x != nil {
-		return x.UploadedSize
+		return x.Key
 	}
-	return 0
+	return ""
 }
 
-func (x *GetUploadProgressResponse) GetTotalSize() int64 {
+func (x *OrphanObject) GetSize() int64 {
 	if x != nil {
-		return x.TotalSize
+		return x.Size
 	}
 	return 0
 }
 
-func (x *GetUploadProgressResponse) GetProgress() float64 {
+func (x *OrphanObject) GetModifiedAt() int64 {
 	if x != nil {
-		return x.Progress
+		return x.ModifiedAt
 	}
 	return 0
 }
 
-// 获取未完成分片
-type GetIncompletePartsRequest struct {
+type MissingObject struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
-	TotalParts    int32                  `protobuf:"varint,2,opt,name=total_parts,json=totalParts,proto3" json:"total_parts,omitempty"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // blob、chunk、derivative、part
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	ObjectName    string                 `protobuf:"bytes,3,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetIncompletePartsRequest) Reset() {
-	*x = GetIncompletePartsRequest{}
-	mi := &file_file_proto_msgTypes[17]
+func (x *MissingObject) Reset() {
+	*x = MissingObject{}
+	mi := &file_file_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetIncompletePartsRequest) String() string {
+func (x *MissingObject) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetIncompletePartsRequest) ProtoMessage() {}
+func (*MissingObject) ProtoMessage() {}
 
-func (x *GetIncompletePartsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[17]
+func (x *MissingObject) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -988,47 +5040,63 @@ func (x *GetIncompletePartsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetIncompletePartsRequest.ProtoReflect.Descriptor instead.
-func (*GetIncompletePartsRequest) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use MissingObject.ProtoReflect.Descriptor instead.
+func (*MissingObject) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{81}
 }
 
-func (x *GetIncompletePartsRequest) GetFileId() int64 {
+func (x *MissingObject) GetKind() string {
 	if x != nil {
-		return x.FileId
+		return x.Kind
 	}
-	return 0
+	return ""
 }
 
-func (x *GetIncompletePartsRequest) GetTotalParts() int32 {
+func (x *MissingObject) GetId() int64 {
 	if x != nil {
-		return x.TotalParts
+		return x.Id
 	}
 	return 0
 }
 
-type GetIncompletePartsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MissingParts  []int32                `protobuf:"varint,1,rep,packed,name=missing_parts,json=missingParts,proto3" json:"missing_parts,omitempty"` // 缺失的分片编号列表
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *MissingObject) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
 }
 
-func (x *GetIncompletePartsResponse) Reset() {
-	*x = GetIncompletePartsResponse{}
-	mi := &file_file_proto_msgTypes[18]
+type ReconcileObjectsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Objects        int64                  `protobuf:"varint,1,opt,name=objects,proto3" json:"objects,omitempty"`
+	OrphanCount    int64                  `protobuf:"varint,2,opt,name=orphan_count,json=orphanCount,proto3" json:"orphan_count,omitempty"`
+	OrphanBytes    int64                  `protobuf:"varint,3,opt,name=orphan_bytes,json=orphanBytes,proto3" json:"orphan_bytes,omitempty"`
+	MissingCount   int64                  `protobuf:"varint,4,opt,name=missing_count,json=missingCount,proto3" json:"missing_count,omitempty"`
+	Orphans        []*OrphanObject        `protobuf:"bytes,5,rep,name=orphans,proto3" json:"orphans,omitempty"`
+	Missing        []*MissingObject       `protobuf:"bytes,6,rep,name=missing,proto3" json:"missing,omitempty"`
+	DeletedObjects int64                  `protobuf:"varint,7,opt,name=deleted_objects,json=deletedObjects,proto3" json:"deleted_objects,omitempty"`
+	MarkedFiles    int64                  `protobuf:"varint,8,opt,name=marked_files,json=markedFiles,proto3" json:"marked_files,omitempty"`
+	ResetBlobs     int64                  `protobuf:"varint,9,opt,name=reset_blobs,json=resetBlobs,proto3" json:"reset_blobs,omitempty"`
+	DeletedParts   int64                  `protobuf:"varint,10,opt,name=deleted_parts,json=deletedParts,proto3" json:"deleted_parts,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReconcileObjectsResponse) Reset() {
+	*x = ReconcileObjectsResponse{}
+	mi := &file_file_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetIncompletePartsResponse) String() string {
+func (x *ReconcileObjectsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetIncompletePartsResponse) ProtoMessage() {}
+func (*ReconcileObjectsResponse) ProtoMessage() {}
 
-func (x *GetIncompletePartsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[18]
+func (x *ReconcileObjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1039,59 +5107,77 @@ func (x *GetIncompletePartsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetIncompletePartsResponse.ProtoReflect.Descriptor instead.
-func (*GetIncompletePartsResponse) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use ReconcileObjectsResponse.ProtoReflect.Descriptor instead.
+func (*ReconcileObjectsResponse) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *GetIncompletePartsResponse) GetMissingParts() []int32 {
+func (x *ReconcileObjectsResponse) GetObjects() int64 {
 	if x != nil {
-		return x.MissingParts
+		return x.Objects
 	}
-	return nil
+	return 0
 }
 
-// 取消上传
-type CancelUploadRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        int64                  `protobuf:"varint,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ReconcileObjectsResponse) GetOrphanCount() int64 {
+	if x != nil {
+		return x.OrphanCount
+	}
+	return 0
 }
 
-func (x *CancelUploadRequest) Reset() {
-	*x = CancelUploadRequest{}
-	mi := &file_file_proto_msgTypes[19]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ReconcileObjectsResponse) GetOrphanBytes() int64 {
+	if x != nil {
+		return x.OrphanBytes
+	}
+	return 0
 }
 
-func (x *CancelUploadRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ReconcileObjectsResponse) GetMissingCount() int64 {
+	if x != nil {
+		return x.MissingCount
+	}
+	return 0
 }
 
-func (*CancelUploadRequest) ProtoMessage() {}
+func (x *ReconcileObjectsResponse) GetOrphans() []*OrphanObject {
+	if x != nil {
+		return x.Orphans
+	}
+	return nil
+}
 
-func (x *CancelUploadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_file_proto_msgTypes[19]
+func (x *ReconcileObjectsResponse) GetMissing() []*MissingObject {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Missing
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use CancelUploadRequest.ProtoReflect.Descriptor instead.
-func (*CancelUploadRequest) Descriptor() ([]byte, []int) {
-	return file_file_proto_rawDescGZIP(), []int{19}
+func (x *ReconcileObjectsResponse) GetDeletedObjects() int64 {
+	if x != nil {
+		return x.DeletedObjects
+	}
+	return 0
 }
 
-func (x *CancelUploadRequest) GetFileId() int64 {
+func (x *ReconcileObjectsResponse) GetMarkedFiles() int64 {
 	if x != nil {
-		return x.FileId
+		return x.MarkedFiles
+	}
+	return 0
+}
+
+func (x *ReconcileObjectsResponse) GetResetBlobs() int64 {
+	if x != nil {
+		return x.ResetBlobs
+	}
+	return 0
+}
+
+func (x *ReconcileObjectsResponse) GetDeletedParts() int64 {
+	if x != nil {
+		return x.DeletedParts
 	}
 	return 0
 }
@@ -1101,19 +5187,70 @@ var File_file_proto protoreflect.FileDescriptor
 const file_file_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"file.proto\x12\ffile_service\x1a\x1bgoogle/protobuf/empty.proto\"\x84\x01\n" +
+	"file.proto\x12\ffile_service\x1a\x1bgoogle/protobuf/empty.proto\"\xf9\x03\n" +
 	"\bFileInfo\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
 	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x16\n" +
 	"\x06userID\x18\x04 \x01(\x03R\x06userID\x12\x10\n" +
 	"\x03md5\x18\x05 \x01(\tR\x03md5\x12\x16\n" +
-	"\x06status\x18\x06 \x01(\x05R\x06status\"n\n" +
+	"\x06status\x18\x06 \x01(\x05R\x06status\x12\x1b\n" +
+	"\tfolder_id\x18\a \x01(\x03R\bfolderId\x12\x1d\n" +
+	"\n" +
+	"trashed_at\x18\b \x01(\x03R\ttrashedAt\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\t \x01(\x03R\tversionId\x12\x16\n" +
+	"\x06sha256\x18\n" +
+	" \x01(\tR\x06sha256\x12\x1b\n" +
+	"\tmime_type\x18\v \x01(\tR\bmimeType\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\f \x01(\x03R\tcreatedAt\x12 \n" +
+	"\vcompression\x18\r \x01(\tR\vcompression\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x0e \x01(\x03R\tupdatedAt\x12\x14\n" +
+	"\x05mtime\x18\x0f \x01(\x03R\x05mtime\x124\n" +
+	"\x04tags\x18\x10 \x03(\v2 .file_service.FileInfo.TagsEntryR\x04tags\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x97\x02\n" +
+	"\vVersionInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x10\n" +
+	"\x03md5\x18\x05 \x01(\tR\x03md5\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\x05R\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"is_current\x18\b \x01(\bR\tisCurrent\x12\x16\n" +
+	"\x06sha256\x18\t \x01(\tR\x06sha256\x12\x1b\n" +
+	"\tmime_type\x18\n" +
+	" \x01(\tR\bmimeType\x12\x14\n" +
+	"\x05mtime\x18\v \x01(\x03R\x05mtime\"\xa4\x01\n" +
+	"\n" +
+	"FolderInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\tparent_id\x18\x03 \x01(\x03R\bparentId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\x03R\tupdatedAt\"\xd3\x02\n" +
 	"\x11InitUploadRequest\x12\x1b\n" +
 	"\tfile_name\x18\x01 \x01(\tR\bfileName\x12\x12\n" +
 	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x10\n" +
 	"\x03md5\x18\x03 \x01(\tR\x03md5\x12\x16\n" +
-	"\x06userID\x18\x04 \x01(\x03R\x06userID\"@\n" +
+	"\x06userID\x18\x04 \x01(\x03R\x06userID\x12\x1b\n" +
+	"\tfolder_id\x18\x05 \x01(\x03R\bfolderId\x12\x16\n" +
+	"\x06sha256\x18\x06 \x01(\tR\x06sha256\x12 \n" +
+	"\vcompression\x18\a \x01(\tR\vcompression\x12\x14\n" +
+	"\x05mtime\x18\b \x01(\x03R\x05mtime\x12=\n" +
+	"\x04tags\x18\t \x03(\v2).file_service.InitUploadRequest.TagsEntryR\x04tags\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"@\n" +
 	"\x12InitUploadResponse\x12*\n" +
 	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\"n\n" +
 	"\fPartMetadata\x12\x17\n" +
@@ -1131,19 +5268,29 @@ const file_file_proto_rawDesc = "" +
 	"\x15CompleteUploadRequest\x12\x17\n" +
 	"\afile_id\x18\x01 \x01(\x03R\x06fileId\"D\n" +
 	"\x16CompleteUploadResponse\x12*\n" +
-	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\"K\n" +
+	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\"\x9a\x01\n" +
 	"\x0fDownloadRequest\x12\x17\n" +
 	"\afile_id\x18\x01 \x01(\x03R\x06fileId\x12\x1f\n" +
 	"\vpart_number\x18\x02 \x01(\x05R\n" +
-	"partNumber\"8\n" +
+	"partNumber\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\x03R\tversionId\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06length\x18\x05 \x01(\x03R\x06length\"}\n" +
 	"\x10DownloadResponse\x12\x12\n" +
 	"\x04data\x18\x01 \x01(\fR\x04data\x12\x10\n" +
-	"\x03md5\x18\x03 \x01(\tR\x03md5\"(\n" +
+	"\x03md5\x18\x03 \x01(\tR\x03md5\x12\x12\n" +
+	"\x04last\x18\x04 \x01(\bR\x04last\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12\x1b\n" +
+	"\tpart_etag\x18\x06 \x01(\tR\bpartEtag\"A\n" +
 	"\rDeleteRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\x03R\x06fileId\"]\n" +
+	"\afile_id\x18\x01 \x01(\x03R\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\"|\n" +
 	"\x1bGeneratePresignedURLRequest\x12\x17\n" +
 	"\afile_id\x18\x01 \x01(\x03R\x06fileId\x12%\n" +
-	"\x0eexpire_seconds\x18\x02 \x01(\x05R\rexpireSeconds\"M\n" +
+	"\x0eexpire_seconds\x18\x02 \x01(\x05R\rexpireSeconds\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\x03R\tversionId\"M\n" +
 	"\x1cGeneratePresignedURLResponse\x12\x10\n" +
 	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1b\n" +
 	"\texpire_at\x18\x02 \x01(\x03R\bexpireAt\"-\n" +
@@ -1152,20 +5299,297 @@ const file_file_proto_rawDesc = "" +
 	"\x13GetFileInfoResponse\x12*\n" +
 	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\"3\n" +
 	"\x18GetUploadProgressRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\x03R\x06fileId\"{\n" +
+	"\afile_id\x18\x01 \x01(\x03R\x06fileId\"\xc3\x01\n" +
 	"\x19GetUploadProgressResponse\x12#\n" +
 	"\ruploaded_size\x18\x01 \x01(\x03R\fuploadedSize\x12\x1d\n" +
 	"\n" +
 	"total_size\x18\x02 \x01(\x03R\ttotalSize\x12\x1a\n" +
-	"\bprogress\x18\x03 \x01(\x01R\bprogress\"U\n" +
+	"\bprogress\x18\x03 \x01(\x01R\bprogress\x12#\n" +
+	"\rimport_status\x18\x04 \x01(\tR\fimportStatus\x12!\n" +
+	"\fimport_error\x18\x05 \x01(\tR\vimportError\"U\n" +
 	"\x19GetIncompletePartsRequest\x12\x17\n" +
 	"\afile_id\x18\x01 \x01(\x03R\x06fileId\x12\x1f\n" +
 	"\vtotal_parts\x18\x02 \x01(\x05R\n" +
 	"totalParts\"A\n" +
 	"\x1aGetIncompletePartsResponse\x12#\n" +
-	"\rmissing_parts\x18\x01 \x03(\x05R\fmissingParts\".\n" +
+	"\rmissing_parts\x18\x01 \x03(\x05R\fmissingParts\"3\n" +
+	"\x18ListUploadedPartsRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\x03R\x06fileId\"U\n" +
+	"\fUploadedPart\x12\x1f\n" +
+	"\vpart_number\x18\x01 \x01(\x05R\n" +
+	"partNumber\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x10\n" +
+	"\x03md5\x18\x03 \x01(\tR\x03md5\"M\n" +
+	"\x19ListUploadedPartsResponse\x120\n" +
+	"\x05parts\x18\x01 \x03(\v2\x1a.file_service.UploadedPartR\x05parts\"J\n" +
+	"\x16GetUploadOffsetRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\x03R\x06fileId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\"\xaa\x01\n" +
+	"\x17GetUploadOffsetResponse\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x03R\x06offset\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x02 \x01(\x03R\ttotalSize\x12\x1b\n" +
+	"\tpart_size\x18\x03 \x01(\x03R\bpartSize\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\x12\x1c\n" +
+	"\tcompleted\x18\x05 \x01(\bR\tcompleted\".\n" +
 	"\x13CancelUploadRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\x03R\x06fileId2\xf5\x06\n" +
+	"\afile_id\x18\x01 \x01(\x03R\x06fileId\"_\n" +
+	"\x13CreateFolderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\x03R\bparentId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"H\n" +
+	"\x14CreateFolderResponse\x120\n" +
+	"\x06folder\x18\x01 \x01(\v2\x18.file_service.FolderInfoR\x06folder\"_\n" +
+	"\x13RenameFolderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\x03R\bfolderId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"H\n" +
+	"\x14RenameFolderResponse\x120\n" +
+	"\x06folder\x18\x01 \x01(\v2\x18.file_service.FolderInfoR\x06folder\"s\n" +
+	"\x11MoveFolderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\x03R\bfolderId\x12(\n" +
+	"\x10target_parent_id\x18\x03 \x01(\x03R\x0etargetParentId\"F\n" +
+	"\x12MoveFolderResponse\x120\n" +
+	"\x06folder\x18\x01 \x01(\v2\x18.file_service.FolderInfoR\x06folder\"i\n" +
+	"\x13DeleteFolderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\x03R\bfolderId\x12\x1c\n" +
+	"\trecursive\x18\x03 \x01(\bR\trecursive\"\xac\x01\n" +
+	"\x14ListDirectoryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\x03R\bfolderId\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x17\n" +
+	"\asort_by\x18\x05 \x01(\tR\x06sortBy\x12\x14\n" +
+	"\x05order\x18\x06 \x01(\tR\x05order\"\x8f\x01\n" +
+	"\x15ListDirectoryResponse\x122\n" +
+	"\afolders\x18\x01 \x03(\v2\x18.file_service.FolderInfoR\afolders\x12,\n" +
+	"\x05files\x18\x02 \x03(\v2\x16.file_service.FileInfoR\x05files\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\"\x8f\x01\n" +
+	"\x11LookupPathRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\x03R\bfolderId\x12\x18\n" +
+	"\afolders\x18\x03 \x03(\tR\afolders\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x16\n" +
+	"\x06create\x18\x05 \x01(\bR\x06create\"s\n" +
+	"\x12LookupPathResponse\x12\x1b\n" +
+	"\tfolder_id\x18\x01 \x01(\x03R\bfolderId\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x12*\n" +
+	"\x04file\x18\x03 \x01(\v2\x16.file_service.FileInfoR\x04file\"\\\n" +
+	"\x10ListTrashRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"W\n" +
+	"\x11ListTrashResponse\x12,\n" +
+	"\x05files\x18\x01 \x03(\v2\x16.file_service.FileInfoR\x05files\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"F\n" +
+	"\x12RestoreFileRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\"A\n" +
+	"\x13RestoreFileResponse\x12*\n" +
+	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\",\n" +
+	"\x11EmptyTrashRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"V\n" +
+	"\x12EmptyTrashResponse\x12!\n" +
+	"\fpurged_count\x18\x01 \x01(\x03R\vpurgedCount\x12\x1d\n" +
+	"\n" +
+	"freed_size\x18\x02 \x01(\x03R\tfreedSize\"G\n" +
+	"\x13ListVersionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\"M\n" +
+	"\x14ListVersionsResponse\x125\n" +
+	"\bversions\x18\x01 \x03(\v2\x19.file_service.VersionInfoR\bversions\"d\n" +
+	"\x11GetVersionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\x03R\tversionId\"I\n" +
+	"\x12GetVersionResponse\x123\n" +
+	"\aversion\x18\x01 \x01(\v2\x19.file_service.VersionInfoR\aversion\"h\n" +
+	"\x15RestoreVersionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\x03R\tversionId\"D\n" +
+	"\x16RestoreVersionResponse\x12*\n" +
+	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\"g\n" +
+	"\x14DeleteVersionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\x03R\tversionId\"Y\n" +
+	"\x10ListFilesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"b\n" +
+	"\x11ListFilesResponse\x12,\n" +
+	"\x05files\x18\x01 \x03(\v2\x16.file_service.FileInfoR\x05files\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\xbf\x04\n" +
+	"\x12SearchFilesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\bmin_size\x18\x03 \x01(\x03R\aminSize\x12\x19\n" +
+	"\bmax_size\x18\x04 \x01(\x03R\amaxSize\x12\x1b\n" +
+	"\x06status\x18\x05 \x01(\x05H\x00R\x06status\x88\x01\x01\x12#\n" +
+	"\rcreated_after\x18\x06 \x01(\x03R\fcreatedAfter\x12%\n" +
+	"\x0ecreated_before\x18\a \x01(\x03R\rcreatedBefore\x12\x1b\n" +
+	"\tmime_type\x18\b \x01(\tR\bmimeType\x12\x16\n" +
+	"\x06cursor\x18\t \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\n" +
+	" \x01(\x05R\x05limit\x12#\n" +
+	"\rupdated_after\x18\v \x01(\x03R\fupdatedAfter\x12%\n" +
+	"\x0eupdated_before\x18\f \x01(\x03R\rupdatedBefore\x12\x1f\n" +
+	"\vmtime_after\x18\r \x01(\x03R\n" +
+	"mtimeAfter\x12!\n" +
+	"\fmtime_before\x18\x0e \x01(\x03R\vmtimeBefore\x12>\n" +
+	"\x04tags\x18\x0f \x03(\v2*.file_service.SearchFilesRequest.TagsEntryR\x04tags\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\t\n" +
+	"\a_status\"d\n" +
+	"\x13SearchFilesResponse\x12,\n" +
+	"\x05files\x18\x01 \x03(\v2\x16.file_service.FileInfoR\x05files\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"y\n" +
+	"\x0fReadFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\x03R\x06fileId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x02 \x01(\x03R\tversionId\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06length\x18\x04 \x01(\x03R\x06length\"&\n" +
+	"\x10ReadFileResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"6\n" +
+	"\bChunkRef\x12\x16\n" +
+	"\x06sha256\x18\x01 \x01(\tR\x06sha256\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\"\xef\x02\n" +
+	"\x18InitChunkedUploadRequest\x12\x1b\n" +
+	"\tfile_name\x18\x01 \x01(\tR\bfileName\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x10\n" +
+	"\x03md5\x18\x03 \x01(\tR\x03md5\x12\x16\n" +
+	"\x06userID\x18\x04 \x01(\x03R\x06userID\x12\x1b\n" +
+	"\tfolder_id\x18\x05 \x01(\x03R\bfolderId\x12\x16\n" +
+	"\x06sha256\x18\x06 \x01(\tR\x06sha256\x12.\n" +
+	"\x06chunks\x18\a \x03(\v2\x16.file_service.ChunkRefR\x06chunks\x12\x14\n" +
+	"\x05mtime\x18\b \x01(\x03R\x05mtime\x12D\n" +
+	"\x04tags\x18\t \x03(\v20.file_service.InitChunkedUploadRequest.TagsEntryR\x04tags\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"n\n" +
+	"\x19InitChunkedUploadResponse\x12*\n" +
+	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\x12%\n" +
+	"\x0emissing_chunks\x18\x02 \x03(\tR\rmissingChunks\"Y\n" +
+	"\x12UploadChunkRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\x03R\x06fileId\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\tR\x06sha256\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"B\n" +
+	"\x13GetThumbnailRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\x03R\x06fileId\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x05R\x04size\"u\n" +
+	"\x14GetThumbnailResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x1b\n" +
+	"\tmime_type\x18\x02 \x01(\tR\bmimeType\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x12\x18\n" +
+	"\apending\x18\x04 \x01(\bR\apending\"\xd9\x01\n" +
+	"\x15UpdateFileTagsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\x12>\n" +
+	"\x03set\x18\x03 \x03(\v2,.file_service.UpdateFileTagsRequest.SetEntryR\x03set\x12\x16\n" +
+	"\x06remove\x18\x04 \x03(\tR\x06remove\x1a6\n" +
+	"\bSetEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"D\n" +
+	"\x16UpdateFileTagsResponse\x12*\n" +
+	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\"t\n" +
+	"\x0fCopyFileRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\x12\x1b\n" +
+	"\tfolder_id\x18\x03 \x01(\x03R\bfolderId\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\">\n" +
+	"\x10CopyFileResponse\x12*\n" +
+	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\"\x8a\x01\n" +
+	"\x0fMoveFileRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\x12\x1b\n" +
+	"\tfolder_id\x18\x04 \x01(\x03R\bfolderId\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04nameJ\x04\b\x03\x10\x04R\x0etarget_user_id\">\n" +
+	"\x10MoveFileResponse\x12*\n" +
+	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\"\x83\x02\n" +
+	"\x14ImportFromURLRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1b\n" +
+	"\tfolder_id\x18\x03 \x01(\x03R\bfolderId\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x14\n" +
+	"\x05mtime\x18\x05 \x01(\x03R\x05mtime\x12@\n" +
+	"\x04tags\x18\x06 \x03(\v2,.file_service.ImportFromURLRequest.TagsEntryR\x04tags\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"C\n" +
+	"\x15ImportFromURLResponse\x12*\n" +
+	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\"f\n" +
+	"\x15ExtractArchiveRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\x12\x1b\n" +
+	"\tfolder_id\x18\x03 \x01(\x03R\bfolderId\"\x89\x01\n" +
+	"\x16ExtractArchiveResponse\x12,\n" +
+	"\x05files\x18\x01 \x03(\v2\x16.file_service.FileInfoR\x05files\x12'\n" +
+	"\x0ffolders_created\x18\x02 \x01(\x05R\x0efoldersCreated\x12\x18\n" +
+	"\askipped\x18\x03 \x03(\tR\askipped\"\xef\x01\n" +
+	"\fScrubFinding\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\x03R\x06fileId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\x03R\tversionId\x12\x1f\n" +
+	"\vobject_name\x18\x04 \x01(\tR\n" +
+	"objectName\x12\x18\n" +
+	"\aproblem\x18\x05 \x01(\tR\aproblem\x12\x16\n" +
+	"\x06detail\x18\x06 \x01(\tR\x06detail\x12\"\n" +
+	"\rfirst_seen_at\x18\a \x01(\x03R\vfirstSeenAt\x12 \n" +
+	"\flast_seen_at\x18\b \x01(\x03R\n" +
+	"lastSeenAt\"I\n" +
+	"\x19ListCorruptedFilesRequest\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"s\n" +
+	"\rCorruptedFile\x12*\n" +
+	"\x04file\x18\x01 \x01(\v2\x16.file_service.FileInfoR\x04file\x126\n" +
+	"\bfindings\x18\x02 \x03(\v2\x1a.file_service.ScrubFindingR\bfindings\"p\n" +
+	"\x1aListCorruptedFilesResponse\x121\n" +
+	"\x05files\x18\x01 \x03(\v2\x1b.file_service.CorruptedFileR\x05files\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"+\n" +
+	"\x10ScrubFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\x03R\x06fileId\"K\n" +
+	"\x11ScrubFileResponse\x126\n" +
+	"\bfindings\x18\x01 \x03(\v2\x1a.file_service.ScrubFindingR\bfindings\"j\n" +
+	"\x17ReconcileObjectsRequest\x12\x14\n" +
+	"\x05apply\x18\x01 \x01(\bR\x05apply\x12#\n" +
+	"\rgrace_seconds\x18\x02 \x01(\x03R\fgraceSeconds\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"U\n" +
+	"\fOrphanObject\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x1f\n" +
+	"\vmodified_at\x18\x03 \x01(\x03R\n" +
+	"modifiedAt\"T\n" +
+	"\rMissingObject\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\x12\x1f\n" +
+	"\vobject_name\x18\x03 \x01(\tR\n" +
+	"objectName\"\x9e\x03\n" +
+	"\x18ReconcileObjectsResponse\x12\x18\n" +
+	"\aobjects\x18\x01 \x01(\x03R\aobjects\x12!\n" +
+	"\forphan_count\x18\x02 \x01(\x03R\vorphanCount\x12!\n" +
+	"\forphan_bytes\x18\x03 \x01(\x03R\vorphanBytes\x12#\n" +
+	"\rmissing_count\x18\x04 \x01(\x03R\fmissingCount\x124\n" +
+	"\aorphans\x18\x05 \x03(\v2\x1a.file_service.OrphanObjectR\aorphans\x125\n" +
+	"\amissing\x18\x06 \x03(\v2\x1b.file_service.MissingObjectR\amissing\x12'\n" +
+	"\x0fdeleted_objects\x18\a \x01(\x03R\x0edeletedObjects\x12!\n" +
+	"\fmarked_files\x18\b \x01(\x03R\vmarkedFiles\x12\x1f\n" +
+	"\vreset_blobs\x18\t \x01(\x03R\n" +
+	"resetBlobs\x12#\n" +
+	"\rdeleted_parts\x18\n" +
+	" \x01(\x03R\fdeletedParts2\xae\x1a\n" +
 	"\vFileService\x12O\n" +
 	"\n" +
 	"InitUpload\x12\x1f.file_service.InitUploadRequest\x1a .file_service.InitUploadResponse\x12G\n" +
@@ -1178,8 +5602,41 @@ const file_file_proto_rawDesc = "" +
 	"\x14GeneratePresignedURL\x12).file_service.GeneratePresignedURLRequest\x1a*.file_service.GeneratePresignedURLResponse\x12R\n" +
 	"\vGetFileInfo\x12 .file_service.GetFileInfoRequest\x1a!.file_service.GetFileInfoResponse\x12d\n" +
 	"\x11GetUploadProgress\x12&.file_service.GetUploadProgressRequest\x1a'.file_service.GetUploadProgressResponse\x12g\n" +
-	"\x12GetIncompleteParts\x12'.file_service.GetIncompletePartsRequest\x1a(.file_service.GetIncompletePartsResponse\x12I\n" +
-	"\fCancelUpload\x12!.file_service.CancelUploadRequest\x1a\x16.google.protobuf.EmptyB\x0fZ\r/proto;filepbb\x06proto3"
+	"\x12GetIncompleteParts\x12'.file_service.GetIncompletePartsRequest\x1a(.file_service.GetIncompletePartsResponse\x12d\n" +
+	"\x11ListUploadedParts\x12&.file_service.ListUploadedPartsRequest\x1a'.file_service.ListUploadedPartsResponse\x12^\n" +
+	"\x0fGetUploadOffset\x12$.file_service.GetUploadOffsetRequest\x1a%.file_service.GetUploadOffsetResponse\x12I\n" +
+	"\fCancelUpload\x12!.file_service.CancelUploadRequest\x1a\x16.google.protobuf.Empty\x12U\n" +
+	"\fCreateFolder\x12!.file_service.CreateFolderRequest\x1a\".file_service.CreateFolderResponse\x12U\n" +
+	"\fRenameFolder\x12!.file_service.RenameFolderRequest\x1a\".file_service.RenameFolderResponse\x12O\n" +
+	"\n" +
+	"MoveFolder\x12\x1f.file_service.MoveFolderRequest\x1a .file_service.MoveFolderResponse\x12I\n" +
+	"\fDeleteFolder\x12!.file_service.DeleteFolderRequest\x1a\x16.google.protobuf.Empty\x12X\n" +
+	"\rListDirectory\x12\".file_service.ListDirectoryRequest\x1a#.file_service.ListDirectoryResponse\x12O\n" +
+	"\n" +
+	"LookupPath\x12\x1f.file_service.LookupPathRequest\x1a .file_service.LookupPathResponse\x12L\n" +
+	"\tListTrash\x12\x1e.file_service.ListTrashRequest\x1a\x1f.file_service.ListTrashResponse\x12R\n" +
+	"\vRestoreFile\x12 .file_service.RestoreFileRequest\x1a!.file_service.RestoreFileResponse\x12O\n" +
+	"\n" +
+	"EmptyTrash\x12\x1f.file_service.EmptyTrashRequest\x1a .file_service.EmptyTrashResponse\x12U\n" +
+	"\fListVersions\x12!.file_service.ListVersionsRequest\x1a\".file_service.ListVersionsResponse\x12O\n" +
+	"\n" +
+	"GetVersion\x12\x1f.file_service.GetVersionRequest\x1a .file_service.GetVersionResponse\x12[\n" +
+	"\x0eRestoreVersion\x12#.file_service.RestoreVersionRequest\x1a$.file_service.RestoreVersionResponse\x12K\n" +
+	"\rDeleteVersion\x12\".file_service.DeleteVersionRequest\x1a\x16.google.protobuf.Empty\x12L\n" +
+	"\tListFiles\x12\x1e.file_service.ListFilesRequest\x1a\x1f.file_service.ListFilesResponse\x12R\n" +
+	"\vSearchFiles\x12 .file_service.SearchFilesRequest\x1a!.file_service.SearchFilesResponse\x12K\n" +
+	"\bReadFile\x12\x1d.file_service.ReadFileRequest\x1a\x1e.file_service.ReadFileResponse0\x01\x12d\n" +
+	"\x11InitChunkedUpload\x12&.file_service.InitChunkedUploadRequest\x1a'.file_service.InitChunkedUploadResponse\x12G\n" +
+	"\vUploadChunk\x12 .file_service.UploadChunkRequest\x1a\x16.google.protobuf.Empty\x12U\n" +
+	"\fGetThumbnail\x12!.file_service.GetThumbnailRequest\x1a\".file_service.GetThumbnailResponse\x12[\n" +
+	"\x0eUpdateFileTags\x12#.file_service.UpdateFileTagsRequest\x1a$.file_service.UpdateFileTagsResponse\x12I\n" +
+	"\bCopyFile\x12\x1d.file_service.CopyFileRequest\x1a\x1e.file_service.CopyFileResponse\x12I\n" +
+	"\bMoveFile\x12\x1d.file_service.MoveFileRequest\x1a\x1e.file_service.MoveFileResponse\x12[\n" +
+	"\x0eExtractArchive\x12#.file_service.ExtractArchiveRequest\x1a$.file_service.ExtractArchiveResponse\x12X\n" +
+	"\rImportFromURL\x12\".file_service.ImportFromURLRequest\x1a#.file_service.ImportFromURLResponse\x12g\n" +
+	"\x12ListCorruptedFiles\x12'.file_service.ListCorruptedFilesRequest\x1a(.file_service.ListCorruptedFilesResponse\x12L\n" +
+	"\tScrubFile\x12\x1e.file_service.ScrubFileRequest\x1a\x1f.file_service.ScrubFileResponse\x12a\n" +
+	"\x10ReconcileObjects\x12%.file_service.ReconcileObjectsRequest\x1a&.file_service.ReconcileObjectsResponseB\x0fZ\r/proto;filepbb\x06proto3"
 
 var (
 	file_file_proto_rawDescOnce sync.Once
@@ -1193,61 +5650,221 @@ func file_file_proto_rawDescGZIP() []byte {
 	return file_file_proto_rawDescData
 }
 
-var file_file_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_file_proto_msgTypes = make([]protoimpl.MessageInfo, 89)
 var file_file_proto_goTypes = []any{
 	(*FileInfo)(nil),                     // 0: file_service.FileInfo
-	(*InitUploadRequest)(nil),            // 1: file_service.InitUploadRequest
-	(*InitUploadResponse)(nil),           // 2: file_service.InitUploadResponse
-	(*PartMetadata)(nil),                 // 3: file_service.PartMetadata
-	(*PartContent)(nil),                  // 4: file_service.PartContent
-	(*UploadPartRequest)(nil),            // 5: file_service.UploadPartRequest
-	(*CompleteUploadRequest)(nil),        // 6: file_service.CompleteUploadRequest
-	(*CompleteUploadResponse)(nil),       // 7: file_service.CompleteUploadResponse
-	(*DownloadRequest)(nil),              // 8: file_service.DownloadRequest
-	(*DownloadResponse)(nil),             // 9: file_service.DownloadResponse
-	(*DeleteRequest)(nil),                // 10: file_service.DeleteRequest
-	(*GeneratePresignedURLRequest)(nil),  // 11: file_service.GeneratePresignedURLRequest
-	(*GeneratePresignedURLResponse)(nil), // 12: file_service.GeneratePresignedURLResponse
-	(*GetFileInfoRequest)(nil),           // 13: file_service.GetFileInfoRequest
-	(*GetFileInfoResponse)(nil),          // 14: file_service.GetFileInfoResponse
-	(*GetUploadProgressRequest)(nil),     // 15: file_service.GetUploadProgressRequest
-	(*GetUploadProgressResponse)(nil),    // 16: file_service.GetUploadProgressResponse
-	(*GetIncompletePartsRequest)(nil),    // 17: file_service.GetIncompletePartsRequest
-	(*GetIncompletePartsResponse)(nil),   // 18: file_service.GetIncompletePartsResponse
-	(*CancelUploadRequest)(nil),          // 19: file_service.CancelUploadRequest
-	(*emptypb.Empty)(nil),                // 20: google.protobuf.Empty
+	(*VersionInfo)(nil),                  // 1: file_service.VersionInfo
+	(*FolderInfo)(nil),                   // 2: file_service.FolderInfo
+	(*InitUploadRequest)(nil),            // 3: file_service.InitUploadRequest
+	(*InitUploadResponse)(nil),           // 4: file_service.InitUploadResponse
+	(*PartMetadata)(nil),                 // 5: file_service.PartMetadata
+	(*PartContent)(nil),                  // 6: file_service.PartContent
+	(*UploadPartRequest)(nil),            // 7: file_service.UploadPartRequest
+	(*CompleteUploadRequest)(nil),        // 8: file_service.CompleteUploadRequest
+	(*CompleteUploadResponse)(nil),       // 9: file_service.CompleteUploadResponse
+	(*DownloadRequest)(nil),              // 10: file_service.DownloadRequest
+	(*DownloadResponse)(nil),             // 11: file_service.DownloadResponse
+	(*DeleteRequest)(nil),                // 12: file_service.DeleteRequest
+	(*GeneratePresignedURLRequest)(nil),  // 13: file_service.GeneratePresignedURLRequest
+	(*GeneratePresignedURLResponse)(nil), // 14: file_service.GeneratePresignedURLResponse
+	(*GetFileInfoRequest)(nil),           // 15: file_service.GetFileInfoRequest
+	(*GetFileInfoResponse)(nil),          // 16: file_service.GetFileInfoResponse
+	(*GetUploadProgressRequest)(nil),     // 17: file_service.GetUploadProgressRequest
+	(*GetUploadProgressResponse)(nil),    // 18: file_service.GetUploadProgressResponse
+	(*GetIncompletePartsRequest)(nil),    // 19: file_service.GetIncompletePartsRequest
+	(*GetIncompletePartsResponse)(nil),   // 20: file_service.GetIncompletePartsResponse
+	(*ListUploadedPartsRequest)(nil),     // 21: file_service.ListUploadedPartsRequest
+	(*UploadedPart)(nil),                 // 22: file_service.UploadedPart
+	(*ListUploadedPartsResponse)(nil),    // 23: file_service.ListUploadedPartsResponse
+	(*GetUploadOffsetRequest)(nil),       // 24: file_service.GetUploadOffsetRequest
+	(*GetUploadOffsetResponse)(nil),      // 25: file_service.GetUploadOffsetResponse
+	(*CancelUploadRequest)(nil),          // 26: file_service.CancelUploadRequest
+	(*CreateFolderRequest)(nil),          // 27: file_service.CreateFolderRequest
+	(*CreateFolderResponse)(nil),         // 28: file_service.CreateFolderResponse
+	(*RenameFolderRequest)(nil),          // 29: file_service.RenameFolderRequest
+	(*RenameFolderResponse)(nil),         // 30: file_service.RenameFolderResponse
+	(*MoveFolderRequest)(nil),            // 31: file_service.MoveFolderRequest
+	(*MoveFolderResponse)(nil),           // 32: file_service.MoveFolderResponse
+	(*DeleteFolderRequest)(nil),          // 33: file_service.DeleteFolderRequest
+	(*ListDirectoryRequest)(nil),         // 34: file_service.ListDirectoryRequest
+	(*ListDirectoryResponse)(nil),        // 35: file_service.ListDirectoryResponse
+	(*LookupPathRequest)(nil),            // 36: file_service.LookupPathRequest
+	(*LookupPathResponse)(nil),           // 37: file_service.LookupPathResponse
+	(*ListTrashRequest)(nil),             // 38: file_service.ListTrashRequest
+	(*ListTrashResponse)(nil),            // 39: file_service.ListTrashResponse
+	(*RestoreFileRequest)(nil),           // 40: file_service.RestoreFileRequest
+	(*RestoreFileResponse)(nil),          // 41: file_service.RestoreFileResponse
+	(*EmptyTrashRequest)(nil),            // 42: file_service.EmptyTrashRequest
+	(*EmptyTrashResponse)(nil),           // 43: file_service.EmptyTrashResponse
+	(*ListVersionsRequest)(nil),          // 44: file_service.ListVersionsRequest
+	(*ListVersionsResponse)(nil),         // 45: file_service.ListVersionsResponse
+	(*GetVersionRequest)(nil),            // 46: file_service.GetVersionRequest
+	(*GetVersionResponse)(nil),           // 47: file_service.GetVersionResponse
+	(*RestoreVersionRequest)(nil),        // 48: file_service.RestoreVersionRequest
+	(*RestoreVersionResponse)(nil),       // 49: file_service.RestoreVersionResponse
+	(*DeleteVersionRequest)(nil),         // 50: file_service.DeleteVersionRequest
+	(*ListFilesRequest)(nil),             // 51: file_service.ListFilesRequest
+	(*ListFilesResponse)(nil),            // 52: file_service.ListFilesResponse
+	(*SearchFilesRequest)(nil),           // 53: file_service.SearchFilesRequest
+	(*SearchFilesResponse)(nil),          // 54: file_service.SearchFilesResponse
+	(*ReadFileRequest)(nil),              // 55: file_service.ReadFileRequest
+	(*ReadFileResponse)(nil),             // 56: file_service.ReadFileResponse
+	(*ChunkRef)(nil),                     // 57: file_service.ChunkRef
+	(*InitChunkedUploadRequest)(nil),     // 58: file_service.InitChunkedUploadRequest
+	(*InitChunkedUploadResponse)(nil),    // 59: file_service.InitChunkedUploadResponse
+	(*UploadChunkRequest)(nil),           // 60: file_service.UploadChunkRequest
+	(*GetThumbnailRequest)(nil),          // 61: file_service.GetThumbnailRequest
+	(*GetThumbnailResponse)(nil),         // 62: file_service.GetThumbnailResponse
+	(*UpdateFileTagsRequest)(nil),        // 63: file_service.UpdateFileTagsRequest
+	(*UpdateFileTagsResponse)(nil),       // 64: file_service.UpdateFileTagsResponse
+	(*CopyFileRequest)(nil),              // 65: file_service.CopyFileRequest
+	(*CopyFileResponse)(nil),             // 66: file_service.CopyFileResponse
+	(*MoveFileRequest)(nil),              // 67: file_service.MoveFileRequest
+	(*MoveFileResponse)(nil),             // 68: file_service.MoveFileResponse
+	(*ImportFromURLRequest)(nil),         // 69: file_service.ImportFromURLRequest
+	(*ImportFromURLResponse)(nil),        // 70: file_service.ImportFromURLResponse
+	(*ExtractArchiveRequest)(nil),        // 71: file_service.ExtractArchiveRequest
+	(*ExtractArchiveResponse)(nil),       // 72: file_service.ExtractArchiveResponse
+	(*ScrubFinding)(nil),                 // 73: file_service.ScrubFinding
+	(*ListCorruptedFilesRequest)(nil),    // 74: file_service.ListCorruptedFilesRequest
+	(*CorruptedFile)(nil),                // 75: file_service.CorruptedFile
+	(*ListCorruptedFilesResponse)(nil),   // 76: file_service.ListCorruptedFilesResponse
+	(*ScrubFileRequest)(nil),             // 77: file_service.ScrubFileRequest
+	(*ScrubFileResponse)(nil),            // 78: file_service.ScrubFileResponse
+	(*ReconcileObjectsRequest)(nil),      // 79: file_service.ReconcileObjectsRequest
+	(*OrphanObject)(nil),                 // 80: file_service.OrphanObject
+	(*MissingObject)(nil),                // 81: file_service.MissingObject
+	(*ReconcileObjectsResponse)(nil),     // 82: file_service.ReconcileObjectsResponse
+	nil,                                  // 83: file_service.FileInfo.TagsEntry
+	nil,                                  // 84: file_service.InitUploadRequest.TagsEntry
+	nil,                                  // 85: file_service.SearchFilesRequest.TagsEntry
+	nil,                                  // 86: file_service.InitChunkedUploadRequest.TagsEntry
+	nil,                                  // 87: file_service.UpdateFileTagsRequest.SetEntry
+	nil,                                  // 88: file_service.ImportFromURLRequest.TagsEntry
+	(*emptypb.Empty)(nil),                // 89: google.protobuf.Empty
 }
 var file_file_proto_depIdxs = []int32{
-	0,  // 0: file_service.InitUploadResponse.file:type_name -> file_service.FileInfo
-	3,  // 1: file_service.UploadPartRequest.part_metadata:type_name -> file_service.PartMetadata
-	4,  // 2: file_service.UploadPartRequest.part_content:type_name -> file_service.PartContent
-	0,  // 3: file_service.CompleteUploadResponse.file:type_name -> file_service.FileInfo
-	0,  // 4: file_service.GetFileInfoResponse.file:type_name -> file_service.FileInfo
-	1,  // 5: file_service.FileService.InitUpload:input_type -> file_service.InitUploadRequest
-	5,  // 6: file_service.FileService.UploadPart:input_type -> file_service.UploadPartRequest
-	6,  // 7: file_service.FileService.CompleteUpload:input_type -> file_service.CompleteUploadRequest
-	8,  // 8: file_service.FileService.DownloadPart:input_type -> file_service.DownloadRequest
-	10, // 9: file_service.FileService.DeleteFile:input_type -> file_service.DeleteRequest
-	11, // 10: file_service.FileService.GeneratePresignedURL:input_type -> file_service.GeneratePresignedURLRequest
-	13, // 11: file_service.FileService.GetFileInfo:input_type -> file_service.GetFileInfoRequest
-	15, // 12: file_service.FileService.GetUploadProgress:input_type -> file_service.GetUploadProgressRequest
-	17, // 13: file_service.FileService.GetIncompleteParts:input_type -> file_service.GetIncompletePartsRequest
-	19, // 14: file_service.FileService.CancelUpload:input_type -> file_service.CancelUploadRequest
-	2,  // 15: file_service.FileService.InitUpload:output_type -> file_service.InitUploadResponse
-	20, // 16: file_service.FileService.UploadPart:output_type -> google.protobuf.Empty
-	7,  // 17: file_service.FileService.CompleteUpload:output_type -> file_service.CompleteUploadResponse
-	9,  // 18: file_service.FileService.DownloadPart:output_type -> file_service.DownloadResponse
-	20, // 19: file_service.FileService.DeleteFile:output_type -> google.protobuf.Empty
-	12, // 20: file_service.FileService.GeneratePresignedURL:output_type -> file_service.GeneratePresignedURLResponse
-	14, // 21: file_service.FileService.GetFileInfo:output_type -> file_service.GetFileInfoResponse
-	16, // 22: file_service.FileService.GetUploadProgress:output_type -> file_service.GetUploadProgressResponse
-	18, // 23: file_service.FileService.GetIncompleteParts:output_type -> file_service.GetIncompletePartsResponse
-	20, // 24: file_service.FileService.CancelUpload:output_type -> google.protobuf.Empty
-	15, // [15:25] is the sub-list for method output_type
-	5,  // [5:15] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	83, // 0: file_service.FileInfo.tags:type_name -> file_service.FileInfo.TagsEntry
+	84, // 1: file_service.InitUploadRequest.tags:type_name -> file_service.InitUploadRequest.TagsEntry
+	0,  // 2: file_service.InitUploadResponse.file:type_name -> file_service.FileInfo
+	5,  // 3: file_service.UploadPartRequest.part_metadata:type_name -> file_service.PartMetadata
+	6,  // 4: file_service.UploadPartRequest.part_content:type_name -> file_service.PartContent
+	0,  // 5: file_service.CompleteUploadResponse.file:type_name -> file_service.FileInfo
+	0,  // 6: file_service.GetFileInfoResponse.file:type_name -> file_service.FileInfo
+	22, // 7: file_service.ListUploadedPartsResponse.parts:type_name -> file_service.UploadedPart
+	2,  // 8: file_service.CreateFolderResponse.folder:type_name -> file_service.FolderInfo
+	2,  // 9: file_service.RenameFolderResponse.folder:type_name -> file_service.FolderInfo
+	2,  // 10: file_service.MoveFolderResponse.folder:type_name -> file_service.FolderInfo
+	2,  // 11: file_service.ListDirectoryResponse.folders:type_name -> file_service.FolderInfo
+	0,  // 12: file_service.ListDirectoryResponse.files:type_name -> file_service.FileInfo
+	0,  // 13: file_service.LookupPathResponse.file:type_name -> file_service.FileInfo
+	0,  // 14: file_service.ListTrashResponse.files:type_name -> file_service.FileInfo
+	0,  // 15: file_service.RestoreFileResponse.file:type_name -> file_service.FileInfo
+	1,  // 16: file_service.ListVersionsResponse.versions:type_name -> file_service.VersionInfo
+	1,  // 17: file_service.GetVersionResponse.version:type_name -> file_service.VersionInfo
+	0,  // 18: file_service.RestoreVersionResponse.file:type_name -> file_service.FileInfo
+	0,  // 19: file_service.ListFilesResponse.files:type_name -> file_service.FileInfo
+	85, // 20: file_service.SearchFilesRequest.tags:type_name -> file_service.SearchFilesRequest.TagsEntry
+	0,  // 21: file_service.SearchFilesResponse.files:type_name -> file_service.FileInfo
+	57, // 22: file_service.InitChunkedUploadRequest.chunks:type_name -> file_service.ChunkRef
+	86, // 23: file_service.InitChunkedUploadRequest.tags:type_name -> file_service.InitChunkedUploadRequest.TagsEntry
+	0,  // 24: file_service.InitChunkedUploadResponse.file:type_name -> file_service.FileInfo
+	87, // 25: file_service.UpdateFileTagsRequest.set:type_name -> file_service.UpdateFileTagsRequest.SetEntry
+	0,  // 26: file_service.UpdateFileTagsResponse.file:type_name -> file_service.FileInfo
+	0,  // 27: file_service.CopyFileResponse.file:type_name -> file_service.FileInfo
+	0,  // 28: file_service.MoveFileResponse.file:type_name -> file_service.FileInfo
+	88, // 29: file_service.ImportFromURLRequest.tags:type_name -> file_service.ImportFromURLRequest.TagsEntry
+	0,  // 30: file_service.ImportFromURLResponse.file:type_name -> file_service.FileInfo
+	0,  // 31: file_service.ExtractArchiveResponse.files:type_name -> file_service.FileInfo
+	0,  // 32: file_service.CorruptedFile.file:type_name -> file_service.FileInfo
+	73, // 33: file_service.CorruptedFile.findings:type_name -> file_service.ScrubFinding
+	75, // 34: file_service.ListCorruptedFilesResponse.files:type_name -> file_service.CorruptedFile
+	73, // 35: file_service.ScrubFileResponse.findings:type_name -> file_service.ScrubFinding
+	80, // 36: file_service.ReconcileObjectsResponse.orphans:type_name -> file_service.OrphanObject
+	81, // 37: file_service.ReconcileObjectsResponse.missing:type_name -> file_service.MissingObject
+	3,  // 38: file_service.FileService.InitUpload:input_type -> file_service.InitUploadRequest
+	7,  // 39: file_service.FileService.UploadPart:input_type -> file_service.UploadPartRequest
+	8,  // 40: file_service.FileService.CompleteUpload:input_type -> file_service.CompleteUploadRequest
+	10, // 41: file_service.FileService.DownloadPart:input_type -> file_service.DownloadRequest
+	12, // 42: file_service.FileService.DeleteFile:input_type -> file_service.DeleteRequest
+	13, // 43: file_service.FileService.GeneratePresignedURL:input_type -> file_service.GeneratePresignedURLRequest
+	15, // 44: file_service.FileService.GetFileInfo:input_type -> file_service.GetFileInfoRequest
+	17, // 45: file_service.FileService.GetUploadProgress:input_type -> file_service.GetUploadProgressRequest
+	19, // 46: file_service.FileService.GetIncompleteParts:input_type -> file_service.GetIncompletePartsRequest
+	21, // 47: file_service.FileService.ListUploadedParts:input_type -> file_service.ListUploadedPartsRequest
+	24, // 48: file_service.FileService.GetUploadOffset:input_type -> file_service.GetUploadOffsetRequest
+	26, // 49: file_service.FileService.CancelUpload:input_type -> file_service.CancelUploadRequest
+	27, // 50: file_service.FileService.CreateFolder:input_type -> file_service.CreateFolderRequest
+	29, // 51: file_service.FileService.RenameFolder:input_type -> file_service.RenameFolderRequest
+	31, // 52: file_service.FileService.MoveFolder:input_type -> file_service.MoveFolderRequest
+	33, // 53: file_service.FileService.DeleteFolder:input_type -> file_service.DeleteFolderRequest
+	34, // 54: file_service.FileService.ListDirectory:input_type -> file_service.ListDirectoryRequest
+	36, // 55: file_service.FileService.LookupPath:input_type -> file_service.LookupPathRequest
+	38, // 56: file_service.FileService.ListTrash:input_type -> file_service.ListTrashRequest
+	40, // 57: file_service.FileService.RestoreFile:input_type -> file_service.RestoreFileRequest
+	42, // 58: file_service.FileService.EmptyTrash:input_type -> file_service.EmptyTrashRequest
+	44, // 59: file_service.FileService.ListVersions:input_type -> file_service.ListVersionsRequest
+	46, // 60: file_service.FileService.GetVersion:input_type -> file_service.GetVersionRequest
+	48, // 61: file_service.FileService.RestoreVersion:input_type -> file_service.RestoreVersionRequest
+	50, // 62: file_service.FileService.DeleteVersion:input_type -> file_service.DeleteVersionRequest
+	51, // 63: file_service.FileService.ListFiles:input_type -> file_service.ListFilesRequest
+	53, // 64: file_service.FileService.SearchFiles:input_type -> file_service.SearchFilesRequest
+	55, // 65: file_service.FileService.ReadFile:input_type -> file_service.ReadFileRequest
+	58, // 66: file_service.FileService.InitChunkedUpload:input_type -> file_service.InitChunkedUploadRequest
+	60, // 67: file_service.FileService.UploadChunk:input_type -> file_service.UploadChunkRequest
+	61, // 68: file_service.FileService.GetThumbnail:input_type -> file_service.GetThumbnailRequest
+	63, // 69: file_service.FileService.UpdateFileTags:input_type -> file_service.UpdateFileTagsRequest
+	65, // 70: file_service.FileService.CopyFile:input_type -> file_service.CopyFileRequest
+	67, // 71: file_service.FileService.MoveFile:input_type -> file_service.MoveFileRequest
+	71, // 72: file_service.FileService.ExtractArchive:input_type -> file_service.ExtractArchiveRequest
+	69, // 73: file_service.FileService.ImportFromURL:input_type -> file_service.ImportFromURLRequest
+	74, // 74: file_service.FileService.ListCorruptedFiles:input_type -> file_service.ListCorruptedFilesRequest
+	77, // 75: file_service.FileService.ScrubFile:input_type -> file_service.ScrubFileRequest
+	79, // 76: file_service.FileService.ReconcileObjects:input_type -> file_service.ReconcileObjectsRequest
+	4,  // 77: file_service.FileService.InitUpload:output_type -> file_service.InitUploadResponse
+	89, // 78: file_service.FileService.UploadPart:output_type -> google.protobuf.Empty
+	9,  // 79: file_service.FileService.CompleteUpload:output_type -> file_service.CompleteUploadResponse
+	11, // 80: file_service.FileService.DownloadPart:output_type -> file_service.DownloadResponse
+	89, // 81: file_service.FileService.DeleteFile:output_type -> google.protobuf.Empty
+	14, // 82: file_service.FileService.GeneratePresignedURL:output_type -> file_service.GeneratePresignedURLResponse
+	16, // 83: file_service.FileService.GetFileInfo:output_type -> file_service.GetFileInfoResponse
+	18, // 84: file_service.FileService.GetUploadProgress:output_type -> file_service.GetUploadProgressResponse
+	20, // 85: file_service.FileService.GetIncompleteParts:output_type -> file_service.GetIncompletePartsResponse
+	23, // 86: file_service.FileService.ListUploadedParts:output_type -> file_service.ListUploadedPartsResponse
+	25, // 87: file_service.FileService.GetUploadOffset:output_type -> file_service.GetUploadOffsetResponse
+	89, // 88: file_service.FileService.CancelUpload:output_type -> google.protobuf.Empty
+	28, // 89: file_service.FileService.CreateFolder:output_type -> file_service.CreateFolderResponse
+	30, // 90: file_service.FileService.RenameFolder:output_type -> file_service.RenameFolderResponse
+	32, // 91: file_service.FileService.MoveFolder:output_type -> file_service.MoveFolderResponse
+	89, // 92: file_service.FileService.DeleteFolder:output_type -> google.protobuf.Empty
+	35, // 93: file_service.FileService.ListDirectory:output_type -> file_service.ListDirectoryResponse
+	37, // 94: file_service.FileService.LookupPath:output_type -> file_service.LookupPathResponse
+	39, // 95: file_service.FileService.ListTrash:output_type -> file_service.ListTrashResponse
+	41, // 96: file_service.FileService.RestoreFile:output_type -> file_service.RestoreFileResponse
+	43, // 97: file_service.FileService.EmptyTrash:output_type -> file_service.EmptyTrashResponse
+	45, // 98: file_service.FileService.ListVersions:output_type -> file_service.ListVersionsResponse
+	47, // 99: file_service.FileService.GetVersion:output_type -> file_service.GetVersionResponse
+	49, // 100: file_service.FileService.RestoreVersion:output_type -> file_service.RestoreVersionResponse
+	89, // 101: file_service.FileService.DeleteVersion:output_type -> google.protobuf.Empty
+	52, // 102: file_service.FileService.ListFiles:output_type -> file_service.ListFilesResponse
+	54, // 103: file_service.FileService.SearchFiles:output_type -> file_service.SearchFilesResponse
+	56, // 104: file_service.FileService.ReadFile:output_type -> file_service.ReadFileResponse
+	59, // 105: file_service.FileService.InitChunkedUpload:output_type -> file_service.InitChunkedUploadResponse
+	89, // 106: file_service.FileService.UploadChunk:output_type -> google.protobuf.Empty
+	62, // 107: file_service.FileService.GetThumbnail:output_type -> file_service.GetThumbnailResponse
+	64, // 108: file_service.FileService.UpdateFileTags:output_type -> file_service.UpdateFileTagsResponse
+	66, // 109: file_service.FileService.CopyFile:output_type -> file_service.CopyFileResponse
+	68, // 110: file_service.FileService.MoveFile:output_type -> file_service.MoveFileResponse
+	72, // 111: file_service.FileService.ExtractArchive:output_type -> file_service.ExtractArchiveResponse
+	70, // 112: file_service.FileService.ImportFromURL:output_type -> file_service.ImportFromURLResponse
+	76, // 113: file_service.FileService.ListCorruptedFiles:output_type -> file_service.ListCorruptedFilesResponse
+	78, // 114: file_service.FileService.ScrubFile:output_type -> file_service.ScrubFileResponse
+	82, // 115: file_service.FileService.ReconcileObjects:output_type -> file_service.ReconcileObjectsResponse
+	77, // [77:116] is the sub-list for method output_type
+	38, // [38:77] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_file_proto_init() }
@@ -1255,17 +5872,18 @@ func file_file_proto_init() {
 	if File_file_proto != nil {
 		return
 	}
-	file_file_proto_msgTypes[5].OneofWrappers = []any{
+	file_file_proto_msgTypes[7].OneofWrappers = []any{
 		(*UploadPartRequest_PartMetadata)(nil),
 		(*UploadPartRequest_PartContent)(nil),
 	}
+	file_file_proto_msgTypes[53].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_file_proto_rawDesc), len(file_file_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   20,
+			NumMessages:   89,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMimeType 无法识别类型时使用的 MIME 类型
+const DefaultMimeType = "application/octet-stream"
+
+// MimeTypeByName 根据文件扩展名推断 MIME 类型，去掉 charset 等参数
+func MimeTypeByName(name string) string {
+	t := mime.TypeByExtension(strings.ToLower(filepath.Ext(name)))
+	if t == "" {
+		return DefaultMimeType
+	}
+	if i := strings.Index(t, ";"); i >= 0 {
+		t = strings.TrimSpace(t[:i])
+	}
+	return t
+}
+
+// SniffLen 识别内容类型需要的开头字节数
+const SniffLen = 512
+
+// DetectMimeType 根据内容开头识别 MIME 类型
+// 内容无法识别、只识别为纯文本或 zip 容器（docx、jar、epub 等）时，采用扩展名推断的更具体的类型
+func DetectMimeType(name string, head []byte) string {
+	byName := MimeTypeByName(name)
+	sniffed := http.DetectContentType(head)
+	if i := strings.Index(sniffed, ";"); i >= 0 {
+		sniffed = strings.TrimSpace(sniffed[:i])
+	}
+	switch {
+	case sniffed == DefaultMimeType:
+		return byName
+	case sniffed == "text/plain" && isTextType(byName):
+		return byName
+	case sniffed == "application/zip" && isZipContainer(byName):
+		return byName
+	}
+	return sniffed
+}
+
+// isTextType 是否为以文本形式存储的类型
+func isTextType(t string) bool {
+	if strings.HasPrefix(t, "text/") {
+		return true
+	}
+	switch t {
+	case "application/json", "application/xml", "application/javascript", "application/x-yaml",
+		"application/yaml", "application/toml", "application/x-sh", "image/svg+xml":
+		return true
+	}
+	return strings.HasSuffix(t, "+json") || strings.HasSuffix(t, "+xml")
+}
+
+// isZipContainer 是否为基于 zip 的文档或包格式
+func isZipContainer(t string) bool {
+	return strings.HasPrefix(t, "application/vnd.openxmlformats-") ||
+		strings.HasPrefix(t, "application/vnd.oasis.opendocument.") ||
+		strings.HasSuffix(t, "+zip") ||
+		t == "application/java-archive" ||
+		t == "application/vnd.android.package-archive"
+}
@@ -0,0 +1,25 @@
+package utils
+
+import "testing"
+
+func TestDetectMimeType(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	zip := []byte("PK\x03\x04\x14\x00\x00\x00")
+	cases := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		// 内容优先于扩展名
+		{"photo.txt", png, "image/png"},
+		{"archive.bin", zip, "application/zip"},
+		{"data.json", []byte(`{"a": 1}`), "application/json"},
+		{"notes", []byte("hello"), "text/plain"},
+		{"unknown.xyz", []byte{0x00, 0x01, 0x02}, DefaultMimeType},
+	}
+	for _, c := range cases {
+		if got := DetectMimeType(c.name, c.head); got != c.want {
+			t.Errorf("DetectMimeType(%q) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+
+	"cloud-storage-user-service/internal/types"
+	pb "cloud-storage-user-service/proto"
+)
+
+// CreateAccessKey 创建访问密钥
+func (s *UserServiceServer) CreateAccessKey(ctx context.Context, req *pb.CreateAccessKeyRequest) (*pb.CreateAccessKeyResponse, error) {
+	key, err := s.userService.CreateAccessKey(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateAccessKeyResponse{
+		AccessKey: toAccessKey(key),
+	}, nil
+}
+
+// ListAccessKeys 列出访问密钥
+func (s *UserServiceServer) ListAccessKeys(ctx context.Context, req *pb.ListAccessKeysRequest) (*pb.ListAccessKeysResponse, error) {
+	keys, err := s.userService.ListAccessKeys(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListAccessKeysResponse{AccessKeys: make([]*pb.AccessKey, 0, len(keys))}
+	for i := range keys {
+		resp.AccessKeys = append(resp.AccessKeys, toAccessKey(&keys[i]))
+	}
+	return resp, nil
+}
+
+// DeleteAccessKey 删除访问密钥
+func (s *UserServiceServer) DeleteAccessKey(ctx context.Context, req *pb.DeleteAccessKeyRequest) (*pb.DeleteAccessKeyResponse, error) {
+	if err := s.userService.DeleteAccessKey(req.UserId, req.AccessKeyId); err != nil {
+		return &pb.DeleteAccessKeyResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &pb.DeleteAccessKeyResponse{
+		Success: true,
+		Message: "删除成功",
+	}, nil
+}
+
+// LookupAccessKey 查询访问密钥所属的用户和密钥
+func (s *UserServiceServer) LookupAccessKey(ctx context.Context, req *pb.LookupAccessKeyRequest) (*pb.LookupAccessKeyResponse, error) {
+	key, err := s.userService.LookupAccessKey(req.AccessKeyId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LookupAccessKeyResponse{
+		UserId:          key.UserID,
+		SecretAccessKey: key.SecretKey,
+	}, nil
+}
+
+func toAccessKey(key *types.AccessKeyInfo) *pb.AccessKey {
+	return &pb.AccessKey{
+		AccessKeyId:     key.AccessKeyID,
+		SecretAccessKey: key.SecretKey,
+		CreatedAt:       key.CreatedAt.Unix(),
+	}
+}
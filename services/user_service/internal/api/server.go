@@ -7,9 +7,6 @@ import (
 	"cloud-storage-user-service/internal/service"
 	"cloud-storage-user-service/internal/types"
 	pb "cloud-storage-user-service/proto"
-
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 // UserServiceServer 实现proto中定义的UserServiceServer接口
@@ -140,8 +137,24 @@ func (s *UserServiceServer) UpdateUserInfo(ctx context.Context, req *pb.UpdateUs
 
 // UpdateUsage 更新用户存储使用量
 func (s *UserServiceServer) UpdateUsage(ctx context.Context, req *pb.UpdateUsageRequest) (*pb.UpdateUsageResponse, error) {
-	// 这个方法在服务层没有实现，暂时返回未实现错误
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateUsage not implemented")
+	// 转换请求参数
+	updateUsageReq := &types.UpdateUsageRequest{
+		UserID: req.UserId,
+		Delta:  req.Delta,
+	}
+
+	// 调用服务层
+	usedSpace, err := s.userService.UpdateUsage(updateUsageReq)
+	if err != nil {
+		return &pb.UpdateUsageResponse{
+			Success: false,
+		}, nil
+	}
+
+	return &pb.UpdateUsageResponse{
+		Success:   true,
+		UsedSpace: usedSpace,
+	}, nil
 }
 
 // UpdateCapacity 更新用户总容量
@@ -168,6 +181,63 @@ func (s *UserServiceServer) UpdateCapacity(ctx context.Context, req *pb.UpdateCa
 	}, nil
 }
 
+// ReserveSpace 预留上传所需空间
+func (s *UserServiceServer) ReserveSpace(ctx context.Context, req *pb.ReserveSpaceRequest) (*pb.ReserveSpaceResponse, error) {
+	// 调用服务层
+	resp, err := s.userService.ReserveSpace(&types.ReserveSpaceRequest{
+		UserID: req.UserId,
+		Size:   req.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := "预留成功"
+	if !resp.Success {
+		message = "剩余空间不足"
+	}
+	return &pb.ReserveSpaceResponse{
+		Success:   resp.Success,
+		Message:   message,
+		UsedSpace: resp.UsedSpace,
+		Remaining: resp.Remaining,
+	}, nil
+}
+
+// SetUsage 在已用空间未被修改时设置用户已用空间
+func (s *UserServiceServer) SetUsage(ctx context.Context, req *pb.SetUsageRequest) (*pb.SetUsageResponse, error) {
+	// 调用服务层
+	ok, err := s.userService.SetUsage(&types.SetUsageRequest{
+		UserID:            req.UserId,
+		UsedSpace:         req.UsedSpace,
+		ExpectedUsedSpace: req.ExpectedUsedSpace,
+	})
+	if err != nil {
+		return &pb.SetUsageResponse{
+			Success: false,
+		}, nil
+	}
+
+	return &pb.SetUsageResponse{
+		Success:  ok,
+		Conflict: !ok,
+	}, nil
+}
+
+// ListUsage 分页列出已用空间不为0的用户
+func (s *UserServiceServer) ListUsage(ctx context.Context, req *pb.ListUsageRequest) (*pb.ListUsageResponse, error) {
+	usages, err := s.userService.ListUsage(req.AfterId, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListUsageResponse{Usages: make([]*pb.UserUsage, 0, len(usages))}
+	for _, u := range usages {
+		resp.Usages = append(resp.Usages, &pb.UserUsage{UserId: u.UserID, UsedSpace: u.UsedSpace})
+	}
+	return resp, nil
+}
+
 // CheckCapacity 检查用户容量是否足够
 func (s *UserServiceServer) CheckCapacity(ctx context.Context, req *pb.CheckCapacityRequest) (*pb.CheckCapacityResponse, error) {
 	// 转换请求参数
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// AccessKey 用户的 S3 访问密钥
+// 校验 SigV4 签名需要原始密钥，SecretKey 以明文保存，只通过内部接口返回给网关
+type AccessKey struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	UserID      int64     `gorm:"index;not null"`
+	AccessKeyID string    `gorm:"uniqueIndex;size:32;not null"`
+	SecretKey   string    `gorm:"size:64;not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
@@ -7,4 +7,13 @@ type UserDAO interface {
 	UpdateUser(user *User) error
 	UpdateUsage(userID int64, delta int64) error
 	UpdateCapacity(userID int64, newTotalSpace int64) error
+	ReserveSpace(userID int64, size int64) (bool, error)
+	SetUsage(userID int64, expected, usedSpace int64) (bool, error)
+	ListUsage(afterID int64, limit int) ([]User, error)
+
+	// 访问密钥
+	CreateAccessKey(key *AccessKey) error
+	ListAccessKeys(userID int64) ([]AccessKey, error)
+	GetAccessKey(accessKeyID string) (*AccessKey, error)
+	DeleteAccessKey(userID int64, accessKeyID string) (bool, error)
 }
@@ -41,9 +41,10 @@ func (d *userDAOImpl) UpdateUser(user *User) error {
 }
 
 func (d *userDAOImpl) UpdateUsage(userID int64, delta int64) error {
+	// 已用空间不能被扣减为负数
 	return d.db.Model(&User{}).
 		Where("id = ?", userID).
-		UpdateColumn("used_space", gorm.Expr("used_space + ?", delta)).
+		UpdateColumn("used_space", gorm.Expr("GREATEST(used_space + ?, 0)", delta)).
 		Error
 }
 
@@ -53,3 +54,61 @@ func (d *userDAOImpl) UpdateCapacity(userID int64, newTotalSpace int64) error {
 		Update("total_space", newTotalSpace).
 		Error
 }
+
+// ReserveSpace 在剩余容量足够时原子地增加已用空间，容量不足返回 false
+func (d *userDAOImpl) ReserveSpace(userID int64, size int64) (bool, error) {
+	result := d.db.Model(&User{}).
+		Where("id = ? AND used_space + ? <= total_space", userID, size).
+		UpdateColumn("used_space", gorm.Expr("used_space + ?", size))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// SetUsage 在已用空间仍为 expected 时设置为 usedSpace，期间被其他请求修改过返回 false
+func (d *userDAOImpl) SetUsage(userID int64, expected, usedSpace int64) (bool, error) {
+	result := d.db.Model(&User{}).
+		Where("id = ? AND used_space = ?", userID, expected).
+		UpdateColumn("used_space", usedSpace)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ListUsage 按ID顺序列出已用空间不为0的用户
+func (d *userDAOImpl) ListUsage(afterID int64, limit int) ([]User, error) {
+	var users []User
+	err := d.db.Select("id", "used_space").
+		Where("id > ? AND used_space <> 0", afterID).
+		Order("id asc").Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+func (d *userDAOImpl) CreateAccessKey(key *AccessKey) error {
+	return d.db.Create(key).Error
+}
+
+func (d *userDAOImpl) ListAccessKeys(userID int64) ([]AccessKey, error) {
+	var keys []AccessKey
+	err := d.db.Where("user_id = ?", userID).Order("id asc").Find(&keys).Error
+	return keys, err
+}
+
+// GetAccessKey 按访问密钥ID查询，不存在时返回 nil
+func (d *userDAOImpl) GetAccessKey(accessKeyID string) (*AccessKey, error) {
+	var key AccessKey
+	err := d.db.Where("access_key_id = ?", accessKeyID).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &key, err
+}
+
+// DeleteAccessKey 删除用户的访问密钥，密钥不存在或不属于该用户时返回 false
+func (d *userDAOImpl) DeleteAccessKey(userID int64, accessKeyID string) (bool, error) {
+	result := d.db.Where("user_id = ? AND access_key_id = ?", userID, accessKeyID).Delete(&AccessKey{})
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"cloud-storage-user-service/internal/model"
+	"cloud-storage-user-service/internal/types"
+)
+
+// maxAccessKeys 每个用户最多持有的访问密钥数
+const maxAccessKeys = 5
+
+// accessKeyAlphabet 访问密钥ID使用的字符，与 AWS 的格式一致
+const accessKeyAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+
+// CreateAccessKey 为用户生成新的访问密钥，密钥只在这里返回一次
+func (s *UserService) CreateAccessKey(userID int64) (*types.AccessKeyInfo, error) {
+	user, err := s.userDAO.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("数据库查询错误: %v", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("用户不存在")
+	}
+
+	keys, err := s.userDAO.ListAccessKeys(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询访问密钥失败: %v", err)
+	}
+	if len(keys) >= maxAccessKeys {
+		return nil, fmt.Errorf("访问密钥不能超过 %d 个", maxAccessKeys)
+	}
+
+	id := make([]byte, 18)
+	secret := make([]byte, 30)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("生成访问密钥失败: %v", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("生成访问密钥失败: %v", err)
+	}
+	key := &model.AccessKey{
+		UserID:      userID,
+		AccessKeyID: "AK" + encodeAccessKeyID(id),
+		SecretKey:   base64.StdEncoding.EncodeToString(secret),
+	}
+	if err := s.userDAO.CreateAccessKey(key); err != nil {
+		return nil, fmt.Errorf("保存访问密钥失败: %v", err)
+	}
+	return toAccessKeyInfo(key, true), nil
+}
+
+// ListAccessKeys 列出用户的访问密钥，不包含密钥本身
+func (s *UserService) ListAccessKeys(userID int64) ([]types.AccessKeyInfo, error) {
+	keys, err := s.userDAO.ListAccessKeys(userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询访问密钥失败: %v", err)
+	}
+	infos := make([]types.AccessKeyInfo, 0, len(keys))
+	for i := range keys {
+		infos = append(infos, *toAccessKeyInfo(&keys[i], false))
+	}
+	return infos, nil
+}
+
+// DeleteAccessKey 删除用户的访问密钥，删除后使用该密钥签名的请求立即失效
+func (s *UserService) DeleteAccessKey(userID int64, accessKeyID string) error {
+	ok, err := s.userDAO.DeleteAccessKey(userID, accessKeyID)
+	if err != nil {
+		return fmt.Errorf("删除访问密钥失败: %v", err)
+	}
+	if !ok {
+		return fmt.Errorf("访问密钥不存在")
+	}
+	return nil
+}
+
+// LookupAccessKey 查询访问密钥所属的用户和密钥，供网关校验签名
+func (s *UserService) LookupAccessKey(accessKeyID string) (*types.AccessKeyInfo, error) {
+	key, err := s.userDAO.GetAccessKey(accessKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("数据库查询错误: %v", err)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("访问密钥不存在")
+	}
+	return toAccessKeyInfo(key, true), nil
+}
+
+func toAccessKeyInfo(key *model.AccessKey, withSecret bool) *types.AccessKeyInfo {
+	info := &types.AccessKeyInfo{
+		AccessKeyID: key.AccessKeyID,
+		UserID:      key.UserID,
+		CreatedAt:   key.CreatedAt,
+	}
+	if withSecret {
+		info.SecretKey = key.SecretKey
+	}
+	return info
+}
+
+// encodeAccessKeyID 把随机字节编码为大写字母和数字
+func encodeAccessKeyID(b []byte) string {
+	out := make([]byte, len(b))
+	for i, c := range b {
+		out[i] = accessKeyAlphabet[int(c)%len(accessKeyAlphabet)]
+	}
+	return string(out)
+}
@@ -178,6 +178,100 @@ func (s *UserService) UpdateCapacity(req *types.UpdateCapacityRequest) error {
 	return nil
 }
 
+// UpdateUsage 增减用户已用空间，返回更新后的已用空间
+func (s *UserService) UpdateUsage(req *types.UpdateUsageRequest) (int64, error) {
+	user, err := s.userDAO.GetByID(req.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("数据库查询错误: %v", err)
+	}
+
+	if user == nil {
+		return 0, fmt.Errorf("用户不存在")
+	}
+
+	if err := s.userDAO.UpdateUsage(req.UserID, req.Delta); err != nil {
+		return 0, fmt.Errorf("更新已用空间失败: %v", err)
+	}
+
+	user, err = s.userDAO.GetByID(req.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("数据库查询错误: %v", err)
+	}
+	return user.UsedSpace, nil
+}
+
+// ReserveSpace 预留上传所需空间，剩余容量不足时 Success 为 false
+func (s *UserService) ReserveSpace(req *types.ReserveSpaceRequest) (*types.ReserveSpaceResponse, error) {
+	if req.Size < 0 {
+		return nil, fmt.Errorf("预留空间不能为负数")
+	}
+
+	user, err := s.userDAO.GetByID(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("数据库查询错误: %v", err)
+	}
+
+	if user == nil {
+		return nil, fmt.Errorf("用户不存在")
+	}
+
+	ok := true
+	if req.Size > 0 {
+		ok, err = s.userDAO.ReserveSpace(req.UserID, req.Size)
+		if err != nil {
+			return nil, fmt.Errorf("预留空间失败: %v", err)
+		}
+		if user, err = s.userDAO.GetByID(req.UserID); err != nil {
+			return nil, fmt.Errorf("数据库查询错误: %v", err)
+		}
+	}
+
+	return &types.ReserveSpaceResponse{
+		Success:   ok,
+		UsedSpace: user.UsedSpace,
+		Remaining: user.TotalSpace - user.UsedSpace,
+	}, nil
+}
+
+// SetUsage 设置用户已用空间，供对账任务修正偏差
+// 只在当前已用空间仍为 ExpectedUsedSpace 时设置，期间被预留或回写修改过时返回 false
+func (s *UserService) SetUsage(req *types.SetUsageRequest) (bool, error) {
+	if req.UsedSpace < 0 {
+		return false, fmt.Errorf("已用空间不能为负数")
+	}
+
+	user, err := s.userDAO.GetByID(req.UserID)
+	if err != nil {
+		return false, fmt.Errorf("数据库查询错误: %v", err)
+	}
+
+	if user == nil {
+		return false, fmt.Errorf("用户不存在")
+	}
+
+	ok, err := s.userDAO.SetUsage(req.UserID, req.ExpectedUsedSpace, req.UsedSpace)
+	if err != nil {
+		return false, fmt.Errorf("设置已用空间失败: %v", err)
+	}
+	return ok, nil
+}
+
+// ListUsage 分页列出已用空间不为0的用户，供对账任务找出没有文件却仍占用空间的用户
+func (s *UserService) ListUsage(afterID int64, limit int) ([]types.UserUsage, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 1000
+	}
+	users, err := s.userDAO.ListUsage(afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("数据库查询错误: %v", err)
+	}
+	usages := make([]types.UserUsage, 0, len(users))
+	for _, u := range users {
+		usages = append(usages, types.UserUsage{UserID: u.ID, UsedSpace: u.UsedSpace})
+	}
+	return usages, nil
+}
+
 func (s *UserService) CheckCapacity(req *types.CheckCapacityRequest) (*types.CheckCapacityResponse, error) {
 	// 获取用户信息
 	user, err := s.userDAO.GetByID(req.UserID)
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // 用户注册
 type CreateUserRequest struct {
 	Username string
@@ -65,6 +67,37 @@ type UpdateCapacityRequest struct {
 	NewTotal int64
 }
 
+// 更新已用空间
+type UpdateUsageRequest struct {
+	UserID int64
+	Delta  int64 // 正数=增加，负数=减少
+}
+
+// 预留空间
+type ReserveSpaceRequest struct {
+	UserID int64
+	Size   int64
+}
+
+type ReserveSpaceResponse struct {
+	Success   bool
+	UsedSpace int64
+	Remaining int64
+}
+
+// 设置已用空间（对账），只在当前已用空间为 ExpectedUsedSpace 时设置
+type SetUsageRequest struct {
+	UserID            int64
+	UsedSpace         int64
+	ExpectedUsedSpace int64
+}
+
+// 用户已用空间（对账）
+type UserUsage struct {
+	UserID    int64
+	UsedSpace int64
+}
+
 // 检查容量是否足够
 type CheckCapacityRequest struct {
 	UserID   int64
@@ -73,4 +106,12 @@ type CheckCapacityRequest struct {
 
 type CheckCapacityResponse struct {
 	IsEnough bool
-}
\ No newline at end of file
+}
+
+// 访问密钥
+type AccessKeyInfo struct {
+	AccessKeyID string
+	SecretKey   string // 只在创建和内部查询时返回
+	UserID      int64
+	CreatedAt   time.Time
+}
@@ -31,8 +31,8 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// 自动迁移 User 模型
-	if err := db.AutoMigrate(&model.User{}); err != nil {
+	// 自动迁移 User 和 AccessKey 模型
+	if err := db.AutoMigrate(&model.User{}, &model.AccessKey{}); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 